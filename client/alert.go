@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// snapshotCommands are the CLI commands whose raw outputs are captured
+// to the snapshot bundle by the file names.
+var snapshotCommands = map[string]string{
+	"show-runtime.txt": "show runtime",
+	"show-errors.txt":  "show errors",
+	"show-memory.txt":  "show memory main-heap verbose",
+}
+
+// AlertConfig configures the alert rules evaluated on the collected stats.
+type AlertConfig struct {
+	// Rules in the 'tab: expression' form, e.g. 'interfaces: rxerrors>0'. The tab
+	// is named by its HTTP endpoint and the expression is a filter expression,
+	// the rule fires once any stats item of the tab matches the expression.
+	Rules []string
+	// SnapshotDir (optional) is the directory the snapshot bundles
+	// are captured to when a rule fires.
+	SnapshotDir string
+	// SyncTag (optional) tags the snapshot bundles, so that the bundles of
+	// instances sharing the tag (e.g. on both ends of a link) are named and
+	// timestamped identically (in UTC).
+	SyncTag string
+}
+
+// syncTagRe matches the sync tags valid as a part of the bundle name.
+var syncTagRe = regexp.MustCompile(`^[\w.-]+$`)
+
+// alertRule is a parsed alert rule.
+type alertRule struct {
+	text string
+	tab  int
+	expr filterExpr
+	// set while the rule fires, so that it fires again
+	// only once the tab stops matching
+	firing bool
+}
+
+// SetAlerts enables the evaluation of the alert rules while the application runs.
+func (app *App) SetAlerts(cfg AlertConfig) error {
+	app.alerts = nil
+	for _, text := range cfg.Rules {
+		rule, err := parseAlertRule(text)
+		if err != nil {
+			return err
+		}
+		app.alerts = append(app.alerts, rule)
+	}
+	if cfg.SyncTag != "" && !syncTagRe.MatchString(cfg.SyncTag) {
+		return fmt.Errorf("invalid sync tag %q, expected letters, digits, '.', '_' or '-'", cfg.SyncTag)
+	}
+	app.snapshotDir = cfg.SnapshotDir
+	app.syncTag = cfg.SyncTag
+	return nil
+}
+
+// parseAlertRule parses the 'tab: expression' alert rule.
+func parseAlertRule(text string) (*alertRule, error) {
+	i := strings.Index(text, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid alert rule %q, expected 'tab: expression'", text)
+	}
+	name := strings.ToLower(strings.TrimSpace(text[:i]))
+	tab, ok := httpEndpoints["/"+name]
+	if !ok {
+		return nil, fmt.Errorf("invalid alert rule %q: unknown tab %q", text, name)
+	}
+	expr, err := parseFilter(tab, strings.TrimSpace(text[i+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid alert rule %q: %v", text, err)
+	}
+	return &alertRule{text: text, tab: tab, expr: expr}, nil
+}
+
+// runAlerts is a blocking call evaluating the alert rules on the cached
+// stats every second until the context is cancelled.
+func (app *App) runAlerts(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for _, rule := range app.alerts {
+				app.evaluateAlert(ctx, rule)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluateAlert fires the rule if the cached stats of its tab match,
+// a snapshot is captured if the snapshot directory is set.
+func (app *App) evaluateAlert(ctx context.Context, rule *alertRule) {
+	entry, ok := app.cache.load(rule.tab)
+	if !ok {
+		return
+	}
+	matches := rule.matches(entry.data, entry.rates)
+	switch {
+	case matches == 0 && rule.firing:
+		rule.firing = false
+		logrus.Infof("alert %q resolved", rule.text)
+	case matches > 0 && !rule.firing:
+		rule.firing = true
+		logrus.Warnf("alert %q fired, %d %s stats match", rule.text, matches, tabNames[rule.tab])
+		app.notify(ctx, gui.SeverityWarning, i18n.T("alert %q fired, %d %s stats match", rule.text, matches, i18n.T(tabNames[rule.tab])))
+		if app.snapshotDir == "" {
+			return
+		}
+		dir, err := app.captureSnapshot(ctx, rule, matches)
+		if err != nil {
+			logrus.Errorf("error occured while capturing snapshot: %v", err)
+			return
+		}
+		logrus.Infof("snapshot captured to %s", dir)
+	}
+}
+
+// matches returns the number of stats items matching the rule.
+func (r *alertRule) matches(data interface{}, rates *statsRates) int {
+	switch summary := data.(type) {
+	case *api.FibSummary:
+		data = summary.Tables
+	case *api.APIClients:
+		data = summary.Clients
+	}
+	items := reflect.ValueOf(data)
+	if items.Kind() != reflect.Slice {
+		return 0
+	}
+	var count int
+	for i := 0; i < items.Len(); i++ {
+		if r.expr.match(items.Index(i).Interface(), rates) {
+			count++
+		}
+	}
+	return count
+}
+
+// snapshotName returns the name of the bundle directory captured at the time.
+// The tagged bundles are named by the tag and the UTC time, so the bundles of
+// the instances sharing the tag can be matched by the name.
+func snapshotName(tag string, now time.Time) string {
+	if tag == "" {
+		return "vpptop-" + now.Format("20060102-150405")
+	}
+	return "vpptop-" + tag + "-" + now.UTC().Format("20060102-150405")
+}
+
+// captureSnapshot writes the cached stats of all tabs as JSON, together
+// with raw outputs of the CLI commands, to a new bundle directory.
+// The directory is returned.
+func (app *App) captureSnapshot(ctx context.Context, rule *alertRule, matches int) (string, error) {
+	now := time.Now()
+	dir := filepath.Join(app.snapshotDir, snapshotName(app.syncTag, now))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	alert := fmt.Sprintf("rule: %s\ntime: %s\nmatches: %d\n", rule.text, now.Format(time.RFC3339), matches)
+	if app.syncTag != "" {
+		host, _ := os.Hostname()
+		alert += fmt.Sprintf("tag: %s\nhost: %s\nutc: %s\n", app.syncTag, host, now.UTC().Format(time.RFC3339Nano))
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "alert.txt"), []byte(alert), 0644); err != nil {
+		return "", err
+	}
+	for path, tab := range httpEndpoints {
+		entry, ok := app.cache.load(tab)
+		if !ok {
+			continue
+		}
+		data, err := json.MarshalIndent(entry.data, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %s stats: %v", tabNames[tab], err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, strings.TrimPrefix(path, "/")+".json"), data, 0644); err != nil {
+			return "", err
+		}
+	}
+	for file, cmd := range snapshotCommands {
+		var cliCtx context.Context
+		var cancel context.CancelFunc
+		if app.pollTimeout > 0 {
+			cliCtx, cancel = context.WithTimeout(ctx, app.pollTimeout)
+		} else {
+			cliCtx, cancel = context.WithCancel(ctx)
+		}
+		out, err := app.vppProvider.RunCli(cliCtx, cmd)
+		cancel()
+		if err != nil {
+			logrus.Warnf("snapshot: %s failed: %v", cmd, err)
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(out), 0644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotName(t *testing.T) {
+	at := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "", want: "vpptop-20210304-050607"},
+		{tag: "link-1", want: "vpptop-link-1-20210304-040607"},
+	}
+	for _, test := range tests {
+		if got := snapshotName(test.tag, at); got != test.want {
+			t.Errorf("Error occured got:%q; want:%q", got, test.want)
+		}
+	}
+}
+
+func TestSetAlertsSyncTag(t *testing.T) {
+	tests := []struct {
+		tag   string
+		valid bool
+	}{
+		{tag: "", valid: true},
+		{tag: "link-1", valid: true},
+		{tag: "router_a.b", valid: true},
+		{tag: "a/b", valid: false},
+		{tag: "a b", valid: false},
+		{tag: "../x", valid: false},
+	}
+	for _, test := range tests {
+		app := &App{}
+		err := app.SetAlerts(AlertConfig{SnapshotDir: "/tmp", SyncTag: test.tag})
+		if (err == nil) != test.valid {
+			t.Errorf("Error occured for tag %q: %v (want valid: %t)", test.tag, err, test.valid)
+			continue
+		}
+		if err == nil && app.syncTag != test.tag {
+			t.Errorf("Error occured got:%q; want:%q", app.syncTag, test.tag)
+		}
+	}
+}
@@ -20,31 +20,50 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"strings"
 	"sync"
 	"time"
 
 	"git.fd.io/govpp.git/core"
+	"github.com/sirupsen/logrus"
 	"go.pantheon.tech/vpptop/gui"
 	"go.pantheon.tech/vpptop/gui/views"
 	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/i18n"
 	"go.pantheon.tech/vpptop/stats"
 	"go.pantheon.tech/vpptop/stats/api"
 )
 
-// Index for each TableView. (total of 5 tabs)
+// Index for each TableView. (total of 20 tabs, the diagnostics tab is optional)
 const (
 	Interfaces = iota
 	Nodes
 	Errors
 	Memory
 	Threads
+	DropsPunts
+	Tunnels
+	Sessions
+	Features
+	Bonds
+	Policers
+	Fib
+	Neighbors
+	SRv6
+	Mpls
+	APITrace
+	APIClients
+	Capture
+	Info
+	Diagnostics
 )
 
+// tabNames are the names of the tabs in the order of their indexes.
+var tabNames = []string{"Interfaces", "Nodes", "Errors", "Memory", "Threads", "Drops/Punts", "Tunnels", "Sessions", "Features", "Bonds", "Policers", "FIB", "Neighbors", "SRv6", "MPLS", "API Trace", "API Clients", "Capture", "Info", "Diagnostics"}
+
 const (
 	// RowsPerIface represents number of rows in the xtui table per interface
-	RowsPerIface = 11
+	RowsPerIface = 15
 	// RowsPerMemory represents number of rows in the xtui table per memory.
 	RowsPerMemory = 8
 )
@@ -52,16 +71,37 @@ const (
 // VPP API handler definition list determines supported versions
 // - VPPs supported by Ligato VPP-Agent
 // - VPPs supported by the local implementation
+// The definitions bundled with vpptop are returned by collect.DefaultHandlers.
 var Defs []api.HandlerDef
 
+// HandlerAuto selects the first compatible handler in the order of Defs.
+const HandlerAuto = "auto"
+
+// SelectHandler restricts Defs to the handler definition with the given name,
+// so the compatibility probing does not fall back to other handlers.
+func SelectHandler(name string) error {
+	if name == "" || name == HandlerAuto {
+		return nil
+	}
+	names := make([]string, 0, len(Defs))
+	for _, def := range Defs {
+		if def.Name() == name {
+			Defs = []api.HandlerDef{def}
+			return nil
+		}
+		names = append(names, def.Name())
+	}
+	return fmt.Errorf("unknown handler %q (available: %s, %s)", name, strings.Join(names, ", "), HandlerAuto)
+}
+
 // App groups VPP provider, GUI and caches
 type App struct {
 	gui         *gui.TermWindow
 	vppProvider api.VppProviderAPI
 
-	// Cache for interface stats to
-	// be able to calculate bytes/s packets/s.
-	ifCache []api.Interface
+	// Cache for the polled data of all tabs, it keeps the previous
+	// data as well to be able to calculate bytes/s packets/s.
+	cache *dataCache
 
 	// sortBy carries information used at sorting stats
 	// for each tab.
@@ -70,37 +110,147 @@ type App struct {
 		field int
 	}
 
+	// handler (optional) is used instead of connecting to the VPP.
+	handler api.HandlerAPI
+	// statsOnly is set if only the stats socket is connected.
+	statsOnly bool
+
+	// push (optional) configures pushing of the collected metrics.
+	push *PushConfig
+
+	// influx (optional) configures exporting of the collected stats to InfluxDB.
+	influx *InfluxConfig
+
+	// http (optional) configures the HTTP server exposing the collected stats.
+	http *HTTPConfig
+
+	// controlSocket (optional) is the path of the UNIX socket accepting
+	// commands of external scripts.
+	controlSocket string
+
+	// alerts (optional) are the rules evaluated on the collected stats.
+	alerts []*alertRule
+	// snapshotDir (optional) is the directory of the snapshots captured on alerts.
+	snapshotDir string
+	// syncTag (optional) tags the snapshots shared with other instances.
+	syncTag string
+
+	// layout (optional) persists the column widths of the tabs.
+	layout *layout
+	// watchlist of the interfaces and nodes pinned to the top of their tables.
+	watchlist *watchlist
+
+	// grouping of sub-interfaces into their parent interfaces.
+	groups *tableGroups
+	// grouping of error counters by their nodes or threads.
+	errorGroups *tableGroups
+
+	// interface rates the top talkers are ranked by.
+	talkers *topTalkers
+
+	// changes of the interface details shown by the events ticker.
+	events *ifaceEvents
+
+	// durations of the polls shown by the diagnostics tab.
+	self *selfMonitor
+
+	// used main heap memory the memory trend is estimated from.
+	memory *memoryTrend
+
+	// packet captures started at the capture tab.
+	captures *captureState
+
+	// filter expressions applied on stats for each tab.
+	filters []filterExpr
+	// filter texts of each tab, either expressions or names.
+	filterTexts []string
+	// hideZeroNodes hides nodes with zero calls and vectors.
+	hideZeroNodes bool
+
+	// node counters the nodes are compared to.
+	baseline *nodeBaseline
+
+	// timed measurement of the counters.
+	measurement *measurement
+
 	// current gui tab.
 	currTab int
+	// tab shown by the other pane of the split view, -1 if not split.
+	splitTab int
+	// cache entries rendered while the updates are paused, nil if not paused.
+	paused map[int]cacheEntry
+
+	// units used to format the interface counters.
+	units unitFormat
+
+	// highlighting of the interface rates deviating from their moving averages.
+	spikes *spikeHighlight
+
+	// compact layout of the interfaces tab.
+	compact *compactLayout
+
+	// tabs with the auto-refresh turned off.
+	manual *manualRefresh
+
+	// link utilization in percent from which the interface rates are highlighted.
+	utilThreshold float64
+
+	// timeout of a single poll of a data source.
+	pollTimeout time.Duration
 
 	// gui notifications about the content change
 	onDataUpdate chan struct{}
 
+	// collector notifications about the current tab data change
+	refresh chan struct{}
+
 	// go routine management.
-	wg       *sync.WaitGroup
-	sortLock *sync.Mutex
-	tabLock  *sync.Mutex
-	vppLock  *sync.Mutex
-	cancel   context.CancelFunc
+	wg         *sync.WaitGroup
+	sortLock   *sync.Mutex
+	filterLock *sync.Mutex
+	tabLock    *sync.Mutex
+	unitsLock  *sync.Mutex
+	cancel     context.CancelFunc
 }
 
-func NewApp(lightTheme bool, logFile io.Writer) (*App, error) {
+func NewApp(logFile io.Writer, opts ...stats.ProviderOption) (*App, error) {
 	app := new(App)
 
 	app.sortLock = new(sync.Mutex)
+	app.filterLock = new(sync.Mutex)
 	app.tabLock = new(sync.Mutex)
-	app.vppLock = new(sync.Mutex)
+	app.unitsLock = new(sync.Mutex)
+	app.cache = newDataCache()
+	app.groups = newTableGroups()
+	app.watchlist = newWatchlist()
+	app.errorGroups = newTableGroups()
+	app.talkers = newTopTalkers(DefaultTalkersWindow)
+	app.events = newIfaceEvents(DefaultEventsLimit)
+	app.self = newSelfMonitor()
+	app.memory = newMemoryTrend(DefaultMemoryTrendWindow)
+	app.captures = newCaptureState()
+	app.baseline = new(nodeBaseline)
+	app.measurement = newMeasurement(DefaultMeasureWindow)
+	app.pollTimeout = DefaultPollTimeout
+	app.spikes = &spikeHighlight{sigma: DefaultSpikeSigma}
+	app.compact = &compactLayout{widths: compactWidths}
+	app.manual = newManualRefresh()
+	app.utilThreshold = DefaultUtilThreshold
 
 	if len(Defs) == 0 {
 		return nil, fmt.Errorf("no VPP handler definition was provided")
 	}
-	app.vppProvider = stats.NewVppProvider(Defs, logFile)
+	app.vppProvider = stats.NewVppProvider(Defs, logFile, opts...)
 	app.wg = new(sync.WaitGroup)
 	app.sortBy = make([]struct {
 		asc   bool
 		field int
-	}, 5)
+	}, len(tabNames))
+	app.filters = make([]filterExpr, len(tabNames))
+	app.filterTexts = make([]string, len(tabNames))
 	app.onDataUpdate = make(chan struct{})
+	app.refresh = make(chan struct{}, 1)
+	app.splitTab = -1
 
 	for i := range app.sortBy {
 		app.sortBy[i].field = NoColumn
@@ -112,10 +262,10 @@ func NewApp(lightTheme bool, logFile io.Writer) (*App, error) {
 		[]gui.TabView{
 			// interface tab.
 			views.NewTableView(
-				[]string{
+				i18n.Slice([]string{
 					"Name",
 					"Index",
-					"State",
+					"Admin",
 					"MTU-L3",
 					"MTU-IP4",
 					"MTU-IP6",
@@ -142,16 +292,26 @@ func NewApp(lightTheme bool, logFile io.Writer) (*App, error) {
 					"Punts",
 					"IP4",
 					"IP6",
-				},
-				xtui.TableRows{{"Name", "Idx", "State", "MTU(L3/IP4/IP6/MPLS)", "RxCounters", "RxCount", "TxCounters", "TxCount", "Drops", "Punts", "IP4", "IP6"}},
+					"TopTalkers-avg",
+					"TopTalkers-peak",
+					"Instance",
+					"RxPackets/s",
+					"RxBytes/s",
+					"TxPackets/s",
+					"TxBytes/s",
+					"RxUtil",
+					"TxUtil",
+					"VRF",
+					"Link",
+				}),
+				interfacesHeader(false, unitFormat{}),
 				IfaceStatIfaceName,
 				RowsPerIface,
-				[]int{24, 5, 5, 20, 10, 16, 11, 16, 11, 11, 11, views.Resize},
-				lightTheme,
+				[]int{24, 5, 5, 7, 7, 28, 10, 16, 11, 16, 11, 11, 11, 11, views.Resize},
 			),
 			// node tab.
 			views.NewTableView(
-				[]string{
+				i18n.Slice([]string{
 					"Name",
 					"State",
 					"Calls",
@@ -159,53 +319,226 @@ func NewApp(lightTheme bool, logFile io.Writer) (*App, error) {
 					"Suspends",
 					"Clocks",
 					"Vectors/Calls",
-				},
-				xtui.TableRows{{"Name", "State", "Calls", "Vectors", "Suspends", "Clocks", "Vectors/Calls"}},
+					"MaxClocks",
+					"Vectors@Max",
+					"Clocks%",
+					"Calls/s",
+					"Vectors/s",
+				}),
+				nodesHeader(false),
 				NodeStatNodeName,
 				1,
-				[]int{50, views.Resize, views.Resize, views.Resize, views.Resize, views.Resize, 22},
-				lightTheme,
+				[]int{40, views.Resize, views.Resize, views.Resize, views.Resize, views.Resize, 14, 12, 12, 9},
 			),
 			// errors tab.
 			views.NewTableView(
-				[]string{"Counter", "Node", "Reason", "Severity"},
-				xtui.TableRows{{"Counter", "Node", "Reason", "Severity"}},
+				i18n.Slice([]string{"Counter", "Node", "Reason", "Severity"}),
+				xtui.TableRows{i18n.Slice([]string{"Counter", "Node", "Reason", "Severity"})},
 				ErrorStatErrorNodeName,
 				1,
 				nil,
-				lightTheme,
 			),
 			// memory tab.
 			views.NewTableView(
 				[]string{},
-				xtui.TableRows{{"Thread/ID/Name", "Current memory usage per Thread"}},
+				xtui.TableRows{i18n.Slice([]string{"Thread/ID/Name", "Current memory usage per Thread"})},
 				MemoryStatName,
 				RowsPerMemory,
 				[]int{30, views.Resize},
-				lightTheme,
 			),
 			// threads tab.
 			views.NewTableView(
 				[]string{},
-				xtui.TableRows{{"ID", "Name", "Type", "PID", "CPUID", "Core", "CPUSocket"}},
+				xtui.TableRows{i18n.Slice([]string{"ID", "Name", "Type", "PID", "CPUID", "Core", "CPUSocket", "CPU%", "Affinity", "Sched", "CtxSw(vol/invol)", "RxPackets/s", "Rx Interfaces (queues, packets/s)"})},
 				NoColumn,
 				1,
-				nil,
-				lightTheme,
+				[]int{4, 12, 8, 8, 6, 5, 10, 7, 10, 10, 17, 12, views.Resize},
+			),
+			// drops/punts tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Type", "Node", "Reason", "Count", "Count/s"}),
+				xtui.TableRows{i18n.Slice([]string{"Type", "Node", "Reason", "Count", "Count/s"})},
+				DropPuntStatReason,
+				1,
+				[]int{6, 30, views.Resize, 16, 16},
+			),
+			// tunnels tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Name", "Type", "Source", "Destination", "VNI/TEID", "RxPackets", "RxBytes", "TxPackets", "TxBytes", "RxPackets/s", "RxBytes/s", "TxPackets/s", "TxBytes/s"}),
+				xtui.TableRows{i18n.Slice([]string{"Name", "Type", "Source", "Destination", "VNI/TEID", "RxPackets", "RxPackets/s", "RxBytes/s", "TxPackets", "TxPackets/s", "TxBytes/s"})},
+				TunnelStatName,
+				1,
+				[]int{20, 7, 16, 16, 10, 14, 14, 14, 14, 14, views.Resize},
+			),
+			// sessions tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Namespace", "Protocol", "State", "Count"}),
+				xtui.TableRows{i18n.Slice([]string{"Namespace", "Protocol", "State", "Count"})},
+				SessionStatNamespace,
+				1,
+				[]int{30, 12, 20, views.Resize},
+			),
+			// features tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Interface", "Arc"}),
+				xtui.TableRows{i18n.Slice([]string{"Interface", "Arc", "Features"})},
+				FeatureStatInterface,
+				1,
+				[]int{30, 20, views.Resize},
+			),
+			// bonds tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Bond", "Member", "RxPackets", "TxPackets", "RxPackets/s", "TxPackets/s"}),
+				xtui.TableRows{i18n.Slice([]string{"Bond", "Mode/LB", "Member", "Active", "LACP Actor/Partner", "Mux State", "RxPackets", "RxPackets/s", "Rx Share", "TxPackets", "TxPackets/s", "Tx Share"})},
+				BondStatBond,
+				1,
+				[]int{18, 12, 24, 7, 36, 24, 12, 12, 9, 12, 12, views.Resize},
+			),
+			// policers tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Name", "Conform", "Exceed", "Violate", "Conform/s", "Exceed/s", "Violate/s"}),
+				xtui.TableRows{i18n.Slice([]string{"Name", "Type", "CIR/EIR", "CB/EB", "Actions (conform/exceed/violate)", "Conform", "Conform/s", "Exceed", "Exceed/s", "Violate", "Violate/s"})},
+				PolicerStatName,
+				1,
+				[]int{24, 10, 18, 18, 48, 12, 12, 12, 12, 12, views.Resize},
+			),
+			// fib tab.
+			views.NewTableView(
+				i18n.Slice([]string{"VRF", "Name", "Routes", "HostRoutes"}),
+				fibHeader(nil),
+				FibStatName,
+				1,
+				[]int{8, 30, 6, 12, 10, views.Resize},
+			),
+			// neighbors tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Interface", "IP", "MAC", "Age", "State"}),
+				xtui.TableRows{i18n.Slice([]string{"Interface", "IP", "MAC", "Age", "State"})},
+				NeighborStatIP,
+				1,
+				[]int{24, 40, 18, 10, views.Resize},
+			),
+			// srv6 tab.
+			views.NewTableView(
+				i18n.Slice([]string{"SID", "Kind", "Packets", "Bytes", "Bad", "Packets/s", "Bytes/s"}),
+				xtui.TableRows{i18n.Slice([]string{"SID", "Kind", "Behavior", "FIB", "Segment Lists", "Steering", "Packets", "Packets/s", "Bytes", "Bytes/s", "Bad"})},
+				SRv6StatSID,
+				1,
+				[]int{24, 9, 24, 5, 40, 30, 12, 12, 12, 12, views.Resize},
+			),
+			// mpls tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Label", "Table", "Packets", "Bytes", "Packets/s", "Bytes/s"}),
+				mplsHeader(nil),
+				MplsStatLabel,
+				1,
+				[]int{20, 6, 40, 16, 12, 12, 12, views.Resize},
+			),
+			// api trace tab.
+			views.NewTableView(
+				[]string{},
+				apiTraceHeader(nil),
+				APITraceStatName,
+				1,
+				[]int{8, 40, views.Resize},
+			),
+			// api clients tab.
+			views.NewTableView(
+				i18n.Slice([]string{"Name", "Transport", "PID", "FD", "Queue", "Health"}),
+				apiClientsHeader(nil, nil, 0, unitFormat{}),
+				APIClientStatName,
+				1,
+				[]int{30, 10, 10, 8, 12, views.Resize},
+			),
+			// capture tab.
+			views.NewTableView(
+				[]string{},
+				xtui.TableRows{i18n.Slice([]string{"Capture", "State", "Interface", "Packets", "File", "Status (Ctrl-T to start/stop the selected capture)"})},
+				CaptureStatKind,
+				1,
+				[]int{10, 6, 24, 12, 50, views.Resize},
+			),
+			// info tab.
+			views.NewTableView(
+				[]string{},
+				xtui.TableRows{i18n.Slice([]string{"Name", "Version", "Description"})},
+				InfoStatName,
+				1,
+				[]int{30, 30, views.Resize},
 			),
 		},
-		[]string{"Interfaces", "Nodes", "Errors", "Memory", "Threads"},
-		[]int{Interfaces, Nodes, Errors},
+		i18n.Slice(tabNames[:Diagnostics]),
+		[]int{Interfaces, Nodes, Errors, Bonds, APITrace},
 		views.NewExitView(),
 	)
+	app.gui.SetSaveTabs(APITrace)
+	app.gui.SetTraceTabs(APITrace, Capture)
+	app.gui.SetGroupTabs(Interfaces, Errors)
+	app.gui.SetDetailTabs(Interfaces)
+	app.gui.SetEventTabs(Interfaces)
+	app.gui.SetQuickFilterTabs(Interfaces)
+	app.gui.SetPinTabs(Interfaces, Nodes)
+	app.gui.SetExpressionFilter(isFilterExpression)
+	app.gui.ViewAtTab(Interfaces).(*views.TableView).SetCellStyler(interfaceCellStyler(app.utilThreshold))
+	app.gui.ViewAtTab(Errors).(*views.TableView).SetCellStyler(errorCellStyler)
 
 	return app, nil
 }
 
+// SetPollTimeout sets the timeout of a single poll of a data source,
+// slower polls are skipped (no timeout if zero).
+func (app *App) SetPollTimeout(timeout time.Duration) {
+	app.pollTimeout = timeout
+}
+
+// SetUtilThreshold sets the link utilization in percent from which
+// the interface rates are highlighted.
+func (app *App) SetUtilThreshold(percent float64) {
+	app.compact.Lock()
+	defer app.compact.Unlock()
+	app.utilThreshold = percent
+	if !app.compact.enabled {
+		app.gui.ViewAtTab(Interfaces).(*views.TableView).SetCellStyler(interfaceCellStyler(percent))
+	}
+}
+
+// SetNotifications sets the time the info notifications are shown for
+// and the number of notifications kept in the history.
+func (app *App) SetNotifications(duration time.Duration, history int) {
+	app.gui.SetNotifications(duration, history)
+}
+
+// SetRenderInterval sets the minimum interval between the renders
+// of the polled data, independent from the poll intervals.
+func (app *App) SetRenderInterval(interval time.Duration) {
+	app.gui.SetRenderInterval(interval)
+}
+
+// SetHandler sets the handler used instead of connecting to the VPP,
+// e.g. the demo handler.
+func (app *App) SetHandler(handler api.HandlerAPI) {
+	app.handler = handler
+}
+
+// SetStatsOnly connects only the stats socket of the VPP, the interface,
+// node and error counters are read directly from the stats segment and the
+// tabs requiring the binary API or the CLI are not polled.
+func (app *App) SetStatsOnly() {
+	app.statsOnly = true
+}
+
 // Init initializes app.
 func (app *App) Init(soc, rAddr string) error {
-	switch rAddr {
-	case "":
+	switch {
+	case app.handler != nil:
+		if err := app.vppProvider.ConnectHandler(app.handler); err != nil {
+			return err
+		}
+	case app.statsOnly:
+		if err := app.vppProvider.ConnectStats(soc); err != nil {
+			return err
+		}
+	case rAddr == "":
 		if err := app.vppProvider.Connect(soc); err != nil {
 			return err
 		}
@@ -218,6 +551,9 @@ func (app *App) Init(soc, rAddr string) error {
 	if err := app.gui.Init(); err != nil {
 		return err
 	}
+	app.applyLayout()
+	app.loadWatchlist()
+	app.updateDisabledTabs()
 	_, state := app.vppProvider.GetState()
 	app.gui.SetState(state)
 
@@ -229,54 +565,94 @@ func (app *App) Run() {
 	var ctx context.Context
 	ctx, app.cancel = context.WithCancel(context.Background())
 
-	currTab := func() int {
-		app.tabLock.Lock()
-		defer app.tabLock.Unlock()
-		return app.currTab
+	collectors := app.collectors()
+	for _, c := range collectors {
+		app.wg.Add(1)
+		go func(c *collector) {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runCollector(ctx, c)
+		}(c)
+	}
+
+	if app.http != nil && app.http.Addr != "" {
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runHTTP(ctx)
+		}()
+	}
+
+	if app.controlSocket != "" {
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runControl(ctx, collectors)
+		}()
+	}
+
+	if len(app.alerts) != 0 {
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runAlerts(ctx)
+		}()
+	}
+
+	if app.push != nil && app.push.URL != "" {
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runPush(ctx)
+		}()
+	}
+
+	if app.influx != nil && app.influx.URL != "" {
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runInflux(ctx)
+		}()
 	}
 
 	app.wg.Add(1)
 
 	go func() {
-		app.updateAll()
-
-		updateTicker := time.NewTicker(1 * time.Second).C
+		defer gui.RecoverPanic()
+		stateTicker := time.NewTicker(1 * time.Second).C
 		var lastState core.ConnectionState
+		var lastStateText string
 
 		for {
 			select {
-			case <-updateTicker:
-				updateGui := false
+			case <-stateTicker:
 				currState, strState := app.vppProvider.GetState()
 				if currState == core.Connected {
-					// reset cache when returned to the connected state
-					if lastState != currState {
-						app.ifCache = nil
-					}
-					app.vppLock.Lock()
-					switch currTab() {
-					case Interfaces:
-						app.updateInterfaces(ctx)
-					case Nodes:
-						app.updateNodes(ctx)
-					case Errors:
-						app.updateErrors(ctx)
-					case Memory:
-						app.updateMemory(ctx)
-					case Threads:
-						app.updateThreads(ctx)
-					}
-					app.vppLock.Unlock()
-					updateGui = true
+					strState += app.hotThread()
 				}
-				if lastState != currState {
-					lastState = currState
-					app.gui.SetState(strState)
-					updateGui = true
+				strState += app.measureStatus()
+				if lastState == currState && lastStateText == strState {
+					continue
 				}
-				if updateGui {
-					app.onDataUpdate <- struct{}{}
+				// reset cache when returned to the connected state
+				if lastState != currState && currState == core.Connected {
+					app.cache.resetAll()
+					app.updateDisabledTabs()
 				}
+				lastState = currState
+				lastStateText = strState
+				app.gui.SetState(strState)
+				app.notifyGui(ctx)
+			case <-app.refresh:
+				for _, tab := range app.visibleTabs() {
+					app.renderTab(tab)
+				}
+				app.notifyGui(ctx)
 			case <-ctx.Done():
 				app.wg.Done()
 				return
@@ -284,212 +660,824 @@ func (app *App) Run() {
 		}
 	}()
 
-	app.gui.AddOnClearCallback(func(event gui.Event) {
+	app.gui.Subscribe(gui.ClearEvent, func(event gui.Event) {
 		tab := event.Payload.(int)
 		// launch in background
 		app.wg.Add(1)
 		go func() {
-			app.vppLock.Lock()
-			defer app.vppLock.Unlock()
+			defer gui.RecoverPanic()
 			defer app.wg.Done()
 
+			tabs := []int{tab}
 			switch tab {
-			case Interfaces:
-				if err := app.vppProvider.ClearInterfaceCounters(ctx); err != nil {
-					log.Printf("error occured while clearing interface stats: %v\n", err)
-				}
-				app.ifCache = nil
+			case Interfaces, Bonds:
+				// bond members are interfaces
+				tabs = []int{Interfaces, Tunnels, Bonds}
+			case Errors:
+				tabs = append(tabs, DropsPunts)
+			}
+			// the tabs are reset before and after the clear as well,
+			// so that data of polls overlapping the clear is dropped
+			app.cache.reset(tabs...)
+			defer app.cache.reset(tabs...)
+
+			switch tab {
+			case Interfaces, Bonds:
+				app.notifyCleared(ctx, clearResult{"interface stats", app.vppProvider.ClearInterfaceCounters(ctx)})
 			case Nodes:
-				if err := app.vppProvider.ClearRuntimeCounters(ctx); err != nil {
-					log.Printf("error occured while clearing node stats: %v\n", err)
-				}
+				err := app.vppProvider.ClearRuntimeCounters(ctx)
+				app.resetBaseline()
+				app.notifyCleared(ctx, clearResult{"node stats", err})
 			case Errors:
-				if err := app.vppProvider.ClearErrorCounters(ctx); err != nil {
-					log.Printf("error occured while clearing error stats: %v\n", err)
-				}
+				app.notifyCleared(ctx, clearResult{"error stats", app.vppProvider.ClearErrorCounters(ctx, app.errorFilter())})
+			case APITrace:
+				app.notifyCleared(ctx, clearResult{"api trace", app.vppProvider.ClearAPITrace(ctx)})
 			}
 		}()
 	})
 
-	app.gui.AddOnSortCallback(func(event gui.Event) {
+	app.gui.Subscribe(gui.MeasureEvent, func(_ gui.Event) {
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.runMeasurement(ctx, collectors)
+		}()
+	})
+
+	app.gui.Subscribe(gui.RefreshEvent, func(event gui.Event) {
+		triggerCollector(collectors, event.Payload.(int))
+	})
+
+	app.gui.Subscribe(gui.SortEvent, func(event gui.Event) {
 		payload := event.Payload.(gui.SortMetadata)
 
 		app.wg.Add(1)
 		go func() {
+			defer gui.RecoverPanic()
 			defer app.wg.Done()
 
 			app.sortLock.Lock()
-			defer app.sortLock.Unlock()
-
 			switch payload.CurrTab {
 			case Interfaces:
 				app.sortBy[Interfaces].field = payload.CurrRow
-				app.sortBy[Interfaces].asc = !app.sortBy[Interfaces].asc
+				app.sortBy[Interfaces].asc = payload.Asc
 			case Nodes:
 				app.sortBy[Nodes].field = payload.CurrRow
-				app.sortBy[Nodes].asc = !app.sortBy[Nodes].asc
+				app.sortBy[Nodes].asc = payload.Asc
 			case Errors:
 				app.sortBy[Errors].field = payload.CurrRow
-				app.sortBy[Errors].asc = !app.sortBy[Errors].asc
+				app.sortBy[Errors].asc = payload.Asc
+			case DropsPunts:
+				app.sortBy[DropsPunts].field = payload.CurrRow
+				app.sortBy[DropsPunts].asc = payload.Asc
+			case Tunnels:
+				app.sortBy[Tunnels].field = payload.CurrRow
+				app.sortBy[Tunnels].asc = payload.Asc
+			case Sessions:
+				app.sortBy[Sessions].field = payload.CurrRow
+				app.sortBy[Sessions].asc = payload.Asc
+			case Features:
+				app.sortBy[Features].field = payload.CurrRow
+				app.sortBy[Features].asc = payload.Asc
+			case Bonds:
+				app.sortBy[Bonds].field = payload.CurrRow
+				app.sortBy[Bonds].asc = payload.Asc
+			case Policers:
+				app.sortBy[Policers].field = payload.CurrRow
+				app.sortBy[Policers].asc = payload.Asc
+			case Fib:
+				app.sortBy[Fib].field = payload.CurrRow
+				app.sortBy[Fib].asc = payload.Asc
+			case Neighbors:
+				app.sortBy[Neighbors].field = payload.CurrRow
+				app.sortBy[Neighbors].asc = payload.Asc
+			case SRv6:
+				app.sortBy[SRv6].field = payload.CurrRow
+				app.sortBy[SRv6].asc = payload.Asc
+			case Mpls:
+				app.sortBy[Mpls].field = payload.CurrRow
+				app.sortBy[Mpls].asc = payload.Asc
+			case APIClients:
+				app.sortBy[APIClients].field = payload.CurrRow
+				app.sortBy[APIClients].asc = payload.Asc
+			}
+			s := app.sortBy[payload.CurrTab]
+			app.sortLock.Unlock()
+			app.gui.ViewAtTab(payload.CurrTab).(*views.TableView).SetSort(s.field, s.asc)
+
+			app.renderTab(payload.CurrTab)
+			app.notifyGui(ctx)
+		}()
+	})
+
+	app.gui.Subscribe(gui.UnitsEvent, func(event gui.Event) {
+		app.unitsLock.Lock()
+		app.units.human = event.Payload.(bool)
+		app.unitsLock.Unlock()
+
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+			app.renderTab(Interfaces)
+			app.renderTab(Tunnels)
+			app.notifyGui(ctx)
+		}()
+	})
+
+	app.gui.Subscribe(gui.TraceEvent, func(event gui.Event) {
+		if event.Payload.(int) == Capture {
+			kind := api.CaptureKind(app.gui.ViewAtTab(Capture).(*views.TableView).SelectedKey())
+			if kind == "" {
+				return
+			}
+			app.wg.Add(1)
+			go func() {
+				defer gui.RecoverPanic()
+				defer app.wg.Done()
+				if err := app.toggleCapture(ctx, kind); err != nil {
+					logrus.Errorf("error occured while toggling %s capture: %v", kind, err)
+					app.notify(ctx, gui.SeverityError, i18n.T("toggling %s capture failed: %v", kind, err))
+				}
+				triggerCollector(collectors, Capture)
+			}()
+			return
+		}
+
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+
+			// the state is dumped by the toggle, the cached one may be stale
+			if _, err := app.vppProvider.ToggleAPITrace(ctx); err != nil {
+				logrus.Errorf("error occured while toggling api trace: %v", err)
+				app.notify(ctx, gui.SeverityError, i18n.T("toggling api trace failed: %v", err))
+			}
+			triggerCollector(collectors, APITrace)
+		}()
+	})
+
+	app.gui.Subscribe(gui.SaveEvent, func(event gui.Event) {
+		if event.Payload.(int) != APITrace {
+			return
+		}
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+
+			file := fmt.Sprintf("vpptop-%s.api", time.Now().Format("20060102-150405"))
+			if err := app.vppProvider.SaveAPITrace(ctx, file); err != nil {
+				logrus.Errorf("error occured while saving api trace: %v", err)
+				app.notify(ctx, gui.SeverityError, i18n.T("saving api trace failed: %v", err))
+				return
 			}
+			logrus.Infof("api trace saved to %s", file)
+			app.notify(ctx, gui.SeverityInfo, i18n.T("api trace saved to %s", file))
 		}()
 	})
 
-	app.gui.AddOnExitCallback(func(_ gui.Event) {
+	app.gui.Subscribe(gui.ExitEvent, func(_ gui.Event) {
 		app.cancel()
 		app.wg.Wait()
 		app.gui.Destroy()
 		app.vppProvider.Disconnect()
 	})
 
-	app.gui.AddOnTabSwitchCallback(func(event gui.Event) {
-		app.tabLock.Lock()
-		defer app.tabLock.Unlock()
-		app.currTab = event.Payload.(int)
+	app.gui.Subscribe(gui.GroupEvent, func(event gui.Event) {
+		tab := event.Payload.(int)
+		switch tab {
+		case Interfaces:
+			app.groups.toggle()
+		case Errors:
+			var errors []api.Error
+			if entry, ok := app.viewEntry(Errors); ok {
+				errors = entry.data.([]api.Error)
+			}
+			app.errorGroups.cycle(hasPerThread(errors))
+		default:
+			return
+		}
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(tab)
+			app.notifyGui(ctx)
+		}()
 	})
 
-	app.gui.Start()
-}
-
-func (app *App) updateInterfaces(ctx context.Context) {
-	ifaces, err := app.vppProvider.GetInterfaces(ctx)
-	if err != nil {
-		log.Printf("error occured while polling interface stats: %v\n", err)
-	}
-
-	app.sortLock.Lock()
-	s := app.sortBy[Interfaces]
-	app.sortLock.Unlock()
+	app.gui.Subscribe(gui.HideZeroEvent, func(event gui.Event) {
+		if event.Payload.(int) != Nodes {
+			return
+		}
+		app.filterLock.Lock()
+		app.hideZeroNodes = !app.hideZeroNodes
+		app.filterLock.Unlock()
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(Nodes)
+			app.notifyGui(ctx)
+		}()
+	})
 
-	app.sortInterfaceStats(ifaces, s.field, s.asc)
-	app.gui.ViewAtTab(Interfaces).Update(app.formatInterfaces(ifaces))
-}
+	app.gui.Subscribe(gui.BaselineEvent, func(event gui.Event) {
+		if event.Payload.(int) != Nodes {
+			return
+		}
+		go func() {
+			defer gui.RecoverPanic()
+			app.toggleBaseline()
+			app.renderTab(Nodes)
+			app.notifyGui(ctx)
+		}()
+	})
 
-func (app *App) updateNodes(ctx context.Context) {
-	nodes, err := app.vppProvider.GetNodes(ctx)
-	if err != nil {
-		log.Printf("error occured while polling nodes stats: %v\n", err)
-	}
+	app.gui.Subscribe(gui.LayoutEvent, app.saveLayout)
 
-	app.sortLock.Lock()
-	s := app.sortBy[Nodes]
-	app.sortLock.Unlock()
+	app.gui.Subscribe(gui.DetailEvent, func(event gui.Event) {
+		if event.Payload.(int) != Interfaces {
+			return
+		}
+		key := app.gui.ViewAtTab(Interfaces).(*views.TableView).SelectedKey()
+		if key != "" {
+			app.showInterfaceDetails(key)
+		}
+	})
 
-	app.sortNodeStats(nodes, s.field, s.asc)
-	app.gui.ViewAtTab(Nodes).Update(app.formatNodes(nodes))
-}
+	app.gui.Subscribe(gui.EventLogEvent, func(event gui.Event) {
+		if event.Payload.(int) != Interfaces {
+			return
+		}
+		app.gui.ShowPopup(i18n.T("Interface events (Esc to close)"), app.events.rows())
+	})
 
-func (app *App) updateErrors(ctx context.Context) {
-	errors, err := app.vppProvider.GetErrors(ctx)
-	if err != nil {
-		log.Printf("error occured while polling errors stats: %v\n", err)
-	}
+	app.gui.Subscribe(gui.QuickFilterEvent, func(event gui.Event) {
+		if event.Payload.(int) != Interfaces {
+			return
+		}
+		entry, ok := app.viewEntry(Interfaces)
+		if !ok {
+			return
+		}
+		app.filterLock.Lock()
+		current := app.filterTexts[Interfaces]
+		app.filterLock.Unlock()
+		app.gui.SetFilter(nextVrfFilter(entry.data.([]api.Interface), current))
+	})
 
-	app.sortLock.Lock()
-	s := app.sortBy[Errors]
-	app.sortLock.Unlock()
+	app.gui.Subscribe(gui.PinEvent, func(event gui.Event) {
+		tab := event.Payload.(int)
+		key := app.gui.ViewAtTab(tab).(*views.TableView).SelectedKey()
+		if key == "" {
+			return
+		}
+		if err := app.watchlist.toggle(tab, key); err != nil {
+			logrus.Warnf("error occured while saving watchlist %s: %v", app.watchlist.path, err)
+		}
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(tab)
+			app.notifyGui(ctx)
+		}()
+	})
 
-	app.sortErrorStats(errors, s.field, s.asc)
-	app.gui.ViewAtTab(Errors).Update(app.formatErrors(errors))
-}
+	app.gui.Subscribe(gui.SelectEvent, func(event gui.Event) {
+		tab := event.Payload.(int)
+		var groups *tableGroups
+		switch tab {
+		case Interfaces:
+			groups = app.groups
+		case Errors:
+			groups = app.errorGroups
+		default:
+			return
+		}
+		if !groups.isEnabled() {
+			return
+		}
+		name := app.gui.ViewAtTab(tab).(*views.TableView).SelectedKey()
+		if name == "" {
+			return
+		}
+		groups.toggleExpanded(name)
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(tab)
+			app.notifyGui(ctx)
+		}()
+	})
 
-func (app *App) updateMemory(ctx context.Context) {
-	memStats, err := app.vppProvider.GetMemory(ctx)
-	if err != nil {
-		log.Printf("error occured while polling memory stats: %v\n", err)
-	}
+	app.gui.Subscribe(gui.FilterEvent, func(event gui.Event) {
+		payload := event.Payload.(gui.FilterMetadata)
+		// filters which are not expressions are applied
+		// on the table rows by the gui.
+		expr, _ := parseFilter(payload.CurrTab, payload.Filter)
 
-	app.gui.ViewAtTab(Memory).Update(app.formatMemstats(memStats))
-}
+		app.filterLock.Lock()
+		app.filters[payload.CurrTab] = expr
+		app.filterTexts[payload.CurrTab] = payload.Filter
+		app.filterLock.Unlock()
 
-func (app *App) updateThreads(ctx context.Context) {
-	threads, err := app.vppProvider.GetThreads(ctx)
-	if err != nil {
-		log.Printf("error occured while polling threads stats: %v\n", err)
-	}
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(payload.CurrTab)
+			app.notifyGui(ctx)
+		}()
+	})
 
-	app.gui.ViewAtTab(Threads).Update(app.formatThreads(threads))
-}
+	app.gui.Subscribe(gui.TabSwitchEvent, func(event gui.Event) {
+		tab := event.Payload.(int)
+		app.tabLock.Lock()
+		app.currTab = tab
+		app.tabLock.Unlock()
+		// the gui is re-rendered after the tab switch, so the cached
+		// data only has to be pushed to the view.
+		app.renderTab(tab)
+		triggerShown(collectors, tab)
+	})
 
-func (app *App) updateAll() {
-	ctx := context.Background()
-	app.updateInterfaces(ctx)
-	app.updateNodes(ctx)
-	app.updateErrors(ctx)
-	app.updateMemory(ctx)
-	app.updateThreads(ctx)
-}
+	app.gui.Subscribe(gui.SplitEvent, func(event gui.Event) {
+		payload := event.Payload.(gui.SplitMetadata)
+		app.tabLock.Lock()
+		app.splitTab = -1
+		if payload.Enabled {
+			app.splitTab = payload.OtherTab
+		}
+		app.tabLock.Unlock()
+		if payload.Enabled {
+			app.renderTab(payload.OtherTab)
+			triggerShown(collectors, payload.OtherTab)
+		}
+	})
 
-// formatInterfaces formats interface stats to xtui.TableRows
-func (app *App) formatInterfaces(ifaces []api.Interface) xtui.TableRows {
-	nameToIdx := make(map[string]int)
+	app.gui.Subscribe(gui.PauseEvent, app.setPaused)
 
-	for i, iface := range app.ifCache {
-		nameToIdx[iface.InterfaceName] = i
-	}
+	app.gui.Subscribe(gui.AutoRefreshEvent, func(event gui.Event) {
+		payload := event.Payload.(gui.AutoRefreshMetadata)
+		app.manual.set(payload.Tab, !payload.Enabled)
+		if payload.Enabled {
+			triggerCollector(collectors, payload.Tab)
+		}
+		app.refreshTab(payload.Tab)
+	})
 
-	rows := make(xtui.TableRows, RowsPerIface*len(ifaces))
-	for i, iface := range ifaces {
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], iface.InterfaceName)
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.InterfaceIndex))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], iface.State)
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprintf("%d/%d/%d/%d", iface.MTU[0], iface.MTU[1], iface.MTU[2], iface.MTU[3]))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], "Packets")
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.Rx.Packets))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], "Packets")
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.Tx.Packets))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.Drops))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.Punts))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.IP4))
-		rows[RowsPerIface*i] = append(rows[RowsPerIface*i], fmt.Sprint(iface.IP6))
-
-		rxbbs := uint64(0) //rx bytes/s
-		txbbs := uint64(0) //tx bytes/s
-		rxpps := uint64(0) //rx packets/s
-		txpps := uint64(0) //tx packets/s
-
-		if idx, ok := nameToIdx[iface.InterfaceName]; ok {
-			// Calculate bytes/s, packets/s
-			rxbbs = iface.Rx.Bytes - app.ifCache[idx].Rx.Bytes
-			txbbs = iface.Tx.Bytes - app.ifCache[idx].Tx.Bytes
-
-			rxpps = iface.Rx.Packets - app.ifCache[idx].Rx.Packets
-			txpps = iface.Tx.Packets - app.ifCache[idx].Tx.Packets
-		}
-
-		rows[RowsPerIface*i+1] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Packets/s", fmt.Sprint(rxpps), "Packets/s", fmt.Sprint(txpps), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+2] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Bytes", fmt.Sprint(iface.Rx.Bytes), "Bytes", fmt.Sprint(iface.Tx.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+3] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Bytes/s", fmt.Sprint(rxbbs), "Bytes/s", fmt.Sprint(txbbs), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+4] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Errors", fmt.Sprint(iface.RxErrors), "Errors", fmt.Sprint(iface.TxErrors), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+5] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Unicast", fmt.Sprintf("%d/%d", iface.RxUnicast.Packets, iface.RxUnicast.Bytes), "UnicastMiss", fmt.Sprintf("%d/%d", iface.TxUnicast.Packets, iface.TxUnicast.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+6] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Multicast", fmt.Sprintf("%d/%d", iface.RxMulticast.Packets, iface.RxMulticast.Bytes), "Multicast", fmt.Sprintf("%d/%d", iface.TxMulticast.Packets, iface.TxMulticast.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+7] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Broadcast", fmt.Sprintf("%d/%d", iface.RxBroadcast.Packets, iface.RxBroadcast.Bytes), "Broadcast", fmt.Sprintf("%d/%d", iface.TxBroadcast.Packets, iface.TxBroadcast.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+8] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "NoBuf", fmt.Sprint(iface.RxNoBuf), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+9] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Miss", fmt.Sprint(iface.RxMiss), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-		rows[RowsPerIface*i+10] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
-
-		// the first row is occupied by the interface name
-		availRows := RowsPerIface - 1
-		for j := 0; j < len(iface.IPAddresses); j++ {
-			if j >= availRows {
-				// no more space
-				break
-			}
-			rows[RowsPerIface*i+j+1][0] = iface.IPAddresses[j]
+	app.gui.Subscribe(gui.SpikeEvent, func(event gui.Event) {
+		if app.spikes.toggle() {
+			app.gui.Notify(gui.SeverityInfo, i18n.T("spike highlighting: on (%g σ)", app.spikes.threshold()))
+		} else {
+			app.gui.Notify(gui.SeverityInfo, i18n.T("spike highlighting: off"))
 		}
-	}
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(Interfaces)
+			app.notifyGui(ctx)
+		}()
+	})
 
-	app.ifCache = ifaces
+	app.gui.Subscribe(gui.CompactEvent, func(event gui.Event) {
+		enabled := !app.compact.isEnabled()
+		app.SetCompactInterfaces(enabled)
+		if enabled {
+			app.gui.Notify(gui.SeverityInfo, i18n.T("compact interfaces: on"))
+		} else {
+			app.gui.Notify(gui.SeverityInfo, i18n.T("compact interfaces: off"))
+		}
+		go func() {
+			defer gui.RecoverPanic()
+			app.renderTab(Interfaces)
+			app.notifyGui(ctx)
+		}()
+	})
+
+	app.gui.Subscribe(gui.ExportEvent, func(event gui.Event) {
+		tab := event.Payload.(int)
+		app.wg.Add(1)
+		go func() {
+			defer gui.RecoverPanic()
+			defer app.wg.Done()
+
+			file, err := app.exportTab(tab)
+			if err != nil {
+				logrus.Errorf("error occured while exporting %s tab: %v", tabNames[tab], err)
+				app.notify(ctx, gui.SeverityError, i18n.T("exporting tab %s failed: %v", i18n.T(tabNames[tab]), err))
+				return
+			}
+			logrus.Infof("%s tab exported to %s", tabNames[tab], file)
+			app.notify(ctx, gui.SeverityInfo, i18n.T("tab %s exported to %s", i18n.T(tabNames[tab]), file))
+		}()
+	})
+
+	app.gui.Start()
+}
+
+// visibleTabs returns the tabs shown by the gui, the current tab
+// and the other tab of the split view.
+func (app *App) visibleTabs() []int {
+	app.tabLock.Lock()
+	defer app.tabLock.Unlock()
+	if app.splitTab < 0 {
+		return []int{app.currTab}
+	}
+	return []int{app.currTab, app.splitTab}
+}
+
+// isVisible returns true if the tab is shown by the gui.
+func (app *App) isVisible(tab int) bool {
+	for _, visible := range app.visibleTabs() {
+		if visible == tab {
+			return true
+		}
+	}
+	return false
+}
+
+// unitFormat returns the units used to format the interface counters.
+func (app *App) unitFormat() unitFormat {
+	app.unitsLock.Lock()
+	defer app.unitsLock.Unlock()
+	return app.units
+}
+
+// notifyGui notifies the gui about the content change.
+func (app *App) notifyGui(ctx context.Context) {
+	select {
+	case app.onDataUpdate <- struct{}{}:
+	case <-ctx.Done():
+	}
+}
+
+// notify shows the notification and notifies the gui to render it.
+func (app *App) notify(ctx context.Context, severity gui.Severity, text string) {
+	app.gui.Notify(severity, text)
+	app.notifyGui(ctx)
+}
+
+// renderTab formats the cached data for the tab and
+// updates the associated view.
+func (app *App) renderTab(tab int) {
+	if view, ok := app.gui.ViewAtTab(tab).(*views.TableView); ok {
+		view.SetBanner(app.pollBanner(tab))
+	}
+	entry, ok := app.viewEntry(tab)
+	if !ok {
+		return
+	}
+
+	app.sortLock.Lock()
+	s := app.sortBy[tab]
+	app.sortLock.Unlock()
+
+	switch tab {
+	case Interfaces:
+		ifaces := app.filterStats(tab, entry.data, entry.rates).([]api.Interface)
+		prev, _ := entry.prev.([]api.Interface)
+		view := app.gui.ViewAtTab(Interfaces).(*views.TableView)
+		view.SetTicker(app.events.ticker())
+		app.refreshCompactHeader(view)
+		if app.groups.isEnabled() {
+			view.SetPinned(0)
+			view.UpdateSource(app.newGroupedInterfaceRows(ifaces, prev, entry.elapsed, s.field, s.asc))
+			break
+		}
+		app.sortInterfaceStats(ifaces, entry.rates, s.field, s.asc)
+		ifaces, pinned := pinInterfaces(app.watchlist.pinned(tab), entry.data.([]api.Interface), ifaces)
+		view.SetPinned(pinned)
+		rows := app.newInterfaceRows(ifaces, entry.rates)
+		rows.deviations, rows.spikeSigma = entry.deviations, app.spikes.threshold()
+		view.UpdateSource(rows)
+	case Nodes:
+		nodes := app.filterStats(tab, entry.data, entry.rates).([]api.Node)
+		if app.isHidingZeroNodes() {
+			nodes = withoutZeroNodes(nodes)
+		}
+		app.sortNodeStats(nodes, entry.rates, s.field, s.asc)
+		nodes, pinned := pinNodes(app.watchlist.pinned(tab), entry.data.([]api.Node), nodes)
+		view := app.gui.ViewAtTab(Nodes).(*views.TableView)
+		view.SetPinned(pinned)
+		view.Update(app.withBaseline(app.formatNodes(nodes), nodes))
+	case Errors:
+		errors := app.filterStats(tab, entry.data, entry.rates).([]api.Error)
+		prev, _ := entry.prev.([]api.Error)
+		view := app.gui.ViewAtTab(Errors).(*views.TableView)
+		severity := app.capabilities().ErrorSeverity
+		if !severity {
+			errors = withoutSeverity(errors)
+		}
+		view.SetHeader(errorsHeader(entry.data.([]api.Error), prev, entry.elapsed, app.unitFormat(), severity))
+		if app.errorGroups.isByThread() {
+			var threads []api.ThreadData
+			if threadEntry, ok := app.viewEntry(Threads); ok {
+				threads = threadEntry.data.([]api.ThreadData)
+			}
+			view.UpdateSource(app.newThreadErrorRows(errors, threads, s.field, s.asc))
+			break
+		}
+		if app.errorGroups.isEnabled() {
+			view.UpdateSource(app.newGroupedErrorRows(errors, s.field, s.asc))
+			break
+		}
+		app.sortErrorStats(errors, s.field, s.asc)
+		view.Update(app.formatErrors(errors))
+	case Memory:
+		app.gui.ViewAtTab(Memory).Update(app.formatMemstats(entry.data.([]string)))
+	case Threads:
+		prev, _ := entry.prev.([]api.ThreadData)
+		app.gui.ViewAtTab(Threads).Update(app.formatThreads(entry.data.([]api.ThreadData), prev, entry.elapsed))
+	case DropsPunts:
+		dropsPunts := app.filterStats(tab, entry.data, entry.rates).([]api.DropPunt)
+		app.sortDropPuntStats(dropsPunts, entry.rates, s.field, s.asc)
+		app.gui.ViewAtTab(DropsPunts).Update(app.formatDropsPunts(dropsPunts, entry.rates))
+	case Tunnels:
+		tunnels := app.filterStats(tab, entry.data, entry.rates).([]api.TunnelCounters)
+		app.sortTunnelStats(tunnels, entry.rates, s.field, s.asc)
+		app.gui.ViewAtTab(Tunnels).Update(app.formatTunnels(tunnels, entry.rates))
+	case Sessions:
+		sessions := app.filterStats(tab, entry.data, entry.rates).([]api.SessionStat)
+		app.sortSessionStats(sessions, s.field, s.asc)
+		app.gui.ViewAtTab(Sessions).Update(app.formatSessions(sessions))
+	case Features:
+		features := app.filterStats(tab, entry.data, entry.rates).([]api.FeatureArc)
+		app.sortFeatureArcs(features, s.field, s.asc)
+		app.gui.ViewAtTab(Features).Update(app.formatFeatures(features))
+	case Bonds:
+		members := app.filterStats(tab, entry.data, entry.rates).([]api.BondMember)
+		app.sortBondMembers(members, entry.rates, s.field, s.asc)
+		app.gui.ViewAtTab(Bonds).Update(app.formatBonds(members, entry.data.([]api.BondMember), entry.rates))
+	case Policers:
+		policers := app.filterStats(tab, entry.data, entry.rates).([]api.Policer)
+		app.sortPolicers(policers, entry.rates, s.field, s.asc)
+		app.gui.ViewAtTab(Policers).Update(app.formatPolicers(policers, entry.rates))
+	case Fib:
+		summary := entry.data.(*api.FibSummary)
+		tables := app.filterStats(tab, summary.Tables, nil).([]api.FibTable)
+		var prev []api.FibTable
+		if prevSummary, ok := entry.prev.(*api.FibSummary); ok {
+			prev = prevSummary.Tables
+		}
+		app.sortFibTables(tables, s.field, s.asc)
+		view := app.gui.ViewAtTab(Fib).(*views.TableView)
+		view.SetHeader(fibHeader(summary))
+		view.Update(app.formatFib(tables, prev))
+	case Neighbors:
+		neighbors := app.filterStats(tab, entry.data, entry.rates).([]api.Neighbor)
+		app.sortNeighbors(neighbors, s.field, s.asc)
+		app.gui.ViewAtTab(Neighbors).Update(app.formatNeighbors(neighbors))
+	case SRv6:
+		sids := app.filterStats(tab, entry.data, entry.rates).([]api.SRv6SID)
+		app.sortSRv6(sids, entry.rates, s.field, s.asc)
+		app.gui.ViewAtTab(SRv6).Update(app.formatSRv6(sids, entry.rates))
+	case Mpls:
+		entries := app.filterStats(tab, entry.data, entry.rates).([]api.MplsEntry)
+		app.sortMpls(entries, entry.rates, s.field, s.asc)
+		view := app.gui.ViewAtTab(Mpls).(*views.TableView)
+		view.SetHeader(mplsHeader(entry.data.([]api.MplsEntry)))
+		view.Update(app.formatMpls(entries, entry.rates))
+	case APITrace:
+		trace := entry.data.(*api.APITrace)
+		view := app.gui.ViewAtTab(APITrace).(*views.TableView)
+		view.SetHeader(apiTraceHeader(trace))
+		view.Update(app.formatAPITrace(trace))
+	case APIClients:
+		summary := entry.data.(*api.APIClients)
+		clients := app.filterStats(tab, summary.Clients, nil).([]api.APIClient)
+		var prev []api.APIRing
+		if prevSummary, ok := entry.prev.(*api.APIClients); ok {
+			prev = prevSummary.Rings
+		}
+		app.sortAPIClients(clients, s.field, s.asc)
+		view := app.gui.ViewAtTab(APIClients).(*views.TableView)
+		view.SetHeader(apiClientsHeader(summary, prev, entry.elapsed, app.unitFormat()))
+		view.Update(app.formatAPIClients(clients))
+	case Capture:
+		app.gui.ViewAtTab(Capture).Update(app.formatCaptures(entry.data.([]api.PacketCapture)))
+	case Info:
+		app.gui.ViewAtTab(Info).Update(app.formatInfo(entry.data.(*api.VPPInfo)))
+	case Diagnostics:
+		app.gui.ViewAtTab(Diagnostics).Update(app.formatDiagnostics(entry.data.(*selfStats)))
+	}
+}
+
+// interfaceKey returns the key of the interface unique among the
+// interfaces of all VPP instances.
+func interfaceKey(iface api.Interface) string {
+	return instanceKey(iface.Instance, iface.InterfaceName)
+}
+
+// instanceKey returns the name of the interface prefixed by its instance
+// (if the interfaces of multiple instances are merged).
+func instanceKey(instance, name string) string {
+	if instance == "" {
+		return name
+	}
+	return instance + "/" + name
+}
+
+// perSecond returns the difference of two counters per second.
+func perSecond(curr, prev uint64, elapsed time.Duration) uint64 {
+	if curr < prev || elapsed <= 0 {
+		return 0
+	}
+	return uint64(float64(curr-prev) / elapsed.Seconds())
+}
+
+// interfaceRows provides the rows of the interface stats lazily, so that
+// only the rows of the visible interfaces are formatted.
+type interfaceRows struct {
+	ifaces []api.Interface
+	rates  *statsRates
+	units  unitFormat
+	// last link state changes by the interface keys
+	links map[string]linkChange
+	now   time.Time
+	// labels (optional) replace the names of the interfaces shown in the table.
+	labels []string
+	// deviations of the rates from their moving averages, the rates deviating
+	// by more than spikeSigma are highlighted (not highlighted if zero).
+	deviations *statsRates
+	spikeSigma float64
+	// compact is set if a single row is shown per interface.
+	compact bool
+	// workerStats is set if the counters per worker thread are available.
+	workerStats bool
+}
+
+// newInterfaceRows returns the interface rows showing the rates of the interfaces.
+func (app *App) newInterfaceRows(ifaces []api.Interface, rates *statsRates) *interfaceRows {
+	return &interfaceRows{
+		ifaces:      ifaces,
+		rates:       rates,
+		units:       app.unitFormat(),
+		links:       app.events.linkChanges(),
+		now:         time.Now(),
+		compact:     app.compact.isEnabled(),
+		workerStats: app.capabilities().WorkerStats,
+	}
+}
+
+// Len returns the number of interfaces.
+func (r *interfaceRows) Len() int { return len(r.ifaces) }
+
+// FilterValue returns the name of the interface prefixed by its instance.
+func (r *interfaceRows) FilterValue(entry int) string { return interfaceKey(r.ifaces[entry]) }
+
+// EntryRows formats the interface stats to xtui.TableRows.
+func (r *interfaceRows) EntryRows(entry int) xtui.TableRows {
+	if r.compact {
+		return r.compactRow(entry)
+	}
+	iface, units := r.ifaces[entry], r.units
+	name := iface.InterfaceName
+	if r.labels != nil {
+		name = r.labels[entry]
+	}
+	rows := make(xtui.TableRows, RowsPerIface)
+	rows[0] = []string{
+		name,
+		fmt.Sprint(iface.InterfaceIndex),
+		stateOf(iface, iface.State),
+		stateOf(iface, iface.LinkState),
+		formatVrf(iface),
+		fmt.Sprintf("%d/%d/%d/%d", iface.MTU[0], iface.MTU[1], iface.MTU[2], iface.MTU[3]),
+		"Packets",
+		units.count(iface.Rx.Packets),
+		"Packets",
+		units.count(iface.Tx.Packets),
+		units.count(iface.Drops),
+		units.count(iface.Punts),
+		units.count(iface.IP4),
+		units.count(iface.IP6),
+	}
+
+	rxbbs := r.rates.count(iface, ifaceRxByteRate)   //rx bytes/s
+	txbbs := r.rates.count(iface, ifaceTxByteRate)   //tx bytes/s
+	rxpps := r.rates.count(iface, ifaceRxPacketRate) //rx packets/s
+	txpps := r.rates.count(iface, ifaceTxPacketRate) //tx packets/s
+
+	rows[1] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Packets/s", units.count(rxpps), "Packets/s", units.count(txpps), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[2] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Bytes", units.bytes(iface.Rx.Bytes), "Bytes", units.bytes(iface.Tx.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[3] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, units.byteRateLabel(), units.byteRate(rxbbs), units.byteRateLabel(), units.byteRate(txbbs), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[4] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Errors", units.count(iface.RxErrors), "Errors", units.count(iface.TxErrors), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[5] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Unicast", units.count(iface.RxUnicast.Packets) + "/" + units.bytes(iface.RxUnicast.Bytes), "UnicastMiss", units.count(iface.TxUnicast.Packets) + "/" + units.bytes(iface.TxUnicast.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[6] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Multicast", units.count(iface.RxMulticast.Packets) + "/" + units.bytes(iface.RxMulticast.Bytes), "Multicast", units.count(iface.TxMulticast.Packets) + "/" + units.bytes(iface.TxMulticast.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[7] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Broadcast", units.count(iface.RxBroadcast.Packets) + "/" + units.bytes(iface.RxBroadcast.Bytes), "Broadcast", units.count(iface.TxBroadcast.Packets) + "/" + units.bytes(iface.TxBroadcast.Bytes), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[8] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "NoBuf", units.count(iface.RxNoBuf), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[9] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Miss", units.count(iface.RxMiss), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[10] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Packets/thr", formatWorkers(iface.Workers, func(w api.WorkerCounters) uint64 { return w.Rx.Packets }, units.count), "Packets/thr", formatWorkers(iface.Workers, func(w api.WorkerCounters) uint64 { return w.Tx.Packets }, units.count), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[11] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "NoBuf/thr", formatWorkers(iface.Workers, func(w api.WorkerCounters) uint64 { return w.RxNoBuf }, units.count), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[12] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Miss/thr", formatWorkers(iface.Workers, func(w api.WorkerCounters) uint64 { return w.RxMiss }, units.count), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[13] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, "Util", formatLinkUtilization(rxbbs, iface.Device.LinkSpeed), "Util", formatLinkUtilization(txbbs, iface.Device.LinkSpeed), xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+	rows[14] = []string{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell}
+
+	// the worker thread counters are not available without the stats segment
+	if !r.workerStats {
+		rows[10][7], rows[10][9] = notAvailable, notAvailable
+		rows[11][7], rows[12][7] = notAvailable, notAvailable
+	}
+
+	// the time since the last link state change is shown below the link state
+	if change, ok := r.links[interfaceKey(iface)]; ok && iface.LinkState != "" {
+		rows[1][3] = formatLinkChange(change, r.now)
+	}
+
+	// the device details are shown below the MTU, if known
+	device := []string{iface.Device.Type, iface.Device.MAC, formatLinkSpeed(iface.Device.LinkSpeed, iface.Device.LinkDuplex)}
+	for j, row := 0, 1; j < len(device); j++ {
+		if device[j] != "" {
+			rows[row][5] = device[j]
+			row++
+		}
+	}
+
+	// the first row is occupied by the interface name
+	availRows := RowsPerIface - 1
+	for j := 0; j < len(iface.IPAddresses); j++ {
+		if j >= availRows {
+			// no more space
+			break
+		}
+		rows[j+1][0] = iface.IPAddresses[j]
+	}
+
+	// the missing data is noted below the addresses
+	if note := missingData(iface); note != "" && len(iface.IPAddresses) < availRows {
+		rows[len(iface.IPAddresses)+1][0] = note
+	}
+
+	// the instance is shown in the last column of the first row
+	for j := range rows {
+		instance := xtui.EmptyCell
+		if j == 0 {
+			instance = iface.Instance
+		}
+		rows[j] = append(rows[j], instance)
+	}
 
 	return rows
 }
 
+// unknownState is shown as the state of interfaces not dumped by the binary API.
+const unknownState = "?"
+
+// stateOf returns the state of the interface, or unknownState if the interface
+// was not dumped by the binary API.
+func stateOf(iface api.Interface, state string) string {
+	if iface.NoDetails {
+		return unknownState
+	}
+	return state
+}
+
+// missingData returns the note about the data missing for the interface,
+// empty if both the counters and the details are known.
+func missingData(iface api.Interface) string {
+	switch {
+	case iface.NoStats:
+		return i18n.T("(no stats)")
+	case iface.NoDetails:
+		return i18n.T("(no details)")
+	}
+	return ""
+}
+
+// formatVrf formats the VRF of the interface, the IPv6 VRF
+// is shown only if it differs from the IPv4 one.
+func formatVrf(iface api.Interface) string {
+	if iface.VrfIPv4 == iface.VrfIPv6 {
+		return fmt.Sprint(iface.VrfIPv4)
+	}
+	return fmt.Sprintf("%d/%d", iface.VrfIPv4, iface.VrfIPv6)
+}
+
+// formatWorkers formats the value of the interface at each worker thread separated by '/'.
+func formatWorkers(workers []api.WorkerCounters, value func(api.WorkerCounters) uint64, format func(uint64) string) string {
+	if len(workers) == 0 {
+		return "-"
+	}
+	values := make([]string, len(workers))
+	for i, worker := range workers {
+		values[i] = format(value(worker))
+	}
+	return strings.Join(values, "/")
+}
+
 // formatNodes formats nodes stats to xtui.TableRows
 func (app *App) formatNodes(nodes []api.Node) xtui.TableRows {
 	rows := make(xtui.TableRows, len(nodes))
 
 	for i, node := range nodes {
-		rows[i] = strings.Split(fmt.Sprintf("%s %s %d %d %d %d %.2f", node.Name, node.State, node.Calls, node.Vectors, node.Suspends, uint64(node.Clocks), node.VectorsPerCall), " ")
+		rows[i] = []string{
+			node.Name,
+			node.State,
+			fmt.Sprint(node.Calls),
+			fmt.Sprint(node.Vectors),
+			fmt.Sprint(node.Suspends),
+			fmt.Sprint(uint64(node.Clocks)),
+			fmt.Sprintf("%.2f", node.VectorsPerCall),
+			fmt.Sprintf("%.3g", node.MaxClocks),
+			fmt.Sprint(node.VectorsAtMax),
+			fmt.Sprintf("%.1f%%", node.ClocksPercent),
+		}
 	}
 
 	return rows
@@ -515,33 +1503,657 @@ func (app *App) formatErrors(errors []api.Error) xtui.TableRows {
 
 }
 
-// formatMemstats formats memory stats to xtui.TableRows
+// errorsHeader returns the header of the errors tab, the reason column
+// summarizes the counts of all errors per severity (if the severities
+// are known) and their total rate against the errors polled before
+// (unknown if there are none).
+func errorsHeader(errors, prev []api.Error, elapsed time.Duration, units unitFormat, severity bool) xtui.TableRows {
+	var total, last uint64
+	bySeverity := make(map[string]uint64)
+	for _, errorC := range errors {
+		bySeverity[errorC.Severity] += errorC.Count
+		total += errorC.Count
+	}
+	rate := "-"
+	if prev != nil && elapsed > 0 {
+		for _, errorC := range prev {
+			last += errorC.Count
+		}
+		rate = units.count(perSecond(total, last, elapsed))
+	}
+	reason := i18n.T("Reason (error: %s, warn: %s, info: %s, all: %s/s)",
+		units.count(bySeverity["error"]), units.count(bySeverity["warn"]), units.count(bySeverity["info"]), rate)
+	if !severity {
+		reason = i18n.T("Reason (all: %s/s)", rate)
+	}
+	return xtui.TableRows{{i18n.T("Counter"), i18n.T("Node"), reason, i18n.T("Severity")}}
+}
+
+// formatMemstats formats memory stats to xtui.TableRows. Every section of
+// the stats (the main heap of a thread, the API segment...) is shown by
+// entries of RowsPerMemory rows, details not fitting into the first entry
+// continue in the next ones. Main heaps of threads end with the trend.
 func (app *App) formatMemstats(memstats []string) xtui.TableRows {
-	// vppProvider.GetMemory returns the stats as []string
-	// where 7 rows corresponds to one entry.
-	const rowsPerEntry = 7
-	count := len(memstats) / rowsPerEntry         // number of entries.
-	rows := make([][]string, RowsPerMemory*count) // our view will have 6 rows per entry.
-
-	for i := 0; i < count; i++ {
-		rows[RowsPerMemory*i] = []string{memstats[rowsPerEntry*i], memstats[rowsPerEntry*i+1]}
-		rows[RowsPerMemory*i+1] = []string{xtui.EmptyCell, memstats[rowsPerEntry*i+2]}
-		rows[RowsPerMemory*i+2] = []string{xtui.EmptyCell, memstats[rowsPerEntry*i+3]}
-		rows[RowsPerMemory*i+3] = []string{xtui.EmptyCell, memstats[rowsPerEntry*i+4]}
-		rows[RowsPerMemory*i+4] = []string{xtui.EmptyCell, memstats[rowsPerEntry*i+5]}
-		rows[RowsPerMemory*i+5] = []string{xtui.EmptyCell, memstats[rowsPerEntry*i+6]}
-		rows[RowsPerMemory*i+6] = []string{xtui.EmptyCell, xtui.EmptyCell}
+	// the last row of an entry separates it from the next one
+	const detailRows = RowsPerMemory - 1
+
+	var rows xtui.TableRows
+	for _, section := range memorySections(memstats) {
+		details := section.details
+		if isThreadHeap(section.name) {
+			details = append(details, app.memory.trend(section.name))
+		}
+		for first := 0; first == 0 || first < len(details); first += detailRows {
+			entry := make(xtui.TableRows, RowsPerMemory)
+			for i := range entry {
+				entry[i] = []string{xtui.EmptyCell, xtui.EmptyCell}
+			}
+			entry[0][0] = section.name
+			for i := 0; i < detailRows && first+i < len(details); i++ {
+				entry[i][1] = details[first+i]
+			}
+			rows = append(rows, entry...)
+		}
 	}
 
 	return rows
 }
 
-// formatThreads formats memory stats to xtui.TableRows
-func (app *App) formatThreads(threads []api.ThreadData) xtui.TableRows {
+// formatThreads formats thread stats to xtui.TableRows, the rx rates
+// are calculated from the previous poll.
+func (app *App) formatThreads(threads, prev []api.ThreadData, elapsed time.Duration) xtui.TableRows {
 	rows := make(xtui.TableRows, len(threads))
 
+	prevRx := make(map[uint32]map[uint32]uint64, len(prev))
+	for _, thread := range prev {
+		prevRx[thread.ID] = make(map[uint32]uint64, len(thread.RxInterfaces))
+		for _, iface := range thread.RxInterfaces {
+			prevRx[thread.ID][iface.SwIfIndex] = iface.RxPackets
+		}
+	}
+
+	units := app.unitFormat()
 	for i, thread := range threads {
 		rows[i] = strings.Split(fmt.Sprintf("%d %s %s %d %d %d %d", thread.ID, thread.Name, thread.Type, thread.PID, thread.CPUID, thread.Core, thread.CPUSocket), " ")
+		rows[i] = append(rows[i], formatUtilization(thread.Utilization), formatAffinity(thread))
+		if thread.SchedPolicy == "" {
+			rows[i] = append(rows[i], "-", "-")
+		} else {
+			rows[i] = append(rows[i],
+				fmt.Sprintf("%s/%d", thread.SchedPolicy, thread.SchedPriority),
+				units.count(thread.VoluntaryCtxSwitches)+"/"+units.count(thread.InvoluntaryCtxSwitches),
+			)
+		}
+		rows[i] = append(rows[i], formatRxInterfaces(thread.RxInterfaces, prevRx[thread.ID], elapsed, units)...)
+	}
+
+	return rows
+}
+
+// formatRxInterfaces formats the aggregate rx rate of the thread and the list
+// of interfaces with their rx queues and rates served by the thread.
+func formatRxInterfaces(ifaces []api.RxInterface, prev map[uint32]uint64, elapsed time.Duration, units unitFormat) []string {
+	if len(ifaces) == 0 {
+		return []string{"-", "-"}
+	}
+	var total uint64
+	list := make([]string, len(ifaces))
+	for i, iface := range ifaces {
+		var pps uint64
+		if prevPackets, ok := prev[iface.SwIfIndex]; ok {
+			pps = perSecond(iface.RxPackets, prevPackets, elapsed)
+		}
+		total += pps
+		queues := make([]string, len(iface.Queues))
+		for j, queue := range iface.Queues {
+			queues[j] = fmt.Sprint(queue)
+		}
+		list[i] = fmt.Sprintf("%s (q%s, %s)", iface.Name, strings.Join(queues, ","), units.count(pps))
+	}
+	return []string{units.count(total), strings.Join(list, " ")}
+}
+
+// formatAffinity formats the CPU affinity of the thread, the affinity
+// is marked if it does not pin the thread to its CPU only.
+func formatAffinity(thread api.ThreadData) string {
+	if thread.Affinity == "" {
+		return "-"
+	}
+	if thread.Affinity != fmt.Sprint(thread.CPUID) {
+		return thread.Affinity + " (!)"
+	}
+	return thread.Affinity
+}
+
+// formatUtilization formats the estimated CPU utilization.
+func formatUtilization(utilization float64) string {
+	if utilization < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", utilization)
+}
+
+// hotThread returns the state line with the most utilized thread,
+// or an empty string if the utilization is not known.
+func (app *App) hotThread() string {
+	entry, ok := app.cache.load(Threads)
+	if !ok {
+		return ""
+	}
+	var hot *api.ThreadData
+	threads := entry.data.([]api.ThreadData)
+	for i := range threads {
+		if threads[i].Utilization >= 0 && (hot == nil || threads[i].Utilization > hot.Utilization) {
+			hot = &threads[i]
+		}
+	}
+	if hot == nil {
+		return ""
+	}
+	return fmt.Sprintf("\nHot thread: %s %s", hot.Name, formatUtilization(hot.Utilization))
+}
+
+// formatDropsPunts formats drop/punt stats to xtui.TableRows with their rates.
+func (app *App) formatDropsPunts(dropsPunts []api.DropPunt, rates *statsRates) xtui.TableRows {
+	rows := make(xtui.TableRows, len(dropsPunts))
+
+	for i, dropPunt := range dropsPunts {
+		rate := rates.count(dropPunt, dropPuntCountRate) // count/s
+		rows[i] = []string{dropPunt.Type, dropPunt.Node, dropPunt.Reason, fmt.Sprint(dropPunt.Count), fmt.Sprint(rate)}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// formatTunnels formats tunnel stats to xtui.TableRows with their rates.
+func (app *App) formatTunnels(tunnels []api.TunnelCounters, rates *statsRates) xtui.TableRows {
+	units := app.unitFormat()
+	rows := make(xtui.TableRows, len(tunnels))
+
+	for i, tunnel := range tunnels {
+		rxpps := rates.count(tunnel, tunnelRxPacketRate)
+		rxbbs := rates.count(tunnel, tunnelRxByteRate)
+		txpps := rates.count(tunnel, tunnelTxPacketRate)
+		txbbs := rates.count(tunnel, tunnelTxByteRate)
+		rows[i] = []string{
+			tunnel.InterfaceName,
+			tunnel.Type,
+			tunnel.Src,
+			tunnel.Dst,
+			fmt.Sprint(tunnel.ID),
+			units.count(tunnel.Rx.Packets),
+			units.count(rxpps),
+			units.byteRate(rxbbs),
+			units.count(tunnel.Tx.Packets),
+			units.count(txpps),
+			units.byteRate(txbbs),
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", "", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// formatSessions formats host-stack session counts to xtui.TableRows
+func (app *App) formatSessions(sessions []api.SessionStat) xtui.TableRows {
+	rows := make(xtui.TableRows, len(sessions))
+	for i, session := range sessions {
+		rows[i] = []string{session.Namespace, session.Protocol, session.State, fmt.Sprint(session.Count)}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", ""})
+	}
+
+	return rows
+}
+
+// formatFeatures formats feature arcs of interfaces to xtui.TableRows
+func (app *App) formatFeatures(features []api.FeatureArc) xtui.TableRows {
+	rows := make(xtui.TableRows, len(features))
+	for i, arc := range features {
+		rows[i] = []string{arc.Interface, arc.Arc, strings.Join(arc.Features, ", ")}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", ""})
+	}
+
+	return rows
+}
+
+// formatBonds formats bond members to xtui.TableRows. The share is the part of the
+// packets of the bond received or transmitted by the member, including all members.
+func (app *App) formatBonds(members, all []api.BondMember, rates *statsRates) xtui.TableRows {
+	units := app.unitFormat()
+	rows := make(xtui.TableRows, len(members))
+	// totals of all members of the bond, including the filtered out ones
+	type bondTotal struct{ rx, tx uint64 }
+	totals := make(map[string]*bondTotal)
+	for _, member := range all {
+		if totals[member.Bond] == nil {
+			totals[member.Bond] = new(bondTotal)
+		}
+		totals[member.Bond].rx += member.Rx.Packets
+		totals[member.Bond].tx += member.Tx.Packets
+	}
+	share := func(packets, total uint64) string {
+		if total == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", float64(packets)/float64(total)*100)
+	}
+
+	for i, member := range members {
+		rxpps := rates.count(member, bondRxPacketRate)
+		txpps := rates.count(member, bondTxPacketRate)
+		total := totals[member.Bond]
+		if total == nil {
+			total = new(bondTotal)
+		}
+		active, lacp := "no", ""
+		if member.Active {
+			active = "yes"
+		}
+		if member.LACP.Actor != "" || member.LACP.Partner != "" {
+			lacp = member.LACP.Actor + " | " + member.LACP.Partner
+		}
+		rows[i] = []string{
+			member.Bond,
+			member.Mode + "/" + member.LoadBalance,
+			member.Member,
+			active,
+			lacp,
+			member.LACP.MuxState,
+			units.count(member.Rx.Packets),
+			units.count(rxpps),
+			share(member.Rx.Packets, total.rx),
+			units.count(member.Tx.Packets),
+			units.count(txpps),
+			share(member.Tx.Packets, total.tx),
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", "", "", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// formatPolicers formats policers to xtui.TableRows, packets of each color are
+// shown with their rates (violate and exceed packets are usually dropped).
+func (app *App) formatPolicers(policers []api.Policer, rates *statsRates) xtui.TableRows {
+	units := app.unitFormat()
+	rows := make(xtui.TableRows, len(policers))
+
+	for i, policer := range policers {
+		conform := rates.count(policer, policerConformRate)
+		exceed := rates.count(policer, policerExceedRate)
+		violate := rates.count(policer, policerViolateRate)
+		rows[i] = []string{
+			policer.Name,
+			policer.Type,
+			fmt.Sprintf("%d/%d %s", policer.CIR, policer.EIR, policer.RateType),
+			fmt.Sprintf("%d/%d", policer.CB, policer.EB),
+			policer.ConformAction + "/" + policer.ExceedAction + "/" + policer.ViolateAction,
+			units.count(policer.Conform.Packets),
+			units.count(conform),
+			units.count(policer.Exceed.Packets),
+			units.count(exceed),
+			units.count(policer.Violate.Packets),
+			units.count(violate),
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", "", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// fibHeader returns the header of the fib tab including the memory used by the FIB.
+func fibHeader(summary *api.FibSummary) xtui.TableRows {
+	memory := i18n.T("unknown")
+	if summary != nil {
+		memory = i18n.T("IPv4 %s incl. mtrie, IPv6 %s",
+			scaleUnits(summary.IP4Memory, 1024, iecSuffixes), scaleUnits(summary.IP6Memory, 1024, iecSuffixes))
+	}
+	return xtui.TableRows{{"VRF", i18n.T("Name"), "AF", i18n.T("Routes"), i18n.T("Change"), i18n.T("Host Routes (FIB memory: %s)", memory)}}
+}
+
+// formatFib formats FIB tables to xtui.TableRows, the change
+// of the number of routes since the previous poll is shown as well.
+func (app *App) formatFib(tables, prev []api.FibTable) xtui.TableRows {
+	type tableKey struct {
+		name string
+		ipv6 bool
+	}
+	units := app.unitFormat()
+	last := make(map[tableKey]uint64, len(prev))
+	for _, table := range prev {
+		last[tableKey{name: table.Name, ipv6: table.IPv6}] = table.Routes
+	}
+
+	rows := make(xtui.TableRows, len(tables))
+	for i, table := range tables {
+		vrf := "-"
+		if table.TableID != api.UnknownTableID {
+			vrf = fmt.Sprint(table.TableID)
+		}
+		change := ""
+		if routes, ok := last[tableKey{name: table.Name, ipv6: table.IPv6}]; ok {
+			change = fmt.Sprintf("%+d", int64(table.Routes)-int64(routes))
+		}
+		rows[i] = []string{
+			vrf,
+			table.Name,
+			fibAF(table),
+			units.count(table.Routes),
+			change,
+			units.count(table.HostRoutes),
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// fibAF returns the address family of the FIB table.
+func fibAF(table api.FibTable) string {
+	if table.IPv6 {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// formatNeighbors formats neighbors to xtui.TableRows.
+func (app *App) formatNeighbors(neighbors []api.Neighbor) xtui.TableRows {
+	rows := make(xtui.TableRows, len(neighbors))
+	for i, neighbor := range neighbors {
+		age := "-"
+		if neighbor.Age != api.UnknownNeighborAge {
+			age = fmt.Sprintf("%.1fs", neighbor.Age)
+		}
+		rows[i] = []string{
+			neighbor.Interface,
+			neighbor.IP,
+			neighbor.MAC,
+			age,
+			neighborState(neighbor),
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// neighborState returns whether the neighbor is static or dynamic,
+// followed by no-fib-entry if the neighbor is not installed to the FIB.
+func neighborState(neighbor api.Neighbor) string {
+	state := "dynamic"
+	if neighbor.Static {
+		state = "static"
+	}
+	if neighbor.NoFibEntry {
+		state += " no-fib-entry"
+	}
+	return state
+}
+
+// formatSRv6 formats SRv6 policies and local SIDs to xtui.TableRows, the
+// counters of a policy are the packets forwarded into the policy by the FIB.
+func (app *App) formatSRv6(sids []api.SRv6SID, rates *statsRates) xtui.TableRows {
+	units := app.unitFormat()
+	rows := make(xtui.TableRows, len(sids))
+	for i, sid := range sids {
+		behavior, fib, bad := sid.Behavior, "", units.count(sid.Bad.Packets)
+		if sid.Policy {
+			behavior = fmt.Sprintf("%s (%s)", sid.Behavior, sid.Type)
+			fib, bad = fmt.Sprint(sid.FibTable), "-"
+		}
+		rows[i] = []string{
+			sid.SID,
+			srv6Kind(sid),
+			behavior,
+			fib,
+			formatSegmentLists(sid.SegmentLists),
+			strings.Join(sid.Steering, ", "),
+			units.count(sid.Good.Packets),
+			units.count(rates.count(sid, srv6PacketRate)),
+			units.bytes(sid.Good.Bytes),
+			units.byteRate(rates.count(sid, srv6ByteRate)),
+			bad,
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", "", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// srv6Kind returns whether the SID is a policy BSID or a local SID.
+func srv6Kind(sid api.SRv6SID) string {
+	if sid.Policy {
+		return "policy"
+	}
+	return "localsid"
+}
+
+// formatSegmentLists formats the segment lists of a policy with their weights,
+// e.g. "<a::1, b::1> w1; <c::1> w1".
+func formatSegmentLists(lists []api.SRv6SegmentList) string {
+	formatted := make([]string, len(lists))
+	for i, list := range lists {
+		formatted[i] = fmt.Sprintf("<%s> w%d", strings.Join(list.Segments, ", "), list.Weight)
+	}
+	return strings.Join(formatted, "; ")
+}
+
+// mplsHeader returns the header of the mpls tab including
+// the number of the MPLS FIB entries and tunnels.
+func mplsHeader(entries []api.MplsEntry) xtui.TableRows {
+	var fibEntries, tunnels int
+	for _, entry := range entries {
+		if entry.Tunnel != "" {
+			tunnels++
+		} else {
+			fibEntries++
+		}
+	}
+	return xtui.TableRows{{
+		i18n.T("Label (FIB entries: %d, tunnels: %d)", fibEntries, tunnels),
+		i18n.T("Table"), i18n.T("Next Hops"), i18n.T("Out Labels"),
+		i18n.T("Packets"), i18n.T("Packets/s"), i18n.T("Bytes"), i18n.T("Bytes/s"),
+	}}
+}
+
+// formatMpls formats MPLS tunnels and FIB entries to xtui.TableRows, the
+// counters of a tunnel are the packets transmitted by its interface.
+func (app *App) formatMpls(entries []api.MplsEntry, rates *statsRates) xtui.TableRows {
+	units := app.unitFormat()
+	rows := make(xtui.TableRows, len(entries))
+	for i, entry := range entries {
+		table := fmt.Sprint(entry.TableID)
+		if entry.Tunnel != "" {
+			table = "-"
+		}
+		rows[i] = []string{
+			mplsLabel(entry),
+			table,
+			strings.Join(entry.NextHops, ", "),
+			formatLabels(entry.OutLabels),
+			units.count(entry.Counter.Packets),
+			units.count(rates.count(entry, mplsPacketRate)),
+			units.bytes(entry.Counter.Bytes),
+			units.byteRate(rates.count(entry, mplsByteRate)),
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// mplsLabel returns the local label of the FIB entry with the end
+// of stack bit, e.g. "33 eos", or the name of the tunnel.
+func mplsLabel(entry api.MplsEntry) string {
+	switch {
+	case entry.Tunnel != "":
+		return entry.Tunnel
+	case entry.EOS:
+		return fmt.Sprintf("%d eos", entry.Label)
+	}
+	return fmt.Sprintf("%d neos", entry.Label)
+}
+
+// formatLabels formats the imposed label stack, e.g. "1001 2001",
+// or "pop" if no label is imposed.
+func formatLabels(labels []uint32) string {
+	if len(labels) == 0 {
+		return "pop"
+	}
+	formatted := make([]string, len(labels))
+	for i, label := range labels {
+		formatted[i] = fmt.Sprint(label)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// apiTraceHeader returns the header of the api trace tab including the trace
+// status and the number of the messages polling the VPP left out of the tab.
+func apiTraceHeader(trace *api.APITrace) xtui.TableRows {
+	status := i18n.T("unknown")
+	if trace != nil {
+		status = i18n.T("off")
+		if trace.Enabled {
+			status = i18n.T("on")
+		}
+		if trace.Hidden != 0 {
+			status = i18n.T("%s, %d CLI polls hidden", status, trace.Hidden)
+		}
+	}
+	return xtui.TableRows{{i18n.T("Index"), i18n.T("Message"), i18n.T("Details (trace: %s, Ctrl-T to toggle, Ctrl-O to save)", status)}}
+}
+
+// formatAPITrace formats traced API messages to xtui.TableRows,
+// the most recent messages first.
+func (app *App) formatAPITrace(trace *api.APITrace) xtui.TableRows {
+	rows := make(xtui.TableRows, 0, len(trace.Messages))
+	for i := len(trace.Messages) - 1; i >= 0; i-- {
+		msg := trace.Messages[i]
+		rows = append(rows, []string{fmt.Sprint(msg.Index), msg.Name, msg.Details})
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", trace.Status})
+	}
+
+	return rows
+}
+
+// apiClientsHeader returns the header of the api clients tab including the
+// memory used by the API segment, the rate of the messages allocated from
+// the rings against the rings polled before and the ring misses.
+func apiClientsHeader(summary *api.APIClients, prev []api.APIRing, elapsed time.Duration, units unitFormat) xtui.TableRows {
+	segment, toVPP, toClients, misses := i18n.T("unknown"), "-", "-", "-"
+	if summary != nil {
+		if summary.SegmentSize != 0 {
+			segment = i18n.T("%s of %s",
+				scaleUnits(summary.SegmentUsed, 1024, iecSuffixes), scaleUnits(summary.SegmentSize, 1024, iecSuffixes))
+		}
+		if prev != nil && elapsed > 0 {
+			toVPP = units.count(perSecond(apiRingAllocations(summary.Rings, "vlib"), apiRingAllocations(prev, "vlib"), elapsed))
+			toClients = units.count(perSecond(apiRingAllocations(summary.Rings, "clnt"), apiRingAllocations(prev, "clnt"), elapsed))
+		}
+		misses = units.count(summary.RingMissFallbacks)
+	}
+	return xtui.TableRows{{i18n.T("Name"), i18n.T("Transport"), "PID", "FD", i18n.T("Queue"),
+		i18n.T("Health (API segment: %s, to VPP: %s msg/s, to clients: %s msg/s, ring misses: %s)", segment, toVPP, toClients, misses)}}
+}
+
+// apiRingAllocations returns the number of messages allocated from the rings
+// of the owner ("vlib" for the messages to the VPP, "clnt" to the clients).
+func apiRingAllocations(rings []api.APIRing, owner string) uint64 {
+	var total uint64
+	for _, ring := range rings {
+		if ring.Owner == owner {
+			total += ring.Hits + ring.Misses
+		}
+	}
+	return total
+}
+
+// formatAPIClients formats binary API clients to xtui.TableRows.
+func (app *App) formatAPIClients(clients []api.APIClient) xtui.TableRows {
+	units := app.unitFormat()
+	rows := make(xtui.TableRows, len(clients))
+	for i, client := range clients {
+		if client.Socket {
+			rows[i] = []string{client.Name, apiClientTransport(client), "-", fmt.Sprint(client.FD), "-", "-"}
+			continue
+		}
+		rows[i] = []string{
+			client.Name,
+			apiClientTransport(client),
+			fmt.Sprint(client.PID),
+			"-",
+			units.count(client.QueueLength),
+			client.Health,
+		}
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, []string{"", "", "", "", "", ""})
+	}
+
+	return rows
+}
+
+// apiClientTransport returns the transport of the binary API client.
+func apiClientTransport(client api.APIClient) string {
+	if client.Socket {
+		return "socket"
+	}
+	return "shm"
+}
+
+// formatInfo formats VPP info and the list of loaded plugins to xtui.TableRows
+func (app *App) formatInfo(info *api.VPPInfo) xtui.TableRows {
+	uptime := time.Duration(info.SessionInfo.Uptime * float64(time.Second)).Round(time.Second)
+
+	rows := xtui.TableRows{
+		{"Program", info.VersionInfo.Program, xtui.EmptyCell},
+		{"Version", info.VersionInfo.Version, xtui.EmptyCell},
+		{"Build date", info.VersionInfo.BuildDate, xtui.EmptyCell},
+		{"Build directory", info.VersionInfo.BuildDirectory, xtui.EmptyCell},
+		{"Binapi version", info.Version, xtui.EmptyCell},
+		{"Uptime", uptime.String(), xtui.EmptyCell},
+		{"PID", fmt.Sprint(info.SessionInfo.PID), xtui.EmptyCell},
+		{"Client index", fmt.Sprint(info.SessionInfo.ClientIdx), xtui.EmptyCell},
+		{xtui.EmptyCell, xtui.EmptyCell, xtui.EmptyCell},
+		{fmt.Sprintf("Plugins (%d)", len(info.Plugins)), xtui.EmptyCell, xtui.EmptyCell},
+	}
+	for _, plugin := range info.Plugins {
+		rows = append(rows, []string{plugin.Name, plugin.Version, plugin.Description})
 	}
 
 	return rows
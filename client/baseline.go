@@ -0,0 +1,150 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"go.pantheon.tech/vpptop/gui/views"
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// nodeKey identifies the node of a thread.
+type nodeKey struct {
+	thread uint
+	name   string
+}
+
+// nodeBaseline keeps the node counters marked as the baseline, the nodes
+// tab shows the counters added since the baseline until it is reset.
+type nodeBaseline struct {
+	sync.Mutex
+	// nodes by their keys, nil if the baseline is not marked
+	nodes map[nodeKey]api.Node
+	// column widths of the nodes tab without the baseline columns
+	widths []int
+}
+
+// baselineColumns are the columns added to the nodes tab while the baseline is marked.
+var baselineColumns = []string{"+Calls", "+Vectors", "+Clocks", "Clocks/V (base -> now)"}
+
+// baselineWidths are the widths of the baseline columns.
+var baselineWidths = []int{12, 12, 10, 24}
+
+// nodesHeader returns the header of the nodes tab.
+func nodesHeader(baseline bool) xtui.TableRows {
+	header := []string{"Name", "State", "Calls", "Vectors", "Suspends", "Clocks", "Vectors/Calls", "MaxClocks", "Vectors@Max", "Clocks%"}
+	if baseline {
+		header = append(header, baselineColumns...)
+	}
+	return xtui.TableRows{i18n.Slice(header)}
+}
+
+// isMarked returns true if the baseline is marked.
+func (b *nodeBaseline) isMarked() bool {
+	b.Lock()
+	defer b.Unlock()
+	return b.nodes != nil
+}
+
+// toggleBaseline marks the polled node counters as the baseline, or resets
+// the baseline if it is marked. The baseline columns are shown while the
+// baseline is marked.
+func (app *App) toggleBaseline() {
+	b := app.baseline
+	view := app.gui.ViewAtTab(Nodes).(*views.TableView)
+
+	b.Lock()
+	defer b.Unlock()
+	if b.nodes != nil {
+		b.nodes = nil
+		view.SetHeader(nodesHeader(false))
+		view.SetColumnWidths(b.widths)
+		return
+	}
+
+	entry, ok := app.cache.load(Nodes)
+	if !ok {
+		return
+	}
+	nodes := entry.data.([]api.Node)
+	b.nodes = make(map[nodeKey]api.Node, len(nodes))
+	for _, node := range nodes {
+		b.nodes[nodeKey{thread: node.Thread, name: node.Name}] = node
+	}
+	b.widths = view.ColumnWidths()
+	view.SetHeader(nodesHeader(true))
+	view.SetColumnWidths(append(append([]int(nil), b.widths...), baselineWidths...))
+}
+
+// resetBaseline resets the baseline, e.g. once the counters are cleared.
+func (app *App) resetBaseline() {
+	if app.baseline.isMarked() {
+		app.toggleBaseline()
+	}
+}
+
+// withBaseline appends the counters added since the baseline to the rows
+// of the nodes, the rows are returned unchanged if the baseline is not marked.
+func (app *App) withBaseline(rows xtui.TableRows, nodes []api.Node) xtui.TableRows {
+	b := app.baseline
+	b.Lock()
+	defer b.Unlock()
+	if b.nodes == nil {
+		return rows
+	}
+
+	for i, node := range nodes {
+		base, ok := b.nodes[nodeKey{thread: node.Thread, name: node.Name}]
+		if !ok {
+			// the node was added after the baseline was marked
+			base = api.Node{}
+		}
+		calls := counterDelta(node.Calls, base.Calls)
+		vectors := counterDelta(node.Vectors, base.Vectors)
+		clocks := node.TotalClocks - base.TotalClocks
+		if clocks < 0 {
+			clocks = 0
+		}
+		// clocks per vector (or per call) spent since the baseline
+		perVector := "-"
+		if vectors > 0 {
+			perVector = fmt.Sprintf("%.3g", clocks/float64(vectors))
+		} else if calls > 0 {
+			perVector = fmt.Sprintf("%.3g", clocks/float64(calls))
+		}
+		rows[i] = append(rows[i],
+			fmt.Sprint(calls),
+			fmt.Sprint(vectors),
+			fmt.Sprintf("%.3g", clocks),
+			fmt.Sprintf("%.3g -> %s", base.Clocks, perVector),
+		)
+	}
+	return rows
+}
+
+// counterDelta returns the increase of the counter, or zero
+// if the counter was cleared.
+func counterDelta(curr, base uint64) uint64 {
+	if curr < base {
+		return 0
+	}
+	return curr - base
+}
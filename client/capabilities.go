@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// notAvailable is shown instead of the data not available from the connected VPP.
+const notAvailable = "n/a"
+
+// tabSupported returns true if the data of the tab can be retrieved by a handler
+// with the capabilities. The counters are read from the stats segment or by the
+// binary API, the data of the other tabs is (at least partially) parsed from the CLI.
+func tabSupported(tab int, caps api.Capabilities) bool {
+	switch tab {
+	case Interfaces, Nodes, Errors, Info, Diagnostics:
+		return true
+	case Threads:
+		return caps.Threads
+	}
+	return caps.CLI
+}
+
+// capabilities returns the capabilities of the connected handler.
+func (app *App) capabilities() api.Capabilities {
+	return app.vppProvider.GetCapabilities()
+}
+
+// isTabSupported returns true if the data of the tab can be retrieved
+// by the connected handler, unsupported tabs are not polled.
+func (app *App) isTabSupported(tab int) bool {
+	return tabSupported(tab, app.capabilities())
+}
+
+// updateDisabledTabs greys out the tabs not supported by the connected handler.
+func (app *App) updateDisabledTabs() {
+	caps := app.capabilities()
+	var disabled []int
+	for tab := range tabNames {
+		if app.hasTab(tab) && !tabSupported(tab, caps) {
+			disabled = append(disabled, tab)
+		}
+	}
+	app.gui.SetDisabledTabs(disabled...)
+}
+
+// withoutSeverity returns a copy of the error counters with the severity
+// not available, if the handler does not retrieve the severities.
+func withoutSeverity(errors []api.Error) []api.Error {
+	result := make([]api.Error, len(errors))
+	for i, errorC := range errors {
+		errorC.Severity = notAvailable
+		result[i] = errorC
+	}
+	return result
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// DefaultCaptureMaxPackets is the default number of packets to capture.
+const DefaultCaptureMaxPackets = 1000
+
+// CaptureConfig configures the packet captures started at the capture tab.
+type CaptureConfig struct {
+	// Interface restricts the pcap trace to the interface (all if empty).
+	Interface string
+	// MaxPackets is the number of packets to capture.
+	MaxPackets uint32
+}
+
+// captureState keeps the configuration of the packet captures together
+// with the files of the started captures and the replies to the last stop,
+// as VPP does not report the file path of a stopped capture.
+type captureState struct {
+	sync.Mutex
+	cfg CaptureConfig
+	// output files of the started captures
+	files map[api.CaptureKind]string
+	// replies to the last stop of the captures
+	results map[api.CaptureKind]string
+}
+
+// newCaptureState returns an instance of <*captureState> with the default config.
+func newCaptureState() *captureState {
+	return &captureState{
+		cfg:     CaptureConfig{MaxPackets: DefaultCaptureMaxPackets},
+		files:   make(map[api.CaptureKind]string),
+		results: make(map[api.CaptureKind]string),
+	}
+}
+
+// SetCapture sets the configuration of the packet captures.
+func (app *App) SetCapture(cfg CaptureConfig) {
+	if cfg.MaxPackets == 0 {
+		cfg.MaxPackets = DefaultCaptureMaxPackets
+	}
+	app.captures.Lock()
+	app.captures.cfg = cfg
+	app.captures.Unlock()
+}
+
+// toggleCapture starts the packet capture of the kind if it is not running,
+// otherwise it stops the capture.
+func (app *App) toggleCapture(ctx context.Context, kind api.CaptureKind) error {
+	enabled := false
+	if entry, ok := app.cache.load(Capture); ok {
+		for _, capture := range entry.data.([]api.PacketCapture) {
+			if capture.Kind == kind {
+				enabled = capture.Enabled
+			}
+		}
+	}
+
+	c := app.captures
+	if enabled {
+		reply, err := app.vppProvider.StopCapture(ctx, kind)
+		if err != nil {
+			return err
+		}
+		c.Lock()
+		c.results[kind] = reply
+		c.Unlock()
+		return nil
+	}
+
+	c.Lock()
+	opts := api.CaptureOptions{
+		Interface:  c.cfg.Interface,
+		MaxPackets: c.cfg.MaxPackets,
+		File:       fmt.Sprintf("vpptop-%s-%s.pcap", kind, time.Now().Format("20060102-150405")),
+	}
+	c.Unlock()
+	if err := app.vppProvider.StartCapture(ctx, kind, opts); err != nil {
+		return err
+	}
+	c.Lock()
+	c.files[kind] = "/tmp/" + opts.File
+	delete(c.results, kind)
+	c.Unlock()
+	return nil
+}
+
+// formatCaptures formats the status of the packet captures to xtui.TableRows.
+func (app *App) formatCaptures(captures []api.PacketCapture) xtui.TableRows {
+	c := app.captures
+	c.Lock()
+	defer c.Unlock()
+
+	rows := make(xtui.TableRows, 0, len(captures))
+	for _, capture := range captures {
+		state, iface := "off", "all"
+		if capture.Enabled {
+			state = "on"
+		}
+		if capture.Kind == api.PcapTrace && c.cfg.Interface != "" {
+			iface = c.cfg.Interface
+		}
+		packets := fmt.Sprintf("%d/%d", capture.Captured, capture.MaxPackets)
+		if capture.MaxPackets == 0 {
+			packets = fmt.Sprintf("-/%d", c.cfg.MaxPackets)
+		}
+		file := capture.File
+		if file == "" {
+			file = c.files[capture.Kind]
+		}
+		status := capture.Status
+		if result, ok := c.results[capture.Kind]; ok && !capture.Enabled {
+			status = result
+		}
+		rows = append(rows, []string{string(capture.Kind), state, iface, packets, file, status})
+	}
+
+	return rows
+}
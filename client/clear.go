@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// clearResult is the result of a single request clearing the counters.
+type clearResult struct {
+	// stats cleared by the request, e.g. "interface stats".
+	stats string
+	err   error
+}
+
+// notifyCleared shows the cleared and the failed requests in the notification
+// area (as an error if any failed), the errors of the failed requests are logged.
+func (app *App) notifyCleared(ctx context.Context, results ...clearResult) {
+	severity := gui.SeverityInfo
+	for _, r := range results {
+		if r.err != nil {
+			severity = gui.SeverityError
+		}
+	}
+	app.notify(ctx, severity, clearNotification(results))
+}
+
+// clearNotification returns the notification listing the cleared stats
+// followed by the stats which failed to be cleared.
+func clearNotification(results []clearResult) string {
+	var cleared, failed []string
+	for _, r := range results {
+		if r.err != nil {
+			logrus.Errorf("error occured while clearing %s: %v", r.stats, r.err)
+			failed = append(failed, i18n.T(r.stats))
+		} else {
+			cleared = append(cleared, i18n.T(r.stats))
+		}
+	}
+	var texts []string
+	if len(cleared) != 0 {
+		texts = append(texts, i18n.T("cleared: %s", strings.Join(cleared, ", ")))
+	}
+	if len(failed) != 0 {
+		texts = append(texts, i18n.T("clear failed: %s (see the log)", strings.Join(failed, ", ")))
+	}
+	return strings.Join(texts, " | ")
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClearNotification(t *testing.T) {
+	tests := []struct {
+		results []clearResult
+		want    string
+	}{
+		{results: []clearResult{{stats: "interface stats"}}, want: "cleared: interface stats"},
+		{results: []clearResult{{stats: "interface stats"}, {stats: "node stats", err: errors.New("timeout")}, {stats: "error stats"}},
+			want: "cleared: interface stats, error stats | clear failed: node stats (see the log)"},
+		{results: []clearResult{{stats: "api trace", err: errors.New("timeout")}}, want: "clear failed: api trace (see the log)"},
+	}
+
+	for _, test := range tests {
+		if got := clearNotification(test.results); got != test.want {
+			t.Errorf("Error occured got:%q; want:%q", got, test.want)
+		}
+	}
+}
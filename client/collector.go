@@ -0,0 +1,511 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"git.fd.io/govpp.git/core"
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// cacheEntry holds the last two polled values of a single data source.
+type cacheEntry struct {
+	// data is the most recently polled value.
+	data interface{}
+	// prev is the value polled before data, used to calculate rates.
+	prev interface{}
+	// elapsed is the time between polling prev and data.
+	elapsed time.Duration
+	// rates of the data computed against prev (nil if the tab has no rates).
+	rates *statsRates
+	// deviations of the rates from their moving averages in standard deviations.
+	deviations *statsRates
+	// polledAt is the time data was polled.
+	polledAt time.Time
+	// generation is increased on each reset, data polled
+	// before the reset is not stored.
+	generation uint64
+}
+
+// pollFailure describes the failed polls of a tab since its last successful poll.
+type pollFailure struct {
+	// err is the error of the last failed poll.
+	err error
+	// since is the time of the first failed poll.
+	since time.Time
+	// count is the number of failed polls.
+	count int
+}
+
+// dataCache is shared between collectors (writers) and the gui (reader).
+type dataCache struct {
+	sync.RWMutex
+	entries map[int]*cacheEntry
+	// failures of the tabs which failed to be polled since their last data.
+	failures map[int]*pollFailure
+	// moving averages of the rates of the tabs.
+	averages map[int]rateAverages
+}
+
+// newDataCache returns an empty instance of <*dataCache>
+func newDataCache() *dataCache {
+	return &dataCache{
+		entries:  make(map[int]*cacheEntry),
+		failures: make(map[int]*pollFailure),
+		averages: make(map[int]rateAverages),
+	}
+}
+
+// fail records a failed poll of the tab, the data polled before is kept.
+func (c *dataCache) fail(tab int, err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	failure, ok := c.failures[tab]
+	if !ok {
+		failure = &pollFailure{since: time.Now()}
+		c.failures[tab] = failure
+	}
+	failure.err = err
+	failure.count++
+}
+
+// failure returns the failed polls of the tab since its last successful poll,
+// false if the last poll succeeded.
+func (c *dataCache) failure(tab int) (pollFailure, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	failure, ok := c.failures[tab]
+	if !ok {
+		return pollFailure{}, false
+	}
+	return *failure, true
+}
+
+// store saves the polled data for the tab, the previous data
+// is kept to be able to calculate rates. The data is dropped
+// if the tab was reset since the polling started (generation).
+func (c *dataCache) store(tab int, data interface{}, generation uint64) {
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.failures, tab)
+	now := time.Now()
+	entry, ok := c.entries[tab]
+	if !ok {
+		c.entries[tab] = &cacheEntry{
+			data:       data,
+			rates:      computeRates(tab, data, nil, 0),
+			polledAt:   now,
+			generation: generation,
+		}
+		return
+	}
+	if entry.generation != generation {
+		return
+	}
+	entry.prev = entry.data
+	entry.data = data
+	entry.elapsed = now.Sub(entry.polledAt)
+	entry.rates = computeRates(tab, entry.data, entry.prev, entry.elapsed)
+	entry.deviations = c.updateAverages(tab, entry.rates)
+	entry.polledAt = now
+}
+
+// updateAverages adds the rates of the tab to their moving averages
+// and returns their deviations.
+func (c *dataCache) updateAverages(tab int, rates *statsRates) *statsRates {
+	if rates == nil {
+		return nil
+	}
+	averages, ok := c.averages[tab]
+	if !ok {
+		averages = make(rateAverages)
+		c.averages[tab] = averages
+	}
+	return averages.update(rates)
+}
+
+// load returns a copy of the cache entry for the tab.
+func (c *dataCache) load(tab int) (cacheEntry, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	entry, ok := c.entries[tab]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	return *entry, true
+}
+
+// snapshot returns copies of the cache entries of all tabs.
+func (c *dataCache) snapshot() map[int]cacheEntry {
+	c.RLock()
+	defer c.RUnlock()
+
+	entries := make(map[int]cacheEntry, len(c.entries))
+	for tab, entry := range c.entries {
+		entries[tab] = *entry
+	}
+	return entries
+}
+
+// generation returns the current generation of the tab, it has
+// to be read before the polling and passed to the store.
+func (c *dataCache) generation(tab int) uint64 {
+	c.RLock()
+	defer c.RUnlock()
+
+	if entry, ok := c.entries[tab]; ok {
+		return entry.generation
+	}
+	return 0
+}
+
+// reset drops the previous data for the tabs so that rates
+// are not calculated against stale counters, the moving averages
+// of the rates start over. Data of polls running during the reset
+// is dropped as well.
+func (c *dataCache) reset(tabs ...int) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, tab := range tabs {
+		if entry, ok := c.entries[tab]; ok {
+			entry.prev = nil
+			entry.elapsed = 0
+			entry.rates = computeRates(tab, entry.data, nil, 0)
+			entry.deviations = nil
+			entry.generation++
+		}
+		delete(c.averages, tab)
+	}
+}
+
+// resetAll drops the previous data for all tabs.
+func (c *dataCache) resetAll() {
+	c.Lock()
+	defer c.Unlock()
+
+	for tab, entry := range c.entries {
+		entry.prev = nil
+		entry.elapsed = 0
+		entry.rates = computeRates(tab, entry.data, nil, 0)
+		entry.deviations = nil
+		entry.generation++
+	}
+	c.averages = make(map[int]rateAverages)
+}
+
+// DefaultPollTimeout is the default timeout of a single poll of a data source.
+const DefaultPollTimeout = 5 * time.Second
+
+// pollResult is the result of a single poll.
+type pollResult struct {
+	data interface{}
+	err  error
+}
+
+// collector polls a single data source with its own cadence
+// and stores the result to the shared cache.
+type collector struct {
+	tab      int
+	interval time.Duration
+	poll     func(ctx context.Context) (interface{}, error)
+	// pending is the result of a poll which timed out and still runs,
+	// the collector skips polling until it finishes
+	pending chan pollResult
+	// trigger requests polling out of the collector's cadence
+	trigger chan struct{}
+	// reset changes the interval of the polling
+	reset chan time.Duration
+	// watch (optional) subscribes to changes of the data source,
+	// each change triggers the polling
+	watch func(ctx context.Context, onChange func()) error
+	// onStore (optional) is called with the cache entry
+	// once the polled data is stored
+	onStore func(entry cacheEntry)
+	// local is set if the data source is vpptop itself,
+	// which is polled without the VPP connection
+	local bool
+	// visibleOnly is set if the tab is polled only while it is shown
+	// by the gui, e.g. the API trace the polls would fill otherwise
+	visibleOnly bool
+}
+
+// collectors returns collectors for all data sources.
+func (app *App) collectors() []*collector {
+	collectors := []*collector{
+		{tab: Interfaces, interval: 1 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetInterfaces(ctx)
+		}, onStore: func(entry cacheEntry) {
+			app.talkers.update(entry)
+			app.events.update(entry)
+		}},
+		{tab: Nodes, interval: 1 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetNodes(ctx)
+		}},
+		{tab: Errors, interval: 1 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetErrors(ctx)
+		}},
+		{tab: Memory, interval: 5 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetMemory(ctx)
+		}, onStore: app.memory.update},
+		{tab: Threads, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetThreads(ctx)
+		}},
+		{tab: DropsPunts, interval: 1 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetDropsPunts(ctx)
+		}},
+		{tab: Tunnels, interval: 1 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetTunnels(ctx)
+		}},
+		{tab: Sessions, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetSessions(ctx)
+		}},
+		{tab: Features, interval: 10 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetFeatures(ctx)
+		}},
+		{tab: Bonds, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetBonds(ctx)
+		}},
+		{tab: Policers, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetPolicers(ctx)
+		}},
+		{tab: Fib, interval: 5 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetFib(ctx)
+		}},
+		{tab: Neighbors, interval: 5 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetNeighbors(ctx)
+		}, watch: app.vppProvider.WatchNeighbors},
+		{tab: SRv6, interval: 5 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetSRv6(ctx)
+		}},
+		{tab: Mpls, interval: 5 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetMpls(ctx)
+		}},
+		{tab: APITrace, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetAPITrace(ctx)
+		}, visibleOnly: true},
+		{tab: APIClients, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetAPIClients(ctx)
+		}},
+		{tab: Capture, interval: 2 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetCaptures(ctx)
+		}},
+		{tab: Info, interval: 30 * time.Second, poll: func(ctx context.Context) (interface{}, error) {
+			return app.vppProvider.GetInfo(ctx)
+		}},
+	}
+	if app.hasTab(Diagnostics) {
+		collectors = append(collectors, &collector{tab: Diagnostics, interval: 1 * time.Second, local: true,
+			poll: func(_ context.Context) (interface{}, error) {
+				stats := app.self.snapshot()
+				stats.Requests = app.vppProvider.GetRequestStats()
+				return stats, nil
+			}})
+	}
+	for _, c := range collectors {
+		c.trigger = make(chan struct{}, 1)
+		c.reset = make(chan time.Duration, 1)
+	}
+	return collectors
+}
+
+// refreshTab requests rendering of the tab if it is shown by the gui.
+func (app *App) refreshTab(tab int) {
+	if !app.isVisible(tab) {
+		return
+	}
+	select {
+	case app.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// pollBanner returns the banner of the tab explaining why its data is stale,
+// empty if the last poll of the tab succeeded and the tab is auto-refreshed.
+func (app *App) pollBanner(tab int) string {
+	if !app.isTabSupported(tab) {
+		if app.statsOnly {
+			return i18n.T("not available in the stats-only mode")
+		}
+		return i18n.T("not supported by the connected VPP")
+	}
+	failure, failed := app.cache.failure(tab)
+	if !failed {
+		if !app.manual.isManual(tab) {
+			return ""
+		}
+		if entry, ok := app.cache.load(tab); ok {
+			return i18n.T("auto-refresh off, last update %s (F5 to refresh)", entry.polledAt.Format("15:04:05"))
+		}
+		return i18n.T("auto-refresh off (F5 to refresh)")
+	}
+	last := i18n.T("no data polled yet")
+	if entry, ok := app.cache.load(tab); ok {
+		last = i18n.T("last update %s", entry.polledAt.Format("15:04:05"))
+	}
+	return i18n.T("polling failed %d× since %s: %v, %s", failure.count, failure.since.Format("15:04:05"), failure.err, last)
+}
+
+// triggerCollector requests the collector of the tab to poll immediately.
+func triggerCollector(collectors []*collector, tab int) {
+	for _, c := range collectors {
+		if c.tab != tab {
+			continue
+		}
+		select {
+		case c.trigger <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// triggerShown requests the collector of the tab polled only while it is
+// shown by the gui to poll immediately once the tab is shown.
+func triggerShown(collectors []*collector, tab int) {
+	for _, c := range collectors {
+		if c.tab == tab && c.visibleOnly {
+			triggerCollector(collectors, tab)
+			return
+		}
+	}
+}
+
+// runCollector is a blocking call polling the collector's data source
+// until the context is cancelled. If the polled tab is shown by the gui,
+// the gui is refreshed. A poll taking longer than the poll timeout is
+// abandoned and the polling is skipped until the poll finishes, so that
+// a stuck request (e.g. a CLI command on a busy VPP) does not block
+// the other collectors, nor the collector once the context is cancelled.
+func (app *App) runCollector(ctx context.Context, c *collector) {
+	collect := func() {
+		if state, _ := app.vppProvider.GetState(); state != core.Connected && !c.local {
+			return
+		}
+		if !app.isTabSupported(c.tab) {
+			return
+		}
+		if c.visibleOnly && !app.isVisible(c.tab) {
+			return
+		}
+		if c.pending != nil {
+			select {
+			case <-c.pending:
+				// the result of the late poll is stale
+				c.pending = nil
+			default:
+				logrus.Warnf("skipped polling %s stats, the previous poll is still running", tabNames[c.tab])
+				app.self.record(c.tab, 0, pollSkipped)
+				return
+			}
+		}
+
+		generation := app.cache.generation(c.tab)
+		var pollCtx context.Context
+		var cancel context.CancelFunc
+		if app.pollTimeout > 0 {
+			pollCtx, cancel = context.WithTimeout(ctx, app.pollTimeout)
+		} else {
+			pollCtx, cancel = context.WithCancel(ctx)
+		}
+		defer cancel()
+		result := make(chan pollResult, 1)
+		start := time.Now()
+		go func() {
+			defer gui.RecoverPanic()
+			data, err := c.poll(pollCtx)
+			result <- pollResult{data: data, err: err}
+		}()
+
+		var r pollResult
+		select {
+		case r = <-result:
+		case <-pollCtx.Done():
+			if ctx.Err() == nil {
+				logrus.Warnf("polling %s stats takes longer than %v, skipped", tabNames[c.tab], app.pollTimeout)
+				app.self.record(c.tab, time.Since(start), pollSkipped)
+				app.cache.fail(c.tab, fmt.Errorf("polling takes longer than %v", app.pollTimeout))
+				app.refreshTab(c.tab)
+			}
+			c.pending = result
+			return
+		}
+		if r.err != nil {
+			logrus.Errorf("error occured while polling %s stats: %v", tabNames[c.tab], r.err)
+			app.self.record(c.tab, time.Since(start), pollFailed)
+			app.cache.fail(c.tab, r.err)
+			app.refreshTab(c.tab)
+			return
+		}
+		app.self.record(c.tab, time.Since(start), pollSucceeded)
+
+		app.cache.store(c.tab, r.data, generation)
+		if c.onStore != nil {
+			if entry, ok := app.cache.load(c.tab); ok {
+				c.onStore(entry)
+			}
+		}
+		app.refreshTab(c.tab)
+	}
+
+	collect()
+	if c.watch != nil {
+		err := c.watch(ctx, func() {
+			if app.manual.isManual(c.tab) {
+				return
+			}
+			select {
+			case c.trigger <- struct{}{}:
+			default:
+			}
+		})
+		switch err {
+		case nil:
+		case api.ErrNotSupported:
+			logrus.Debugf("%s changes are not watched, polling only", tabNames[c.tab])
+		default:
+			logrus.Warnf("error occured while watching %s changes: %v", tabNames[c.tab], err)
+		}
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !app.manual.isManual(c.tab) {
+				collect()
+			}
+		case <-c.trigger:
+			collect()
+		case interval := <-c.reset:
+			c.interval = interval
+			ticker.Reset(interval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDataCache_failure(t *testing.T) {
+	cache := newDataCache()
+	if _, failed := cache.failure(Nodes); failed {
+		t.Errorf("Error occured got failure before any poll")
+	}
+
+	cache.fail(Nodes, errors.New("parse error"))
+	cache.fail(Nodes, errors.New("timeout"))
+	failure, failed := cache.failure(Nodes)
+	if !failed || failure.count != 2 || failure.err.Error() != "timeout" {
+		t.Errorf("Error occured got:%v %d %v; want:true 2 timeout", failed, failure.count, failure.err)
+	}
+	if _, failed := cache.failure(Errors); failed {
+		t.Errorf("Error occured got failure of a tab which did not fail")
+	}
+
+	cache.store(Nodes, []string{}, cache.generation(Nodes))
+	if _, failed := cache.failure(Nodes); failed {
+		t.Errorf("Error occured got failure after a successful poll")
+	}
+}
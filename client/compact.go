@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	tui "github.com/gizak/termui/v3"
+	"go.pantheon.tech/vpptop/gui/views"
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// Columns of the compact layout of the interfaces tab, the name, index,
+// admin and link state are shown in the same columns as in the full layout.
+const (
+	compactRxPacketRateCol = iota + 4
+	compactRxByteRateCol
+	compactTxPacketRateCol
+	compactTxByteRateCol
+	compactDropsCol
+	compactRxErrorsCol
+	compactTxErrorsCol
+)
+
+// compactWidths are the default column widths of the compact layout.
+var compactWidths = []int{24, 5, 5, 7, 12, 12, 12, 12, 11, 11, 11, views.Resize}
+
+// compactRateCells maps the cells of the rates in the compact layout to the rate columns.
+var compactRateCells = map[[2]int]int{
+	{0, compactRxPacketRateCol}: ifaceRxPacketRate,
+	{0, compactRxByteRateCol}:   ifaceRxByteRate,
+	{0, compactTxPacketRateCol}: ifaceTxPacketRate,
+	{0, compactTxByteRateCol}:   ifaceTxByteRate,
+}
+
+// compactLayout is the state of the compact layout of the interfaces tab,
+// showing a single row per interface.
+type compactLayout struct {
+	sync.Mutex
+	enabled bool
+	// widths of the columns of the layout which is not shown,
+	// restored when the layout is toggled.
+	widths []int
+}
+
+// isEnabled returns true if the compact layout is shown.
+func (c *compactLayout) isEnabled() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.enabled
+}
+
+// interfacesHeader returns the header of the interfaces tab. The header of the
+// compact layout shows the byte rates in the units of the values.
+func interfacesHeader(compact bool, units unitFormat) xtui.TableRows {
+	if !compact {
+		return xtui.TableRows{i18n.Slice([]string{"Name", "Idx", "Admin", "Link", "VRF", "MTU(L3/IP4/IP6/MPLS)/Device", "RxCounters", "RxCount", "TxCounters", "TxCount", "Drops", "Punts", "IP4", "IP6", "Instance"})}
+	}
+	return xtui.TableRows{i18n.Slice([]string{"Name", "Idx", "Admin", "Link", "RxPackets/s", "Rx" + units.byteRateLabel(), "TxPackets/s", "Tx" + units.byteRateLabel(), "Drops", "RxErrors", "TxErrors", "Instance"})}
+}
+
+// SetCompactInterfaces sets whether the interfaces tab shows a single row
+// per interface with its state, rates, drops and errors. The column widths
+// of both layouts are kept while the other one is shown.
+func (app *App) SetCompactInterfaces(enabled bool) {
+	app.compact.Lock()
+	defer app.compact.Unlock()
+	if app.compact.enabled == enabled {
+		return
+	}
+	app.compact.enabled = enabled
+
+	view := app.gui.ViewAtTab(Interfaces).(*views.TableView)
+	widths := view.ColumnWidths()
+	view.SetHeader(interfacesHeader(enabled, app.unitFormat()))
+	view.SetColumnWidths(app.compact.widths)
+	app.compact.widths = widths
+	if enabled {
+		view.SetRowsPerEntry(1)
+		view.SetCellStyler(compactCellStyler)
+	} else {
+		view.SetRowsPerEntry(RowsPerIface)
+		view.SetCellStyler(interfaceCellStyler(app.utilThreshold))
+	}
+}
+
+// keepFullWidths keeps the persisted widths of the full layout of the
+// interfaces tab while the compact layout is shown, it returns false
+// if the full layout is shown and the widths have to be set.
+func (app *App) keepFullWidths(widths []int) bool {
+	app.compact.Lock()
+	defer app.compact.Unlock()
+	if !app.compact.enabled {
+		return false
+	}
+	app.compact.widths = widths
+	return true
+}
+
+// refreshCompactHeader updates the units of the byte rates in the header
+// of the compact layout, if shown.
+func (app *App) refreshCompactHeader(view *views.TableView) {
+	app.compact.Lock()
+	defer app.compact.Unlock()
+	if app.compact.enabled {
+		view.SetHeader(interfacesHeader(true, app.unitFormat()))
+	}
+}
+
+// compactRow formats the interface to the single row of the compact layout.
+func (r *interfaceRows) compactRow(entry int) xtui.TableRows {
+	iface, units := r.ifaces[entry], r.units
+	name := iface.InterfaceName
+	if r.labels != nil {
+		name = r.labels[entry]
+	}
+	row := []string{
+		name,
+		fmt.Sprint(iface.InterfaceIndex),
+		stateOf(iface, iface.State),
+		stateOf(iface, iface.LinkState),
+		units.count(r.rates.count(iface, ifaceRxPacketRate)),
+		units.byteRate(r.rates.count(iface, ifaceRxByteRate)),
+		units.count(r.rates.count(iface, ifaceTxPacketRate)),
+		units.byteRate(r.rates.count(iface, ifaceTxByteRate)),
+		units.count(iface.Drops),
+		units.count(iface.RxErrors),
+		units.count(iface.TxErrors),
+		iface.Instance,
+	}
+	// the counters of interfaces missing in the stats segment are not known
+	if iface.NoStats {
+		for col := compactRxPacketRateCol; col <= compactTxErrorsCol; col++ {
+			row[col] = notAvailable
+		}
+	}
+	return xtui.TableRows{row}
+}
+
+// compactCellStyler paints the admin and link state, and the drops
+// and rx/tx errors exceeding thresholds in the compact layout.
+func compactCellStyler(_ int, row []string, col int) (tui.Color, bool) {
+	switch col {
+	case ifaceStateCol, ifaceLinkCol:
+		switch row[col] {
+		case "down":
+			return criticalColor(), true
+		case unknownState:
+			return warningColor(), true
+		}
+		return okColor(), true
+	case compactDropsCol, compactRxErrorsCol, compactTxErrorsCol:
+		return thresholdColor(row[col])
+	}
+	return tui.ColorClear, false
+}
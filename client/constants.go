@@ -28,6 +28,11 @@ const (
 	NodeStatNodeCalls
 	NodeStatNodeSuspends
 	NodeStatNodeVC
+	NodeStatNodeMaxClocks
+	NodeStatNodeVectorsAtMax
+	NodeStatNodeClocksPercent
+	NodeStatCallRate
+	NodeStatVectorRate
 )
 
 // Mapped interface stats fields
@@ -61,6 +66,17 @@ const (
 	IfaceStatIfacePunts
 	IfaceStatIfaceIP4
 	IfaceStatIfaceIP6
+	IfaceStatTopTalkersAvg
+	IfaceStatTopTalkersPeak
+	IfaceStatIfaceInstance
+	IfaceStatRxPacketRate
+	IfaceStatRxByteRate
+	IfaceStatTxPacketRate
+	IfaceStatTxByteRate
+	IfaceStatRxUtil
+	IfaceStatTxUtil
+	IfaceStatIfaceVrf
+	IfaceStatIfaceLink
 )
 
 // Mapped error stats fields.
@@ -71,6 +87,150 @@ const (
 	ErrorStatErrorSeverity
 )
 
+// Mapped drop/punt stats fields.
+const (
+	DropPuntStatType = iota
+	DropPuntStatNode
+	DropPuntStatReason
+	DropPuntStatCount
+	DropPuntStatCountRate
+)
+
+// Mapped tunnel stats fields.
+const (
+	TunnelStatName = iota
+	TunnelStatType
+	TunnelStatSrc
+	TunnelStatDst
+	TunnelStatID
+	TunnelStatRxPackets
+	TunnelStatRxBytes
+	TunnelStatTxPackets
+	TunnelStatTxBytes
+	TunnelStatRxPacketRate
+	TunnelStatRxByteRate
+	TunnelStatTxPacketRate
+	TunnelStatTxByteRate
+)
+
+// Mapped session stats fields.
+const (
+	SessionStatNamespace = iota
+	SessionStatProtocol
+	SessionStatState
+	SessionStatCount
+)
+
+// Mapped feature arc fields.
+const (
+	FeatureStatInterface = iota
+	FeatureStatArc
+)
+
+// Mapped bond member fields.
+const (
+	BondStatBond = iota
+	BondStatMember
+	BondStatRxPackets
+	BondStatTxPackets
+	BondStatRxPacketRate
+	BondStatTxPacketRate
+)
+
+// Mapped policer fields.
+const (
+	PolicerStatName = iota
+	PolicerStatConform
+	PolicerStatExceed
+	PolicerStatViolate
+	PolicerStatConformRate
+	PolicerStatExceedRate
+	PolicerStatViolateRate
+)
+
+// Mapped fib table fields.
+const (
+	FibStatTableID = iota
+	FibStatName
+	FibStatRoutes
+	FibStatHostRoutes
+)
+
+// Mapped neighbor fields.
+const (
+	NeighborStatInterface = iota
+	NeighborStatIP
+	NeighborStatMAC
+	NeighborStatAge
+	NeighborStatState
+)
+
+// Mapped srv6 fields.
+const (
+	SRv6StatSID = iota
+	SRv6StatKind
+	SRv6StatPackets
+	SRv6StatBytes
+	SRv6StatBad
+	SRv6StatPacketRate
+	SRv6StatByteRate
+)
+
+// Mapped mpls fields.
+const (
+	MplsStatLabel = iota
+	MplsStatTable
+	MplsStatPackets
+	MplsStatBytes
+	MplsStatPacketRate
+	MplsStatByteRate
+)
+
+// Mapped api trace fields.
+const (
+	APITraceStatIndex = iota
+	APITraceStatName
+	APITraceStatDetails
+)
+
+// Mapped api clients fields.
+const (
+	APIClientStatName = iota
+	APIClientStatTransport
+	APIClientStatPID
+	APIClientStatFD
+	APIClientStatQueue
+	APIClientStatHealth
+)
+
+// Mapped capture fields.
+const (
+	CaptureStatKind = iota
+	CaptureStatState
+	CaptureStatInterface
+	CaptureStatPackets
+	CaptureStatFile
+	CaptureStatStatus
+)
+
+// Mapped info fields.
+const (
+	InfoStatName = iota
+	InfoStatVersion
+	InfoStatDescription
+)
+
+// Mapped diagnostics fields.
+const (
+	DiagnosticsStatName = iota
+	DiagnosticsStatLast
+	DiagnosticsStatAvg
+	DiagnosticsStatMax
+	DiagnosticsStatCount
+	DiagnosticsStatErrors
+	DiagnosticsStatSkipped
+)
+
 const (
 	MemoryStatName = iota
 	MemoryStatID
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// minPollInterval is the shortest poll interval set by the control socket.
+const minPollInterval = 100 * time.Millisecond
+
+// controlHelp is the reply to the help command.
+const controlHelp = "commands: dump <tab> [json], set interval [<tab>] <duration>, help"
+
+// SetControlSocket enables the control socket accepting commands of external
+// scripts while the application runs (disabled if the path is empty).
+func (app *App) SetControlSocket(path string) {
+	app.controlSocket = path
+}
+
+// runControl is a blocking call serving the commands sent to the control
+// socket until the context is cancelled. Every line of a connection is
+// a command, answered by a single line (prefixed by 'error: ' on failure).
+func (app *App) runControl(ctx context.Context, collectors []*collector) {
+	// a socket left by a crashed instance is replaced
+	if info, err := os.Stat(app.controlSocket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(app.controlSocket)
+	}
+	listener, err := net.Listen("unix", app.controlSocket)
+	if err != nil {
+		logrus.Errorf("error occured while listening on control socket %s: %v", app.controlSocket, err)
+		return
+	}
+	if err := os.Chmod(app.controlSocket, 0600); err != nil {
+		logrus.Warnf("failed to restrict access to control socket %s: %v", app.controlSocket, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				logrus.Errorf("error occured while accepting control connection: %v", err)
+			}
+			return
+		}
+		go app.serveControl(ctx, conn, collectors)
+	}
+}
+
+// serveControl answers the commands of a single connection until it is
+// closed by the client or the context is cancelled.
+func (app *App) serveControl(ctx context.Context, conn net.Conn, collectors []*collector) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		reply, err := app.controlCommand(ctx, strings.Fields(line), collectors)
+		if err != nil {
+			reply = "error: " + err.Error()
+		}
+		if _, err := fmt.Fprintln(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// controlCommand executes the command split to fields and returns the reply.
+func (app *App) controlCommand(ctx context.Context, fields []string, collectors []*collector) (string, error) {
+	switch fields[0] {
+	case "dump":
+		if len(fields) < 2 || len(fields) > 3 || (len(fields) == 3 && fields[2] != "json") {
+			return "", fmt.Errorf("usage: dump <tab> [json]")
+		}
+		tab, ok := httpEndpoints["/"+fields[1]]
+		if !ok {
+			return "", fmt.Errorf("unknown tab %q, one of: %s", fields[1], strings.Join(controlTabNames(), ", "))
+		}
+		entry, ok := app.cache.load(tab)
+		if !ok {
+			return "", fmt.Errorf("stats not collected yet")
+		}
+		data, err := json.Marshal(entry.data)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal %s stats: %v", tabNames[tab], err)
+		}
+		return string(data), nil
+	case "set":
+		if len(fields) < 3 || len(fields) > 4 || fields[1] != "interval" {
+			return "", fmt.Errorf("usage: set interval [<tab>] <duration>")
+		}
+		interval, err := time.ParseDuration(fields[len(fields)-1])
+		if err != nil {
+			return "", fmt.Errorf("invalid interval: %v", err)
+		}
+		if interval < minPollInterval {
+			return "", fmt.Errorf("invalid interval: %v, at least %v", interval, minPollInterval)
+		}
+		tab, name := -1, "all tabs"
+		if len(fields) == 4 {
+			var ok bool
+			if tab, ok = httpEndpoints["/"+fields[2]]; !ok {
+				return "", fmt.Errorf("unknown tab %q, one of: %s", fields[2], strings.Join(controlTabNames(), ", "))
+			}
+			name = tabNames[tab]
+		}
+		for _, c := range collectors {
+			if tab >= 0 && c.tab != tab {
+				continue
+			}
+			select {
+			case c.reset <- interval:
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+		logrus.Infof("poll interval of %s set to %v by the control socket", name, interval)
+		return "ok", nil
+	case "help":
+		return controlHelp, nil
+	}
+	return "", fmt.Errorf("unknown command %q, see help", fields[0])
+}
+
+// controlTabNames returns the sorted names of the tabs served by the control socket.
+func controlTabNames() []string {
+	names := make([]string, 0, len(httpEndpoints))
+	for path := range httpEndpoints {
+		names = append(names, strings.TrimPrefix(path, "/"))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func TestControlCommand(t *testing.T) {
+	app := &App{cache: newDataCache()}
+	app.cache.store(Memory, []string{"main heap"}, 0)
+	collectors := []*collector{
+		{tab: Interfaces, reset: make(chan time.Duration, 1)},
+		{tab: Memory, reset: make(chan time.Duration, 1)},
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		command string
+		want    string
+		err     string
+	}{
+		{command: "dump memory json", want: `["main heap"]`},
+		{command: "dump memory", want: `["main heap"]`},
+		{command: "dump nodes", err: "stats not collected yet"},
+		{command: "dump foo", err: `unknown tab "foo"`},
+		{command: "dump memory yaml", err: "usage: dump"},
+		{command: "set interval memory 5s", want: "ok"},
+		{command: "set interval 50ms", err: "invalid interval"},
+		{command: "set interval memory soon", err: "invalid interval"},
+		{command: "frobnicate", err: `unknown command "frobnicate"`},
+	}
+	for _, test := range tests {
+		got, err := app.controlCommand(ctx, strings.Fields(test.command), collectors)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Error occured %q got error:%v; want:%q", test.command, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("Error occured %q got:%q (%v); want:%q", test.command, got, err, test.want)
+		}
+	}
+
+	select {
+	case interval := <-collectors[1].reset:
+		if interval != 5*time.Second {
+			t.Errorf("Error occured got interval:%v; want:%v", interval, 5*time.Second)
+		}
+	default:
+		t.Errorf("Error occured the memory collector was not reset")
+	}
+	if len(collectors[0].reset) != 0 {
+		t.Errorf("Error occured the interfaces collector was reset")
+	}
+}
+
+func TestServeControl(t *testing.T) {
+	app := &App{cache: newDataCache()}
+	app.cache.store(Interfaces, []api.Interface{}, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server, conn := net.Pipe()
+	go app.serveControl(ctx, server, nil)
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for _, step := range []struct{ command, want string }{
+		{command: "dump interfaces json", want: "[]\n"},
+		{command: "set interval", want: "error: usage: set interval [<tab>] <duration>\n"},
+	} {
+		if _, err := fmt.Fprintln(conn, step.command); err != nil {
+			t.Fatalf("Error occured writing %q: %v", step.command, err)
+		}
+		got, err := reader.ReadString('\n')
+		if err != nil || got != step.want {
+			t.Errorf("Error occured %q got:%q (%v); want:%q", step.command, got, err, step.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// interfaceNodeSuffixes are the suffixes of the nodes of an interface
+// (e.g. GigabitEthernet0/8/0-tx), their error counters are listed in
+// the error details of the interface.
+var interfaceNodeSuffixes = []string{"-tx", "-output"}
+
+// showInterfaceDetails shows the error details of the interface selected
+// at the interfaces tab in a popup.
+func (app *App) showInterfaceDetails(key string) {
+	entry, ok := app.viewEntry(Interfaces)
+	if !ok {
+		return
+	}
+	ifaces, _ := entry.data.([]api.Interface)
+	for _, iface := range ifaces {
+		if interfaceKey(iface) != key {
+			continue
+		}
+		var errors []api.Error
+		// error counters are polled from the connected VPP only, interfaces
+		// of other instances have unknown state
+		if iface.State != "" {
+			if entry, ok := app.viewEntry(Errors); ok {
+				errors, _ = entry.data.([]api.Error)
+			}
+		}
+		title := i18n.T("Errors: %s (Esc to close)", key)
+		app.gui.ShowPopup(title, interfaceErrorDetails(iface, errors, app.unitFormat()))
+		return
+	}
+}
+
+// interfaceErrorDetails returns the rows of the error details of the interface:
+// the error counters per direction, the counters of each worker thread (read
+// from the /if stats, summed up over the queues served by the thread), the
+// rings of a memif interface and the /err counters of the interface nodes.
+func interfaceErrorDetails(iface api.Interface, errors []api.Error, units unitFormat) []string {
+	var rows []string
+	if iface.LLDP != nil {
+		rows = append(rows, formatLLDPPeer(*iface.LLDP), "")
+	}
+	rows = append(rows,
+		fmt.Sprintf("Rx: rx-error %s, rx-miss %s, rx-no-buf %s, drops %s, punts %s",
+			units.count(iface.RxErrors), units.count(iface.RxMiss), units.count(iface.RxNoBuf),
+			units.count(iface.Drops), units.count(iface.Punts)),
+		fmt.Sprintf("Tx: tx-error %s", units.count(iface.TxErrors)),
+		"",
+	)
+
+	if len(iface.Workers) == 0 {
+		rows = append(rows, i18n.T("per thread counters are not available"))
+	} else {
+		// VPP counts the interfaces per thread, not per queue
+		rows = append(rows, i18n.T("per thread counters (summed up over the queues of the thread):"))
+		table := [][]string{{"Thread", "Rx", "rx-error", "rx-miss", "rx-no-buf", "Tx", "tx-error"}}
+		for _, q := range iface.Workers {
+			table = append(table, []string{
+				fmt.Sprintf("worker %d", q.Worker),
+				units.count(q.Rx.Packets),
+				units.count(q.RxErrors),
+				units.count(q.RxMiss),
+				units.count(q.RxNoBuf),
+				units.count(q.Tx.Packets),
+				units.count(q.TxErrors),
+			})
+		}
+		rows = append(rows, alignColumns(table, 1)...)
+	}
+	rows = append(rows, "")
+	if len(iface.MemifRings) != 0 {
+		rows = append(rows, memifRingDetails(iface.MemifRings, units)...)
+		rows = append(rows, "")
+	}
+
+	var nodeErrors []api.Error
+	for _, e := range errors {
+		for _, suffix := range interfaceNodeSuffixes {
+			if e.Node == iface.InterfaceName+suffix {
+				nodeErrors = append(nodeErrors, e)
+				break
+			}
+		}
+	}
+	if len(nodeErrors) == 0 {
+		return append(rows, i18n.T("no error counters of the interface nodes"))
+	}
+	sort.Slice(nodeErrors, func(i, j int) bool {
+		if nodeErrors[i].Node != nodeErrors[j].Node {
+			return nodeErrors[i].Node < nodeErrors[j].Node
+		}
+		return nodeErrors[i].Reason < nodeErrors[j].Reason
+	})
+	table := [][]string{i18n.Slice([]string{"Node", "Reason", "Count"})}
+	for _, e := range nodeErrors {
+		table = append(table, []string{e.Node, e.Reason, units.count(e.Count)})
+	}
+	return append(rows, alignColumns(table, 2)...)
+}
+
+// memifRingDetails returns the rows of the rings of a memif interface, the
+// occupancy is the share of the ring filled by the producer and not consumed
+// yet, a full ring means the consumer does not keep up (e.g. a container app
+// on the slave side of the master-to-slave ring).
+func memifRingDetails(rings []api.MemifRing, units unitFormat) []string {
+	table := [][]string{i18n.Slice([]string{"Ring", "Size", "Head", "Tail", "Occupancy", "Full polls"})}
+	for _, ring := range rings {
+		occupancy := fmt.Sprintf("%d", ring.Occupancy())
+		if ring.Size != 0 {
+			occupancy = fmt.Sprintf("%d (%d%%)", ring.Occupancy(), ring.Occupancy()*100/ring.Size)
+		}
+		if ring.Full() {
+			occupancy = i18n.T("%s full", occupancy)
+		}
+		table = append(table, []string{
+			fmt.Sprintf("%s %d", ring.Direction, ring.ID),
+			fmt.Sprintf("%d", ring.Size),
+			fmt.Sprintf("%d", ring.Head),
+			fmt.Sprintf("%d", ring.Tail),
+			occupancy,
+			units.count(ring.FullPolls),
+		})
+	}
+	return alignColumns(table, 1)
+}
+
+// formatLLDPPeer formats the switch port attached to the interface learned by the LLDP.
+func formatLLDPPeer(peer api.LLDPNeighbor) string {
+	status := "inactive"
+	if peer.Active {
+		status = "active"
+	}
+	if peer.LastHeard < 0 {
+		return i18n.T("Peer: never heard (%s)", status)
+	}
+	return i18n.T("Peer: %s port %s (%s, heard %.1fs ago)", peer.ChassisID, peer.PortID, status, peer.LastHeard)
+}
+
+// alignColumns joins the cells of each row, padding the cells to the width
+// of the widest cell of the column. The first textCols columns are aligned
+// to the left, the other columns are counters aligned to the right.
+func alignColumns(table [][]string, textCols int) []string {
+	var widths []int
+	for _, row := range table {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+	rows := make([]string, 0, len(table))
+	for _, row := range table {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			if i < textCols {
+				cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+			} else {
+				cells[i] = fmt.Sprintf("%*s", widths[i], cell)
+			}
+		}
+		rows = append(rows, strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+	return rows
+}
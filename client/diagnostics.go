@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"go.pantheon.tech/vpptop/gui/views"
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Outcomes of a poll recorded by the self monitor.
+const (
+	pollSucceeded = iota
+	pollFailed
+	// the poll timed out, or was skipped as the previous one still runs
+	pollSkipped
+)
+
+// pollStats are the durations and the outcomes of the polls of a tab.
+type pollStats struct {
+	Tab     string
+	Polls   uint64
+	Errors  uint64
+	Skipped uint64
+	Last    time.Duration
+	Avg     time.Duration
+	Max     time.Duration
+	// total duration of the polls the average is computed from
+	total time.Duration
+}
+
+// selfStats is the resource footprint of vpptop itself, shown by the
+// diagnostics tab to report performance problems or find slow handler calls.
+type selfStats struct {
+	HeapAlloc   uint64
+	HeapSys     uint64
+	Sys         uint64
+	Goroutines  int
+	GCCount     uint32
+	GCLastPause time.Duration
+	GCAvgPause  time.Duration
+	GCMaxPause  time.Duration
+	Polls       []pollStats
+	// durations of the handler requests made by the polls
+	Requests []api.RequestStats
+}
+
+// selfMonitor records the durations of the polls of all tabs.
+type selfMonitor struct {
+	sync.Mutex
+	// set if the diagnostics tab is shown
+	enabled bool
+	polls   map[int]*pollStats
+}
+
+// newSelfMonitor returns an empty instance of <*selfMonitor>
+func newSelfMonitor() *selfMonitor {
+	return &selfMonitor{
+		polls: make(map[int]*pollStats),
+	}
+}
+
+// EnableDiagnostics adds the diagnostics tab showing the Go runtime stats
+// of vpptop, the durations of the polls of all tabs and of the handler
+// requests made by them. It has to be called before the app is initialized.
+func (app *App) EnableDiagnostics() {
+	app.self.Lock()
+	app.self.enabled = true
+	app.self.Unlock()
+	columns := []string{"Name", "Last", "Avg", "Max", "Count", "Errors", "Skipped"}
+	app.gui.AddTab(i18n.T(tabNames[Diagnostics]), views.NewTableView(
+		[]string{},
+		xtui.TableRows{i18n.Slice(columns)},
+		DiagnosticsStatName,
+		1,
+		[]int{30, 12, 12, 12, 10, 10, views.Resize},
+	))
+}
+
+// hasTab returns true if the tab is shown by the gui, the diagnostics
+// tab is optional.
+func (app *App) hasTab(tab int) bool {
+	if tab != Diagnostics {
+		return true
+	}
+	app.self.Lock()
+	defer app.self.Unlock()
+	return app.self.enabled
+}
+
+// record adds the duration and the outcome of a poll of the tab.
+func (m *selfMonitor) record(tab int, duration time.Duration, outcome int) {
+	m.Lock()
+	defer m.Unlock()
+	stats, ok := m.polls[tab]
+	if !ok {
+		stats = &pollStats{Tab: tabNames[tab]}
+		m.polls[tab] = stats
+	}
+	switch outcome {
+	case pollFailed:
+		stats.Errors++
+	case pollSkipped:
+		stats.Skipped++
+		return
+	}
+	stats.Polls++
+	stats.Last = duration
+	stats.total += duration
+	stats.Avg = stats.total / time.Duration(stats.Polls)
+	if duration > stats.Max {
+		stats.Max = duration
+	}
+}
+
+// snapshot returns the current Go runtime stats and the poll stats of all
+// tabs ordered by the tabs.
+func (m *selfMonitor) snapshot() *selfStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := &selfStats{
+		HeapAlloc:  mem.HeapAlloc,
+		HeapSys:    mem.HeapSys,
+		Sys:        mem.Sys,
+		Goroutines: runtime.NumGoroutine(),
+		GCCount:    mem.NumGC,
+	}
+	if mem.NumGC > 0 {
+		stats.GCLastPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+		stats.GCAvgPause = time.Duration(mem.PauseTotalNs / uint64(mem.NumGC))
+		// the pauses of the last 256 collections are kept
+		for _, pause := range mem.PauseNs {
+			if time.Duration(pause) > stats.GCMaxPause {
+				stats.GCMaxPause = time.Duration(pause)
+			}
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	tabs := make([]int, 0, len(m.polls))
+	for tab := range m.polls {
+		tabs = append(tabs, tab)
+	}
+	sort.Ints(tabs)
+	for _, tab := range tabs {
+		stats.Polls = append(stats.Polls, *m.polls[tab])
+	}
+	return stats
+}
+
+// formatDiagnostics formats the stats of vpptop itself to xtui.TableRows.
+func (app *App) formatDiagnostics(stats *selfStats) xtui.TableRows {
+	bytes := func(value uint64) string {
+		return scaleUnits(value, 1024, iecSuffixes)
+	}
+	rows := xtui.TableRows{
+		{i18n.T("Heap allocated"), bytes(stats.HeapAlloc)},
+		{i18n.T("Heap reserved"), bytes(stats.HeapSys)},
+		{i18n.T("Memory obtained from the OS"), bytes(stats.Sys)},
+		{i18n.T("Goroutines"), fmt.Sprint(stats.Goroutines)},
+		{i18n.T("GC pause"), formatPollDuration(stats.GCLastPause), formatPollDuration(stats.GCAvgPause),
+			formatPollDuration(stats.GCMaxPause), fmt.Sprint(stats.GCCount)},
+	}
+	for _, poll := range stats.Polls {
+		rows = append(rows, []string{
+			i18n.T("poll: %s", i18n.T(poll.Tab)),
+			formatPollDuration(poll.Last),
+			formatPollDuration(poll.Avg),
+			formatPollDuration(poll.Max),
+			fmt.Sprint(poll.Polls),
+			fmt.Sprint(poll.Errors),
+			fmt.Sprint(poll.Skipped),
+		})
+	}
+	for _, request := range stats.Requests {
+		rows = append(rows, []string{
+			i18n.T("request: %s", request.Request),
+			formatPollDuration(request.Last),
+			formatPollDuration(request.Avg),
+			formatPollDuration(request.Max),
+			fmt.Sprint(request.Calls),
+			fmt.Sprint(request.Errors),
+			"",
+		})
+	}
+	return rows
+}
+
+// formatPollDuration formats the duration rounded to a precision
+// suitable for the polls and GC pauses.
+func formatPollDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
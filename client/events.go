@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// DefaultEventsLimit is the default number of the interface events kept.
+const DefaultEventsLimit = 100
+
+// Recent interface events shown by the ticker of the interfaces tab.
+const (
+	tickerEvents = 5
+	tickerWindow = 5 * time.Minute
+)
+
+// ifaceEvent is a change of the interface details detected between polls.
+type ifaceEvent struct {
+	at   time.Time
+	text string
+}
+
+// ifaceDetails are the interface details compared between polls.
+type ifaceDetails struct {
+	state string
+	link  string
+	mtu   string
+	ips   []string
+}
+
+// linkChange is the last change of the link state of an interface.
+type linkChange struct {
+	at time.Time
+	// unset if the link state did not change since the interface
+	// was polled first, the change happened before
+	observed bool
+}
+
+// ifaceEvents detects changes of the interface addresses, MTU, admin and
+// link state between polls, and keeps the last events up to the limit.
+type ifaceEvents struct {
+	sync.Mutex
+	limit int
+	// events ordered from the oldest one
+	events []ifaceEvent
+	// details of the interfaces from the last poll by the interface keys
+	details map[string]ifaceDetails
+	// last link state changes by the interface keys
+	links map[string]linkChange
+	// time of the last poll compared
+	last time.Time
+}
+
+// newIfaceEvents returns an empty instance of <*ifaceEvents>
+func newIfaceEvents(limit int) *ifaceEvents {
+	return &ifaceEvents{
+		limit: limit,
+	}
+}
+
+// SetEventsLimit sets the number of the interface events kept.
+func (app *App) SetEventsLimit(limit int) {
+	app.events.Lock()
+	app.events.limit = limit
+	app.events.trim()
+	app.events.Unlock()
+}
+
+// update compares the polled interfaces to the previous poll. Interfaces
+// which were added or removed since are not compared.
+func (e *ifaceEvents) update(entry cacheEntry) {
+	ifaces, _ := entry.data.([]api.Interface)
+
+	e.Lock()
+	defer e.Unlock()
+	if !entry.polledAt.After(e.last) {
+		// the poll was already compared
+		return
+	}
+	e.last = entry.polledAt
+	details := make(map[string]ifaceDetails, len(ifaces))
+	links := make(map[string]linkChange, len(ifaces))
+	for _, iface := range ifaces {
+		key := interfaceKey(iface)
+		if iface.NoDetails {
+			// the state is not known, the last known details are kept
+			if prev, ok := e.details[key]; ok {
+				details[key], links[key] = prev, e.links[key]
+			}
+			continue
+		}
+		curr := ifaceDetails{
+			state: iface.State,
+			link:  iface.LinkState,
+			mtu:   formatMTU(iface.MTU),
+			ips:   iface.IPAddresses,
+		}
+		details[key] = curr
+		prev, ok := e.details[key]
+		if !ok {
+			links[key] = linkChange{at: entry.polledAt}
+			continue
+		}
+		links[key] = e.links[key]
+		if prev.link != curr.link {
+			links[key] = linkChange{at: entry.polledAt, observed: true}
+		}
+		for _, text := range ifaceChanges(key, prev, curr) {
+			logrus.Infof("interface event: %s", text)
+			e.events = append(e.events, ifaceEvent{at: entry.polledAt, text: text})
+		}
+	}
+	e.details = details
+	e.links = links
+	e.trim()
+}
+
+// linkChanges returns the last link state changes by the interface keys.
+func (e *ifaceEvents) linkChanges() map[string]linkChange {
+	e.Lock()
+	defer e.Unlock()
+	links := make(map[string]linkChange, len(e.links))
+	for key, change := range e.links {
+		links[key] = change
+	}
+	return links
+}
+
+// formatLinkChange formats the time elapsed since the last link state
+// change, prefixed by '>' if the change was not observed.
+func formatLinkChange(change linkChange, now time.Time) string {
+	if change.at.IsZero() {
+		return "-"
+	}
+	since := formatSince(now.Sub(change.at))
+	if !change.observed {
+		return ">" + since
+	}
+	return since
+}
+
+// formatSince formats the duration compactly in the two largest units,
+// e.g. 42s, 5m10s, 3h12m or 2d5h.
+func formatSince(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	secs := int64(d / time.Second)
+	days, hours, mins := secs/86400, secs/3600%24, secs/60%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, mins)
+	case mins > 0:
+		return fmt.Sprintf("%dm%ds", mins, secs%60)
+	}
+	return fmt.Sprintf("%ds", secs)
+}
+
+// trim drops the oldest events over the limit.
+func (e *ifaceEvents) trim() {
+	if over := len(e.events) - e.limit; over > 0 {
+		e.events = append([]ifaceEvent(nil), e.events[over:]...)
+	}
+}
+
+// ifaceChanges returns the changes of the interface details.
+func ifaceChanges(name string, prev, curr ifaceDetails) []string {
+	var changes []string
+	if prev.state != curr.state {
+		changes = append(changes, i18n.T("%s: state %s → %s", name, prev.state, curr.state))
+	}
+	if prev.link != curr.link {
+		changes = append(changes, i18n.T("%s: link %s → %s", name, prev.link, curr.link))
+	}
+	if prev.mtu != curr.mtu {
+		changes = append(changes, i18n.T("%s: MTU %s → %s", name, prev.mtu, curr.mtu))
+	}
+	for _, ip := range missingAddresses(curr.ips, prev.ips) {
+		changes = append(changes, i18n.T("%s: address %s added", name, ip))
+	}
+	for _, ip := range missingAddresses(prev.ips, curr.ips) {
+		changes = append(changes, i18n.T("%s: address %s removed", name, ip))
+	}
+	return changes
+}
+
+// missingAddresses returns the addresses which are missing in the other list.
+func missingAddresses(ips, other []string) []string {
+	var missing []string
+	for _, ip := range ips {
+		found := false
+		for _, o := range other {
+			if o == ip {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, ip)
+		}
+	}
+	return missing
+}
+
+// formatMTU formats the MTU of the interface as L3/IP4/IP6/MPLS.
+func formatMTU(mtu []uint32) string {
+	values := make([]string, len(mtu))
+	for i, value := range mtu {
+		values[i] = fmt.Sprint(value)
+	}
+	return strings.Join(values, "/")
+}
+
+// ticker returns the recent events from the newest one, shown
+// by the ticker of the interfaces tab.
+func (e *ifaceEvents) ticker() string {
+	e.Lock()
+	defer e.Unlock()
+	since := time.Now().Add(-tickerWindow)
+	var texts []string
+	for i := len(e.events) - 1; i >= 0 && len(texts) < tickerEvents; i-- {
+		if e.events[i].at.Before(since) {
+			break
+		}
+		texts = append(texts, e.events[i].at.Format("15:04:05")+" "+e.events[i].text)
+	}
+	return strings.Join(texts, " · ")
+}
+
+// rows returns all kept events from the newest one.
+func (e *ifaceEvents) rows() []string {
+	e.Lock()
+	defer e.Unlock()
+	rows := make([]string, 0, len(e.events))
+	for i := len(e.events) - 1; i >= 0; i-- {
+		rows = append(rows, e.events[i].at.Format("2006-01-02 15:04:05")+"  "+e.events[i].text)
+	}
+	if len(rows) == 0 {
+		rows = append(rows, i18n.T("no interface events"))
+	}
+	return rows
+}
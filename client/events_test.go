@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func TestIfaceEvents(t *testing.T) {
+	iface := func(name, state string, mtu uint32, ips ...string) api.Interface {
+		iface := api.Interface{State: state, MTU: []uint32{mtu, 0, 0, 0}, IPAddresses: ips}
+		iface.InterfaceName = name
+		return iface
+	}
+	events := newIfaceEvents(3)
+	start := time.Now()
+	polls := [][]api.Interface{
+		{iface("loop0", "up", 1500, "10.0.0.1/24"), iface("tap0", "up", 1500)},
+		{iface("loop0", "down", 1500, "10.0.0.1/24"), iface("tap0", "up", 9000)},
+		{iface("loop0", "down", 1500, "10.0.0.2/24"), iface("tap1", "up", 1500)},
+	}
+	for i, ifaces := range polls {
+		events.update(cacheEntry{data: ifaces, polledAt: start.Add(time.Duration(i) * time.Second)})
+	}
+
+	var got []string
+	for _, event := range events.events {
+		got = append(got, event.text)
+	}
+	want := []string{
+		"tap0: MTU 1500/0/0/0 → 9000/0/0/0",
+		"loop0: address 10.0.0.2/24 added",
+		"loop0: address 10.0.0.1/24 removed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Error occured got:%q; want:%q", got, want)
+	}
+
+	if rows := events.rows(); len(rows) != 3 || !strings.HasSuffix(rows[0], want[2]) {
+		t.Errorf("Error occured rows got:%q; want the newest event first", rows)
+	}
+}
+
+func TestIfaceEvents_linkChanges(t *testing.T) {
+	iface := func(name, link string) api.Interface {
+		iface := api.Interface{State: "up", LinkState: link, MTU: []uint32{1500, 0, 0, 0}}
+		iface.InterfaceName = name
+		return iface
+	}
+	events := newIfaceEvents(10)
+	start := time.Now()
+	polls := [][]api.Interface{
+		{iface("tap0", "up"), iface("tap1", "up")},
+		{iface("tap0", "down"), iface("tap1", "up")},
+		{iface("tap0", "down"), iface("tap1", "up")},
+	}
+	for i, ifaces := range polls {
+		events.update(cacheEntry{data: ifaces, polledAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	now := start.Add(5 * time.Minute)
+	links := events.linkChanges()
+	if got, want := formatLinkChange(links["tap0"], now), "4m0s"; got != want {
+		t.Errorf("Error occured tap0 got:%q; want:%q", got, want)
+	}
+	if got, want := formatLinkChange(links["tap1"], now), ">5m0s"; got != want {
+		t.Errorf("Error occured tap1 got:%q; want:%q", got, want)
+	}
+	if len(events.events) != 1 || events.events[0].text != "tap0: link up → down" {
+		t.Errorf("Error occured events got:%v; want the link change of tap0", events.events)
+	}
+}
+
+func TestFormatSince(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 42 * time.Second, want: "42s"},
+		{d: 5*time.Minute + 10*time.Second, want: "5m10s"},
+		{d: 3*time.Hour + 12*time.Minute + 5*time.Second, want: "3h12m"},
+		{d: 53 * time.Hour, want: "2d5h"},
+		{d: -time.Second, want: "0s"},
+	}
+	for _, test := range tests {
+		if got := formatSince(test.d); got != test.want {
+			t.Errorf("Error occured got:%q; want:%q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"go.pantheon.tech/vpptop/gui"
+)
+
+// setPaused freezes the data rendered at the tabs to the data cached at the
+// time of the pause, or resumes rendering of the polled data. The collectors
+// keep polling while paused (alerts, the HTTP server and exports are not paused).
+func (app *App) setPaused(event gui.Event) {
+	app.tabLock.Lock()
+	if event.Payload.(bool) {
+		app.paused = app.cache.snapshot()
+	} else {
+		app.paused = nil
+	}
+	app.tabLock.Unlock()
+	app.unfreezeMeasurement()
+}
+
+// viewEntry returns the cache entry rendered at the tab, which is
+// the entry cached at the time of the pause while paused.
+func (app *App) viewEntry(tab int) (cacheEntry, bool) {
+	app.tabLock.Lock()
+	paused := app.paused
+	app.tabLock.Unlock()
+	if paused != nil {
+		entry, ok := paused[tab]
+		return entry, ok
+	}
+	return app.cache.load(tab)
+}
+
+// exportTab writes the data rendered at the tab as JSON to the file
+// 'vpptop-<tab>-<time>.json' in the working directory. The file is returned.
+func (app *App) exportTab(tab int) (string, error) {
+	entry, ok := app.viewEntry(tab)
+	if !ok {
+		return "", fmt.Errorf("no data of the %s tab", tabNames[tab])
+	}
+	data, err := json.MarshalIndent(entry.data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s stats: %v", tabNames[tab], err)
+	}
+	file := fmt.Sprintf("vpptop-%s-%s.json", tabFileName(tab), time.Now().Format("20060102-150405"))
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+// tabFileName returns the name of the tab used in file names, which is
+// the HTTP endpoint of the tab if it has one.
+func tabFileName(tab int) string {
+	for path, endpointTab := range httpEndpoints {
+		if endpointTab == tab {
+			return strings.TrimPrefix(path, "/")
+		}
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "", "/", "").Replace(tabNames[tab]))
+}
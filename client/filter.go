@@ -0,0 +1,363 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// filterField returns the value of a field of the stats item,
+// the value is either a string or a number (float64).
+type filterField func(item interface{}) interface{}
+
+// filterFields is the registry of fields usable in filter expressions per tab.
+var filterFields = map[int]map[string]filterField{
+	Interfaces: {
+		"name":      func(i interface{}) interface{} { return i.(api.Interface).InterfaceName },
+		"instance":  func(i interface{}) interface{} { return i.(api.Interface).Instance },
+		"index":     func(i interface{}) interface{} { return float64(i.(api.Interface).InterfaceIndex) },
+		"state":     func(i interface{}) interface{} { return i.(api.Interface).State },
+		"link":      func(i interface{}) interface{} { return i.(api.Interface).LinkState },
+		"ip":        func(i interface{}) interface{} { return strings.Join(i.(api.Interface).IPAddresses, " ") },
+		"vrf":       func(i interface{}) interface{} { return float64(i.(api.Interface).VrfIPv4) },
+		"vrf6":      func(i interface{}) interface{} { return float64(i.(api.Interface).VrfIPv6) },
+		"rxpackets": func(i interface{}) interface{} { return float64(i.(api.Interface).Rx.Packets) },
+		"rxbytes":   func(i interface{}) interface{} { return float64(i.(api.Interface).Rx.Bytes) },
+		"rxerrors":  func(i interface{}) interface{} { return float64(i.(api.Interface).RxErrors) },
+		"rxnobuf":   func(i interface{}) interface{} { return float64(i.(api.Interface).RxNoBuf) },
+		"rxmiss":    func(i interface{}) interface{} { return float64(i.(api.Interface).RxMiss) },
+		"txpackets": func(i interface{}) interface{} { return float64(i.(api.Interface).Tx.Packets) },
+		"txbytes":   func(i interface{}) interface{} { return float64(i.(api.Interface).Tx.Bytes) },
+		"txerrors":  func(i interface{}) interface{} { return float64(i.(api.Interface).TxErrors) },
+		"drops":     func(i interface{}) interface{} { return float64(i.(api.Interface).Drops) },
+		"punts":     func(i interface{}) interface{} { return float64(i.(api.Interface).Punts) },
+		"ip4":       func(i interface{}) interface{} { return float64(i.(api.Interface).IP4) },
+		"ip6":       func(i interface{}) interface{} { return float64(i.(api.Interface).IP6) },
+		"mac":       func(i interface{}) interface{} { return i.(api.Interface).Device.MAC },
+		"devtype":   func(i interface{}) interface{} { return i.(api.Interface).Device.Type },
+		"speed":     func(i interface{}) interface{} { return float64(i.(api.Interface).Device.LinkSpeed) * 1000 },
+		"duplex":    func(i interface{}) interface{} { return i.(api.Interface).Device.LinkDuplex },
+	},
+	Nodes: {
+		"name":      func(i interface{}) interface{} { return i.(api.Node).Name },
+		"state":     func(i interface{}) interface{} { return i.(api.Node).State },
+		"calls":     func(i interface{}) interface{} { return float64(i.(api.Node).Calls) },
+		"vectors":   func(i interface{}) interface{} { return float64(i.(api.Node).Vectors) },
+		"suspends":  func(i interface{}) interface{} { return float64(i.(api.Node).Suspends) },
+		"clocks":    func(i interface{}) interface{} { return i.(api.Node).Clocks },
+		"vpc":       func(i interface{}) interface{} { return i.(api.Node).VectorsPerCall },
+		"maxclocks": func(i interface{}) interface{} { return i.(api.Node).MaxClocks },
+		"clockspct": func(i interface{}) interface{} { return i.(api.Node).ClocksPercent },
+	},
+	Errors: {
+		"count":    func(i interface{}) interface{} { return float64(i.(api.Error).Count) },
+		"node":     func(i interface{}) interface{} { return i.(api.Error).Node },
+		"reason":   func(i interface{}) interface{} { return i.(api.Error).Reason },
+		"severity": func(i interface{}) interface{} { return i.(api.Error).Severity },
+	},
+	DropsPunts: {
+		"type":   func(i interface{}) interface{} { return i.(api.DropPunt).Type },
+		"node":   func(i interface{}) interface{} { return i.(api.DropPunt).Node },
+		"reason": func(i interface{}) interface{} { return i.(api.DropPunt).Reason },
+		"count":  func(i interface{}) interface{} { return float64(i.(api.DropPunt).Count) },
+	},
+	Tunnels: {
+		"name":      func(i interface{}) interface{} { return i.(api.TunnelCounters).InterfaceName },
+		"type":      func(i interface{}) interface{} { return i.(api.TunnelCounters).Type },
+		"src":       func(i interface{}) interface{} { return i.(api.TunnelCounters).Src },
+		"dst":       func(i interface{}) interface{} { return i.(api.TunnelCounters).Dst },
+		"id":        func(i interface{}) interface{} { return float64(i.(api.TunnelCounters).ID) },
+		"rxpackets": func(i interface{}) interface{} { return float64(i.(api.TunnelCounters).Rx.Packets) },
+		"rxbytes":   func(i interface{}) interface{} { return float64(i.(api.TunnelCounters).Rx.Bytes) },
+		"txpackets": func(i interface{}) interface{} { return float64(i.(api.TunnelCounters).Tx.Packets) },
+		"txbytes":   func(i interface{}) interface{} { return float64(i.(api.TunnelCounters).Tx.Bytes) },
+	},
+	Sessions: {
+		"namespace": func(i interface{}) interface{} { return i.(api.SessionStat).Namespace },
+		"protocol":  func(i interface{}) interface{} { return i.(api.SessionStat).Protocol },
+		"state":     func(i interface{}) interface{} { return i.(api.SessionStat).State },
+		"count":     func(i interface{}) interface{} { return float64(i.(api.SessionStat).Count) },
+	},
+	Features: {
+		"interface": func(i interface{}) interface{} { return i.(api.FeatureArc).Interface },
+		"arc":       func(i interface{}) interface{} { return i.(api.FeatureArc).Arc },
+		"feature":   func(i interface{}) interface{} { return strings.Join(i.(api.FeatureArc).Features, " ") },
+	},
+	Bonds: {
+		"bond":      func(i interface{}) interface{} { return i.(api.BondMember).Bond },
+		"mode":      func(i interface{}) interface{} { return i.(api.BondMember).Mode },
+		"member":    func(i interface{}) interface{} { return i.(api.BondMember).Member },
+		"active":    func(i interface{}) interface{} { return fmt.Sprint(i.(api.BondMember).Active) },
+		"mux":       func(i interface{}) interface{} { return i.(api.BondMember).LACP.MuxState },
+		"rxpackets": func(i interface{}) interface{} { return float64(i.(api.BondMember).Rx.Packets) },
+		"txpackets": func(i interface{}) interface{} { return float64(i.(api.BondMember).Tx.Packets) },
+	},
+	Policers: {
+		"name":    func(i interface{}) interface{} { return i.(api.Policer).Name },
+		"type":    func(i interface{}) interface{} { return i.(api.Policer).Type },
+		"cir":     func(i interface{}) interface{} { return float64(i.(api.Policer).CIR) },
+		"eir":     func(i interface{}) interface{} { return float64(i.(api.Policer).EIR) },
+		"conform": func(i interface{}) interface{} { return float64(i.(api.Policer).Conform.Packets) },
+		"exceed":  func(i interface{}) interface{} { return float64(i.(api.Policer).Exceed.Packets) },
+		"violate": func(i interface{}) interface{} { return float64(i.(api.Policer).Violate.Packets) },
+	},
+	Fib: {
+		"vrf":        func(i interface{}) interface{} { return float64(i.(api.FibTable).TableID) },
+		"name":       func(i interface{}) interface{} { return i.(api.FibTable).Name },
+		"af":         func(i interface{}) interface{} { return fibAF(i.(api.FibTable)) },
+		"routes":     func(i interface{}) interface{} { return float64(i.(api.FibTable).Routes) },
+		"hostroutes": func(i interface{}) interface{} { return float64(i.(api.FibTable).HostRoutes) },
+	},
+	Neighbors: {
+		"interface": func(i interface{}) interface{} { return i.(api.Neighbor).Interface },
+		"ip":        func(i interface{}) interface{} { return i.(api.Neighbor).IP },
+		"mac":       func(i interface{}) interface{} { return i.(api.Neighbor).MAC },
+		"age":       func(i interface{}) interface{} { return i.(api.Neighbor).Age },
+		"state":     func(i interface{}) interface{} { return neighborState(i.(api.Neighbor)) },
+	},
+	SRv6: {
+		"sid":      func(i interface{}) interface{} { return i.(api.SRv6SID).SID },
+		"kind":     func(i interface{}) interface{} { return srv6Kind(i.(api.SRv6SID)) },
+		"behavior": func(i interface{}) interface{} { return i.(api.SRv6SID).Behavior },
+		"segment":  func(i interface{}) interface{} { return formatSegmentLists(i.(api.SRv6SID).SegmentLists) },
+		"steering": func(i interface{}) interface{} { return strings.Join(i.(api.SRv6SID).Steering, " ") },
+		"packets":  func(i interface{}) interface{} { return float64(i.(api.SRv6SID).Good.Packets) },
+		"bytes":    func(i interface{}) interface{} { return float64(i.(api.SRv6SID).Good.Bytes) },
+		"bad":      func(i interface{}) interface{} { return float64(i.(api.SRv6SID).Bad.Packets) },
+	},
+	Mpls: {
+		"label":   func(i interface{}) interface{} { return mplsLabel(i.(api.MplsEntry)) },
+		"table":   func(i interface{}) interface{} { return float64(i.(api.MplsEntry).TableID) },
+		"nexthop": func(i interface{}) interface{} { return strings.Join(i.(api.MplsEntry).NextHops, " ") },
+		"out":     func(i interface{}) interface{} { return formatLabels(i.(api.MplsEntry).OutLabels) },
+		"packets": func(i interface{}) interface{} { return float64(i.(api.MplsEntry).Counter.Packets) },
+		"bytes":   func(i interface{}) interface{} { return float64(i.(api.MplsEntry).Counter.Bytes) },
+	},
+	APIClients: {
+		"name":      func(i interface{}) interface{} { return i.(api.APIClient).Name },
+		"transport": func(i interface{}) interface{} { return apiClientTransport(i.(api.APIClient)) },
+		"pid":       func(i interface{}) interface{} { return float64(i.(api.APIClient).PID) },
+		"queue":     func(i interface{}) interface{} { return float64(i.(api.APIClient).QueueLength) },
+		"health":    func(i interface{}) interface{} { return i.(api.APIClient).Health },
+	},
+}
+
+// filterOperators are the supported operators, the two character
+// operators have to precede the single character ones.
+var filterOperators = []string{">=", "<=", "!=", "=", ">", "<", "~"}
+
+// filterCondition is a single 'field operator value' condition.
+type filterCondition struct {
+	field filterField
+	// rate is the rate column compared if the field is nil.
+	rate     int
+	operator string
+	value    string
+	number   float64
+	isNumber bool
+	regexp   *regexp.Regexp
+}
+
+// filterExpr is a filter expression of conditions joined by '&&',
+// e.g. 'rxerrors>0 && name~vxlan'.
+type filterExpr []*filterCondition
+
+// parseFilter parses the filter expression for the tab. An error is
+// returned if the filter is not an expression of the fields or the rate
+// columns of the tab.
+func parseFilter(tab int, filter string) (filterExpr, error) {
+	fields, ok := filterFields[tab]
+	if !ok {
+		return nil, fmt.Errorf("tab does not support filter expressions")
+	}
+	var expr filterExpr
+	for _, part := range strings.Split(filter, "&&") {
+		cond, err := parseCondition(fields, rateSources[tab].columns, strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		expr = append(expr, cond)
+	}
+	return expr, nil
+}
+
+// parseCondition parses a single condition of the filter expression.
+func parseCondition(fields map[string]filterField, rates []rateColumn, part string) (*filterCondition, error) {
+	pos, operator := -1, ""
+	for _, op := range filterOperators {
+		if i := strings.Index(part, op); i > 0 && (pos < 0 || i < pos) {
+			pos, operator = i, op
+		}
+	}
+	if pos < 0 {
+		return nil, fmt.Errorf("no operator in condition %q", part)
+	}
+	name := strings.ToLower(strings.TrimSpace(part[:pos]))
+	cond := &filterCondition{
+		field:    fields[name],
+		rate:     -1,
+		operator: operator,
+		value:    strings.TrimSpace(part[pos+len(operator):]),
+	}
+	for column, rate := range rates {
+		if cond.field == nil && rate.name == name {
+			cond.rate = column
+		}
+	}
+	if cond.field == nil && cond.rate < 0 {
+		return nil, fmt.Errorf("unknown field %q", name)
+	}
+	if operator == "~" {
+		re, err := regexp.Compile(cond.value)
+		if err != nil {
+			return nil, err
+		}
+		cond.regexp = re
+	}
+	if number, err := strconv.ParseFloat(cond.value, 64); err == nil {
+		cond.number, cond.isNumber = number, true
+	} else if count, ok := parseCount(cond.value); ok {
+		cond.number, cond.isNumber = float64(count), true
+	}
+	return cond, nil
+}
+
+// match returns true if the item matches all conditions of the expression,
+// rate columns are looked up in the rates (nil if the item has no rates).
+func (e filterExpr) match(item interface{}, rates *statsRates) bool {
+	for _, cond := range e {
+		if !cond.match(item, rates) {
+			return false
+		}
+	}
+	return true
+}
+
+// match returns true if the item matches the condition. Numbers are compared
+// numerically if the value is a number, otherwise values are compared as strings.
+func (c *filterCondition) match(item interface{}, rates *statsRates) bool {
+	var value interface{}
+	if c.field != nil {
+		value = c.field(item)
+	} else {
+		value = rates.get(item, c.rate)
+	}
+	if c.regexp != nil {
+		return c.regexp.MatchString(fmt.Sprint(value))
+	}
+	cmp := 0
+	if number, ok := value.(float64); ok && c.isNumber {
+		switch {
+		case number < c.number:
+			cmp = -1
+		case number > c.number:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(strings.ToLower(fmt.Sprint(value)), strings.ToLower(c.value))
+	}
+	switch c.operator {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// isFilterExpression returns true if the filter is a valid expression for the tab.
+func isFilterExpression(tab int, filter string) bool {
+	if filter == "" {
+		return false
+	}
+	_, err := parseFilter(tab, filter)
+	return err == nil
+}
+
+// filterStats returns a copy of the stats slice containing only
+// items matching the filter expression of the tab.
+func (app *App) filterStats(tab int, stats interface{}, rates *statsRates) interface{} {
+	app.filterLock.Lock()
+	expr := app.filters[tab]
+	app.filterLock.Unlock()
+
+	in := reflect.ValueOf(stats)
+	out := reflect.MakeSlice(in.Type(), 0, in.Len())
+	for i := 0; i < in.Len(); i++ {
+		if item := in.Index(i); expr == nil || expr.match(item.Interface(), rates) {
+			out = reflect.Append(out, item)
+		}
+	}
+	return out.Interface()
+}
+
+// errorFilter returns the match of the errors shown with the current filter
+// of the errors tab, or nil if the tab is not filtered.
+func (app *App) errorFilter() func(api.Error) bool {
+	app.filterLock.Lock()
+	expr, text := app.filters[Errors], app.filterTexts[Errors]
+	app.filterLock.Unlock()
+
+	switch {
+	case expr != nil:
+		return func(e api.Error) bool { return expr.match(e, nil) }
+	case text != "":
+		// names are matched on the node column by the gui
+		return func(e api.Error) bool { return strings.Contains(e.Node, text) }
+	}
+	return nil
+}
+
+// SetHideZeroNodes sets whether nodes with zero calls and vectors are hidden.
+func (app *App) SetHideZeroNodes(hide bool) {
+	app.filterLock.Lock()
+	app.hideZeroNodes = hide
+	app.filterLock.Unlock()
+}
+
+// isHidingZeroNodes returns true if nodes with zero calls and vectors are hidden.
+func (app *App) isHidingZeroNodes() bool {
+	app.filterLock.Lock()
+	defer app.filterLock.Unlock()
+	return app.hideZeroNodes
+}
+
+// withoutZeroNodes returns the nodes with non-zero calls or vectors
+// since the last clear, the slice is filtered in place.
+func withoutZeroNodes(nodes []api.Node) []api.Node {
+	result := nodes[:0]
+	for _, node := range nodes {
+		if node.Calls != 0 || node.Vectors != 0 {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func TestFilterExpression(t *testing.T) {
+	iface := api.Interface{State: "up"}
+	iface.InterfaceName = "vxlan_tunnel0"
+	iface.RxErrors, iface.Drops = 5, 1500
+
+	tests := []struct {
+		filter string
+		match  bool
+		err    bool
+	}{
+		// '>=' and '!=' take precedence over '>' and '='
+		{filter: "rxerrors>=5", match: true},
+		{filter: "rxerrors>5", match: false},
+		{filter: "rxerrors<=5", match: true},
+		{filter: "rxerrors<5", match: false},
+		{filter: "state!=down", match: true},
+		{filter: "state!=up", match: false},
+		{filter: "state=UP", match: true},
+		// '=' inside the regular expression does not split the condition
+		{filter: "name~tunnel[=0]$", match: true},
+		{filter: "name~=tunnel", match: false},
+		{filter: "name ~ ^vxlan", match: true},
+		{filter: "name~[", err: true},
+		// conditions joined by '&&' have to match all
+		{filter: "state=up && rxerrors>=5", match: true},
+		{filter: "state=up && rxerrors>5", match: false},
+		{filter: "state=up&&name~vxlan&&drops>0", match: true},
+		// counts with the SI suffixes
+		{filter: "drops>1K", match: true},
+		{filter: "drops<1.5K", match: false},
+		{filter: "drops<=1.5K", match: true},
+		{filter: "drops>1M", match: false},
+		// rate columns are zero without the rates
+		{filter: "rxpackets/s=0", match: true},
+		{filter: "rxpackets/s>0", match: false},
+		// invalid expressions
+		{filter: "foo>1", err: true},
+		{filter: "rxerrors", err: true},
+		{filter: "=up", err: true},
+		{filter: "state=up && foo=1", err: true},
+	}
+	for _, test := range tests {
+		expr, err := parseFilter(Interfaces, test.filter)
+		if (err != nil) != test.err {
+			t.Errorf("Error occured parsing %q: %v (want error: %t)", test.filter, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := expr.match(iface, nil); got != test.match {
+			t.Errorf("Error occured matching %q got:%t; want:%t", test.filter, got, test.match)
+		}
+	}
+
+	if _, err := parseFilter(Threads, "name=vpp_main"); err == nil {
+		t.Errorf("Error occured: the threads tab does not support filter expressions")
+	}
+}
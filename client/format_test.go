@@ -0,0 +1,263 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// update rewrites the golden files by the formatted rows:
+//
+//	go test ./client -run TestFormat -update
+var update = flag.Bool("update", false, "update the golden files")
+
+// checkGolden compares the rows to the golden file testdata/<name>.golden,
+// which contains a line per row with cells separated by tabs.
+func checkGolden(t *testing.T, name string, rows xtui.TableRows) {
+	t.Helper()
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(strings.Join(row, "\t"))
+		b.WriteString("\n")
+	}
+	got := b.String()
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatalf("failed to update %s: %v", golden, err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", golden, err)
+	}
+	if got != string(want) {
+		t.Errorf("rows differ from %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	app := &App{memory: newMemoryTrend(DefaultMemoryTrendWindow), unitsLock: new(sync.Mutex)}
+	tests := []struct {
+		name string
+		rows xtui.TableRows
+	}{
+		{
+			name: "nodes",
+			rows: app.formatNodes([]api.Node{
+				{Name: "ip4-lookup", State: "active", Calls: 120, Vectors: 480, Clocks: 1234.56,
+					VectorsPerCall: 4, MaxClocks: 25630, VectorsAtMax: 8, ClocksPercent: 12.5},
+				{Name: "dpdk-input", State: "polling", Calls: 1000000, Clocks: 75.25, ClocksPercent: 87.5},
+			}),
+		},
+		{
+			name: "errors",
+			rows: app.formatErrors([]api.Error{
+				{Count: 3, Node: "ip4-arp", Reason: "ARP requests sent", Severity: "info"},
+				{Count: 12, Node: "ethernet-input", Reason: "no error"},
+			}),
+		},
+		{
+			name: "sessions",
+			rows: app.formatSessions([]api.SessionStat{
+				{Namespace: "default", Protocol: "tcp", State: "ESTABLISHED", Count: 4},
+				{Namespace: "default", Protocol: "udp", State: "OPENED", Count: 1},
+			}),
+		},
+		{
+			name: "features",
+			rows: app.formatFeatures([]api.FeatureArc{
+				{Interface: "loop0", Arc: "ip4-unicast", Features: []string{"ip4-not-enabled", "ip4-lookup"}},
+				{Interface: "tap0", Arc: "ip6-output", Features: []string{"ip6-outacl"}},
+			}),
+		},
+		{
+			name: "neighbors",
+			rows: app.formatNeighbors([]api.Neighbor{
+				{Interface: "loop0", IP: "10.10.0.2", MAC: "02:fe:00:00:00:01", Age: api.UnknownNeighborAge, Static: true},
+				{Interface: "tap0", IP: "fd00::2", MAC: "02:fe:00:00:00:02", Age: 12.5, NoFibEntry: true},
+			}),
+		},
+		{
+			name: "srv6",
+			rows: app.formatSRv6([]api.SRv6SID{
+				{SID: "2001:db8:100::1", Policy: true, Behavior: "Encapsulation", Type: "Spray", FibTable: 10,
+					SegmentLists: []api.SRv6SegmentList{
+						{Segments: []string{"2001:db8:2::1", "2001:db8:3::d4"}, Weight: 1},
+						{Segments: []string{"2001:db8:4::d4"}, Weight: 2},
+					},
+					Steering: []string{"L3 10.0.100.0/24", "L2 tap0"},
+					Good:     govppapi.InterfaceCounterCombined{Packets: 1200, Bytes: 614400}},
+				{SID: "2001:db8:1::d4", Behavior: "DX4 (Next-hop: 10.0.0.2, iface: tap0)",
+					Good: govppapi.InterfaceCounterCombined{Packets: 500, Bytes: 270000},
+					Bad:  govppapi.InterfaceCounterCombined{Packets: 3, Bytes: 1620}},
+			}, nil),
+		},
+		{
+			name: "mpls",
+			rows: app.formatMpls([]api.MplsEntry{
+				{Tunnel: "mpls_tunnel0", SwIfIndex: 6, NextHops: []string{"10.0.0.2 GigabitEthernet0/8/0"},
+					OutLabels: []uint32{1001, 2001}, Counter: govppapi.InterfaceCounterCombined{Packets: 3500, Bytes: 2450000}},
+				{Label: 100, EOS: true, NextHops: []string{"10.0.0.2 GigabitEthernet0/8/0", "192.168.1.2 GigabitEthernet0/9/0"},
+					OutLabels: []uint32{200}, Counter: govppapi.InterfaceCounterCombined{Packets: 4200, Bytes: 2520000}},
+				{Label: 300, TableID: 10, NextHops: []string{"10.0.100.2 GigabitEthernet0/8/0.100"}},
+			}, nil),
+		},
+		{
+			name: "memory",
+			rows: app.formatMemstats([]string{
+				"Thread 0 vpp_main",
+				"  base 0x7f0000000000, size 1g, locked, unmap-on-destroy, name 'main heap'",
+				"    total: 1023.99M, used: 24.50M, free: 999.49M, trimmable: 998.39M",
+				"API segment",
+				"  base 0x130000000, size 64m, locked, unmap-on-destroy, name 'api segment'",
+				"Memory map",
+				"  StartAddr          size   FD PageSz  Pages Numa0 NotMap Name",
+				"  00007f0000000000     1g        4K 262144  7040 255104 main heap",
+				"  0000000130000000    64m        4K  16384   516  15868 api segment",
+				"  00007f1000000000    32m    9   4K   8192   163   8029 stat segment",
+				"  00007f2000000000     2m        4K    512     4    508 vlib buffer",
+				"  00007f2000200000     2m        4K    512     4    508 vlib buffer",
+				"  00007f2000400000     2m        4K    512     4    508 vlib buffer",
+				"  00007f2000600000     2m        4K    512     4    508 vlib buffer",
+			}),
+		},
+		{
+			name: "apitrace",
+			rows: app.formatAPITrace(&api.APITrace{
+				Enabled: true,
+				Messages: []api.APITraceMessage{
+					{Index: 1, Name: "control_ping", Details: "context: 1"},
+					{Index: 2, Name: "sw_interface_dump", Details: "sw_if_index: 4294967295"},
+				},
+			}),
+		},
+		{
+			name: "apiclients",
+			rows: app.formatAPIClients([]api.APIClient{
+				{Name: "stats-exporter", PID: 1311, QueueLength: 37, Health: "questionable"},
+				{Name: "vpptop", Socket: true, FD: 27},
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkGolden(t, test.name, test.rows)
+		})
+	}
+}
+
+func TestInterfaceErrorDetails(t *testing.T) {
+	iface := api.Interface{
+		State: "up",
+		LLDP: &api.LLDPNeighbor{Interface: "GigabitEthernet0/8/0", ChassisID: "0c:42:a1:00:10:00",
+			PortID: "Ethernet1/1", LastHeard: 12.5, Active: true},
+		Workers: []api.WorkerCounters{
+			{Worker: 0, RxMiss: 3},
+			{Worker: 1, RxErrors: 12, RxNoBuf: 1, TxErrors: 2},
+		},
+	}
+	iface.InterfaceName = "GigabitEthernet0/8/0"
+	iface.RxErrors, iface.TxErrors, iface.RxMiss, iface.RxNoBuf, iface.Drops = 12, 2, 3, 1, 4
+	iface.Rx.Packets, iface.Tx.Packets = 1500, 900
+	iface.Workers[0].Rx.Packets, iface.Workers[1].Rx.Packets = 1000, 500
+	iface.Workers[1].Tx.Packets = 900
+	errors := []api.Error{
+		{Count: 2, Node: "GigabitEthernet0/8/0-tx", Reason: "Tx packet drops (dpdk tx failure)"},
+		{Count: 5, Node: "ip4-input", Reason: "ip4 ttl <= 1"},
+	}
+
+	var rows xtui.TableRows
+	for _, row := range interfaceErrorDetails(iface, errors, unitFormat{}) {
+		rows = append(rows, []string{row})
+	}
+	checkGolden(t, "ifdetails", rows)
+}
+
+func TestMemifRingDetails(t *testing.T) {
+	rings := []api.MemifRing{
+		{Interface: "memif0/0", Direction: "slave-to-master", Size: 1024, Head: 2, Tail: 65535},
+		{Interface: "memif0/0", Direction: "master-to-slave", Size: 1024, Head: 5120, Tail: 4096, FullPolls: 7},
+	}
+
+	var rows xtui.TableRows
+	for _, row := range memifRingDetails(rings, unitFormat{}) {
+		rows = append(rows, []string{row})
+	}
+	checkGolden(t, "ifmemif", rows)
+}
+
+func TestCompactInterfaceRows(t *testing.T) {
+	prev := []api.Interface{{State: "up", LinkState: "up"}, {State: "down", LinkState: "down"}}
+	prev[0].InterfaceName, prev[0].InterfaceIndex = "GigabitEthernet0/8/0", 1
+	prev[0].Rx.Packets, prev[0].Rx.Bytes, prev[0].Tx.Packets, prev[0].Tx.Bytes = 1000, 600000, 500, 100000
+	prev[1].InterfaceName, prev[1].InterfaceIndex = "tap0", 3
+	curr := append([]api.Interface(nil), prev...)
+	curr[0].Rx.Packets, curr[0].Rx.Bytes, curr[0].Tx.Packets, curr[0].Tx.Bytes = 3500, 2100000, 1700, 820000
+	curr[0].Drops, curr[0].RxErrors, curr[0].TxErrors = 4, 12, 2
+
+	rows := &interfaceRows{ifaces: curr, rates: computeRates(Interfaces, curr, prev, time.Second), compact: true}
+	var got xtui.TableRows
+	for i := 0; i < rows.Len(); i++ {
+		got = append(got, rows.EntryRows(i)...)
+	}
+	checkGolden(t, "ifcompact", got)
+}
+
+func TestErrorsHeader(t *testing.T) {
+	prev := []api.Error{
+		{Count: 100, Node: "ip4-input", Reason: "ip4 ttl <= 1", Severity: "error"},
+		{Count: 10, Node: "ip4-arp", Reason: "ARP requests sent", Severity: "info"},
+	}
+	errors := []api.Error{
+		{Count: 1500, Node: "ip4-input", Reason: "ip4 ttl <= 1", Severity: "error"},
+		{Count: 3, Node: "ip4-local", Reason: "unknown ip protocol", Severity: "warn"},
+		{Count: 7, Node: "ethernet-input", Reason: "no error"},
+		{Count: 20, Node: "ip4-arp", Reason: "ARP requests sent", Severity: "info"},
+	}
+
+	tests := []struct {
+		prev       []api.Error
+		units      unitFormat
+		noSeverity bool
+		want       string
+	}{
+		{want: "Reason (error: 1500, warn: 3, info: 20, all: -/s)"},
+		{prev: prev, want: "Reason (error: 1500, warn: 3, info: 20, all: 710/s)"},
+		{prev: prev, units: unitFormat{human: true}, want: "Reason (error: 1.50K, warn: 3, info: 20, all: 710/s)"},
+		{prev: prev, noSeverity: true, want: "Reason (all: 710/s)"},
+	}
+	for _, test := range tests {
+		header := errorsHeader(errors, test.prev, 2*time.Second, test.units, !test.noSeverity)
+		if got := header[0][2]; got != test.want {
+			t.Errorf("Error occured got:%q; want:%q", got, test.want)
+		}
+	}
+}
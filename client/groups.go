@@ -0,0 +1,354 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// tableGroups keeps the state of the grouping mode of a table, where
+// entries are rolled up into their parent entry (e.g. sub-interfaces
+// into their parent interface).
+type tableGroups struct {
+	sync.Mutex
+	enabled bool
+	// set if the entries are grouped by the threads counting
+	// them instead of their parent (error counters only)
+	byThread bool
+	// expanded parent entries showing their children.
+	expanded map[string]bool
+}
+
+// newTableGroups returns grouping with all groups collapsed.
+func newTableGroups() *tableGroups {
+	return &tableGroups{expanded: make(map[string]bool)}
+}
+
+// toggle enables or disables the grouping mode.
+func (g *tableGroups) toggle() {
+	g.Lock()
+	g.enabled = !g.enabled
+	g.Unlock()
+}
+
+// cycle switches the grouping mode from disabled to grouping by the parent,
+// then to grouping by the threads if the entries are counted per thread,
+// and back to disabled.
+func (g *tableGroups) cycle(perThread bool) {
+	g.Lock()
+	defer g.Unlock()
+	switch {
+	case !g.enabled:
+		g.enabled = true
+	case !g.byThread && perThread:
+		g.byThread = true
+	default:
+		g.enabled = false
+		g.byThread = false
+	}
+}
+
+// isByThread returns true if the entries are grouped by the threads.
+func (g *tableGroups) isByThread() bool {
+	g.Lock()
+	defer g.Unlock()
+	return g.enabled && g.byThread
+}
+
+// isEnabled returns true if the grouping mode is enabled.
+func (g *tableGroups) isEnabled() bool {
+	g.Lock()
+	defer g.Unlock()
+	return g.enabled
+}
+
+// toggleExpanded expands or collapses the group of the parent entry.
+func (g *tableGroups) toggleExpanded(name string) {
+	g.Lock()
+	g.expanded[name] = !g.expanded[name]
+	g.Unlock()
+}
+
+// isExpanded returns true if the group of the parent entry is expanded.
+func (g *tableGroups) isExpanded(name string) bool {
+	g.Lock()
+	defer g.Unlock()
+	return g.expanded[name]
+}
+
+// rollUpInterfaces returns the parent interfaces with counters of their
+// sub-interfaces added, and the sub-interfaces by the parent key.
+// The parent is given by the sup_sw_if_index, or by the interface name
+// (e.g. 'GigabitEthernet0/8/0.100') if the index is not known.
+func rollUpInterfaces(ifaces []api.Interface) ([]api.Interface, map[string][]api.Interface) {
+	type indexKey struct {
+		instance string
+		index    uint32
+	}
+	byIndex := make(map[indexKey]int, len(ifaces))
+	byName := make(map[string]int, len(ifaces))
+	for i, iface := range ifaces {
+		byIndex[indexKey{iface.Instance, iface.InterfaceIndex}] = i
+		byName[interfaceKey(iface)] = i
+	}
+
+	parentOf := func(iface api.Interface) (int, bool) {
+		if iface.SupSwIfIndex != iface.InterfaceIndex {
+			if i, ok := byIndex[indexKey{iface.Instance, iface.SupSwIfIndex}]; ok {
+				return i, true
+			}
+		}
+		if dot := strings.LastIndex(iface.InterfaceName, "."); dot > 0 {
+			if i, ok := byName[instanceKey(iface.Instance, iface.InterfaceName[:dot])]; ok {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	var parents []api.Interface
+	children := make(map[string][]api.Interface)
+	parentPos := make(map[int]int)
+	var subIfaces []int
+	for i, iface := range ifaces {
+		if p, ok := parentOf(iface); ok && p != i {
+			subIfaces = append(subIfaces, i)
+			continue
+		}
+		parentPos[i] = len(parents)
+		parents = append(parents, iface)
+	}
+	for _, i := range subIfaces {
+		p, _ := parentOf(ifaces[i])
+		pos, ok := parentPos[p]
+		if !ok {
+			// the parent is a sub-interface itself
+			parentPos[i] = len(parents)
+			parents = append(parents, ifaces[i])
+			continue
+		}
+		addCounters(&parents[pos].InterfaceCounters, ifaces[i].InterfaceCounters)
+		key := interfaceKey(parents[pos])
+		children[key] = append(children[key], ifaces[i])
+	}
+	return parents, children
+}
+
+// addCounters adds the counters of the interface to the total.
+func addCounters(total *govppapi.InterfaceCounters, c govppapi.InterfaceCounters) {
+	addCombined := func(total *govppapi.InterfaceCounterCombined, c govppapi.InterfaceCounterCombined) {
+		total.Packets += c.Packets
+		total.Bytes += c.Bytes
+	}
+	addCombined(&total.Rx, c.Rx)
+	addCombined(&total.Tx, c.Tx)
+	addCombined(&total.RxUnicast, c.RxUnicast)
+	addCombined(&total.RxMulticast, c.RxMulticast)
+	addCombined(&total.RxBroadcast, c.RxBroadcast)
+	addCombined(&total.TxUnicast, c.TxUnicast)
+	addCombined(&total.TxMulticast, c.TxMulticast)
+	addCombined(&total.TxBroadcast, c.TxBroadcast)
+	total.RxErrors += c.RxErrors
+	total.TxErrors += c.TxErrors
+	total.Drops += c.Drops
+	total.Punts += c.Punts
+	total.IP4 += c.IP4
+	total.IP6 += c.IP6
+	total.RxNoBuf += c.RxNoBuf
+	total.RxMiss += c.RxMiss
+	total.Mpls += c.Mpls
+}
+
+// newGroupedInterfaceRows returns the interface rows with sub-interfaces
+// rolled up into their parents. Sub-interfaces of expanded parents
+// follow the parent, both are sorted by the field. The rates of the
+// parents are computed from the rolled up counters.
+func (app *App) newGroupedInterfaceRows(ifaces, prev []api.Interface, elapsed time.Duration, field int, asc bool) *interfaceRows {
+	parents, children := rollUpInterfaces(ifaces)
+	prevParents, prevChildren := rollUpInterfaces(prev)
+	all := append([]api.Interface(nil), parents...)
+	for _, subIfaces := range children {
+		all = append(all, subIfaces...)
+	}
+	for _, subIfaces := range prevChildren {
+		prevParents = append(prevParents, subIfaces...)
+	}
+	rates := computeRates(Interfaces, all, prevParents, elapsed)
+	app.sortInterfaceStats(parents, rates, field, asc)
+
+	var list []api.Interface
+	var labels []string
+	for _, parent := range parents {
+		subIfaces := children[interfaceKey(parent)]
+		expanded := app.groups.isExpanded(interfaceKey(parent))
+
+		list = append(list, parent)
+		switch {
+		case len(subIfaces) == 0:
+			labels = append(labels, parent.InterfaceName)
+		case expanded:
+			labels = append(labels, fmt.Sprintf("- %s (%d sub)", parent.InterfaceName, len(subIfaces)))
+		default:
+			labels = append(labels, fmt.Sprintf("+ %s (%d sub)", parent.InterfaceName, len(subIfaces)))
+		}
+		if !expanded {
+			continue
+		}
+		app.sortInterfaceStats(subIfaces, rates, field, asc)
+		for _, subIface := range subIfaces {
+			list = append(list, subIface)
+			labels = append(labels, "  "+subIface.InterfaceName)
+		}
+	}
+
+	rows := app.newInterfaceRows(list, rates)
+	rows.labels = labels
+	return rows
+}
+
+// errorSeverities rank the error severities, a node group
+// has the most severe severity of its counters.
+var errorSeverities = map[string]int{"unknown": 0, "info": 1, "warn": 2, "error": 3}
+
+// newGroupedErrorRows returns the error counters grouped by their nodes.
+// The rows of the nodes show the total count of their counters, which are
+// shown below expanded nodes. Both nodes and counters are sorted by the field.
+func (app *App) newGroupedErrorRows(errors []api.Error, field int, asc bool) *xtui.TreeRows {
+	var nodes []api.Error
+	counters := make(map[string][]api.Error)
+	for _, e := range errors {
+		// Not supported in older versions
+		if e.Severity == "" {
+			e.Severity = "unknown"
+		}
+		if _, ok := counters[e.Node]; !ok {
+			nodes = append(nodes, api.Error{Node: e.Node, Severity: e.Severity})
+		}
+		counters[e.Node] = append(counters[e.Node], e)
+	}
+	for i := range nodes {
+		for _, e := range counters[nodes[i].Node] {
+			nodes[i].Count += e.Count
+			if errorSeverities[e.Severity] > errorSeverities[nodes[i].Severity] {
+				nodes[i].Severity = e.Severity
+			}
+		}
+	}
+	app.sortErrorStats(nodes, field, asc)
+
+	tree := make([]xtui.TreeNode, len(nodes))
+	for i, node := range nodes {
+		nodeCounters := counters[node.Node]
+		app.sortErrorStats(nodeCounters, field, asc)
+		tree[i] = xtui.TreeNode{
+			Key: node.Node,
+			Row: []string{fmt.Sprint(node.Count), node.Node, fmt.Sprintf("(%d reasons)", len(nodeCounters)), node.Severity},
+		}
+		for _, e := range nodeCounters {
+			tree[i].Children = append(tree[i].Children, xtui.TreeNode{
+				Key: e.Node + "/" + e.Reason,
+				Row: []string{fmt.Sprint(e.Count), "", e.Reason, e.Severity},
+			})
+		}
+	}
+	return xtui.NewTreeRows(tree, ErrorStatErrorNodeName, app.errorGroups.isExpanded)
+}
+
+// hasPerThread returns true if any of the error counters is counted per thread.
+func hasPerThread(errors []api.Error) bool {
+	for _, e := range errors {
+		if len(e.PerThread) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// threadName returns the name of the thread by its index, or 'thread <index>'
+// if the thread is not known.
+func threadName(threads []api.ThreadData, index int) string {
+	for _, thread := range threads {
+		if int(thread.ID) == index && thread.Name != "" {
+			return thread.Name
+		}
+	}
+	return fmt.Sprintf("thread %d", index)
+}
+
+// newThreadErrorRows returns the error counters grouped by the threads
+// counting them, to tell whether errors are confined to a single worker.
+// The rows of the threads show the total count of the thread, the counters
+// of the thread are shown below expanded threads. Counters which are not
+// counted per thread are left out. Both threads and counters are sorted
+// by the field, the threads are ordered by their index if not sorted.
+func (app *App) newThreadErrorRows(errors []api.Error, threads []api.ThreadData, field int, asc bool) *xtui.TreeRows {
+	var totals []api.Error
+	counters := make(map[string][]api.Error)
+	for _, e := range errors {
+		if e.Severity == "" {
+			e.Severity = "unknown"
+		}
+		for index, count := range e.PerThread {
+			if count == 0 {
+				continue
+			}
+			for len(totals) <= index {
+				totals = append(totals, api.Error{Node: threadName(threads, len(totals)), Severity: "unknown"})
+			}
+			total := &totals[index]
+			total.Count += count
+			if errorSeverities[e.Severity] > errorSeverities[total.Severity] {
+				total.Severity = e.Severity
+			}
+			counter := e
+			counter.Count = count
+			counter.PerThread = nil
+			counters[total.Node] = append(counters[total.Node], counter)
+		}
+	}
+	var nodes []api.Error
+	for _, total := range totals {
+		if total.Count != 0 {
+			nodes = append(nodes, total)
+		}
+	}
+	app.sortErrorStats(nodes, field, asc)
+
+	tree := make([]xtui.TreeNode, len(nodes))
+	for i, node := range nodes {
+		threadCounters := counters[node.Node]
+		app.sortErrorStats(threadCounters, field, asc)
+		tree[i] = xtui.TreeNode{
+			Key: node.Node,
+			Row: []string{fmt.Sprint(node.Count), node.Node, fmt.Sprintf("(%d counters)", len(threadCounters)), node.Severity},
+		}
+		for _, e := range threadCounters {
+			tree[i].Children = append(tree[i].Children, xtui.TreeNode{
+				Key: node.Node + "/" + e.Node + "/" + e.Reason,
+				Row: []string{fmt.Sprint(e.Count), e.Node, e.Reason, e.Severity},
+			})
+		}
+	}
+	return xtui.NewTreeRows(tree, ErrorStatErrorNodeName, app.errorGroups.isExpanded)
+}
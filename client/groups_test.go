@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func TestTableGroups_cycle(t *testing.T) {
+	g := newTableGroups()
+	mode := func() string {
+		switch {
+		case g.isByThread():
+			return "thread"
+		case g.isEnabled():
+			return "node"
+		}
+		return "off"
+	}
+	var got []string
+	for _, perThread := range []bool{true, true, true, false, false} {
+		g.cycle(perThread)
+		got = append(got, mode())
+	}
+	want := []string{"node", "thread", "off", "node", "off"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Error occured got:%q; want:%q", got, want)
+	}
+}
+
+func TestNewThreadErrorRows(t *testing.T) {
+	app := &App{errorGroups: newTableGroups()}
+	app.errorGroups.toggleExpanded("vpp_wk_0")
+	errors := []api.Error{
+		{Node: "ip4-lookup", Reason: "adjacency drop", Severity: "error", Count: 12, PerThread: []uint64{0, 10, 2}},
+		{Node: "arp-reply", Reason: "replies sent", Severity: "info", Count: 3, PerThread: []uint64{3}},
+		{Node: "ip4-input", Reason: "ttl <= 1", Severity: "warn", Count: 5, PerThread: []uint64{0, 5}},
+		// counted by the CLI, not per thread
+		{Node: "dpdk-input", Reason: "rx errors", Count: 7},
+	}
+	threads := []api.ThreadData{{ID: 0, Name: "vpp_main"}, {ID: 1, Name: "vpp_wk_0"}}
+
+	rows := app.newThreadErrorRows(errors, threads, ErrorStatErrorCounter, false)
+	var got []string
+	for i := 0; i < rows.Len(); i++ {
+		got = append(got, strings.Join(rows.EntryRows(i)[0], "|"))
+	}
+	want := []string{
+		"15|- vpp_wk_0|(2 counters)|error",
+		"10|    ip4-lookup|adjacency drop|error",
+		"5|    ip4-input|ttl <= 1|warn",
+		"3|+ vpp_main|(1 counters)|info",
+		"2|+ thread 2|(1 counters)|error",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Error occured got:%q; want:%q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// httpEndpoints are the tabs served by the HTTP server by their paths.
+var httpEndpoints = map[string]int{
+	"/interfaces": Interfaces,
+	"/nodes":      Nodes,
+	"/errors":     Errors,
+	"/memory":     Memory,
+	"/threads":    Threads,
+	"/drops":      DropsPunts,
+	"/tunnels":    Tunnels,
+	"/sessions":   Sessions,
+	"/features":   Features,
+	"/bonds":      Bonds,
+	"/policers":   Policers,
+	"/fib":        Fib,
+	"/neighbors":  Neighbors,
+	"/srv6":       SRv6,
+	"/mpls":       Mpls,
+	"/apiclients": APIClients,
+	"/info":       Info,
+}
+
+// HTTPConfig configures the HTTP server exposing the collected stats as JSON.
+type HTTPConfig struct {
+	// Addr the server listens on, the server is disabled if empty.
+	Addr string
+	// Token (optional) required in the 'Authorization: Bearer <token>' header.
+	Token string
+}
+
+// SetHTTP enables the HTTP server while the application runs.
+func (app *App) SetHTTP(cfg HTTPConfig) {
+	app.http = &cfg
+}
+
+// runHTTP is a blocking call serving the cached stats
+// until the context is cancelled.
+func (app *App) runHTTP(ctx context.Context) {
+	mux := http.NewServeMux()
+	for path, tab := range httpEndpoints {
+		mux.Handle(path, app.authorize(app.statsHandler(tab)))
+	}
+	server := &http.Server{Addr: app.http.Addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logrus.Errorf("error occured while serving http: %v", err)
+	}
+}
+
+// authorize rejects requests without the token if the token is set.
+func (app *App) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if app.http.Token != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(app.http.Token)) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statsHandler returns the handler writing the cached stats of the tab as JSON.
+func (app *App) statsHandler(tab int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		entry, ok := app.cache.load(tab)
+		if !ok {
+			http.Error(w, "stats not collected yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", entry.polledAt.UTC().Format(http.TimeFormat))
+		if err := json.NewEncoder(w).Encode(entry.data); err != nil {
+			logrus.Warnf("error occured while writing %s stats: %v", tabNames[tab], err)
+		}
+	})
+}
@@ -0,0 +1,328 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// influxUDPPayload is the max size of a single UDP datagram with points.
+const influxUDPPayload = 1400
+
+// InfluxConfig configures exporting of the collected stats
+// in the InfluxDB line protocol.
+type InfluxConfig struct {
+	// URL of the endpoint the points are written to, exporting is disabled if empty.
+	// Supported are 'udp://host:port', the HTTP write URL of the InfluxDB
+	// (e.g. 'http://influxdb:8086/write?db=vpp') and a file path, optionally
+	// prefixed by 'file://', the points are appended to.
+	URL string
+	// Token (optional) authorizes the writes to the HTTP endpoint of InfluxDB 2.
+	Token string
+	// Host is the host tag of the points, the hostname is used if empty.
+	Host string
+	// Interval between two exports.
+	Interval time.Duration
+}
+
+// SetInflux enables exporting of the collected stats while the application runs.
+func (app *App) SetInflux(cfg InfluxConfig) {
+	if cfg.Host == "" {
+		cfg.Host, _ = os.Hostname()
+	}
+	app.influx = &cfg
+}
+
+// influxSink writes batches of points to the endpoint.
+type influxSink interface {
+	write(ctx context.Context, points []byte) error
+	close()
+}
+
+// newInfluxSink returns the sink for the scheme of the URL.
+func newInfluxSink(rawURL, token string, timeout time.Duration) (influxSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "udp":
+		conn, err := net.Dial("udp", u.Host)
+		if err != nil {
+			return nil, err
+		}
+		return &influxUDPSink{conn: conn}, nil
+	case "http", "https":
+		return &influxHTTPSink{url: rawURL, token: token, client: &http.Client{Timeout: timeout}}, nil
+	case "file", "":
+		path := u.Path
+		if u.Scheme == "" {
+			path = rawURL
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return &influxFileSink{file: file}, nil
+	}
+	return nil, fmt.Errorf("unsupported scheme %q (udp, http, https or file)", u.Scheme)
+}
+
+// influxUDPSink writes points in datagrams split at line boundaries.
+type influxUDPSink struct {
+	conn net.Conn
+}
+
+func (s *influxUDPSink) write(_ context.Context, points []byte) error {
+	for len(points) > 0 {
+		n := len(points)
+		if n > influxUDPPayload {
+			if i := bytes.LastIndexByte(points[:influxUDPPayload], '\n'); i >= 0 {
+				n = i + 1
+			} else if i := bytes.IndexByte(points, '\n'); i >= 0 {
+				// a single line longer than the payload
+				n = i + 1
+			}
+		}
+		if _, err := s.conn.Write(points[:n]); err != nil {
+			return err
+		}
+		points = points[n:]
+	}
+	return nil
+}
+
+func (s *influxUDPSink) close() {
+	s.conn.Close()
+}
+
+// influxHTTPSink posts points to the InfluxDB write endpoint.
+type influxHTTPSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func (s *influxHTTPSink) write(ctx context.Context, points []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(points))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Token "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (s *influxHTTPSink) close() {}
+
+// influxFileSink appends points to the file.
+type influxFileSink struct {
+	file *os.File
+}
+
+func (s *influxFileSink) write(_ context.Context, points []byte) error {
+	_, err := s.file.Write(points)
+	return err
+}
+
+func (s *influxFileSink) close() {
+	s.file.Close()
+}
+
+// runInflux is a blocking call exporting the cached stats
+// until the context is cancelled.
+func (app *App) runInflux(ctx context.Context) {
+	sink, err := newInfluxSink(app.influx.URL, app.influx.Token, app.influx.Interval)
+	if err != nil {
+		logrus.Errorf("error occured while opening influx endpoint %s: %v", app.influx.URL, err)
+		return
+	}
+	defer sink.close()
+
+	ticker := time.NewTicker(app.influx.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			var buf bytes.Buffer
+			app.writePoints(&buf, now)
+			if buf.Len() == 0 {
+				continue
+			}
+			if err := sink.write(ctx, buf.Bytes()); err != nil {
+				logrus.Warnf("error occured while exporting points: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Escapers of the line protocol, measurements escape commas and spaces,
+// tag keys and values escape the equal sign as well.
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`)
+	fieldEscaper       = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// pointWriter writes points in the InfluxDB line protocol,
+// all points share the timestamp and the host tag.
+type pointWriter struct {
+	w    io.Writer
+	host string
+	ts   int64
+}
+
+// write writes a single point of the measurement. Tags are given as
+// name/value pairs, fields as names with uint64, float64 or string values.
+func (p *pointWriter) write(measurement string, tags []string, fields []string, values []interface{}) {
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(measurement))
+	if p.host != "" {
+		tags = append([]string{"host", p.host}, tags...)
+	}
+	for i := 0; i+1 < len(tags); i += 2 {
+		if tags[i+1] == "" {
+			// empty tag values are not allowed
+			continue
+		}
+		fmt.Fprintf(&b, ",%s=%s", tagEscaper.Replace(tags[i]), tagEscaper.Replace(tags[i+1]))
+	}
+	for i, field := range fields {
+		sep := ","
+		if i == 0 {
+			sep = " "
+		}
+		b.WriteString(sep + tagEscaper.Replace(field) + "=")
+		switch value := values[i].(type) {
+		case uint64:
+			b.WriteString(strconv.FormatUint(value, 10) + "i")
+		case float64:
+			b.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+		case string:
+			b.WriteString(`"` + fieldEscaper.Replace(value) + `"`)
+		}
+	}
+	fmt.Fprintf(&b, " %d\n", p.ts)
+	io.WriteString(p.w, b.String())
+}
+
+// writePoints writes the cached interface, node, error and thread
+// stats, tabs which were not polled yet are skipped.
+func (app *App) writePoints(w io.Writer, now time.Time) {
+	p := &pointWriter{w: w, host: app.influx.Host, ts: now.UnixNano()}
+
+	if entry, ok := app.cache.load(Interfaces); ok {
+		for _, iface := range entry.data.([]api.Interface) {
+			p.write("vpp_interface",
+				[]string{"interface", iface.InterfaceName, "instance", iface.Instance, "state", iface.State},
+				[]string{"rx_packets", "rx_bytes", "rx_errors", "tx_packets", "tx_bytes", "tx_errors",
+					"drops", "punts", "rx_no_buf", "rx_miss", "ip4", "ip6"},
+				[]interface{}{iface.Rx.Packets, iface.Rx.Bytes, iface.RxErrors, iface.Tx.Packets, iface.Tx.Bytes, iface.TxErrors,
+					iface.Drops, iface.Punts, iface.RxNoBuf, iface.RxMiss, iface.IP4, iface.IP6},
+			)
+		}
+	}
+
+	if entry, ok := app.cache.load(Nodes); ok {
+		// nodes are listed per thread, counters are summed up
+		var names []string
+		nodes := make(map[string]*api.Node)
+		for _, node := range entry.data.([]api.Node) {
+			total, ok := nodes[node.Name]
+			if !ok {
+				names = append(names, node.Name)
+				nodes[node.Name] = &api.Node{Name: node.Name}
+				total = nodes[node.Name]
+			}
+			total.Calls += node.Calls
+			total.Vectors += node.Vectors
+			total.Suspends += node.Suspends
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			p.write("vpp_node",
+				[]string{"node", name},
+				[]string{"calls", "vectors", "suspends"},
+				[]interface{}{nodes[name].Calls, nodes[name].Vectors, nodes[name].Suspends},
+			)
+		}
+	}
+
+	if entry, ok := app.cache.load(Errors); ok {
+		// the same node and reason may be reported more than once
+		type errorKey struct{ node, reason, severity string }
+		var keys []errorKey
+		counts := make(map[errorKey]uint64)
+		for _, e := range entry.data.([]api.Error) {
+			key := errorKey{e.Node, e.Reason, e.Severity}
+			if _, ok := counts[key]; !ok {
+				keys = append(keys, key)
+			}
+			counts[key] += e.Count
+		}
+		for _, key := range keys {
+			p.write("vpp_error",
+				[]string{"node", key.node, "reason", key.reason, "severity", key.severity},
+				[]string{"count"},
+				[]interface{}{counts[key]},
+			)
+		}
+	}
+
+	if entry, ok := app.cache.load(Threads); ok {
+		for _, thread := range entry.data.([]api.ThreadData) {
+			fields := []string{"cpu_id", "pid"}
+			values := []interface{}{uint64(thread.CPUID), uint64(thread.PID)}
+			if thread.Utilization >= 0 {
+				fields = append(fields, "utilization")
+				values = append(values, thread.Utilization)
+			}
+			p.write("vpp_thread",
+				[]string{"thread", fmt.Sprint(thread.ID), "name", thread.Name},
+				fields, values,
+			)
+		}
+	}
+}
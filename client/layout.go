@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/gui"
+)
+
+// DefaultLayoutFile returns the default path of the file persisting
+// the column widths, or an empty string if there is no config directory.
+func DefaultLayoutFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "vpptop", "layout.json")
+}
+
+// layout are the column widths of the tabs persisted in a file,
+// the widths are stored per tab name. Columns resized with the
+// terminal window have the width views.Resize.
+type layout struct {
+	sync.Mutex
+	path   string
+	widths map[string][]int
+}
+
+// SetLayoutFile sets the file the column widths are loaded from and
+// saved to when resized by the user. Widths are not persisted if empty.
+func (app *App) SetLayoutFile(path string) {
+	app.layout = &layout{
+		path:   path,
+		widths: make(map[string][]int),
+	}
+}
+
+// load reads the column widths from the layout file,
+// a missing file is not an error.
+func (l *layout) load() error {
+	data, err := ioutil.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+	return json.Unmarshal(data, &l.widths)
+}
+
+// save stores the column widths of the tab and writes them to the layout file.
+func (l *layout) save(tab int, widths []int) error {
+	l.Lock()
+	defer l.Unlock()
+	l.widths[tabNames[tab]] = widths
+
+	data, err := json.MarshalIndent(l.widths, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(l.path, data, 0644)
+}
+
+// applyLayout loads the layout file and sets the persisted column widths
+// to the views of the tabs.
+func (app *App) applyLayout() {
+	if app.layout == nil || app.layout.path == "" {
+		return
+	}
+	if err := app.layout.load(); err != nil {
+		logrus.Warnf("error occured while loading layout %s: %v", app.layout.path, err)
+		return
+	}
+	app.layout.Lock()
+	defer app.layout.Unlock()
+	for tab, name := range tabNames {
+		if !app.hasTab(tab) {
+			continue
+		}
+		if widths, ok := app.layout.widths[name]; ok {
+			if tab == Interfaces && app.keepFullWidths(widths) {
+				continue
+			}
+			if view, ok := app.gui.ViewAtTab(tab).(gui.ColumnView); ok {
+				view.SetColumnWidths(widths)
+			}
+		}
+	}
+}
+
+// saveLayout persists the column widths of the tab resized by the user.
+func (app *App) saveLayout(event gui.Event) {
+	if app.layout == nil || app.layout.path == "" {
+		return
+	}
+	meta := event.Payload.(gui.LayoutMetadata)
+	if meta.CurrTab == Interfaces && app.compact.isEnabled() {
+		// only the widths of the full layout are persisted
+		return
+	}
+	if err := app.layout.save(meta.CurrTab, meta.Widths); err != nil {
+		logrus.Warnf("error occured while saving layout %s: %v", app.layout.path, err)
+	}
+}
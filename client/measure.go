@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// DefaultMeasureWindow is the default duration of the timed measurement.
+const DefaultMeasureWindow = 10 * time.Second
+
+// measureTabs are the tabs of the counters cleared by the measurement,
+// the tabs are frozen to the counters accumulated within the window.
+var measureTabs = []int{Interfaces, Nodes, Errors, DropsPunts, Tunnels, Bonds}
+
+// measurement is the state of the timed measurement.
+type measurement struct {
+	sync.Mutex
+	// duration of the measurement.
+	window time.Duration
+	// end of the running measurement, zero if not running.
+	end time.Time
+	// window of the measurement the tabs are frozen to, zero if not frozen.
+	frozen time.Duration
+}
+
+// newMeasurement returns the measurement state with the window.
+func newMeasurement(window time.Duration) *measurement {
+	return &measurement{window: window}
+}
+
+// SetMeasureWindow sets the duration of the timed measurement.
+func (app *App) SetMeasureWindow(window time.Duration) {
+	app.measurement.Lock()
+	app.measurement.window = window
+	app.measurement.Unlock()
+}
+
+// measureStatus returns the state line with the countdown of the running
+// measurement or the window of the measurement shown, or an empty string.
+func (app *App) measureStatus() string {
+	m := app.measurement
+	m.Lock()
+	defer m.Unlock()
+	switch {
+	case !m.end.IsZero():
+		left := time.Until(m.end).Round(time.Second)
+		if left < 0 {
+			left = 0
+		}
+		return "\n" + i18n.T("Measuring: %v left", left)
+	case m.frozen != 0:
+		return "\n" + i18n.T("Measured: %v window (p to resume)", m.frozen)
+	}
+	return ""
+}
+
+// unfreezeMeasurement drops the window of the measurement
+// shown once the updates are resumed.
+func (app *App) unfreezeMeasurement() {
+	app.measurement.Lock()
+	app.measurement.frozen = 0
+	app.measurement.Unlock()
+}
+
+// runMeasurement clears the interface, runtime and error counters and waits
+// for the measurement window. The tabs are then frozen to the counters
+// accumulated within the window, with the average rates of the window.
+// The call is ignored if a measurement is already running.
+func (app *App) runMeasurement(ctx context.Context, collectors []*collector) {
+	m := app.measurement
+	m.Lock()
+	if !m.end.IsZero() {
+		m.Unlock()
+		return
+	}
+	window := m.window
+	m.end = time.Now().Add(window)
+	m.frozen = 0
+	m.Unlock()
+	defer func() {
+		m.Lock()
+		m.end = time.Time{}
+		m.Unlock()
+	}()
+
+	app.tabLock.Lock()
+	app.paused = nil
+	app.tabLock.Unlock()
+
+	// the tabs are reset before and after the clear as well,
+	// so that data of polls overlapping the clear is dropped
+	app.cache.reset(measureTabs...)
+	ifaceErr := app.vppProvider.ClearInterfaceCounters(ctx)
+	nodeErr := app.vppProvider.ClearRuntimeCounters(ctx)
+	app.resetBaseline()
+	errorErr := app.vppProvider.ClearErrorCounters(ctx, nil)
+	app.notifyCleared(ctx,
+		clearResult{"interface stats", ifaceErr},
+		clearResult{"node stats", nodeErr},
+		clearResult{"error stats", errorErr},
+	)
+	app.cache.reset(measureTabs...)
+
+	start := app.awaitPolls(ctx, collectors, measureTabs, time.Now())
+	m.Lock()
+	m.end = time.Now().Add(window)
+	m.Unlock()
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return
+	}
+	end := app.awaitPolls(ctx, collectors, measureTabs, time.Now())
+
+	frozen := app.cache.snapshot()
+	for tab, entry := range end {
+		if first, ok := start[tab]; ok {
+			entry.prev = first.data
+			entry.elapsed = entry.polledAt.Sub(first.polledAt)
+			entry.rates = computeRates(tab, entry.data, entry.prev, entry.elapsed)
+		}
+		frozen[tab] = entry
+	}
+	app.tabLock.Lock()
+	app.paused = frozen
+	app.tabLock.Unlock()
+	m.Lock()
+	m.frozen = window
+	m.Unlock()
+	logrus.Infof("measurement of %v done", window)
+
+	app.gui.SetPaused(true)
+	for _, tab := range app.visibleTabs() {
+		app.renderTab(tab)
+	}
+	app.notifyGui(ctx)
+}
+
+// awaitPolls triggers the collectors of the tabs and returns the cache entries
+// polled after the time. The entries of tabs not polled within the poll timeout
+// are missing.
+func (app *App) awaitPolls(ctx context.Context, collectors []*collector, tabs []int, after time.Time) map[int]cacheEntry {
+	for _, tab := range tabs {
+		triggerCollector(collectors, tab)
+	}
+	timeout := app.pollTimeout
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	entries := make(map[int]cacheEntry, len(tabs))
+	for {
+		for _, tab := range tabs {
+			if entry, ok := app.cache.load(tab); ok && entry.polledAt.After(after) {
+				entries[tab] = entry
+			}
+		}
+		if len(entries) == len(tabs) {
+			return entries
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return entries
+		case <-ctx.Done():
+			return entries
+		}
+	}
+}
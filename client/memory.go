@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMemoryTrendWindow is the default window of the main heap usage
+// the growth rate is estimated from.
+const DefaultMemoryTrendWindow = time.Hour
+
+// heapUsedRe matches the used memory of the main heap in the
+// 'show memory main-heap verbose' output, e.g. "total: 1023.99M, used: 86.14M, ..."
+var heapUsedRe = regexp.MustCompile(`used:\s+([0-9.]+)([KMGT]?)`)
+
+// memorySample is the used main heap memory of a thread at the time of a poll.
+type memorySample struct {
+	at   time.Time
+	used uint64
+}
+
+// memoryTrend keeps the used main heap memory of threads polled within the
+// window, to show the trend and the growth rate at the memory tab. Growth
+// lasting longer than alertAfter is reported as a possible memory leak.
+type memoryTrend struct {
+	sync.Mutex
+	window time.Duration
+	// alertAfter (optional) is the duration of the growth which fires the alert
+	alertAfter time.Duration
+	// samples by the thread lines, e.g. "Thread 0 vpp_main"
+	samples map[string][]memorySample
+	// threads whose growth fired the alert
+	alerting map[string]bool
+	// time of the last poll added to the samples
+	last time.Time
+}
+
+// newMemoryTrend returns an empty instance of <*memoryTrend>
+func newMemoryTrend(window time.Duration) *memoryTrend {
+	return &memoryTrend{
+		window:   window,
+		samples:  make(map[string][]memorySample),
+		alerting: make(map[string]bool),
+	}
+}
+
+// SetMemoryTrendWindow sets the window of the main heap usage the growth
+// rate is estimated from, and the duration of the growth reported as
+// a possible memory leak (disabled if zero).
+func (app *App) SetMemoryTrendWindow(window, alertAfter time.Duration) {
+	app.memory.Lock()
+	app.memory.window = window
+	app.memory.alertAfter = alertAfter
+	app.memory.Unlock()
+}
+
+// update adds the used memory of the polled threads to the window,
+// samples older than both the window and the alert duration are dropped.
+func (m *memoryTrend) update(entry cacheEntry) {
+	rows, _ := entry.data.([]string)
+	used := parseHeapUsage(rows)
+
+	m.Lock()
+	defer m.Unlock()
+	if !entry.polledAt.After(m.last) {
+		// the poll was already added
+		return
+	}
+	m.last = entry.polledAt
+	keep := m.window
+	if m.alertAfter > keep {
+		keep = m.alertAfter
+	}
+	since := entry.polledAt.Add(-keep)
+	samples := make(map[string][]memorySample, len(used))
+	for thread, bytes := range used {
+		window := m.samples[thread]
+		for len(window) > 0 && window[0].at.Before(since) {
+			window = window[1:]
+		}
+		samples[thread] = append(window, memorySample{at: entry.polledAt, used: bytes})
+	}
+	m.samples = samples
+	if m.alertAfter > 0 {
+		for thread := range samples {
+			m.checkGrowth(thread)
+		}
+	}
+}
+
+// checkGrowth fires the alert if the used memory of the thread did not return
+// to its minimum within the kept samples for the alert duration, and resolves
+// it once it does.
+func (m *memoryTrend) checkGrowth(thread string) {
+	window := m.samples[thread]
+	low := window[0]
+	for _, sample := range window {
+		if sample.used < low.used {
+			low = sample
+		}
+	}
+	last := window[len(window)-1]
+	growing := last.used > low.used && last.at.Sub(low.at) >= m.alertAfter
+	switch {
+	case growing && !m.alerting[thread]:
+		m.alerting[thread] = true
+		logrus.Warnf("possible memory leak: main heap of %s grows for %v, from %s to %s",
+			thread, last.at.Sub(low.at).Round(time.Second),
+			scaleUnits(low.used, 1024, iecSuffixes), scaleUnits(last.used, 1024, iecSuffixes))
+	case !growing && m.alerting[thread]:
+		delete(m.alerting, thread)
+		logrus.Infof("main heap of %s stopped growing", thread)
+	}
+}
+
+// trend returns the trend of the used memory of the thread since the previous
+// poll, followed by the growth rate per hour estimated from the samples
+// within the window.
+func (m *memoryTrend) trend(thread string) string {
+	m.Lock()
+	defer m.Unlock()
+	window := m.samples[thread]
+	since := m.last.Add(-m.window)
+	for len(window) > 0 && window[0].at.Before(since) {
+		window = window[1:]
+	}
+	if len(window) < 2 {
+		return "trend: collecting samples"
+	}
+	first, prev, last := window[0], window[len(window)-2], window[len(window)-1]
+	arrow := "→"
+	if last.used > prev.used {
+		arrow = "↑"
+	} else if last.used < prev.used {
+		arrow = "↓"
+	}
+	elapsed := last.at.Sub(first.at)
+	rate := (float64(last.used) - float64(first.used)) / elapsed.Hours()
+	sign := "+"
+	if rate < 0 {
+		sign, rate = "-", -rate
+	}
+	text := fmt.Sprintf("trend: %s %s%s/h over the last %v", arrow, sign,
+		scaleUnits(uint64(rate), 1024, iecSuffixes), elapsed.Round(time.Second))
+	if m.alerting[thread] {
+		text += " (possible leak)"
+	}
+	return text
+}
+
+// memorySection is a section of the memory stats, i.e. the main heap
+// of a thread or another heap, with the indented detail rows.
+type memorySection struct {
+	name    string
+	details []string
+}
+
+// memorySections splits the memory stats to sections, every section
+// starts by a row without the indentation.
+func memorySections(rows []string) []memorySection {
+	var sections []memorySection
+	for _, row := range rows {
+		trimmed := strings.TrimSpace(row)
+		if !strings.HasPrefix(row, " ") || len(sections) == 0 {
+			sections = append(sections, memorySection{name: trimmed})
+			continue
+		}
+		section := &sections[len(sections)-1]
+		section.details = append(section.details, trimmed)
+	}
+	return sections
+}
+
+// isThreadHeap returns true if the memory section is the main heap of
+// a thread, e.g. "Thread 0 vpp_main".
+func isThreadHeap(name string) bool {
+	return strings.HasPrefix(name, "Thread ")
+}
+
+// parseHeapUsage returns the used main heap memory in bytes by the thread
+// sections of the memory stats, other heaps are skipped.
+func parseHeapUsage(rows []string) map[string]uint64 {
+	used := make(map[string]uint64)
+	var thread string
+	for _, row := range rows {
+		if !strings.HasPrefix(row, " ") {
+			thread = ""
+			if isThreadHeap(row) {
+				thread = row
+			}
+			continue
+		}
+		m := heapUsedRe.FindStringSubmatch(row)
+		if m == nil || thread == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		for _, suffix := range "KMGT" {
+			if m[2] == "" {
+				break
+			}
+			value *= 1024
+			if string(suffix) == m[2] {
+				break
+			}
+		}
+		used[thread] = uint64(value)
+	}
+	return used
+}
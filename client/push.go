@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// pushContentType is the Prometheus text exposition format.
+const pushContentType = "text/plain; version=0.0.4"
+
+// PushConfig configures pushing of the collected metrics
+// to a Prometheus Pushgateway.
+type PushConfig struct {
+	// URL of the Pushgateway, pushing is disabled if empty.
+	URL string
+	// Job is the job label of the pushed metrics.
+	Job string
+	// Instance is the instance label of the pushed metrics,
+	// the hostname is used if empty.
+	Instance string
+	// Interval between two pushes.
+	Interval time.Duration
+}
+
+// SetPush enables pushing of the collected metrics while the application runs.
+func (app *App) SetPush(cfg PushConfig) {
+	if cfg.Instance == "" {
+		cfg.Instance, _ = os.Hostname()
+	}
+	app.push = &cfg
+}
+
+// runPush is a blocking call pushing the cached metrics
+// until the context is cancelled.
+func (app *App) runPush(ctx context.Context) {
+	target := fmt.Sprintf("%s/metrics/job/%s/instance/%s", strings.TrimRight(app.push.URL, "/"),
+		url.PathEscape(app.push.Job), url.PathEscape(app.push.Instance))
+	client := &http.Client{Timeout: app.push.Interval}
+
+	ticker := time.NewTicker(app.push.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			var buf bytes.Buffer
+			app.writeMetrics(&buf)
+			if buf.Len() == 0 {
+				continue
+			}
+			if err := pushMetrics(ctx, client, target, &buf); err != nil {
+				logrus.Warnf("error occured while pushing metrics: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pushMetrics replaces the metrics of the job and instance at the Pushgateway.
+func pushMetrics(ctx context.Context, client *http.Client, target string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", pushContentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// labelEscaper escapes label values of the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricWriter writes metrics in the Prometheus text exposition format,
+// the type line is written only once per metric.
+type metricWriter struct {
+	w     io.Writer
+	typed map[string]bool
+}
+
+// write writes a single sample of the metric with the labels given as name/value pairs.
+func (m *metricWriter) write(name, kind string, value float64, labels ...string) {
+	if !m.typed[name] {
+		fmt.Fprintf(m.w, "# TYPE %s %s\n", name, kind)
+		m.typed[name] = true
+	}
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], labelEscaper.Replace(labels[i+1])))
+	}
+	if len(pairs) == 0 {
+		fmt.Fprintf(m.w, "%s %v\n", name, value)
+		return
+	}
+	fmt.Fprintf(m.w, "%s{%s} %v\n", name, strings.Join(pairs, ","), value)
+}
+
+// writeMetrics writes the cached interface, node, error and thread
+// stats, tabs which were not polled yet are skipped.
+func (app *App) writeMetrics(w io.Writer) {
+	m := &metricWriter{w: w, typed: make(map[string]bool)}
+
+	if entry, ok := app.cache.load(Interfaces); ok {
+		for _, iface := range entry.data.([]api.Interface) {
+			// the instance label is reserved by Prometheus, the instance
+			// of merged interfaces is prefixed to the interface name
+			name := interfaceKey(iface)
+			m.write("vpp_interface_rx_packets_total", "counter", float64(iface.Rx.Packets), "interface", name)
+			m.write("vpp_interface_rx_bytes_total", "counter", float64(iface.Rx.Bytes), "interface", name)
+			m.write("vpp_interface_rx_errors_total", "counter", float64(iface.RxErrors), "interface", name)
+			m.write("vpp_interface_tx_packets_total", "counter", float64(iface.Tx.Packets), "interface", name)
+			m.write("vpp_interface_tx_bytes_total", "counter", float64(iface.Tx.Bytes), "interface", name)
+			m.write("vpp_interface_tx_errors_total", "counter", float64(iface.TxErrors), "interface", name)
+			m.write("vpp_interface_drops_total", "counter", float64(iface.Drops), "interface", name)
+			m.write("vpp_interface_punts_total", "counter", float64(iface.Punts), "interface", name)
+			m.write("vpp_interface_rx_no_buf_total", "counter", float64(iface.RxNoBuf), "interface", name)
+			m.write("vpp_interface_rx_miss_total", "counter", float64(iface.RxMiss), "interface", name)
+			up := 0.0
+			if iface.State == "up" {
+				up = 1
+			}
+			m.write("vpp_interface_up", "gauge", up, "interface", name)
+		}
+	}
+
+	if entry, ok := app.cache.load(Nodes); ok {
+		// nodes are listed per thread, counters are summed up
+		var names []string
+		nodes := make(map[string]*api.Node)
+		for _, node := range entry.data.([]api.Node) {
+			total, ok := nodes[node.Name]
+			if !ok {
+				names = append(names, node.Name)
+				nodes[node.Name] = &api.Node{Name: node.Name}
+				total = nodes[node.Name]
+			}
+			total.Calls += node.Calls
+			total.Vectors += node.Vectors
+			total.Suspends += node.Suspends
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			m.write("vpp_node_calls_total", "counter", float64(nodes[name].Calls), "node", name)
+			m.write("vpp_node_vectors_total", "counter", float64(nodes[name].Vectors), "node", name)
+			m.write("vpp_node_suspends_total", "counter", float64(nodes[name].Suspends), "node", name)
+		}
+	}
+
+	if entry, ok := app.cache.load(Errors); ok {
+		// the same node and reason may be reported more than once
+		type errorKey struct{ node, reason string }
+		var keys []errorKey
+		counts := make(map[errorKey]uint64)
+		for _, e := range entry.data.([]api.Error) {
+			key := errorKey{e.Node, e.Reason}
+			if _, ok := counts[key]; !ok {
+				keys = append(keys, key)
+			}
+			counts[key] += e.Count
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].node != keys[j].node {
+				return keys[i].node < keys[j].node
+			}
+			return keys[i].reason < keys[j].reason
+		})
+		for _, key := range keys {
+			m.write("vpp_error_count_total", "counter", float64(counts[key]), "node", key.node, "reason", key.reason)
+		}
+	}
+
+	if entry, ok := app.cache.load(Threads); ok {
+		for _, thread := range entry.data.([]api.ThreadData) {
+			if thread.Utilization < 0 {
+				continue
+			}
+			m.write("vpp_thread_utilization_ratio", "gauge", thread.Utilization/100,
+				"thread", fmt.Sprint(thread.ID), "name", thread.Name)
+		}
+	}
+}
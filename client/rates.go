@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Rate columns of the interfaces.
+const (
+	ifaceRxPacketRate = iota
+	ifaceRxByteRate
+	ifaceTxPacketRate
+	ifaceTxByteRate
+	ifaceRxUtil
+	ifaceTxUtil
+)
+
+// Rate columns of the nodes.
+const (
+	nodeCallRate = iota
+	nodeVectorRate
+)
+
+// Rate columns of the drops/punts.
+const (
+	dropPuntCountRate = iota
+)
+
+// Rate columns of the tunnels.
+const (
+	tunnelRxPacketRate = iota
+	tunnelRxByteRate
+	tunnelTxPacketRate
+	tunnelTxByteRate
+)
+
+// Rate columns of the bond members.
+const (
+	bondRxPacketRate = iota
+	bondTxPacketRate
+)
+
+// Rate columns of the policers.
+const (
+	policerConformRate = iota
+	policerExceedRate
+	policerViolateRate
+)
+
+// Rate columns of the srv6 SIDs.
+const (
+	srv6PacketRate = iota
+	srv6ByteRate
+)
+
+// Rate columns of the mpls entries.
+const (
+	mplsPacketRate = iota
+	mplsByteRate
+)
+
+// rateColumn is a column derived from the counters of two subsequent polls.
+type rateColumn struct {
+	// name of the column in filter expressions.
+	name string
+	// rate returns the rate of the item against the previously polled item.
+	rate func(curr, prev interface{}, elapsed time.Duration) float64
+}
+
+// rateSource describes the rate columns of a tab.
+type rateSource struct {
+	// key identifies the stats item among the polls.
+	key func(item interface{}) string
+	// columns indexed by the rate column constants of the tab.
+	columns []rateColumn
+	// counted returns false for items without counters (optional), their
+	// rates are not known until they are counted in two polls.
+	counted func(item interface{}) bool
+}
+
+// counterRate returns the rate function of the counter per second.
+func counterRate(counter func(item interface{}) uint64) func(curr, prev interface{}, elapsed time.Duration) float64 {
+	return func(curr, prev interface{}, elapsed time.Duration) float64 {
+		return float64(perSecond(counter(curr), counter(prev), elapsed))
+	}
+}
+
+// linkUtilization returns the rate function of the link utilization in percent
+// by the byte counter, zero if the link speed is not known.
+func linkUtilization(counter func(iface api.Interface) uint64) func(curr, prev interface{}, elapsed time.Duration) float64 {
+	return func(curr, prev interface{}, elapsed time.Duration) float64 {
+		iface := curr.(api.Interface)
+		if iface.Device.LinkSpeed == 0 {
+			return 0
+		}
+		bytesPerSec := perSecond(counter(iface), counter(prev.(api.Interface)), elapsed)
+		return float64(bytesPerSec*8) / float64(iface.Device.LinkSpeed*1000) * 100
+	}
+}
+
+// rateSources is the registry of the rate columns per tab, the rates
+// are sortable and usable in filter expressions like the counters.
+var rateSources = map[int]rateSource{
+	Interfaces: {
+		key:     func(i interface{}) string { return interfaceKey(i.(api.Interface)) },
+		counted: func(i interface{}) bool { return !i.(api.Interface).NoStats },
+		columns: []rateColumn{
+			ifaceRxPacketRate: {"rxpackets/s", counterRate(func(i interface{}) uint64 { return i.(api.Interface).Rx.Packets })},
+			ifaceRxByteRate:   {"rxbytes/s", counterRate(func(i interface{}) uint64 { return i.(api.Interface).Rx.Bytes })},
+			ifaceTxPacketRate: {"txpackets/s", counterRate(func(i interface{}) uint64 { return i.(api.Interface).Tx.Packets })},
+			ifaceTxByteRate:   {"txbytes/s", counterRate(func(i interface{}) uint64 { return i.(api.Interface).Tx.Bytes })},
+			ifaceRxUtil:       {"rxutil", linkUtilization(func(iface api.Interface) uint64 { return iface.Rx.Bytes })},
+			ifaceTxUtil:       {"txutil", linkUtilization(func(iface api.Interface) uint64 { return iface.Tx.Bytes })},
+		},
+	},
+	Nodes: {
+		key: func(i interface{}) string { return i.(api.Node).Name },
+		columns: []rateColumn{
+			nodeCallRate:   {"calls/s", counterRate(func(i interface{}) uint64 { return i.(api.Node).Calls })},
+			nodeVectorRate: {"vectors/s", counterRate(func(i interface{}) uint64 { return i.(api.Node).Vectors })},
+		},
+	},
+	DropsPunts: {
+		key: func(i interface{}) string {
+			dropPunt := i.(api.DropPunt)
+			return dropPunt.Type + dropPunt.Node + dropPunt.Reason
+		},
+		columns: []rateColumn{
+			dropPuntCountRate: {"count/s", counterRate(func(i interface{}) uint64 { return i.(api.DropPunt).Count })},
+		},
+	},
+	Tunnels: {
+		key: func(i interface{}) string { return fmt.Sprint(i.(api.TunnelCounters).SwIfIndex) },
+		columns: []rateColumn{
+			tunnelRxPacketRate: {"rxpackets/s", counterRate(func(i interface{}) uint64 { return i.(api.TunnelCounters).Rx.Packets })},
+			tunnelRxByteRate:   {"rxbytes/s", counterRate(func(i interface{}) uint64 { return i.(api.TunnelCounters).Rx.Bytes })},
+			tunnelTxPacketRate: {"txpackets/s", counterRate(func(i interface{}) uint64 { return i.(api.TunnelCounters).Tx.Packets })},
+			tunnelTxByteRate:   {"txbytes/s", counterRate(func(i interface{}) uint64 { return i.(api.TunnelCounters).Tx.Bytes })},
+		},
+	},
+	Bonds: {
+		key: func(i interface{}) string { return i.(api.BondMember).Member },
+		columns: []rateColumn{
+			bondRxPacketRate: {"rxpackets/s", counterRate(func(i interface{}) uint64 { return i.(api.BondMember).Rx.Packets })},
+			bondTxPacketRate: {"txpackets/s", counterRate(func(i interface{}) uint64 { return i.(api.BondMember).Tx.Packets })},
+		},
+	},
+	Policers: {
+		key: func(i interface{}) string { return i.(api.Policer).Name },
+		columns: []rateColumn{
+			policerConformRate: {"conform/s", counterRate(func(i interface{}) uint64 { return i.(api.Policer).Conform.Packets })},
+			policerExceedRate:  {"exceed/s", counterRate(func(i interface{}) uint64 { return i.(api.Policer).Exceed.Packets })},
+			policerViolateRate: {"violate/s", counterRate(func(i interface{}) uint64 { return i.(api.Policer).Violate.Packets })},
+		},
+	},
+	SRv6: {
+		key: func(i interface{}) string { return srv6Kind(i.(api.SRv6SID)) + i.(api.SRv6SID).SID },
+		columns: []rateColumn{
+			srv6PacketRate: {"packets/s", counterRate(func(i interface{}) uint64 { return i.(api.SRv6SID).Good.Packets })},
+			srv6ByteRate:   {"bytes/s", counterRate(func(i interface{}) uint64 { return i.(api.SRv6SID).Good.Bytes })},
+		},
+	},
+	Mpls: {
+		key: func(i interface{}) string {
+			return fmt.Sprintf("%s/%d", mplsLabel(i.(api.MplsEntry)), i.(api.MplsEntry).TableID)
+		},
+		columns: []rateColumn{
+			mplsPacketRate: {"packets/s", counterRate(func(i interface{}) uint64 { return i.(api.MplsEntry).Counter.Packets })},
+			mplsByteRate:   {"bytes/s", counterRate(func(i interface{}) uint64 { return i.(api.MplsEntry).Counter.Bytes })},
+		},
+	},
+}
+
+// statsRates are the rates of the stats items of a tab by the item key,
+// each item has a rate per rate column of the tab.
+type statsRates struct {
+	key   func(item interface{}) string
+	rates map[string][]float64
+}
+
+// computeRates returns the rates of the stats items (a slice) against
+// the previously polled items. Items not polled before have zero rates.
+func computeRates(tab int, data, prev interface{}, elapsed time.Duration) *statsRates {
+	source, ok := rateSources[tab]
+	if !ok || data == nil {
+		return nil
+	}
+	r := &statsRates{
+		key:   source.key,
+		rates: make(map[string][]float64),
+	}
+	if prev == nil || elapsed <= 0 {
+		return r
+	}
+	last := make(map[string]interface{})
+	prevItems := reflect.ValueOf(prev)
+	for i := 0; i < prevItems.Len(); i++ {
+		item := prevItems.Index(i).Interface()
+		if source.counted != nil && !source.counted(item) {
+			continue
+		}
+		last[source.key(item)] = item
+	}
+	items := reflect.ValueOf(data)
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i).Interface()
+		key := source.key(item)
+		prevItem, ok := last[key]
+		if !ok || source.counted != nil && !source.counted(item) {
+			continue
+		}
+		rates := make([]float64, len(source.columns))
+		for column, c := range source.columns {
+			rates[column] = c.rate(item, prevItem, elapsed)
+		}
+		r.rates[key] = rates
+	}
+	return r
+}
+
+// of returns the rates of the item, nil if not known.
+func (r *statsRates) of(item interface{}) []float64 {
+	if r == nil {
+		return nil
+	}
+	return r.rates[r.key(item)]
+}
+
+// get returns the rate of the column of the item, zero if not known.
+func (r *statsRates) get(item interface{}, column int) float64 {
+	if rates := r.of(item); rates != nil {
+		return rates[column]
+	}
+	return 0
+}
+
+// count returns the rate of the column of the item as a counter.
+func (r *statsRates) count(item interface{}, column int) uint64 {
+	return uint64(r.get(item, column))
+}
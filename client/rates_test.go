@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// testInterface returns an interface with the rx counters.
+func testInterface(name string, packets, bytes, speed uint64) api.Interface {
+	iface := api.Interface{Device: api.DeviceDetails{LinkSpeed: speed}}
+	iface.InterfaceName = name
+	iface.Rx.Packets, iface.Rx.Bytes = packets, bytes
+	return iface
+}
+
+func TestComputeRates(t *testing.T) {
+	prev := []api.Interface{
+		testInterface("GigabitEthernet0/8/0", 1000, 0, 10000000),
+		testInterface("tap0", 10, 1000, 0),
+	}
+	curr := []api.Interface{
+		testInterface("GigabitEthernet0/8/0", 3000, 250000000, 10000000),
+		testInterface("tap0", 30, 3000, 0),
+		testInterface("loop0", 50, 5000, 0),
+	}
+	rates := computeRates(Interfaces, curr, prev, 2*time.Second)
+
+	tests := []struct {
+		iface  api.Interface
+		column int
+		want   float64
+	}{
+		{iface: curr[0], column: ifaceRxPacketRate, want: 1000},
+		{iface: curr[0], column: ifaceRxByteRate, want: 125000000},
+		{iface: curr[0], column: ifaceRxUtil, want: 10},
+		{iface: curr[1], column: ifaceRxPacketRate, want: 10},
+		{iface: curr[1], column: ifaceRxUtil, want: 0},
+		{iface: curr[2], column: ifaceRxPacketRate, want: 0},
+	}
+	for _, test := range tests {
+		if got := rates.get(test.iface, test.column); got != test.want {
+			t.Errorf("Error occured interface:%s column:%d got:%v; want:%v", test.iface.InterfaceName, test.column, got, test.want)
+		}
+	}
+
+	expr, err := parseFilter(Interfaces, "rxpackets/s>=10 && rxutil<5")
+	if err != nil {
+		t.Fatalf("Error occured got:%v; want:nil", err)
+	}
+	var matched []string
+	for _, iface := range curr {
+		if expr.match(iface, rates) {
+			matched = append(matched, iface.InterfaceName)
+		}
+	}
+	if len(matched) != 1 || matched[0] != "tap0" {
+		t.Errorf("Error occured got:%v; want:[tap0]", matched)
+	}
+
+	app := &App{}
+	app.sortInterfaceStats(curr, rates, IfaceStatRxPacketRate, false)
+	for i, want := range []string{"GigabitEthernet0/8/0", "tap0", "loop0"} {
+		if curr[i].InterfaceName != want {
+			t.Errorf("Error occured index:%d got:%s; want:%s", i, curr[i].InterfaceName, want)
+		}
+	}
+}
+
+func TestComputeRatesNoStats(t *testing.T) {
+	uncounted := testInterface("tap1", 0, 0, 0)
+	uncounted.NoStats = true
+	prev := []api.Interface{uncounted, testInterface("tap0", 10, 1000, 0)}
+	curr := []api.Interface{
+		testInterface("tap1", 5000, 500000, 0),
+		testInterface("tap0", 30, 3000, 0),
+	}
+	rates := computeRates(Interfaces, curr, prev, 2*time.Second)
+
+	if got := rates.of(curr[0]); got != nil {
+		t.Errorf("Error occured interface:tap1 got:%v; want:nil", got)
+	}
+	if got := rates.get(curr[1], ifaceRxPacketRate); got != 10 {
+		t.Errorf("Error occured interface:tap0 got:%v; want:10", got)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// manualRefresh holds the tabs with the auto-refresh turned off, their
+// collectors poll on demand only (F5 or Ctrl-R).
+type manualRefresh struct {
+	sync.RWMutex
+	tabs map[int]bool
+}
+
+// newManualRefresh returns an instance of <*manualRefresh> with
+// the auto-refresh of all tabs on.
+func newManualRefresh() *manualRefresh {
+	return &manualRefresh{tabs: make(map[int]bool)}
+}
+
+// isManual returns true if the auto-refresh of the tab is off.
+func (m *manualRefresh) isManual(tab int) bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.tabs[tab]
+}
+
+// set turns the auto-refresh of the tab on or off.
+func (m *manualRefresh) set(tab int, manual bool) {
+	m.Lock()
+	defer m.Unlock()
+	if manual {
+		m.tabs[tab] = true
+	} else {
+		delete(m.tabs, tab)
+	}
+}
+
+// SetManualRefresh turns off the auto-refresh of the tabs given by the names
+// of their HTTP endpoints (e.g. memory), they are polled once at the start
+// and then refreshed on demand by F5 until the auto-refresh is toggled back.
+func (app *App) SetManualRefresh(names []string) error {
+	tabs := make([]int, 0, len(names))
+	for _, name := range names {
+		tab, ok := httpEndpoints["/"+name]
+		if !ok {
+			return fmt.Errorf("unknown tab %q, one of: %s", name, strings.Join(controlTabNames(), ", "))
+		}
+		tabs = append(tabs, tab)
+	}
+	for _, tab := range tabs {
+		app.manual.set(tab, true)
+	}
+	app.gui.SetManualRefreshTabs(tabs...)
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotBundle is a snapshot bundle captured with a sync tag.
+type SnapshotBundle struct {
+	// Dir is the directory of the bundle
+	Dir  string
+	Tag  string
+	Host string
+	// Time is the time the bundle was captured at
+	Time time.Time
+}
+
+// SnapshotPair are the bundles of both ends of a link
+// captured with the same tag within the tolerance.
+type SnapshotPair struct {
+	A, B SnapshotBundle
+}
+
+// Offset returns the time of the bundle B relative to the bundle A.
+func (p SnapshotPair) Offset() time.Duration {
+	return p.B.Time.Sub(p.A.Time)
+}
+
+// loadSnapshotBundles returns the tagged bundles of the snapshot directory
+// ordered by their time. Bundles captured without a sync tag are skipped.
+func loadSnapshotBundles(dir string) ([]SnapshotBundle, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var bundles []SnapshotBundle
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		bundle, ok, err := loadSnapshotBundle(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			bundles = append(bundles, bundle)
+		}
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Time.Before(bundles[j].Time) })
+	return bundles, nil
+}
+
+// loadSnapshotBundle reads the sync tag, the host and the UTC time of the
+// bundle from its alert.txt. The bundle is not ok if it is not tagged.
+func loadSnapshotBundle(dir string) (SnapshotBundle, bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "alert.txt"))
+	if os.IsNotExist(err) {
+		return SnapshotBundle{}, false, nil
+	} else if err != nil {
+		return SnapshotBundle{}, false, err
+	}
+	bundle := SnapshotBundle{Dir: dir}
+	var utc string
+	for _, line := range strings.Split(string(data), "\n") {
+		i := strings.Index(line, ": ")
+		if i < 0 {
+			continue
+		}
+		switch value := line[i+2:]; line[:i] {
+		case "tag":
+			bundle.Tag = value
+		case "host":
+			bundle.Host = value
+		case "utc":
+			utc = value
+		}
+	}
+	if bundle.Tag == "" || utc == "" {
+		return SnapshotBundle{}, false, nil
+	}
+	if bundle.Time, err = time.Parse(time.RFC3339Nano, utc); err != nil {
+		return SnapshotBundle{}, false, fmt.Errorf("invalid time of the snapshot %s: %v", dir, err)
+	}
+	return bundle, true, nil
+}
+
+// pairSnapshots pairs the bundles of both ends with the same tag captured
+// within the tolerance, the closest bundles in time are paired first. The
+// pairs are ordered by the time of the bundle A, the bundles which are not
+// paired are returned as well.
+func pairSnapshots(a, b []SnapshotBundle, tolerance time.Duration) (pairs []SnapshotPair, unpairedA, unpairedB []SnapshotBundle) {
+	type candidate struct {
+		i, j   int
+		offset time.Duration
+	}
+	var candidates []candidate
+	for i := range a {
+		for j := range b {
+			offset := time.Duration(math.Abs(float64(b[j].Time.Sub(a[i].Time))))
+			if a[i].Tag == b[j].Tag && offset <= tolerance {
+				candidates = append(candidates, candidate{i: i, j: j, offset: offset})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(x, y int) bool { return candidates[x].offset < candidates[y].offset })
+
+	pairedA, pairedB := make(map[int]bool), make(map[int]bool)
+	for _, c := range candidates {
+		if pairedA[c.i] || pairedB[c.j] {
+			continue
+		}
+		pairedA[c.i], pairedB[c.j] = true, true
+		pairs = append(pairs, SnapshotPair{A: a[c.i], B: b[c.j]})
+	}
+	sort.Slice(pairs, func(x, y int) bool { return pairs[x].A.Time.Before(pairs[y].A.Time) })
+	for i := range a {
+		if !pairedA[i] {
+			unpairedA = append(unpairedA, a[i])
+		}
+	}
+	for j := range b {
+		if !pairedB[j] {
+			unpairedB = append(unpairedB, b[j])
+		}
+	}
+	return pairs, unpairedA, unpairedB
+}
+
+// MergeResult are the bundles merged by MergeSnapshots.
+type MergeResult struct {
+	Pairs []SnapshotPair
+	// Unpaired are the tagged bundles of both ends without a counterpart
+	Unpaired []SnapshotBundle
+}
+
+// MergeSnapshots merges the tagged bundles of the snapshot directories of
+// both ends of a link into the output directory. The bundles are paired by
+// the tag and by the time within the tolerance, each pair is aligned side by
+// side in a 'vpptop-<tag>-<UTC time>' directory by the time of the bundle A
+// with the bundles copied to the '1-<host>' and '2-<host>' subdirectories and
+// the offset of the bundle B recorded in sync.txt.
+func MergeSnapshots(dirA, dirB, out string, tolerance time.Duration) (*MergeResult, error) {
+	a, err := loadSnapshotBundles(dirA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := loadSnapshotBundles(dirB)
+	if err != nil {
+		return nil, err
+	}
+	pairs, unpairedA, unpairedB := pairSnapshots(a, b, tolerance)
+	for _, pair := range pairs {
+		dir := filepath.Join(out, snapshotName(pair.A.Tag, pair.A.Time))
+		for i, bundle := range []SnapshotBundle{pair.A, pair.B} {
+			if err := copyDir(bundle.Dir, filepath.Join(dir, fmt.Sprintf("%d-%s", i+1, bundle.Host))); err != nil {
+				return nil, err
+			}
+		}
+		meta := fmt.Sprintf("tag: %s\nhost 1: %s\nutc 1: %s\nhost 2: %s\nutc 2: %s\noffset: %v\n",
+			pair.A.Tag, pair.A.Host, pair.A.Time.Format(time.RFC3339Nano),
+			pair.B.Host, pair.B.Time.Format(time.RFC3339Nano), pair.Offset())
+		if err := ioutil.WriteFile(filepath.Join(dir, "sync.txt"), []byte(meta), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return &MergeResult{Pairs: pairs, Unpaired: append(unpairedA, unpairedB...)}, nil
+}
+
+// copyDir copies the files of the bundle directory to the destination.
+func copyDir(src, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dst, entry.Name()), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
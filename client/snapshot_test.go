@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeBundle writes a snapshot bundle captured at the time, the bundle
+// is not tagged if the tag is empty.
+func writeBundle(t *testing.T, dir, tag, host string, at time.Time) {
+	bundle := filepath.Join(dir, snapshotName(tag, at))
+	if err := os.MkdirAll(bundle, 0755); err != nil {
+		t.Fatalf("Error occured: %v", err)
+	}
+	alert := fmt.Sprintf("rule: interfaces: rxmiss>0\ntime: %s\nmatches: 1\n", at.Format(time.RFC3339))
+	if tag != "" {
+		alert += fmt.Sprintf("tag: %s\nhost: %s\nutc: %s\n", tag, host, at.UTC().Format(time.RFC3339Nano))
+	}
+	for file, data := range map[string]string{"alert.txt": alert, "interfaces.json": "[]"} {
+		if err := ioutil.WriteFile(filepath.Join(bundle, file), []byte(data), 0644); err != nil {
+			t.Fatalf("Error occured: %v", err)
+		}
+	}
+}
+
+func TestMergeSnapshots(t *testing.T) {
+	dirA, dirB, out := t.TempDir(), t.TempDir(), t.TempDir()
+	at := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	// the second boundary is crossed within the tolerance
+	writeBundle(t, dirA, "link", "router-a", at.Add(900*time.Millisecond))
+	writeBundle(t, dirB, "link", "router-b", at.Add(2100*time.Millisecond))
+	// the closest bundle of the same tag is paired
+	writeBundle(t, dirA, "link", "router-a", at.Add(time.Minute))
+	writeBundle(t, dirB, "link", "router-b", at.Add(time.Minute+4*time.Second))
+	writeBundle(t, dirB, "link", "router-b", at.Add(time.Minute-time.Second))
+	// other tags and bundles out of the tolerance are not paired
+	writeBundle(t, dirA, "other", "router-a", at.Add(2*time.Minute))
+	writeBundle(t, dirB, "link", "router-b", at.Add(2*time.Minute))
+	writeBundle(t, dirA, "link", "router-a", at.Add(3*time.Minute))
+	writeBundle(t, dirB, "link", "router-b", at.Add(3*time.Minute+10*time.Second))
+	// bundles without the tag are skipped
+	writeBundle(t, dirA, "", "", at.Add(4*time.Minute))
+
+	result, err := MergeSnapshots(dirA, dirB, out, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Error occured: %v", err)
+	}
+	var got []string
+	for _, pair := range result.Pairs {
+		got = append(got, fmt.Sprintf("%s %s", pair.A.Time.Format("15:04:05.0"), pair.Offset()))
+	}
+	want := []string{"05:06:07.9 1.2s", "05:07:07.0 -1s"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Error occured got:%q; want:%q", got, want)
+	}
+	if len(result.Unpaired) != 5 {
+		t.Errorf("Error occured got %d unpaired bundles; want 5", len(result.Unpaired))
+	}
+
+	pair := filepath.Join(out, "vpptop-link-20210304-050607")
+	for _, file := range []string{"1-router-a/alert.txt", "1-router-a/interfaces.json", "2-router-b/interfaces.json", "sync.txt"} {
+		if _, err := os.Stat(filepath.Join(pair, file)); err != nil {
+			t.Errorf("Error occured: %v", err)
+		}
+	}
+	sync, err := ioutil.ReadFile(filepath.Join(pair, "sync.txt"))
+	if err != nil {
+		t.Fatalf("Error occured: %v", err)
+	}
+	want1 := "tag: link\nhost 1: router-a\nutc 1: 2021-03-04T05:06:07.9Z\nhost 2: router-b\nutc 2: 2021-03-04T05:06:09.1Z\noffset: 1.2s\n"
+	if string(sync) != want1 {
+		t.Errorf("Error occured got:%q; want:%q", sync, want1)
+	}
+}
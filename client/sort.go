@@ -21,8 +21,23 @@ import (
 	"sort"
 )
 
+// lessRate compares the rates of the column of two stats items, items with
+// equal rates are ordered by their keys, so that the order is kept among
+// the polls. The rate sort fields of each tab follow the order of its rate
+// columns.
+func lessRate(rates *statsRates, a, b interface{}, column int, ascending bool) bool {
+	ra, rb := rates.get(a, column), rates.get(b, column)
+	if ra == rb && rates != nil {
+		return rates.key(a) < rates.key(b)
+	}
+	if ascending {
+		return ra < rb
+	}
+	return ra > rb
+}
+
 // sortNodeStats sort the slice based specified field
-func (app *App) sortNodeStats(nodeStats []api.Node, field int, ascending bool) {
+func (app *App) sortNodeStats(nodeStats []api.Node, rates *statsRates, field int, ascending bool) {
 	if field == NoColumn {
 		return
 	}
@@ -78,12 +93,39 @@ func (app *App) sortNodeStats(nodeStats []api.Node, field int, ascending bool) {
 			}
 			return nodeStats[i].VectorsPerCall > nodeStats[j].VectorsPerCall
 		}
+	case NodeStatNodeMaxClocks:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return nodeStats[i].MaxClocks < nodeStats[j].MaxClocks
+			}
+			return nodeStats[i].MaxClocks > nodeStats[j].MaxClocks
+		}
+	case NodeStatNodeVectorsAtMax:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return nodeStats[i].VectorsAtMax < nodeStats[j].VectorsAtMax
+			}
+			return nodeStats[i].VectorsAtMax > nodeStats[j].VectorsAtMax
+		}
+	case NodeStatNodeClocksPercent:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return nodeStats[i].ClocksPercent < nodeStats[j].ClocksPercent
+			}
+			return nodeStats[i].ClocksPercent > nodeStats[j].ClocksPercent
+		}
+	case NodeStatCallRate, NodeStatVectorRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, nodeStats[i], nodeStats[j], field-NodeStatCallRate, ascending)
+		}
+	default:
+		return
 	}
 	sort.Slice(nodeStats, sortFunc)
 }
 
 // sortInterfaceStats sort the slice based on the specified field
-func (app *App) sortInterfaceStats(interfaceStats []api.Interface, field int, ascending bool) {
+func (app *App) sortInterfaceStats(interfaceStats []api.Interface, rates *statsRates, field int, ascending bool) {
 	if field == NoColumn {
 		return
 	}
@@ -292,8 +334,62 @@ func (app *App) sortInterfaceStats(interfaceStats []api.Interface, field int, as
 			}
 			return interfaceStats[i].IP6 > interfaceStats[j].IP6
 		}
+	case IfaceStatIfaceLink:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return interfaceStats[i].LinkState < interfaceStats[j].LinkState
+			}
+			return interfaceStats[i].LinkState > interfaceStats[j].LinkState
+		}
+	case IfaceStatIfaceVrf:
+		sortFunc = func(i, j int) bool {
+			vi, vj := interfaceStats[i], interfaceStats[j]
+			if vi.VrfIPv4 == vj.VrfIPv4 {
+				if ascending {
+					return vi.VrfIPv6 < vj.VrfIPv6
+				}
+				return vi.VrfIPv6 > vj.VrfIPv6
+			}
+			if ascending {
+				return vi.VrfIPv4 < vj.VrfIPv4
+			}
+			return vi.VrfIPv4 > vj.VrfIPv4
+		}
+	case IfaceStatIfaceInstance:
+		sortFunc = func(i, j int) bool {
+			if interfaceStats[i].Instance == interfaceStats[j].Instance {
+				return interfaceStats[i].InterfaceIndex < interfaceStats[j].InterfaceIndex
+			}
+			if ascending {
+				return interfaceStats[i].Instance < interfaceStats[j].Instance
+			}
+			return interfaceStats[i].Instance > interfaceStats[j].Instance
+		}
+	case IfaceStatTopTalkersAvg, IfaceStatTopTalkersPeak:
+		rates := make(map[string]uint64, len(interfaceStats))
+		for _, iface := range interfaceStats {
+			avg, peak := app.talkers.rates(interfaceKey(iface))
+			if field == IfaceStatTopTalkersAvg {
+				rates[interfaceKey(iface)] = avg
+			} else {
+				rates[interfaceKey(iface)] = peak
+			}
+		}
+		sortFunc = func(i, j int) bool {
+			ri, rj := rates[interfaceKey(interfaceStats[i])], rates[interfaceKey(interfaceStats[j])]
+			if ascending {
+				return ri < rj
+			}
+			return ri > rj
+		}
+	case IfaceStatRxPacketRate, IfaceStatRxByteRate, IfaceStatTxPacketRate, IfaceStatTxByteRate, IfaceStatRxUtil, IfaceStatTxUtil:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, interfaceStats[i], interfaceStats[j], field-IfaceStatRxPacketRate, ascending)
+		}
+	default:
+		return
 	}
-	sort.Slice(interfaceStats, sortFunc)
+	sort.SliceStable(interfaceStats, sortFunc)
 }
 
 // sortErrorStats sorts the slice based on the specified field
@@ -334,3 +430,527 @@ func (app *App) sortErrorStats(errorStats []api.Error, field int, ascending bool
 	}
 	sort.Slice(errorStats, sortFunc)
 }
+
+// sortDropPuntStats sorts the slice based on the specified field
+func (app *App) sortDropPuntStats(dropPuntStats []api.DropPunt, rates *statsRates, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case DropPuntStatType:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return dropPuntStats[i].Type < dropPuntStats[j].Type
+			}
+			return dropPuntStats[i].Type > dropPuntStats[j].Type
+		}
+	case DropPuntStatNode:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return dropPuntStats[i].Node < dropPuntStats[j].Node
+			}
+			return dropPuntStats[i].Node > dropPuntStats[j].Node
+		}
+	case DropPuntStatReason:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return dropPuntStats[i].Reason < dropPuntStats[j].Reason
+			}
+			return dropPuntStats[i].Reason > dropPuntStats[j].Reason
+		}
+	case DropPuntStatCount:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return dropPuntStats[i].Count < dropPuntStats[j].Count
+			}
+			return dropPuntStats[i].Count > dropPuntStats[j].Count
+		}
+	case DropPuntStatCountRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, dropPuntStats[i], dropPuntStats[j], dropPuntCountRate, ascending)
+		}
+	default:
+		return
+	}
+	sort.Slice(dropPuntStats, sortFunc)
+}
+
+// sortTunnelStats sort the slice based specified field
+func (app *App) sortTunnelStats(tunnelStats []api.TunnelCounters, rates *statsRates, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case TunnelStatName:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].InterfaceName < tunnelStats[j].InterfaceName
+			}
+			return tunnelStats[i].InterfaceName > tunnelStats[j].InterfaceName
+		}
+	case TunnelStatType:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Type < tunnelStats[j].Type
+			}
+			return tunnelStats[i].Type > tunnelStats[j].Type
+		}
+	case TunnelStatSrc:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Src < tunnelStats[j].Src
+			}
+			return tunnelStats[i].Src > tunnelStats[j].Src
+		}
+	case TunnelStatDst:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Dst < tunnelStats[j].Dst
+			}
+			return tunnelStats[i].Dst > tunnelStats[j].Dst
+		}
+	case TunnelStatID:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].ID < tunnelStats[j].ID
+			}
+			return tunnelStats[i].ID > tunnelStats[j].ID
+		}
+	case TunnelStatRxPackets:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Rx.Packets < tunnelStats[j].Rx.Packets
+			}
+			return tunnelStats[i].Rx.Packets > tunnelStats[j].Rx.Packets
+		}
+	case TunnelStatRxBytes:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Rx.Bytes < tunnelStats[j].Rx.Bytes
+			}
+			return tunnelStats[i].Rx.Bytes > tunnelStats[j].Rx.Bytes
+		}
+	case TunnelStatTxPackets:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Tx.Packets < tunnelStats[j].Tx.Packets
+			}
+			return tunnelStats[i].Tx.Packets > tunnelStats[j].Tx.Packets
+		}
+	case TunnelStatTxBytes:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tunnelStats[i].Tx.Bytes < tunnelStats[j].Tx.Bytes
+			}
+			return tunnelStats[i].Tx.Bytes > tunnelStats[j].Tx.Bytes
+		}
+	case TunnelStatRxPacketRate, TunnelStatRxByteRate, TunnelStatTxPacketRate, TunnelStatTxByteRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, tunnelStats[i], tunnelStats[j], field-TunnelStatRxPacketRate, ascending)
+		}
+	default:
+		return
+	}
+	sort.Slice(tunnelStats, sortFunc)
+}
+
+// sortSessionStats sort the slice based specified field
+func (app *App) sortSessionStats(sessionStats []api.SessionStat, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case SessionStatNamespace:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sessionStats[i].Namespace < sessionStats[j].Namespace
+			}
+			return sessionStats[i].Namespace > sessionStats[j].Namespace
+		}
+	case SessionStatProtocol:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sessionStats[i].Protocol < sessionStats[j].Protocol
+			}
+			return sessionStats[i].Protocol > sessionStats[j].Protocol
+		}
+	case SessionStatState:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sessionStats[i].State < sessionStats[j].State
+			}
+			return sessionStats[i].State > sessionStats[j].State
+		}
+	case SessionStatCount:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sessionStats[i].Count < sessionStats[j].Count
+			}
+			return sessionStats[i].Count > sessionStats[j].Count
+		}
+	default:
+		return
+	}
+	sort.Slice(sessionStats, sortFunc)
+}
+
+// sortFeatureArcs sort the slice based specified field
+func (app *App) sortFeatureArcs(features []api.FeatureArc, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case FeatureStatInterface:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return features[i].Interface < features[j].Interface
+			}
+			return features[i].Interface > features[j].Interface
+		}
+	case FeatureStatArc:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return features[i].Arc < features[j].Arc
+			}
+			return features[i].Arc > features[j].Arc
+		}
+	default:
+		return
+	}
+	sort.Slice(features, sortFunc)
+}
+
+// sortBondMembers sort the slice based specified field
+func (app *App) sortBondMembers(members []api.BondMember, rates *statsRates, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case BondStatBond:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return members[i].Bond < members[j].Bond
+			}
+			return members[i].Bond > members[j].Bond
+		}
+	case BondStatMember:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return members[i].Member < members[j].Member
+			}
+			return members[i].Member > members[j].Member
+		}
+	case BondStatRxPackets:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return members[i].Rx.Packets < members[j].Rx.Packets
+			}
+			return members[i].Rx.Packets > members[j].Rx.Packets
+		}
+	case BondStatTxPackets:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return members[i].Tx.Packets < members[j].Tx.Packets
+			}
+			return members[i].Tx.Packets > members[j].Tx.Packets
+		}
+	case BondStatRxPacketRate, BondStatTxPacketRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, members[i], members[j], field-BondStatRxPacketRate, ascending)
+		}
+	default:
+		return
+	}
+	sort.Slice(members, sortFunc)
+}
+
+// sortPolicers sort the slice based specified field
+func (app *App) sortPolicers(policers []api.Policer, rates *statsRates, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case PolicerStatName:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return policers[i].Name < policers[j].Name
+			}
+			return policers[i].Name > policers[j].Name
+		}
+	case PolicerStatConform:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return policers[i].Conform.Packets < policers[j].Conform.Packets
+			}
+			return policers[i].Conform.Packets > policers[j].Conform.Packets
+		}
+	case PolicerStatExceed:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return policers[i].Exceed.Packets < policers[j].Exceed.Packets
+			}
+			return policers[i].Exceed.Packets > policers[j].Exceed.Packets
+		}
+	case PolicerStatViolate:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return policers[i].Violate.Packets < policers[j].Violate.Packets
+			}
+			return policers[i].Violate.Packets > policers[j].Violate.Packets
+		}
+	case PolicerStatConformRate, PolicerStatExceedRate, PolicerStatViolateRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, policers[i], policers[j], field-PolicerStatConformRate, ascending)
+		}
+	default:
+		return
+	}
+	sort.Slice(policers, sortFunc)
+}
+
+// sortFibTables sort the slice based specified field
+func (app *App) sortFibTables(tables []api.FibTable, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case FibStatTableID:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tables[i].TableID < tables[j].TableID
+			}
+			return tables[i].TableID > tables[j].TableID
+		}
+	case FibStatName:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tables[i].Name < tables[j].Name
+			}
+			return tables[i].Name > tables[j].Name
+		}
+	case FibStatRoutes:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tables[i].Routes < tables[j].Routes
+			}
+			return tables[i].Routes > tables[j].Routes
+		}
+	case FibStatHostRoutes:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return tables[i].HostRoutes < tables[j].HostRoutes
+			}
+			return tables[i].HostRoutes > tables[j].HostRoutes
+		}
+	default:
+		return
+	}
+	sort.Slice(tables, sortFunc)
+}
+
+// sortNeighbors sort the slice based specified field
+func (app *App) sortNeighbors(neighbors []api.Neighbor, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case NeighborStatInterface:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return neighbors[i].Interface < neighbors[j].Interface
+			}
+			return neighbors[i].Interface > neighbors[j].Interface
+		}
+	case NeighborStatIP:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return neighbors[i].IP < neighbors[j].IP
+			}
+			return neighbors[i].IP > neighbors[j].IP
+		}
+	case NeighborStatMAC:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return neighbors[i].MAC < neighbors[j].MAC
+			}
+			return neighbors[i].MAC > neighbors[j].MAC
+		}
+	case NeighborStatAge:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return neighbors[i].Age < neighbors[j].Age
+			}
+			return neighbors[i].Age > neighbors[j].Age
+		}
+	case NeighborStatState:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return neighborState(neighbors[i]) < neighborState(neighbors[j])
+			}
+			return neighborState(neighbors[i]) > neighborState(neighbors[j])
+		}
+	default:
+		return
+	}
+	sort.Slice(neighbors, sortFunc)
+}
+
+// sortSRv6 sort the slice based specified field
+func (app *App) sortSRv6(sids []api.SRv6SID, rates *statsRates, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case SRv6StatSID:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sids[i].SID < sids[j].SID
+			}
+			return sids[i].SID > sids[j].SID
+		}
+	case SRv6StatKind:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return srv6Kind(sids[i]) < srv6Kind(sids[j])
+			}
+			return srv6Kind(sids[i]) > srv6Kind(sids[j])
+		}
+	case SRv6StatPackets:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sids[i].Good.Packets < sids[j].Good.Packets
+			}
+			return sids[i].Good.Packets > sids[j].Good.Packets
+		}
+	case SRv6StatBytes:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sids[i].Good.Bytes < sids[j].Good.Bytes
+			}
+			return sids[i].Good.Bytes > sids[j].Good.Bytes
+		}
+	case SRv6StatBad:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return sids[i].Bad.Packets < sids[j].Bad.Packets
+			}
+			return sids[i].Bad.Packets > sids[j].Bad.Packets
+		}
+	case SRv6StatPacketRate, SRv6StatByteRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, sids[i], sids[j], field-SRv6StatPacketRate, ascending)
+		}
+	default:
+		return
+	}
+	sort.Slice(sids, sortFunc)
+}
+
+// sortMpls sort the slice based specified field
+func (app *App) sortMpls(entries []api.MplsEntry, rates *statsRates, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case MplsStatLabel:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return mplsLabel(entries[i]) < mplsLabel(entries[j])
+			}
+			return mplsLabel(entries[i]) > mplsLabel(entries[j])
+		}
+	case MplsStatTable:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return entries[i].TableID < entries[j].TableID
+			}
+			return entries[i].TableID > entries[j].TableID
+		}
+	case MplsStatPackets:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return entries[i].Counter.Packets < entries[j].Counter.Packets
+			}
+			return entries[i].Counter.Packets > entries[j].Counter.Packets
+		}
+	case MplsStatBytes:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return entries[i].Counter.Bytes < entries[j].Counter.Bytes
+			}
+			return entries[i].Counter.Bytes > entries[j].Counter.Bytes
+		}
+	case MplsStatPacketRate, MplsStatByteRate:
+		sortFunc = func(i, j int) bool {
+			return lessRate(rates, entries[i], entries[j], field-MplsStatPacketRate, ascending)
+		}
+	default:
+		return
+	}
+	sort.Slice(entries, sortFunc)
+}
+
+// sortAPIClients sort the slice based specified field
+func (app *App) sortAPIClients(clients []api.APIClient, field int, ascending bool) {
+	if field == NoColumn {
+		return
+	}
+	var sortFunc func(i, j int) bool
+	switch field {
+	case APIClientStatName:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return clients[i].Name < clients[j].Name
+			}
+			return clients[i].Name > clients[j].Name
+		}
+	case APIClientStatTransport:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return apiClientTransport(clients[i]) < apiClientTransport(clients[j])
+			}
+			return apiClientTransport(clients[i]) > apiClientTransport(clients[j])
+		}
+	case APIClientStatPID:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return clients[i].PID < clients[j].PID
+			}
+			return clients[i].PID > clients[j].PID
+		}
+	case APIClientStatFD:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return clients[i].FD < clients[j].FD
+			}
+			return clients[i].FD > clients[j].FD
+		}
+	case APIClientStatQueue:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return clients[i].QueueLength < clients[j].QueueLength
+			}
+			return clients[i].QueueLength > clients[j].QueueLength
+		}
+	case APIClientStatHealth:
+		sortFunc = func(i, j int) bool {
+			if ascending {
+				return clients[i].Health < clients[j].Health
+			}
+			return clients[i].Health > clients[j].Health
+		}
+	default:
+		return
+	}
+	sort.Slice(clients, sortFunc)
+}
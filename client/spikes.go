@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"math"
+	"sync"
+
+	tui "github.com/gizak/termui/v3"
+)
+
+// DefaultSpikeSigma is the default deviation of a rate from its moving
+// average in standard deviations from which the rate is highlighted.
+const DefaultSpikeSigma = 3
+
+const (
+	// spikeAlpha is the weight of the latest rate in the moving average,
+	// the average follows roughly the last 1/spikeAlpha polls.
+	spikeAlpha = 0.2
+	// spikeMinSamples is the number of rates averaged before
+	// the deviations are evaluated.
+	spikeMinSamples = 5
+	// spikeMinStdDev is the lowest standard deviation relative to the average
+	// (and at least 1 per second), so that rates of a nearly constant traffic
+	// are not highlighted for negligible deviations.
+	spikeMinStdDev = 0.01
+)
+
+// rateAverage is the exponential moving average and variance of a rate.
+type rateAverage struct {
+	mean     float64
+	variance float64
+	samples  int
+}
+
+// add adds the rate to the average and returns the deviation of the rate
+// from the average before the update in standard deviations, zero until
+// spikeMinSamples rates were added.
+func (a *rateAverage) add(rate float64) float64 {
+	var deviation float64
+	if a.samples >= spikeMinSamples {
+		stdDev := math.Max(math.Sqrt(a.variance), math.Max(math.Abs(a.mean)*spikeMinStdDev, 1))
+		deviation = (rate - a.mean) / stdDev
+	}
+	if a.samples == 0 {
+		a.mean = rate
+	} else {
+		diff := rate - a.mean
+		incr := spikeAlpha * diff
+		a.mean += incr
+		a.variance = (1 - spikeAlpha) * (a.variance + diff*incr)
+	}
+	a.samples++
+	return deviation
+}
+
+// rateAverages are the moving averages of the rates of a tab by the item keys,
+// each item has an average per rate column of the tab.
+type rateAverages map[string][]rateAverage
+
+// update adds the rates to the averages and returns the deviations of the rates
+// from the averages in standard deviations (indexed like the rates). Averages
+// of the items without rates are dropped.
+func (a rateAverages) update(rates *statsRates) *statsRates {
+	if rates == nil {
+		return nil
+	}
+	deviations := &statsRates{
+		key:   rates.key,
+		rates: make(map[string][]float64, len(rates.rates)),
+	}
+	for key := range a {
+		if _, ok := rates.rates[key]; !ok {
+			delete(a, key)
+		}
+	}
+	for key, values := range rates.rates {
+		averages, ok := a[key]
+		if !ok {
+			averages = make([]rateAverage, len(values))
+			a[key] = averages
+		}
+		deviations.rates[key] = make([]float64, len(values))
+		for column, value := range values {
+			deviations.rates[key][column] = averages[column].add(value)
+		}
+	}
+	return deviations
+}
+
+// spikeHighlight configures the highlighting of the rate spikes.
+type spikeHighlight struct {
+	sync.Mutex
+	enabled bool
+	// sigma is the deviation from which the rates are highlighted.
+	sigma float64
+}
+
+// threshold returns the deviation from which the rates are highlighted,
+// zero if the highlighting is disabled.
+func (s *spikeHighlight) threshold() float64 {
+	s.Lock()
+	defer s.Unlock()
+	if !s.enabled {
+		return 0
+	}
+	return s.sigma
+}
+
+// toggle enables or disables the highlighting, it returns true if enabled.
+func (s *spikeHighlight) toggle() bool {
+	s.Lock()
+	defer s.Unlock()
+	s.enabled = !s.enabled
+	return s.enabled
+}
+
+// SetSpikeHighlight sets whether the interface rates deviating from their
+// moving average by more than sigma standard deviations are highlighted.
+func (app *App) SetSpikeHighlight(enabled bool, sigma float64) {
+	app.spikes.Lock()
+	app.spikes.enabled = enabled
+	app.spikes.sigma = sigma
+	app.spikes.Unlock()
+}
+
+// ifaceRateCells maps the cells of the interface rates to the rate columns.
+var ifaceRateCells = map[[2]int]int{
+	{ifacePacketRateRow, ifaceRxCountCol}: ifaceRxPacketRate,
+	{ifacePacketRateRow, ifaceTxCountCol}: ifaceTxPacketRate,
+	{ifaceByteRateRow, ifaceRxCountCol}:   ifaceRxByteRate,
+	{ifaceByteRateRow, ifaceTxCountCol}:   ifaceTxByteRate,
+}
+
+// CellColor highlights the rates of the interface deviating from their
+// moving average by more than the spike threshold, rises in the critical
+// and drops in the warning color.
+func (r *interfaceRows) CellColor(entry, entryRow, col int) (tui.Color, bool) {
+	if r.spikeSigma <= 0 {
+		return tui.ColorClear, false
+	}
+	cells := ifaceRateCells
+	if r.compact {
+		cells = compactRateCells
+	}
+	column, ok := cells[[2]int{entryRow, col}]
+	if !ok {
+		return tui.ColorClear, false
+	}
+	deviation := r.deviations.get(r.ifaces[entry], column)
+	switch {
+	case deviation > r.spikeSigma:
+		return criticalColor(), true
+	case deviation < -r.spikeSigma:
+		return warningColor(), true
+	}
+	return tui.ColorClear, false
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"testing"
+)
+
+// testRates returns the rates of a single rate column by the item names.
+func testRates(rates map[string]float64) *statsRates {
+	r := &statsRates{
+		key:   func(item interface{}) string { return item.(string) },
+		rates: make(map[string][]float64),
+	}
+	for key, rate := range rates {
+		r.rates[key] = []float64{rate}
+	}
+	return r
+}
+
+func TestRateAverages_update(t *testing.T) {
+	averages := make(rateAverages)
+	for i := 0; i < 10; i++ {
+		rate := 990.0
+		if i%2 == 0 {
+			rate = 1010
+		}
+		deviations := averages.update(testRates(map[string]float64{"tap0": rate, "loop0": 0}))
+		if got := deviations.get("tap0", 0); i < spikeMinSamples && got != 0 {
+			t.Errorf("Error occured sample:%d got:%v; want:0", i, got)
+		}
+		if got := deviations.get("tap0", 0); got > DefaultSpikeSigma || got < -DefaultSpikeSigma {
+			t.Errorf("Error occured sample:%d got:%v; want:within %v", i, got, DefaultSpikeSigma)
+		}
+	}
+
+	tests := []struct {
+		rates map[string]float64
+		key   string
+		spike bool
+	}{
+		{rates: map[string]float64{"tap0": 5000, "loop0": 1}, key: "tap0", spike: true},
+		{rates: map[string]float64{"tap0": 1000, "loop0": 1}, key: "loop0", spike: false},
+		{rates: map[string]float64{"tap0": 1000, "loop0": 100}, key: "loop0", spike: true},
+	}
+	for _, test := range tests {
+		deviations := averages.update(testRates(test.rates))
+		got := deviations.get(test.key, 0)
+		if spike := got > DefaultSpikeSigma; spike != test.spike {
+			t.Errorf("Error occured key:%s rate:%v deviation:%v got:%v; want:%v", test.key, test.rates[test.key], got, spike, test.spike)
+		}
+	}
+
+	averages.update(testRates(map[string]float64{"tap0": 1000}))
+	if _, ok := averages["loop0"]; ok {
+		t.Errorf("Error occured got:%v; want:no average of loop0", averages["loop0"])
+	}
+}
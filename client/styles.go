@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	tui "github.com/gizak/termui/v3"
+	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/gui/xtui"
+)
+
+// Error counter thresholds for cell coloring.
+const (
+	// ErrorsWarnThreshold is the lowest counter value painted as a warning.
+	ErrorsWarnThreshold = 1
+	// ErrorsCritThreshold is the lowest counter value painted as critical.
+	ErrorsCritThreshold = 1000
+)
+
+// DefaultUtilThreshold is the default link utilization in percent
+// from which the interface rates are painted as critical.
+const DefaultUtilThreshold = 80
+
+// Interface tab cell positions (entry row, column) used for styling.
+const (
+	ifaceStateCol      = 2
+	ifaceLinkCol       = 3
+	ifaceRxCountCol    = 7
+	ifaceTxCountCol    = 9
+	ifaceDropsCol      = 10
+	ifacePacketRateRow = 1
+	ifaceByteRateRow   = 3
+	ifaceErrorsRow     = 4
+	ifaceUtilRow       = 13
+	errorsSeverityCol  = 3
+)
+
+// okColor, warningColor and criticalColor return the severity colors of the gui theme.
+func okColor() tui.Color       { return tui.Color(gui.ActiveTheme().OK) }
+func warningColor() tui.Color  { return tui.Color(gui.ActiveTheme().Warning) }
+func criticalColor() tui.Color { return tui.Color(gui.ActiveTheme().Critical) }
+
+// thresholdColor returns the color for the counter based on the error thresholds.
+func thresholdColor(cell string) (tui.Color, bool) {
+	value, ok := parseCount(cell)
+	if !ok {
+		return tui.ColorClear, false
+	}
+	switch {
+	case value >= ErrorsCritThreshold:
+		return criticalColor(), true
+	case value >= ErrorsWarnThreshold:
+		return warningColor(), true
+	}
+	return tui.ColorClear, false
+}
+
+// interfaceCellStyler returns the styler painting the admin and link state, rx/tx errors
+// and drops exceeding thresholds, and the rx/tx link utilization reaching utilThreshold.
+func interfaceCellStyler(utilThreshold float64) xtui.CellStyler {
+	return func(entryRow int, row []string, col int) (tui.Color, bool) {
+		switch {
+		case entryRow == 0 && (col == ifaceStateCol || col == ifaceLinkCol):
+			switch row[col] {
+			case "down":
+				return criticalColor(), true
+			case unknownState:
+				return warningColor(), true
+			}
+			return okColor(), true
+		case entryRow == 0 && col == ifaceDropsCol:
+			return thresholdColor(row[col])
+		case entryRow == ifaceErrorsRow && (col == ifaceRxCountCol || col == ifaceTxCountCol):
+			return thresholdColor(row[col])
+		case entryRow == ifaceUtilRow && (col == ifaceRxCountCol || col == ifaceTxCountCol):
+			if util, ok := parseLinkUtilization(row[col]); ok && util >= utilThreshold {
+				return criticalColor(), true
+			}
+		}
+		return tui.ColorClear, false
+	}
+}
+
+// errorCellStyler paints the error counters based on their severity.
+func errorCellStyler(_ int, row []string, col int) (tui.Color, bool) {
+	if col != errorsSeverityCol {
+		return tui.ColorClear, false
+	}
+	switch row[col] {
+	case "error":
+		return criticalColor(), true
+	case "warn":
+		return warningColor(), true
+	}
+	return tui.ColorClear, false
+}
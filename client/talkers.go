@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"sync"
+	"time"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// DefaultTalkersWindow is the default window of the interface rates
+// the top talkers are ranked by.
+const DefaultTalkersWindow = 5 * time.Minute
+
+// rateSample is the rate of an interface at the time of a poll.
+type rateSample struct {
+	at   time.Time
+	rate uint64
+}
+
+// topTalkers keeps the Rx+Tx byte rates of interfaces polled within
+// the sliding window, so that interfaces can be ranked by their average
+// or peak rate instead of the rate since the previous poll.
+type topTalkers struct {
+	sync.Mutex
+	window time.Duration
+	// samples by the interface keys
+	samples map[string][]rateSample
+	// time of the last poll added to the samples
+	last time.Time
+}
+
+// newTopTalkers returns an empty instance of <*topTalkers>
+func newTopTalkers(window time.Duration) *topTalkers {
+	return &topTalkers{
+		window:  window,
+		samples: make(map[string][]rateSample),
+	}
+}
+
+// SetTalkersWindow sets the window of the interface rates
+// the top talkers are ranked by.
+func (app *App) SetTalkersWindow(window time.Duration) {
+	app.talkers.Lock()
+	app.talkers.window = window
+	app.talkers.Unlock()
+}
+
+// update adds the rates of the polled interfaces to the window, samples
+// older than the window and removed interfaces are dropped.
+func (t *topTalkers) update(entry cacheEntry) {
+	ifaces, _ := entry.data.([]api.Interface)
+	if entry.prev == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	if !entry.polledAt.After(t.last) {
+		// the poll was already added
+		return
+	}
+	t.last = entry.polledAt
+	samples := make(map[string][]rateSample, len(ifaces))
+	since := entry.polledAt.Add(-t.window)
+	for _, iface := range ifaces {
+		key := interfaceKey(iface)
+		window := t.samples[key]
+		for len(window) > 0 && window[0].at.Before(since) {
+			window = window[1:]
+		}
+		if rates := entry.rates.of(iface); rates != nil {
+			window = append(window, rateSample{
+				at:   entry.polledAt,
+				rate: uint64(rates[ifaceRxByteRate] + rates[ifaceTxByteRate]),
+			})
+		}
+		samples[key] = window
+	}
+	t.samples = samples
+}
+
+// rates returns the average and the peak Rx+Tx byte rate
+// of the interface within the window.
+func (t *topTalkers) rates(key string) (avg, peak uint64) {
+	t.Lock()
+	defer t.Unlock()
+	window := t.samples[key]
+	if len(window) == 0 {
+		return 0, 0
+	}
+	var sum uint64
+	for _, sample := range window {
+		sum += sample.rate
+		if sample.rate > peak {
+			peak = sample.rate
+		}
+	}
+	return sum / uint64(len(window)), peak
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// siSuffixes are used for packet counters and bit rates (base 1000).
+	siSuffixes = []string{"", "K", "M", "G", "T", "P", "E"}
+	// iecSuffixes are used for byte counters (base 1024).
+	iecSuffixes = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+)
+
+// unitFormat formats counters either as raw numbers,
+// or using SI/IEC suffixes if human is set.
+type unitFormat struct {
+	human bool
+}
+
+// count formats a packet (or any other) counter.
+func (f unitFormat) count(value uint64) string {
+	if !f.human {
+		return fmt.Sprint(value)
+	}
+	return scaleUnits(value, 1000, siSuffixes)
+}
+
+// bytes formats a byte counter.
+func (f unitFormat) bytes(value uint64) string {
+	if !f.human {
+		return fmt.Sprint(value)
+	}
+	return scaleUnits(value, 1024, iecSuffixes)
+}
+
+// byteRate formats bytes per second, shown as bits per second
+// if human readable units are used.
+func (f unitFormat) byteRate(value uint64) string {
+	if !f.human {
+		return fmt.Sprint(value)
+	}
+	return scaleUnits(value*8, 1000, siSuffixes) + "bps"
+}
+
+// byteRateLabel returns the label of the byteRate value.
+func (f unitFormat) byteRateLabel() string {
+	if !f.human {
+		return "Bytes/s"
+	}
+	return "Bits/s"
+}
+
+// formatLinkSpeed formats the link speed given in kbps with the duplex.
+func formatLinkSpeed(kbps uint64, duplex string) string {
+	if kbps == 0 {
+		return duplex
+	}
+	speed := scaleUnits(kbps*1000, 1000, siSuffixes) + "bps"
+	if duplex == "" {
+		return speed
+	}
+	return speed + " " + duplex
+}
+
+// formatLinkUtilization formats the rate given in bytes per second as bits
+// per second, followed by the utilization of the link speed given in kbps
+// if the speed is known.
+func formatLinkUtilization(bytesPerSec, kbps uint64) string {
+	rate := scaleUnits(bytesPerSec*8, 1000, siSuffixes) + "bps"
+	if kbps == 0 {
+		return rate
+	}
+	return fmt.Sprintf("%s %.1f%%", rate, float64(bytesPerSec*8)/float64(kbps*1000)*100)
+}
+
+// parseLinkUtilization parses the link utilization formatted by formatLinkUtilization.
+func parseLinkUtilization(cell string) (float64, bool) {
+	fields := strings.Fields(cell)
+	if len(fields) != 2 || !strings.HasSuffix(fields[1], "%") {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.TrimSuffix(fields[1], "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// scaleUnits divides the value by base until it is lower than
+// the base and appends the corresponding suffix.
+func scaleUnits(value uint64, base uint64, suffixes []string) string {
+	if value < base {
+		return fmt.Sprintf("%d%s", value, suffixes[0])
+	}
+	scaled := float64(value)
+	i := 0
+	for scaled >= float64(base) && i < len(suffixes)-1 {
+		scaled /= float64(base)
+		i++
+	}
+	return fmt.Sprintf("%.2f%s", scaled, suffixes[i])
+}
+
+// parseCount parses a counter formatted by unitFormat.count.
+func parseCount(cell string) (uint64, bool) {
+	if value, err := strconv.ParseUint(cell, 10, 64); err == nil {
+		return value, true
+	}
+	for i := len(siSuffixes) - 1; i > 0; i-- {
+		if !strings.HasSuffix(cell, siSuffixes[i]) {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSuffix(cell, siSuffixes[i]), 64)
+		if err != nil {
+			return 0, false
+		}
+		for j := 0; j < i; j++ {
+			value *= 1000
+		}
+		return uint64(value), true
+	}
+	return 0, false
+}
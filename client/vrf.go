@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sort"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// vrfFilter returns the filter expression of the interfaces bound to the IPv4 VRF.
+func vrfFilter(vrf uint32) string {
+	return fmt.Sprintf("vrf=%d", vrf)
+}
+
+// interfaceVrfs returns the sorted IPv4 VRFs the interfaces are bound to.
+func interfaceVrfs(ifaces []api.Interface) []uint32 {
+	seen := make(map[uint32]bool)
+	var vrfs []uint32
+	for _, iface := range ifaces {
+		if !seen[iface.VrfIPv4] {
+			seen[iface.VrfIPv4] = true
+			vrfs = append(vrfs, iface.VrfIPv4)
+		}
+	}
+	sort.Slice(vrfs, func(i, j int) bool { return vrfs[i] < vrfs[j] })
+	return vrfs
+}
+
+// nextVrfFilter returns the filter of the interfaces of the VRF following
+// the one filtered by the current filter, or the first VRF if the current
+// filter is not a VRF filter. The filter is cleared after the last VRF.
+func nextVrfFilter(ifaces []api.Interface, current string) string {
+	vrfs := interfaceVrfs(ifaces)
+	for i, vrf := range vrfs {
+		if vrfFilter(vrf) != current {
+			continue
+		}
+		if i+1 < len(vrfs) {
+			return vrfFilter(vrfs[i+1])
+		}
+		return ""
+	}
+	if len(vrfs) == 0 {
+		return ""
+	}
+	return vrfFilter(vrfs[0])
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"testing"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func TestNextVrfFilter(t *testing.T) {
+	ifaces := []api.Interface{{VrfIPv4: 10}, {VrfIPv4: 0}, {VrfIPv4: 10}, {VrfIPv4: 2}}
+	filter := "name~tap"
+	var got []string
+	for i := 0; i < 5; i++ {
+		filter = nextVrfFilter(ifaces, filter)
+		got = append(got, filter)
+	}
+	want := []string{"vrf=0", "vrf=2", "vrf=10", "", "vrf=0"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Error occured got:%q; want:%q", got, want)
+			break
+		}
+	}
+}
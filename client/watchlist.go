@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// DefaultWatchlistFile returns the default path of the file persisting
+// the pinned entries, or an empty string if there is no config directory.
+func DefaultWatchlistFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "vpptop", "watchlist.json")
+}
+
+// watchlist are the keys of the entries pinned to the top of the tables
+// (interface and node names), stored per tab name in the order pinned.
+type watchlist struct {
+	sync.Mutex
+	// path (optional) of the file the keys are persisted in.
+	path string
+	keys map[string][]string
+}
+
+// newWatchlist returns a watchlist without pinned entries.
+func newWatchlist() *watchlist {
+	return &watchlist{keys: make(map[string][]string)}
+}
+
+// SetWatchlistFile sets the file the pinned entries are loaded from and
+// saved to when pinned by the user. Pinned entries are not persisted if empty.
+func (app *App) SetWatchlistFile(path string) {
+	app.watchlist.Lock()
+	app.watchlist.path = path
+	app.watchlist.Unlock()
+}
+
+// load reads the pinned entries from the watchlist file,
+// a missing file is not an error.
+func (l *watchlist) load() error {
+	l.Lock()
+	defer l.Unlock()
+	if l.path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &l.keys)
+}
+
+// toggle pins the entry of the tab, or unpins it if already pinned,
+// and writes the watchlist file.
+func (l *watchlist) toggle(tab int, key string) error {
+	l.Lock()
+	defer l.Unlock()
+	name := tabNames[tab]
+	keys, unpinned := l.keys[name], false
+	for i, k := range keys {
+		if k == key {
+			keys, unpinned = append(keys[:i:i], keys[i+1:]...), true
+			break
+		}
+	}
+	if !unpinned {
+		keys = append(keys, key)
+	}
+	l.keys[name] = keys
+
+	if l.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(l.keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(l.path, data, 0644)
+}
+
+// pinned returns the order in which the entries of the tab were pinned by their keys.
+func (l *watchlist) pinned(tab int) map[string]int {
+	l.Lock()
+	defer l.Unlock()
+	keys := l.keys[tabNames[tab]]
+	order := make(map[string]int, len(keys))
+	for i, key := range keys {
+		order[key] = i
+	}
+	return order
+}
+
+// loadWatchlist loads the watchlist file.
+func (app *App) loadWatchlist() {
+	if err := app.watchlist.load(); err != nil {
+		logrus.Warnf("error occured while loading watchlist %s: %v", app.watchlist.path, err)
+	}
+}
+
+// pinInterfaces returns the pinned interfaces of all interfaces in the order
+// they were pinned, followed by the shown interfaces which are not pinned.
+// The number of the pinned interfaces is returned as well.
+func pinInterfaces(pinned map[string]int, all, shown []api.Interface) ([]api.Interface, int) {
+	if len(pinned) == 0 {
+		return shown, 0
+	}
+	var ifaces []api.Interface
+	for _, iface := range all {
+		if _, ok := pinned[interfaceKey(iface)]; ok {
+			ifaces = append(ifaces, iface)
+		}
+	}
+	sort.SliceStable(ifaces, func(i, j int) bool {
+		return pinned[interfaceKey(ifaces[i])] < pinned[interfaceKey(ifaces[j])]
+	})
+	count := len(ifaces)
+	for _, iface := range shown {
+		if _, ok := pinned[interfaceKey(iface)]; !ok {
+			ifaces = append(ifaces, iface)
+		}
+	}
+	return ifaces, count
+}
+
+// pinNodes returns the pinned nodes of all nodes in the order they were
+// pinned (the node of each thread), followed by the shown nodes which are
+// not pinned. The number of the pinned nodes is returned as well.
+func pinNodes(pinned map[string]int, all, shown []api.Node) ([]api.Node, int) {
+	if len(pinned) == 0 {
+		return shown, 0
+	}
+	var nodes []api.Node
+	for _, node := range all {
+		if _, ok := pinned[node.Name]; ok {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return pinned[nodes[i].Name] < pinned[nodes[j].Name]
+	})
+	count := len(nodes)
+	for _, node := range shown {
+		if _, ok := pinned[node.Name]; !ok {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, count
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func TestPinInterfaces(t *testing.T) {
+	all := []api.Interface{{InterfaceName: "local0"}, {InterfaceName: "tap0"}, {InterfaceName: "tap1"}, {InterfaceName: "loop0"}}
+	// tap1 and loop0 are filtered out, loop0 is pinned before tap0
+	shown := []api.Interface{all[1], all[0]}
+	pinned := map[string]int{"loop0": 0, "tap0": 1}
+
+	ifaces, count := pinInterfaces(pinned, all, shown)
+	var got []string
+	for _, iface := range ifaces {
+		got = append(got, iface.InterfaceName)
+	}
+	if want := "loop0 tap0 local0"; strings.Join(got, " ") != want || count != 2 {
+		t.Errorf("Error occured got:%q (%d pinned); want:%q (2 pinned)", got, count, want)
+	}
+}
+
+func TestWatchlistToggle(t *testing.T) {
+	list := newWatchlist()
+	for _, key := range []string{"tap0", "loop0", "tap0", "tap1"} {
+		if err := list.toggle(Interfaces, key); err != nil {
+			t.Fatalf("Error occured: %v", err)
+		}
+	}
+	got := list.pinned(Interfaces)
+	if len(got) != 2 || got["loop0"] != 0 || got["tap1"] != 1 {
+		t.Errorf("Error occured got:%v; want:map[loop0:0 tap1:1]", got)
+	}
+	if got := list.pinned(Nodes); len(got) != 0 {
+		t.Errorf("Error occured got:%v; want:map[]", got)
+	}
+}
@@ -0,0 +1,352 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package collect provides the data collection of vpptop without the terminal
+// user interface. A Collector connects to the VPP, polls its stats in an interval
+// and passes them to the callbacks, so that the stats can be processed by other
+// programs:
+//
+//	c := collect.New(collect.Config{Interval: time.Second}, collect.Callbacks{
+//		Interfaces: func(ifaces []api.Interface) { ... },
+//	})
+//	err := c.Run(ctx)
+package collect
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"git.fd.io/govpp.git/adapter"
+	"git.fd.io/govpp.git/core"
+	"go.pantheon.tech/vpptop/stats"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/local"
+	"go.pantheon.tech/vpptop/stats/vpp"
+)
+
+// DefaultInterval is the default interval between the polls.
+const DefaultInterval = time.Second
+
+// DefaultHandlers returns the definitions of the handlers bundled with vpptop,
+// in the order they are probed for the connected VPP.
+func DefaultHandlers() []api.HandlerDef {
+	return []api.HandlerDef{&local.HandlerDef{}, &vpp.HandlerDef{}}
+}
+
+// Config configures the connection to the VPP and the polling.
+type Config struct {
+	// Socket is the stats socket of the local VPP
+	// (adapter.DefaultStatsSocket if empty).
+	Socket string
+	// RemoteAddr (optional) is the address of the vpptop proxy,
+	// the local VPP is connected if empty.
+	RemoteAddr string
+	// Handler (optional) is used instead of connecting to the VPP,
+	// e.g. the handler of the demo package.
+	Handler api.HandlerAPI
+	// Handlers are the handler definitions probed for the connected VPP
+	// (DefaultHandlers if empty).
+	Handlers []api.HandlerDef
+	// Interval between the polls (DefaultInterval if zero).
+	Interval time.Duration
+	// Log (optional) is the output of the VPP connection logs.
+	Log io.Writer
+	// Options configure the provider, e.g. stats.WithRetry.
+	Options []stats.ProviderOption
+}
+
+// Callbacks are called with the polled stats, only the stats with a callback
+// are polled. The callbacks are called one by one from the polling goroutine.
+type Callbacks struct {
+	Interfaces func([]api.Interface)
+	Nodes      func([]api.Node)
+	Errors     func([]api.Error)
+	Memory     func([]string)
+	Threads    func([]api.ThreadData)
+	DropsPunts func([]api.DropPunt)
+	Tunnels    func([]api.TunnelCounters)
+	Sessions   func([]api.SessionStat)
+	Features   func([]api.FeatureArc)
+	Bonds      func([]api.BondMember)
+	Policers   func([]api.Policer)
+	Fib        func(*api.FibSummary)
+	Neighbors  func([]api.Neighbor)
+	SRv6       func([]api.SRv6SID)
+	Mpls       func([]api.MplsEntry)
+	APIClients func(*api.APIClients)
+	Info       func(*api.VPPInfo)
+	// Error (optional) is called if polling of the stats fails,
+	// the stats are named as the endpoints of the HTTP server.
+	Error func(stats string, err error)
+}
+
+// Collector polls the stats of the VPP and passes them to the callbacks.
+type Collector struct {
+	cfg       Config
+	callbacks Callbacks
+	provider  api.VppProviderAPI
+}
+
+// New returns a collector configured by the config, the VPP is not
+// connected until the collector is run.
+func New(cfg Config, callbacks Callbacks) *Collector {
+	if cfg.Socket == "" {
+		cfg.Socket = adapter.DefaultStatsSocket
+	}
+	if len(cfg.Handlers) == 0 {
+		cfg.Handlers = DefaultHandlers()
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Log == nil {
+		cfg.Log = ioutil.Discard
+	}
+	return &Collector{
+		cfg:       cfg,
+		callbacks: callbacks,
+		provider:  stats.NewVppProvider(cfg.Handlers, cfg.Log, cfg.Options...),
+	}
+}
+
+// Provider returns the provider of the collector, it can be used for requests
+// not covered by the callbacks (e.g. CLI commands or clearing of the counters)
+// while the collector runs.
+func (c *Collector) Provider() api.VppProviderAPI {
+	return c.provider
+}
+
+// Run is a blocking call connecting to the VPP and polling the stats until
+// the context is cancelled, the VPP is disconnected afterwards. An error
+// is returned if the VPP could not be connected.
+func (c *Collector) Run(ctx context.Context) error {
+	if err := c.connect(); err != nil {
+		return fmt.Errorf("error occurred during connect: %v", err)
+	}
+	defer c.provider.Disconnect()
+
+	sources := c.sources()
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		c.poll(ctx, sources)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// connect connects the provider to the VPP, the proxy or the handler.
+func (c *Collector) connect() error {
+	switch {
+	case c.cfg.Handler != nil:
+		return c.provider.ConnectHandler(c.cfg.Handler)
+	case c.cfg.RemoteAddr != "":
+		return c.provider.ConnectRemote(c.cfg.RemoteAddr)
+	default:
+		return c.provider.Connect(c.cfg.Socket)
+	}
+}
+
+// poll polls the sources once, the polling is skipped while
+// the VPP is disconnected.
+func (c *Collector) poll(ctx context.Context, sources []source) {
+	if state, _ := c.provider.GetState(); state != core.Connected {
+		return
+	}
+	for _, s := range sources {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.poll(ctx); err != nil && c.callbacks.Error != nil {
+			c.callbacks.Error(s.name, err)
+		}
+	}
+}
+
+// source polls the stats and passes them to the callback.
+type source struct {
+	name string
+	poll func(ctx context.Context) error
+}
+
+// sources returns the sources of the stats with a callback.
+func (c *Collector) sources() []source {
+	p, cb := c.provider, c.callbacks
+	var sources []source
+	add := func(name string, poll func(ctx context.Context) error) {
+		sources = append(sources, source{name: name, poll: poll})
+	}
+	if cb.Interfaces != nil {
+		add("interfaces", func(ctx context.Context) error {
+			ifaces, err := p.GetInterfaces(ctx)
+			if err == nil {
+				cb.Interfaces(ifaces)
+			}
+			return err
+		})
+	}
+	if cb.Nodes != nil {
+		add("nodes", func(ctx context.Context) error {
+			nodes, err := p.GetNodes(ctx)
+			if err == nil {
+				cb.Nodes(nodes)
+			}
+			return err
+		})
+	}
+	if cb.Errors != nil {
+		add("errors", func(ctx context.Context) error {
+			errors, err := p.GetErrors(ctx)
+			if err == nil {
+				cb.Errors(errors)
+			}
+			return err
+		})
+	}
+	if cb.Memory != nil {
+		add("memory", func(ctx context.Context) error {
+			memory, err := p.GetMemory(ctx)
+			if err == nil {
+				cb.Memory(memory)
+			}
+			return err
+		})
+	}
+	if cb.Threads != nil {
+		add("threads", func(ctx context.Context) error {
+			threads, err := p.GetThreads(ctx)
+			if err == nil {
+				cb.Threads(threads)
+			}
+			return err
+		})
+	}
+	if cb.DropsPunts != nil {
+		add("drops", func(ctx context.Context) error {
+			dropsPunts, err := p.GetDropsPunts(ctx)
+			if err == nil {
+				cb.DropsPunts(dropsPunts)
+			}
+			return err
+		})
+	}
+	if cb.Tunnels != nil {
+		add("tunnels", func(ctx context.Context) error {
+			tunnels, err := p.GetTunnels(ctx)
+			if err == nil {
+				cb.Tunnels(tunnels)
+			}
+			return err
+		})
+	}
+	if cb.Sessions != nil {
+		add("sessions", func(ctx context.Context) error {
+			sessions, err := p.GetSessions(ctx)
+			if err == nil {
+				cb.Sessions(sessions)
+			}
+			return err
+		})
+	}
+	if cb.Features != nil {
+		add("features", func(ctx context.Context) error {
+			features, err := p.GetFeatures(ctx)
+			if err == nil {
+				cb.Features(features)
+			}
+			return err
+		})
+	}
+	if cb.Bonds != nil {
+		add("bonds", func(ctx context.Context) error {
+			members, err := p.GetBonds(ctx)
+			if err == nil {
+				cb.Bonds(members)
+			}
+			return err
+		})
+	}
+	if cb.Policers != nil {
+		add("policers", func(ctx context.Context) error {
+			policers, err := p.GetPolicers(ctx)
+			if err == nil {
+				cb.Policers(policers)
+			}
+			return err
+		})
+	}
+	if cb.Fib != nil {
+		add("fib", func(ctx context.Context) error {
+			summary, err := p.GetFib(ctx)
+			if err == nil {
+				cb.Fib(summary)
+			}
+			return err
+		})
+	}
+	if cb.Neighbors != nil {
+		add("neighbors", func(ctx context.Context) error {
+			neighbors, err := p.GetNeighbors(ctx)
+			if err == nil {
+				cb.Neighbors(neighbors)
+			}
+			return err
+		})
+	}
+	if cb.SRv6 != nil {
+		add("srv6", func(ctx context.Context) error {
+			sids, err := p.GetSRv6(ctx)
+			if err == nil {
+				cb.SRv6(sids)
+			}
+			return err
+		})
+	}
+	if cb.Mpls != nil {
+		add("mpls", func(ctx context.Context) error {
+			entries, err := p.GetMpls(ctx)
+			if err == nil {
+				cb.Mpls(entries)
+			}
+			return err
+		})
+	}
+	if cb.APIClients != nil {
+		add("apiclients", func(ctx context.Context) error {
+			clients, err := p.GetAPIClients(ctx)
+			if err == nil {
+				cb.APIClients(clients)
+			}
+			return err
+		})
+	}
+	if cb.Info != nil {
+		add("info", func(ctx context.Context) error {
+			info, err := p.GetInfo(ctx)
+			if err == nil {
+				cb.Info(info)
+			}
+			return err
+		})
+	}
+	return sources
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package collect_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"go.pantheon.tech/vpptop/collect"
+	"go.pantheon.tech/vpptop/stats"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/demo"
+)
+
+// The interface counters of the local VPP are printed every 5 seconds until interrupted.
+func ExampleCollector() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	c := collect.New(collect.Config{
+		Interval: 5 * time.Second,
+		Options:  []stats.ProviderOption{stats.WithRetry(stats.RetryConfig{Attempts: 3})},
+	}, collect.Callbacks{
+		Interfaces: func(ifaces []api.Interface) {
+			for _, iface := range ifaces {
+				fmt.Printf("%s rx %d tx %d\n", iface.InterfaceName, iface.Rx.Packets, iface.Tx.Packets)
+			}
+		},
+		Error: func(stats string, err error) {
+			log.Printf("polling %s failed: %v", stats, err)
+		},
+	})
+	if err := c.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// The demo handler provides generated stats without a VPP, the CLI commands
+// are sent over the provider of the running collector.
+func ExampleCollector_Provider() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var c *collect.Collector
+	c = collect.New(collect.Config{Handler: demo.NewHandler()}, collect.Callbacks{
+		Errors: func(errors []api.Error) {
+			if len(errors) == 0 {
+				return
+			}
+			out, err := c.Provider().RunCli(ctx, "show errors")
+			if err != nil {
+				return
+			}
+			fmt.Print(out)
+		},
+	})
+	if err := c.Run(ctx); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/client"
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringArray("alert", nil, "Alert rule 'tab: expression' firing once any stats item of the tab matches the filter expression, e.g. 'interfaces: rxerrors>0' (repeatable)")
+	rootCmd.PersistentFlags().String("snapshot-dir", "", "Directory the snapshots of all tabs and raw CLI outputs are captured to when an alert fires (disabled if empty)")
+	rootCmd.PersistentFlags().String("sync-tag", "", "Tag naming the snapshots with their UTC time, so the snapshots of instances sharing the tag (e.g. on both ends of a link) can be merged by merge-snapshots")
+	rootCmd.PersistentFlags().Duration("memory-leak-alert", 0, "Duration of the main heap growth reported as a possible memory leak (disabled if zero)")
+}
+
+// alertConfig returns the alert rules configuration set by the flags.
+func alertConfig(cmd *cobra.Command) (client.AlertConfig, error) {
+	flags := cmd.Flags()
+	var cfg client.AlertConfig
+	var err error
+	if cfg.Rules, err = flags.GetStringArray("alert"); err != nil {
+		return cfg, err
+	}
+	if cfg.SnapshotDir, err = flags.GetString("snapshot-dir"); err != nil {
+		return cfg, err
+	}
+	if cfg.SyncTag, err = flags.GetString("sync-tag"); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
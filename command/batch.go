@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"io"
+
+	"git.fd.io/govpp.git/adapter/socketclient"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/stats"
+)
+
+func init() {
+	rootCmd.PersistentFlags().Bool("compress", false, "Compress the data of the tabs fetched from the remote proxy in a batch (pays off on slow links)")
+	proxyCmd.Flags().Bool("batch", true, "Serve the data of the tabs polled every second in a single round-trip (connects vpptop to the local VPP)")
+}
+
+// batchCompression returns the compression of the batch RPC set by the flag.
+func batchCompression(cmd *cobra.Command) (stats.ProviderOption, error) {
+	compress, err := cmd.Flags().GetBool("compress")
+	if err != nil {
+		return nil, err
+	}
+	return stats.WithBatchCompression(compress), nil
+}
+
+// serveBatch connects a VPP provider to the local VPP in the background and
+// serves its batch RPC next to the proxy RPC once connected, the provider is
+// disconnected once the context is cancelled. The provider connects only
+// the default binapi socket, the batch RPC is not served with another one.
+func serveBatch(ctx context.Context, cmd *cobra.Command, binapiSocket, statsSocket string, logFile io.Writer) error {
+	if binapiSocket != socketclient.DefaultSocketName {
+		logrus.Warnf("batch RPC is not served, only the default binapi socket %s is supported", socketclient.DefaultSocketName)
+		return nil
+	}
+	timeout, err := requestTimeout(cmd)
+	if err != nil {
+		return err
+	}
+	counters, err := interfaceCounters(cmd)
+	if err != nil {
+		return err
+	}
+	go func() {
+		provider, err := connectProvider(statsSocket, nil, stats.RetryConfig{}, timeout, counters, logFile)
+		if err != nil {
+			logrus.Errorf("batch RPC is not served: %v", err)
+			return
+		}
+		if ctx.Err() != nil {
+			provider.Disconnect()
+			return
+		}
+		if err := stats.ServeBatch(provider); err != nil {
+			logrus.Errorf("batch RPC is not served: %v", err)
+			provider.Disconnect()
+			return
+		}
+		logrus.Infoln("batch RPC served at:", stats.BatchRPCPath)
+		<-ctx.Done()
+		provider.Disconnect()
+	}()
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/client"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String("capture-interface", "", "Interface the pcap trace started at the capture tab is restricted to (all interfaces if empty)")
+	rootCmd.PersistentFlags().Uint32("capture-max-packets", client.DefaultCaptureMaxPackets, "Number of packets captured by the pcap and dispatch trace started at the capture tab")
+}
+
+// captureConfig returns the packet capture configuration set by the flags.
+func captureConfig(cmd *cobra.Command) (client.CaptureConfig, error) {
+	flags := cmd.Flags()
+	var cfg client.CaptureConfig
+	var err error
+	if cfg.Interface, err = flags.GetString("capture-interface"); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxPackets, err = flags.GetUint32("capture-max-packets"); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
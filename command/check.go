@@ -0,0 +1,394 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"regexp"
+
+	"git.fd.io/govpp.git/adapter"
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/demo"
+	"gopkg.in/yaml.v2"
+)
+
+// checkRules is the rules file of the check command.
+type checkRules struct {
+	Rules []*checkRule `yaml:"rules"`
+}
+
+// checkRule limits a field of the items of a tab matching the rule, either
+// by absolute thresholds or by the change against the baseline in percent.
+type checkRule struct {
+	Tab string `yaml:"tab"`
+	// Match is a regular expression matched against the item names,
+	// all items of the tab are checked if empty.
+	Match string `yaml:"match"`
+	// Field is the name of the field in the dump output, e.g. "rx_errors".
+	Field       string   `yaml:"field"`
+	Min         *float64 `yaml:"min"`
+	Max         *float64 `yaml:"max"`
+	MaxIncrease *float64 `yaml:"max_increase"`
+	MaxDecrease *float64 `yaml:"max_decrease"`
+
+	match *regexp.Regexp
+}
+
+// checkItemNames return the id of the dumped item unique within its tab,
+// and the name of the item matched by the rules.
+var checkItemNames = map[string]func(item interface{}) (id, name string){
+	watchInterfaces: func(i interface{}) (string, string) {
+		return i.(dumpInterface).Name, i.(dumpInterface).Name
+	},
+	watchNodes: func(i interface{}) (string, string) {
+		node := i.(dumpNode)
+		return fmt.Sprintf("%s (thread %d)", node.Name, node.Thread), node.Name
+	},
+	watchErrors: func(i interface{}) (string, string) {
+		name := i.(dumpError).Node + "/" + i.(dumpError).Reason
+		return name, name
+	},
+	watchDrops: func(i interface{}) (string, string) {
+		name := i.(dumpDrop).Type + "/" + i.(dumpDrop).Node + "/" + i.(dumpDrop).Reason
+		return name, name
+	},
+}
+
+// checkFields are the numeric fields of the dumped items per tab,
+// named as in the dump output.
+var checkFields = map[string]map[string]func(item interface{}) float64{
+	watchInterfaces: {
+		"rx_packets": func(i interface{}) float64 { return float64(i.(dumpInterface).RxPackets) },
+		"rx_bytes":   func(i interface{}) float64 { return float64(i.(dumpInterface).RxBytes) },
+		"rx_errors":  func(i interface{}) float64 { return float64(i.(dumpInterface).RxErrors) },
+		"tx_packets": func(i interface{}) float64 { return float64(i.(dumpInterface).TxPackets) },
+		"tx_bytes":   func(i interface{}) float64 { return float64(i.(dumpInterface).TxBytes) },
+		"tx_errors":  func(i interface{}) float64 { return float64(i.(dumpInterface).TxErrors) },
+		"drops":      func(i interface{}) float64 { return float64(i.(dumpInterface).Drops) },
+		"punts":      func(i interface{}) float64 { return float64(i.(dumpInterface).Punts) },
+	},
+	watchNodes: {
+		"calls":            func(i interface{}) float64 { return float64(i.(dumpNode).Calls) },
+		"vectors":          func(i interface{}) float64 { return float64(i.(dumpNode).Vectors) },
+		"suspends":         func(i interface{}) float64 { return float64(i.(dumpNode).Suspends) },
+		"clocks":           func(i interface{}) float64 { return i.(dumpNode).Clocks },
+		"vectors_per_call": func(i interface{}) float64 { return i.(dumpNode).VectorsPerCall },
+	},
+	watchErrors: {
+		"count": func(i interface{}) float64 { return float64(i.(dumpError).Count) },
+	},
+	watchDrops: {
+		"count": func(i interface{}) float64 { return float64(i.(dumpDrop).Count) },
+	},
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks VPP counters against thresholds and a baseline",
+	Long: `check polls the tabs referenced by the rules once and checks the fields
+of their items against the absolute thresholds (min, max) and against the
+change from the baseline in percent (max_increase, max_decrease). The baseline
+is the output of the dump command in JSON or YAML. The violations are printed
+and the command fails if there are any, e.g. to gate a CI pipeline:
+
+  rules:
+  - tab: nodes
+    match: ^ip4-
+    field: clocks
+    max_increase: 10
+  - tab: interfaces
+    field: rx_errors
+    max: 0`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket, err := resolveSocket(cmd)
+		if err != nil {
+			return err
+		}
+		rulesFile, err := cmd.Flags().GetString("rules")
+		if err != nil {
+			return err
+		}
+		baselineFile, err := cmd.Flags().GetString("baseline")
+		if err != nil {
+			return err
+		}
+		rules, err := loadCheckRules(rulesFile, baselineFile != "")
+		if err != nil {
+			return err
+		}
+		var baseline *dumpOutput
+		if baselineFile != "" {
+			if baseline, err = loadBaseline(baselineFile); err != nil {
+				return err
+			}
+		}
+		demoMode, err := cmd.Flags().GetBool("demo")
+		if err != nil {
+			return err
+		}
+		retry, err := retryConfig(cmd)
+		if err != nil {
+			return err
+		}
+		timeout, err := requestTimeout(cmd)
+		if err != nil {
+			return err
+		}
+		counters, err := interfaceCounters(cmd)
+		if err != nil {
+			return err
+		}
+		var handler api.HandlerAPI
+		if demoMode {
+			handler = demo.NewHandler()
+		}
+
+		logs, err := openLog(cmd, "vpptop.log")
+		if err != nil {
+			return err
+		}
+
+		defer logs.Close()
+
+		provider, err := connectProvider(socket, handler, retry, timeout, counters, logs)
+		if err != nil {
+			return err
+		}
+		defer provider.Disconnect()
+
+		snapshot, err := dumpCounters(context.Background(), provider, checkTabs(rules))
+		if err != nil {
+			return err
+		}
+		violations := runCheck(cmd.OutOrStdout(), rules, snapshot, baseline)
+		if violations != 0 {
+			return fmt.Errorf("check failed: %d violation(s)", violations)
+		}
+		return nil
+	},
+}
+
+func init() {
+	checkCmd.Flags().StringP("socket", "s", adapter.DefaultStatsSocket, "vpp stats segment socket (discovered if not set)")
+	checkCmd.Flags().String("rules", "", "YAML file with the rules the counters are checked by")
+	checkCmd.Flags().String("baseline", "", "Output of the dump command (JSON or YAML) the changes are checked against")
+	checkCmd.MarkFlagRequired("rules")
+	rootCmd.AddCommand(checkCmd)
+}
+
+// loadCheckRules reads and validates the rules file, the rules limiting
+// the change from the baseline are allowed only if the baseline is set.
+func loadCheckRules(file string, withBaseline bool) ([]*checkRule, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %v", err)
+	}
+	var rules checkRules
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules %s: %v", file, err)
+	}
+	if len(rules.Rules) == 0 {
+		return nil, fmt.Errorf("no rules in %s", file)
+	}
+	for i, rule := range rules.Rules {
+		fields, ok := checkFields[rule.Tab]
+		if !ok {
+			return nil, fmt.Errorf("rule %d: unsupported tab %q (use %s, %s, %s or %s)", i+1, rule.Tab,
+				watchInterfaces, watchNodes, watchErrors, watchDrops)
+		}
+		if _, ok := fields[rule.Field]; !ok {
+			return nil, fmt.Errorf("rule %d: unknown field %q of the %s", i+1, rule.Field, rule.Tab)
+		}
+		if rule.Min == nil && rule.Max == nil && rule.MaxIncrease == nil && rule.MaxDecrease == nil {
+			return nil, fmt.Errorf("rule %d: no limit set (min, max, max_increase or max_decrease)", i+1)
+		}
+		if !withBaseline && (rule.MaxIncrease != nil || rule.MaxDecrease != nil) {
+			return nil, fmt.Errorf("rule %d: max_increase and max_decrease require the --baseline", i+1)
+		}
+		if rule.match, err = regexp.Compile(rule.Match); err != nil {
+			return nil, fmt.Errorf("rule %d: invalid match %q: %v", i+1, rule.Match, err)
+		}
+	}
+	return rules.Rules, nil
+}
+
+// loadBaseline reads the output of the dump command, parsed as YAML
+// if the file has the .yaml or .yml extension, as JSON otherwise.
+func loadBaseline(file string) (*dumpOutput, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %v", err)
+	}
+	baseline := new(dumpOutput)
+	switch filepath.Ext(file) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, baseline)
+	default:
+		err = json.Unmarshal(data, baseline)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %v", file, err)
+	}
+	if baseline.SchemaVersion != dumpSchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d of the baseline %s (expected %d)",
+			baseline.SchemaVersion, file, dumpSchemaVersion)
+	}
+	return baseline, nil
+}
+
+// checkTabs returns the tabs referenced by the rules.
+func checkTabs(rules []*checkRule) []string {
+	var tabs []string
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if !seen[rule.Tab] {
+			seen[rule.Tab] = true
+			tabs = append(tabs, rule.Tab)
+		}
+	}
+	return tabs
+}
+
+// dumpItems returns the dumped items of the tab.
+func dumpItems(dump *dumpOutput, tab string) []interface{} {
+	var items []interface{}
+	switch tab {
+	case watchInterfaces:
+		for _, iface := range dump.Interfaces {
+			items = append(items, iface)
+		}
+	case watchNodes:
+		for _, node := range dump.Nodes {
+			items = append(items, node)
+		}
+	case watchErrors:
+		for _, errorC := range dump.Errors {
+			items = append(items, errorC)
+		}
+	case watchDrops:
+		for _, drop := range dump.Drops {
+			items = append(items, drop)
+		}
+	}
+	return items
+}
+
+// String returns the tab, field and match of the rule, e.g. "nodes clocks (^ip4-)".
+func (r *checkRule) String() string {
+	if r.Match == "" {
+		return r.Tab + " " + r.Field
+	}
+	return fmt.Sprintf("%s %s (%s)", r.Tab, r.Field, r.Match)
+}
+
+// runCheck checks the items of the snapshot by the rules, the violations
+// and a summary are printed to the out writer. Items missing in the baseline
+// are not checked against it, a rule matching no items is a violation.
+func runCheck(out io.Writer, rules []*checkRule, snapshot, baseline *dumpOutput) (violations int) {
+	var checked, missing int
+	violate := func(rule *checkRule, format string, args ...interface{}) {
+		violations++
+		fmt.Fprintf(out, "FAIL %s: %s\n", rule, fmt.Sprintf(format, args...))
+	}
+	for _, rule := range rules {
+		names := checkItemNames[rule.Tab]
+		field := checkFields[rule.Tab][rule.Field]
+		base := make(map[string]interface{})
+		if baseline != nil {
+			for _, item := range dumpItems(baseline, rule.Tab) {
+				id, _ := names(item)
+				base[id] = item
+			}
+		}
+
+		matched := 0
+		for _, item := range dumpItems(snapshot, rule.Tab) {
+			id, name := names(item)
+			if !rule.match.MatchString(name) {
+				continue
+			}
+			matched++
+			checked++
+			value := field(item)
+			if rule.Max != nil && value > *rule.Max {
+				violate(rule, "%s: %s above the maximum %s", id, formatCheckValue(value), formatCheckValue(*rule.Max))
+			}
+			if rule.Min != nil && value < *rule.Min {
+				violate(rule, "%s: %s below the minimum %s", id, formatCheckValue(value), formatCheckValue(*rule.Min))
+			}
+			if rule.MaxIncrease == nil && rule.MaxDecrease == nil {
+				continue
+			}
+			baseItem, ok := base[id]
+			if !ok {
+				missing++
+				continue
+			}
+			baseValue := field(baseItem)
+			change := changePercent(value, baseValue)
+			if rule.MaxIncrease != nil && change > *rule.MaxIncrease {
+				violate(rule, "%s: %s increased by %s%% from the baseline %s (max %s%%)", id, formatCheckValue(value),
+					formatCheckValue(change), formatCheckValue(baseValue), formatCheckValue(*rule.MaxIncrease))
+			}
+			if rule.MaxDecrease != nil && -change > *rule.MaxDecrease {
+				violate(rule, "%s: %s decreased by %s%% from the baseline %s (max %s%%)", id, formatCheckValue(value),
+					formatCheckValue(-change), formatCheckValue(baseValue), formatCheckValue(*rule.MaxDecrease))
+			}
+		}
+		if matched == 0 {
+			violate(rule, "no %s matched", rule.Tab)
+		}
+	}
+
+	summary := fmt.Sprintf("%d rules, %d items checked, %d violations", len(rules), checked, violations)
+	if missing != 0 {
+		summary += fmt.Sprintf(", %d items not in the baseline", missing)
+	}
+	if violations != 0 {
+		fmt.Fprintln(out, "FAIL", summary)
+	} else {
+		fmt.Fprintln(out, "PASS", summary)
+	}
+	return violations
+}
+
+// changePercent returns the change of the value from the baseline value in
+// percent, any increase from zero is infinite.
+func changePercent(value, baseValue float64) float64 {
+	if baseValue == 0 {
+		if value == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), value)
+	}
+	return (value - baseValue) / math.Abs(baseValue) * 100
+}
+
+// formatCheckValue formats the value without the decimals if it is whole.
+func formatCheckValue(value float64) string {
+	if value == math.Trunc(value) || math.IsInf(value, 0) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.2f", value)
+}
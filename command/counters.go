@@ -0,0 +1,44 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/stats"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String("interface-counters", string(api.CountersFromStats), "Source of the interface counters, either the stats segment (stats) or the 'show interface' CLI run over the VPP API (cli) if the stats socket is not accessible")
+}
+
+// interfaceCounters returns the provider option selecting the source
+// of the interface counters set by the flag.
+func interfaceCounters(cmd *cobra.Command) (stats.ProviderOption, error) {
+	value, err := cmd.Flags().GetString("interface-counters")
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range api.InterfaceCounterSources {
+		if value == string(source) {
+			return stats.WithInterfaceCounters(source), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid interface counters source: %q (expected stats or cli)", value)
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"git.fd.io/govpp.git/adapter/socketclient"
+	"git.fd.io/govpp.git/adapter/statsclient"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// proxySelector selects the proxy pods deployed by the deploy-proxy command,
+// the node command discovers the proxies by it.
+const proxySelector = "app.kubernetes.io/name=vpptop-proxy"
+
+// proxyPortName is the name of the container port the proxy serves on.
+const proxyPortName = "vpptop-proxy"
+
+var deployProxyCmd = &cobra.Command{
+	Use:   "deploy-proxy",
+	Short: "Deploys the proxy server to all nodes of a k8s cluster",
+	Long: `Renders a DaemonSet running the vpptop proxy on each node of the k8s
+cluster and applies it (creates it, or updates an existing one). The proxy
+uses the host network, so it serves on the node address at --port, and the
+directories of the VPP sockets are mounted from the host.
+
+The 'node' command discovers the deployed proxy pods. With --dry-run the
+manifest is printed as YAML instead, e.g. to be applied by kubectl.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var cfg proxyDeployment
+		var err error
+		flags := cmd.Flags()
+		if cfg.name, err = flags.GetString("name"); err != nil {
+			return err
+		}
+		if cfg.namespace, err = flags.GetString("namespace"); err != nil {
+			return err
+		}
+		if cfg.image, err = flags.GetString("image"); err != nil {
+			return err
+		}
+		if cfg.port, err = flags.GetInt("port"); err != nil {
+			return err
+		}
+		if cfg.binapiSocket, err = flags.GetString("binapi-socket"); err != nil {
+			return err
+		}
+		if cfg.statsSocket, err = flags.GetString("stats-socket"); err != nil {
+			return err
+		}
+		if cfg.nodeSelector, err = flags.GetStringToString("node-selector"); err != nil {
+			return err
+		}
+		if cfg.port < 1 || cfg.port > 65535 {
+			return fmt.Errorf("invalid proxy port %d", cfg.port)
+		}
+		ds := proxyDaemonSet(cfg)
+
+		dryRun, err := flags.GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			manifest, err := yaml.Marshal(ds)
+			if err != nil {
+				return fmt.Errorf("failed to render the manifest: %v", err)
+			}
+			_, err = os.Stdout.Write(manifest)
+			return err
+		}
+
+		kubeconfig, err := flags.GetString("kubeconfig")
+		if err != nil {
+			return err
+		}
+		return applyDaemonSet(kubeconfig, ds)
+	},
+}
+
+func init() {
+	if home := homeDir(); home != "" {
+		deployProxyCmd.Flags().StringP("kubeconfig", "c", filepath.Join(home, ".kube", "config"), "(optional) absolute path to kubeconfig")
+	} else {
+		deployProxyCmd.Flags().StringP("kubeconfig", "c", "", "absolute path to the kubeconfig")
+	}
+	deployProxyCmd.Flags().String("name", "vpptop-proxy", "Name of the DaemonSet")
+	deployProxyCmd.Flags().StringP("namespace", "n", "default", "Namespace of the DaemonSet")
+	deployProxyCmd.Flags().String("image", "", "Image containing the vpptop binary (required)")
+	deployProxyCmd.Flags().Int("port", 7878, "Port the proxies serve on at the node addresses")
+	deployProxyCmd.Flags().String("binapi-socket", socketclient.DefaultSocketName, "Path to VPP binapi socket on the nodes")
+	deployProxyCmd.Flags().String("stats-socket", statsclient.DefaultSocketName, "Path to VPP stats socket on the nodes")
+	deployProxyCmd.Flags().StringToString("node-selector", nil, "Labels of the nodes running the proxy, e.g. 'vpp=true' (all nodes if empty)")
+	deployProxyCmd.Flags().Bool("dry-run", false, "Print the manifest instead of applying it")
+	deployProxyCmd.MarkFlagRequired("image")
+	rootCmd.AddCommand(deployProxyCmd)
+}
+
+// proxyDeployment configures the DaemonSet of the proxies.
+type proxyDeployment struct {
+	name      string
+	namespace string
+	image     string
+	port      int
+	// paths to the VPP sockets on the nodes
+	binapiSocket string
+	statsSocket  string
+	nodeSelector map[string]string
+}
+
+// proxyDaemonSet returns the DaemonSet running the proxy on each node. The
+// directories of the sockets are mounted rather than the sockets, so that
+// the sockets recreated by a VPP restart are seen by the proxy.
+func proxyDaemonSet(cfg proxyDeployment) *appsv1.DaemonSet {
+	labels := map[string]string{
+		"app.kubernetes.io/name":     "vpptop-proxy",
+		"app.kubernetes.io/instance": cfg.name,
+	}
+
+	dirs := map[string]bool{filepath.Dir(cfg.binapiSocket): true, filepath.Dir(cfg.statsSocket): true}
+	paths := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		paths = append(paths, dir)
+	}
+	sort.Strings(paths)
+	var volumes []v1.Volume
+	var mounts []v1.VolumeMount
+	dirType := v1.HostPathDirectory
+	for i, path := range paths {
+		name := "vpp-sockets-" + strconv.Itoa(i)
+		volumes = append(volumes, v1.Volume{
+			Name: name,
+			VolumeSource: v1.VolumeSource{
+				HostPath: &v1.HostPathVolumeSource{Path: path, Type: &dirType},
+			},
+		})
+		mounts = append(mounts, v1.VolumeMount{Name: name, MountPath: path})
+	}
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "DaemonSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cfg.name,
+			Namespace: cfg.namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: v1.PodSpec{
+					HostNetwork:  true,
+					DNSPolicy:    v1.DNSClusterFirstWithHostNet,
+					NodeSelector: cfg.nodeSelector,
+					// run on all nodes, including the tainted ones
+					Tolerations: []v1.Toleration{{Operator: v1.TolerationOpExists}},
+					Containers: []v1.Container{{
+						Name:  "vpptop-proxy",
+						Image: cfg.image,
+						Command: []string{"vpptop", "proxy",
+							"--addr", ":" + strconv.Itoa(cfg.port),
+							"--binapi-socket", cfg.binapiSocket,
+							"--stats-socket", cfg.statsSocket,
+						},
+						Ports: []v1.ContainerPort{{
+							Name:          proxyPortName,
+							ContainerPort: int32(cfg.port),
+							HostPort:      int32(cfg.port),
+							Protocol:      v1.ProtocolTCP,
+						}},
+						ReadinessProbe: &v1.Probe{
+							Handler: v1.Handler{
+								TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(cfg.port)},
+							},
+							PeriodSeconds: 10,
+						},
+						VolumeMounts: mounts,
+					}},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+}
+
+// applyDaemonSet creates the DaemonSet, or updates it if it exists.
+func applyDaemonSet(kubeconfig string, ds *appsv1.DaemonSet) error {
+	clientset, err := kubeClient(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the cluster: %v", err)
+	}
+	ctx := context.Background()
+	daemonSets := clientset.AppsV1().DaemonSets(ds.Namespace)
+	current, err := daemonSets.Get(ctx, ds.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		if _, err := daemonSets.Create(ctx, ds, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create the DaemonSet %s/%s: %v", ds.Namespace, ds.Name, err)
+		}
+		fmt.Printf("DaemonSet %s/%s created\n", ds.Namespace, ds.Name)
+	case err != nil:
+		return fmt.Errorf("failed to get the DaemonSet %s/%s: %v", ds.Namespace, ds.Name, err)
+	default:
+		ds.ResourceVersion = current.ResourceVersion
+		if _, err := daemonSets.Update(ctx, ds, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update the DaemonSet %s/%s: %v", ds.Namespace, ds.Name, err)
+		}
+		fmt.Printf("DaemonSet %s/%s updated\n", ds.Namespace, ds.Name)
+	}
+	return nil
+}
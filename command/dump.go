@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"git.fd.io/govpp.git/adapter"
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/demo"
+	"gopkg.in/yaml.v2"
+)
+
+// dumpSchemaVersion is the version of the dump output, it is incremented
+// whenever a field is renamed or removed (adding fields keeps the version).
+const dumpSchemaVersion = 1
+
+// output formats of the dump command
+const (
+	dumpTable = "table"
+	dumpJSON  = "json"
+	dumpYAML  = "yaml"
+)
+
+// dumpOutput is the output of the dump command, only the dumped tabs are set.
+type dumpOutput struct {
+	SchemaVersion int             `json:"schema_version" yaml:"schema_version"`
+	Time          time.Time       `json:"time" yaml:"time"`
+	Tabs          []string        `json:"tabs" yaml:"tabs"`
+	Interfaces    []dumpInterface `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+	Nodes         []dumpNode      `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Errors        []dumpError     `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Drops         []dumpDrop      `json:"drops,omitempty" yaml:"drops,omitempty"`
+}
+
+// dumpInterface is a dumped interface.
+type dumpInterface struct {
+	Name      string `json:"name" yaml:"name"`
+	Index     uint32 `json:"index" yaml:"index"`
+	State     string `json:"state" yaml:"state"`
+	RxPackets uint64 `json:"rx_packets" yaml:"rx_packets"`
+	RxBytes   uint64 `json:"rx_bytes" yaml:"rx_bytes"`
+	RxErrors  uint64 `json:"rx_errors" yaml:"rx_errors"`
+	TxPackets uint64 `json:"tx_packets" yaml:"tx_packets"`
+	TxBytes   uint64 `json:"tx_bytes" yaml:"tx_bytes"`
+	TxErrors  uint64 `json:"tx_errors" yaml:"tx_errors"`
+	Drops     uint64 `json:"drops" yaml:"drops"`
+	Punts     uint64 `json:"punts" yaml:"punts"`
+}
+
+// dumpNode is a dumped node of a single thread.
+type dumpNode struct {
+	Name           string  `json:"name" yaml:"name"`
+	Thread         uint    `json:"thread" yaml:"thread"`
+	State          string  `json:"state" yaml:"state"`
+	Calls          uint64  `json:"calls" yaml:"calls"`
+	Vectors        uint64  `json:"vectors" yaml:"vectors"`
+	Suspends       uint64  `json:"suspends" yaml:"suspends"`
+	Clocks         float64 `json:"clocks" yaml:"clocks"`
+	VectorsPerCall float64 `json:"vectors_per_call" yaml:"vectors_per_call"`
+}
+
+// dumpError is a dumped error counter.
+type dumpError struct {
+	Node     string `json:"node" yaml:"node"`
+	Reason   string `json:"reason" yaml:"reason"`
+	Severity string `json:"severity" yaml:"severity"`
+	Count    uint64 `json:"count" yaml:"count"`
+}
+
+// dumpDrop is a dumped drop or punt counter.
+type dumpDrop struct {
+	Type   string `json:"type" yaml:"type"`
+	Node   string `json:"node" yaml:"node"`
+	Reason string `json:"reason" yaml:"reason"`
+	Count  uint64 `json:"count" yaml:"count"`
+}
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Prints VPP counters of the selected tabs once",
+	Long: `dump polls counters of the selected tabs (interfaces, nodes, errors
+or drops) once and prints them as a table, JSON or YAML. The JSON and YAML
+output contains the schema version, field names are kept stable within
+the version so that the output can be processed by other tools.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket, err := resolveSocket(cmd)
+		if err != nil {
+			return err
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		switch format {
+		case dumpTable, dumpJSON, dumpYAML:
+		default:
+			return fmt.Errorf("invalid format %q (use %s, %s or %s)", format, dumpTable, dumpJSON, dumpYAML)
+		}
+		tabs, err := dumpTabs(cmd)
+		if err != nil {
+			return err
+		}
+		demoMode, err := cmd.Flags().GetBool("demo")
+		if err != nil {
+			return err
+		}
+		retry, err := retryConfig(cmd)
+		if err != nil {
+			return err
+		}
+		timeout, err := requestTimeout(cmd)
+		if err != nil {
+			return err
+		}
+		counters, err := interfaceCounters(cmd)
+		if err != nil {
+			return err
+		}
+		var handler api.HandlerAPI
+		if demoMode {
+			handler = demo.NewHandler()
+		}
+
+		logs, err := openLog(cmd, "vpptop.log")
+		if err != nil {
+			return err
+		}
+
+		defer logs.Close()
+
+		provider, err := connectProvider(socket, handler, retry, timeout, counters, logs)
+		if err != nil {
+			return err
+		}
+		defer provider.Disconnect()
+
+		dump, err := dumpCounters(context.Background(), provider, tabs)
+		if err != nil {
+			return err
+		}
+		return printDump(cmd.OutOrStdout(), dump, format)
+	},
+}
+
+func init() {
+	dumpCmd.Flags().StringP("socket", "s", adapter.DefaultStatsSocket, "vpp stats segment socket (discovered if not set)")
+	dumpCmd.Flags().StringP("format", "f", dumpTable, "Output format (table, json, yaml)")
+	dumpCmd.Flags().String("tabs", strings.Join([]string{watchInterfaces, watchNodes, watchErrors, watchDrops}, ","),
+		"Comma separated tabs to dump (interfaces, nodes, errors, drops)")
+	rootCmd.AddCommand(dumpCmd)
+}
+
+// dumpTabs returns the tabs set by the --tabs flag.
+func dumpTabs(cmd *cobra.Command) ([]string, error) {
+	value, err := cmd.Flags().GetString("tabs")
+	if err != nil {
+		return nil, err
+	}
+	var tabs []string
+	seen := make(map[string]bool)
+	for _, tab := range strings.Split(value, ",") {
+		tab = strings.TrimSpace(tab)
+		switch tab {
+		case "":
+			continue
+		case watchInterfaces, watchNodes, watchErrors, watchDrops:
+		default:
+			return nil, fmt.Errorf("unsupported tab %q (use %s, %s, %s or %s)", tab,
+				watchInterfaces, watchNodes, watchErrors, watchDrops)
+		}
+		if !seen[tab] {
+			seen[tab] = true
+			tabs = append(tabs, tab)
+		}
+	}
+	if len(tabs) == 0 {
+		return nil, fmt.Errorf("invalid tabs %q: no tab selected", value)
+	}
+	return tabs, nil
+}
+
+// dumpCounters polls counters of the tabs.
+func dumpCounters(ctx context.Context, provider api.VppProviderAPI, tabs []string) (*dumpOutput, error) {
+	dump := &dumpOutput{
+		SchemaVersion: dumpSchemaVersion,
+		Time:          time.Now(),
+		Tabs:          tabs,
+	}
+	for _, tab := range tabs {
+		switch tab {
+		case watchInterfaces:
+			ifaces, err := provider.GetInterfaces(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get interfaces: %v", err)
+			}
+			for _, iface := range ifaces {
+				dump.Interfaces = append(dump.Interfaces, dumpInterface{
+					Name:      iface.InterfaceName,
+					Index:     iface.InterfaceIndex,
+					State:     iface.State,
+					RxPackets: iface.Rx.Packets,
+					RxBytes:   iface.Rx.Bytes,
+					RxErrors:  iface.RxErrors,
+					TxPackets: iface.Tx.Packets,
+					TxBytes:   iface.Tx.Bytes,
+					TxErrors:  iface.TxErrors,
+					Drops:     iface.Drops,
+					Punts:     iface.Punts,
+				})
+			}
+		case watchNodes:
+			nodes, err := provider.GetNodes(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get nodes: %v", err)
+			}
+			for _, node := range nodes {
+				dump.Nodes = append(dump.Nodes, dumpNode{
+					Name:           node.Name,
+					Thread:         node.Thread,
+					State:          node.State,
+					Calls:          node.Calls,
+					Vectors:        node.Vectors,
+					Suspends:       node.Suspends,
+					Clocks:         node.Clocks,
+					VectorsPerCall: node.VectorsPerCall,
+				})
+			}
+		case watchErrors:
+			errors, err := provider.GetErrors(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get errors: %v", err)
+			}
+			for _, errorC := range errors {
+				dump.Errors = append(dump.Errors, dumpError{
+					Node:     errorC.Node,
+					Reason:   errorC.Reason,
+					Severity: errorC.Severity,
+					Count:    errorC.Count,
+				})
+			}
+		case watchDrops:
+			dropsPunts, err := provider.GetDropsPunts(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get drops: %v", err)
+			}
+			for _, dropPunt := range dropsPunts {
+				dump.Drops = append(dump.Drops, dumpDrop{
+					Type:   dropPunt.Type,
+					Node:   dropPunt.Node,
+					Reason: dropPunt.Reason,
+					Count:  dropPunt.Count,
+				})
+			}
+		}
+	}
+	return dump, nil
+}
+
+// printDump prints the dump to the out writer in the format.
+func printDump(out io.Writer, dump *dumpOutput, format string) error {
+	switch format {
+	case dumpJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dump)
+	case dumpYAML:
+		data, err := yaml.Marshal(dump)
+		if err != nil {
+			return fmt.Errorf("failed to marshal dump: %v", err)
+		}
+		_, err = out.Write(data)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for i, tab := range dump.Tabs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		switch tab {
+		case watchInterfaces:
+			fmt.Fprintln(w, "NAME\tINDEX\tSTATE\tRX PACKETS\tRX BYTES\tRX ERRORS\tTX PACKETS\tTX BYTES\tTX ERRORS\tDROPS\tPUNTS")
+			for _, iface := range dump.Interfaces {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n", iface.Name, iface.Index, iface.State,
+					iface.RxPackets, iface.RxBytes, iface.RxErrors, iface.TxPackets, iface.TxBytes, iface.TxErrors,
+					iface.Drops, iface.Punts)
+			}
+		case watchNodes:
+			fmt.Fprintln(w, "NAME\tTHREAD\tSTATE\tCALLS\tVECTORS\tSUSPENDS\tCLOCKS\tVECTORS/CALL")
+			for _, node := range dump.Nodes {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\t%d\t%.2f\t%.2f\n", node.Name, node.Thread, node.State,
+					node.Calls, node.Vectors, node.Suspends, node.Clocks, node.VectorsPerCall)
+			}
+		case watchErrors:
+			fmt.Fprintln(w, "NODE\tREASON\tSEVERITY\tCOUNT")
+			for _, errorC := range dump.Errors {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", errorC.Node, errorC.Reason, errorC.Severity, errorC.Count)
+			}
+		case watchDrops:
+			fmt.Fprintln(w, "TYPE\tNODE\tREASON\tCOUNT")
+			for _, drop := range dump.Drops {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", drop.Type, drop.Node, drop.Reason, drop.Count)
+			}
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/client"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String("http", "", "Address of the HTTP server exposing the collected stats as JSON, e.g. ':8080' (disabled if empty)")
+	rootCmd.PersistentFlags().String("http-token", "", "Token required by the HTTP server in the 'Authorization: Bearer' header (VPPTOP_HTTP_TOKEN if not set)")
+	rootCmd.PersistentFlags().String("control-socket", "", "Path of the UNIX socket accepting commands of external scripts, e.g. 'dump interfaces json' (disabled if empty)")
+}
+
+// httpConfig returns the HTTP server configuration set by the flags.
+func httpConfig(cmd *cobra.Command) (client.HTTPConfig, error) {
+	flags := cmd.Flags()
+	var cfg client.HTTPConfig
+	var err error
+	if cfg.Addr, err = flags.GetString("http"); err != nil {
+		return cfg, err
+	}
+	if cfg.Token, err = flags.GetString("http-token"); err != nil {
+		return cfg, err
+	}
+	if cfg.Token == "" {
+		// keep the token out of the process list
+		cfg.Token = os.Getenv("VPPTOP_HTTP_TOKEN")
+	}
+	return cfg, nil
+}
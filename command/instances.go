@@ -0,0 +1,49 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/stats"
+)
+
+func init() {
+	rootCmd.Flags().StringArray("stats-instance", nil, "Stats socket '[name=]socket' of another VPP instance managed together with the connected one (e.g. per-NUMA instance), "+
+		"its interface counters are merged into the interfaces tab (repeatable)")
+}
+
+// statsInstances returns the provider option merging the interfaces of the
+// instances set by the flag, the connected VPP is named by its stats socket.
+// The flag is defined only for the local VPP.
+func statsInstances(cmd *cobra.Command, socket string) (stats.ProviderOption, error) {
+	if cmd.Flags().Lookup("stats-instance") == nil {
+		return stats.WithStatsInstances(""), nil
+	}
+	values, err := cmd.Flags().GetStringArray("stats-instance")
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]stats.StatsInstance, 0, len(values))
+	for _, value := range values {
+		instance, err := stats.ParseStatsInstance(value)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, instance)
+	}
+	return stats.WithStatsInstances(stats.InstanceName(socket), instances...), nil
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String("lang", "", "Language of the user interface ("+strings.Join(i18n.Locales(), ", ")+"), detected from LC_ALL, LC_MESSAGES or LANG if not set")
+}
+
+// setLocale selects the language of the user interface set by the flag,
+// or detected from the environment.
+func setLocale(cmd *cobra.Command) error {
+	locale, err := cmd.Flags().GetString("lang")
+	if err != nil {
+		return err
+	}
+	if locale == "" {
+		locale = i18n.DetectLocale()
+	}
+	return i18n.SetLocale(locale)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringP("log", "l", "vpptop.log", "Log file")
+	rootCmd.PersistentFlags().String("log-level", logrus.InfoLevel.String(), "Log level (panic, fatal, error, warn, info, debug, trace)")
+	rootCmd.PersistentFlags().Int("log-max-size", 10, "Maximum size of the log file in megabytes before it is rotated, 0 disables rotation")
+	rootCmd.PersistentFlags().Int("log-max-backups", 3, "Maximum number of rotated log files to keep")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Log every binapi/CLI request with its duration (sets the log level to debug)")
+}
+
+// openLog creates the log file set by the flags (or the defaultName if the flag
+// was not set) and configures the logger. The returned writer has to be closed.
+func openLog(cmd *cobra.Command, defaultName string) (io.WriteCloser, error) {
+	flags := cmd.Flags()
+	logFile := defaultName
+	if flags.Changed("log") {
+		var err error
+		if logFile, err = flags.GetString("log"); err != nil {
+			return nil, err
+		}
+	}
+	levelName, err := flags.GetString("log-level")
+	if err != nil {
+		return nil, err
+	}
+	maxSize, err := flags.GetInt("log-max-size")
+	if err != nil {
+		return nil, err
+	}
+	maxBackups, err := flags.GetInt("log-max-backups")
+	if err != nil {
+		return nil, err
+	}
+	verbose, err := flags.GetBool("verbose")
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := logrus.ParseLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+	if verbose && level < logrus.DebugLevel {
+		level = logrus.DebugLevel
+	}
+
+	logs, err := newRotatingFile(logFile, int64(maxSize)*1024*1024, maxBackups)
+	if err != nil {
+		return nil, fmt.Errorf("error occured while creating file: %v", err)
+	}
+
+	logrus.SetOutput(logs)
+	logrus.SetLevel(level)
+	logrus.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	// libraries using the standard logger must not write to the terminal
+	log.SetOutput(logs)
+
+	return logs, nil
+}
+
+// rotatingFile is a log file rotated when it exceeds the maximum size.
+// Rotated files are suffixed with a number, the higher the older.
+type rotatingFile struct {
+	sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	file *os.File
+	size int64
+}
+
+// newRotatingFile creates (or truncates) the file at the path.
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		file:       file,
+	}, nil
+}
+
+// Write writes to the file, rotating it first if the write would exceed the maximum size.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate shifts the backups, moves the current file to the first
+// backup and opens a new file.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if f.maxBackups > 0 {
+		for i := f.maxBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", f.path, i), fmt.Sprintf("%s.%d", f.path, i+1))
+		}
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return err
+		}
+	}
+	file, err := os.Create(f.path)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	f.size = 0
+	return nil
+}
+
+// Close closes the file.
+func (f *rotatingFile) Close() error {
+	f.Lock()
+	defer f.Unlock()
+	return f.file.Close()
+}
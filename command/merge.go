@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/client"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge-snapshots <snapshot-dir-1> <snapshot-dir-2>",
+	Short: "Merges the snapshots captured with a sync tag at both ends of a link",
+	Long: `merge-snapshots pairs the snapshot bundles captured by two instances
+sharing the --sync-tag (e.g. on both ends of a link, the snapshot directories
+copied from both hosts) by the tag and by their UTC time within the tolerance.
+Each pair is aligned side by side in the output directory:
+
+  vpptop-<tag>-<UTC time>/
+    1-<host>/    bundle of the first directory
+    2-<host>/    bundle of the second directory
+    sync.txt     hosts, times and the offset of the second bundle`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		tolerance, err := cmd.Flags().GetDuration("tolerance")
+		if err != nil {
+			return err
+		}
+		result, err := client.MergeSnapshots(args[0], args[1], output, tolerance)
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		for _, pair := range result.Pairs {
+			fmt.Fprintf(out, "%s: %s (%s) + %s (%s), offset %v\n", pair.A.Tag,
+				pair.A.Dir, pair.A.Host, pair.B.Dir, pair.B.Host, pair.Offset())
+		}
+		for _, bundle := range result.Unpaired {
+			fmt.Fprintf(out, "%s: %s (%s) not paired\n", bundle.Tag, bundle.Dir, bundle.Host)
+		}
+		fmt.Fprintf(out, "%d pair(s) merged to %s\n", len(result.Pairs), output)
+		return nil
+	},
+}
+
+func init() {
+	mergeCmd.Flags().StringP("output", "o", "vpptop-merged", "Directory the paired snapshots are merged to")
+	mergeCmd.Flags().Duration("tolerance", 5*time.Second, "Max difference of the UTC times of the paired snapshots")
+	rootCmd.AddCommand(mergeCmd)
+}
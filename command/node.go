@@ -17,31 +17,34 @@
 package command
 
 import (
-	"errors"
-	"fmt"
-	"log"
-	"os"
+	"context"
 	"path/filepath"
 	"time"
 
 	"git.fd.io/govpp.git/adapter/socketclient"
 	"git.fd.io/govpp.git/adapter/statsclient"
 	"git.fd.io/govpp.git/proxy"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"go.ligato.io/cn-infra/v2/logging"
 )
 
 var nodeCmd = &cobra.Command{
-	Use:   "node <nodeName>",
+	Use:   "node [nodeName]",
 	Short: "Collects vpp statistics from the specified node",
+	Long: `Collects vpp statistics from the specified node. The node is either
+a k8s node name from the kubeconfig or an ip address. If no node is
+specified, the nodes of the cluster are listed to select one.
+
+The proxy pods deployed by the 'deploy-proxy' command are discovered in
+the cluster. Otherwise the vpptop proxies are probed on the ports set by
+--proxy-ports, every proxy serves a single VPP instance. Alternatively,
+the proxies are listed by the registry endpoint set by --registry. If more
+instances are found, the user selects the one to attach to.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 {
-			return errors.New("no node specified")
-		}
-
-		logs, err := os.Create("remote.log")
+		logs, err := openLog(cmd, "remote.log")
 		if err != nil {
-			return fmt.Errorf("error occured while creating file: %v", err)
+			return err
 		}
 
 		defer logs.Close()
@@ -53,19 +56,76 @@ var nodeCmd = &cobra.Command{
 			return err
 		}
 
+		ports, err := proxyPorts(cmd)
+		if err != nil {
+			return err
+		}
+		registry, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		if registry != "" {
+			instances, err := fetchRegistry(registry)
+			if err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				instances = registryInstances(instances, args[0])
+			}
+			addr, err := selectInstance(cmd, instances)
+			if err != nil {
+				return err
+			}
+			return startClient(cmd, "", addr, logs)
+		}
+
+		// proxies deployed by the deploy-proxy command
+		instances, err := proxyPods(kubeconfig)
+		if err != nil {
+			logrus.Debugf("failed to list the proxy pods: %v", err)
+		}
+		if len(args) > 0 {
+			instances = registryInstances(instances, args[0])
+		}
+		if len(instances) > 0 {
+			addr, err := selectInstance(cmd, instances)
+			if err != nil {
+				return err
+			}
+			return startClient(cmd, "", addr, logs)
+		}
+
+		if len(args) < 1 {
+			node, err := selectNode(cmd, kubeconfig, ports)
+			if err != nil {
+				return err
+			}
+			addr, err := selectInstance(cmd, addrInstances(node.name, node.ip, node.proxies, ports))
+			if err != nil {
+				return err
+			}
+			return startClient(cmd, "", addr, logs)
+		}
+
 		ipaddr, found := resolveNode(kubeconfig, args[0])
 		if found {
-			return startClient("", ipaddr+":"+"7878", logs)
+			addrs := probeProxies(ipaddr, ports)
+			addr, err := selectInstance(cmd, addrInstances(args[0], ipaddr, addrs, ports))
+			if err != nil {
+				return err
+			}
+			return startClient(cmd, "", addr, logs)
 		}
 
-		log.Println("failed to resolve addr:", args[0])
+		logrus.Warnln("failed to resolve addr:", args[0])
 
 		rAddr, err := cmd.Flags().GetString("addr")
 		if err != nil {
 			return err
 		}
 
-		log.Println("trying to connect to a local server at:", rAddr)
+		logrus.Infoln("trying to connect to a local server at:", rAddr)
 
 		for i := 0; i < 3; i++ {
 			if _, err = proxy.Connect(rAddr); err == nil {
@@ -75,8 +135,8 @@ var nodeCmd = &cobra.Command{
 		}
 
 		if err != nil {
-			log.Println("no server found")
-			log.Println("starting local server at:", rAddr)
+			logrus.Infoln("no server found")
+			logrus.Infoln("starting local server at:", rAddr)
 
 			binapiSocket, err := cmd.Flags().GetString("binapi-socket")
 			if err != nil {
@@ -89,31 +149,13 @@ var nodeCmd = &cobra.Command{
 			}
 
 			go func() {
-				p, err := proxy.NewServer()
-				if err != nil {
-					log.Fatalln("creating local server failed")
-				}
-
-				statsAdapter := statsclient.NewStatsClient(statsSocket)
-				binapiAdapter := socketclient.NewVppClient(binapiSocket)
-
-				if err := p.ConnectStats(statsAdapter); err != nil {
-					log.Fatalln("connecting to stats failed:", err)
-				}
-
-				defer p.DisconnectStats()
-
-				if err := p.ConnectBinapi(binapiAdapter); err != nil {
-					log.Fatalln("connecting to binapi failed:", err)
+				if err := runProxy(context.Background(), rAddr, binapiSocket, statsSocket); err != nil {
+					logrus.Fatalln(err)
 				}
-
-				defer p.DisconnectBinapi()
-
-				p.ListenAndServe(rAddr)
 			}()
 		}
 
-		return startClient("", rAddr, logs)
+		return startClient(cmd, "", rAddr, logs)
 	},
 }
 
@@ -127,5 +169,7 @@ func init() {
 	nodeCmd.Flags().String("binapi-socket", socketclient.DefaultSocketName, "Path to VPP binapi socket")
 	nodeCmd.Flags().String("stats-socket", statsclient.DefaultSocketName, "Path to VPP stats socket")
 	nodeCmd.Flags().String("addr", ":9191", "Address on which proxy serves RPC.")
+	nodeCmd.Flags().String("proxy-ports", defaultProxyPorts, "Ports of the vpptop proxies probed on the node, e.g. '7878,9191-9194'")
+	nodeCmd.Flags().String("registry", "", "URL of the registry listing the vpptop proxies as JSON [{\"name\", \"node\", \"addr\"}] (the ports are probed if empty)")
 	rootCmd.AddCommand(nodeCmd)
 }
@@ -0,0 +1,309 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/gui"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultProxyPorts are the ports of the vpptop proxies probed on k8s nodes.
+const defaultProxyPorts = "7878"
+
+// proxyDialTimeout limits the check whether a proxy is reachable.
+const proxyDialTimeout = time.Second
+
+// registryTimeout limits the request of the proxy registry.
+const registryTimeout = 5 * time.Second
+
+// proxyInstance is a vpptop proxy serving a single VPP,
+// the registry returns a JSON list of instances.
+type proxyInstance struct {
+	// Name (optional) of the VPP instance.
+	Name string `json:"name"`
+	// Node the proxy runs on.
+	Node string `json:"node"`
+	// Addr of the proxy (host:port).
+	Addr string `json:"addr"`
+}
+
+// nodeEntry is a k8s node offered by the node selector.
+type nodeEntry struct {
+	name string
+	ip   string
+	// addresses of the reachable proxies
+	proxies []string
+}
+
+// proxyPorts returns the ports set by the --proxy-ports flag, a comma
+// separated list of ports and port ranges, e.g. '7878,9191-9194'.
+func proxyPorts(cmd *cobra.Command) ([]int, error) {
+	spec, err := cmd.Flags().GetString("proxy-ports")
+	if err != nil {
+		return nil, err
+	}
+	var ports []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		from, to := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			from, to = part[:i], part[i+1:]
+		}
+		first, err := strconv.Atoi(from)
+		if err != nil || first < 1 || first > 65535 {
+			return nil, fmt.Errorf("invalid proxy port %q", part)
+		}
+		last, err := strconv.Atoi(to)
+		if err != nil || last < first || last > 65535 {
+			return nil, fmt.Errorf("invalid proxy port range %q", part)
+		}
+		for port := first; port <= last; port++ {
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+// probeProxies returns the addresses of the proxies reachable
+// on the host ports, the ports are probed concurrently.
+func probeProxies(host string, ports []int) []string {
+	reachable := make([]bool, len(ports))
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			conn, err := net.DialTimeout("tcp", addr, proxyDialTimeout)
+			if err != nil {
+				return
+			}
+			conn.Close()
+			reachable[i] = true
+		}(i, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+	wg.Wait()
+
+	var addrs []string
+	for i, port := range ports {
+		if reachable[i] {
+			addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(port)))
+		}
+	}
+	return addrs
+}
+
+// fetchRegistry returns the proxy instances listed by the registry endpoint.
+func fetchRegistry(url string) ([]proxyInstance, error) {
+	client := &http.Client{Timeout: registryTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("proxy registry request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy registry request failed: %s", resp.Status)
+	}
+	var instances []proxyInstance
+	if err := json.NewDecoder(resp.Body).Decode(&instances); err != nil {
+		return nil, fmt.Errorf("invalid proxy registry response: %v", err)
+	}
+	return instances, nil
+}
+
+// registryInstances returns the instances running on the node,
+// given either by its name or address.
+func registryInstances(instances []proxyInstance, node string) []proxyInstance {
+	var result []proxyInstance
+	for _, instance := range instances {
+		host, _, _ := net.SplitHostPort(instance.Addr)
+		if instance.Node == node || host == node {
+			result = append(result, instance)
+		}
+	}
+	return result
+}
+
+// proxyPods returns the instances of the running proxy pods deployed by the
+// deploy-proxy command, in all namespaces. The pods serve on the node address.
+func proxyPods(kubeconfig string) ([]proxyInstance, error) {
+	clientset, err := kubeClient(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{
+		LabelSelector: proxySelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return podInstances(pods.Items), nil
+}
+
+// podInstances returns the instances of the running pods serving
+// on the proxy port, sorted by the node name.
+func podInstances(pods []v1.Pod) []proxyInstance {
+	var instances []proxyInstance
+	for _, pod := range pods {
+		if pod.Status.Phase != v1.PodRunning || pod.Status.HostIP == "" {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				if port.Name != proxyPortName {
+					continue
+				}
+				instances = append(instances, proxyInstance{
+					Name: pod.Namespace + "/" + pod.Name,
+					Node: pod.Spec.NodeName,
+					Addr: net.JoinHostPort(pod.Status.HostIP, strconv.Itoa(int(port.ContainerPort))),
+				})
+			}
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Node < instances[j].Node })
+	return instances
+}
+
+// selectNode lists the nodes of the cluster with their addresses and the
+// number of reachable proxies, and returns the node selected by the user.
+func selectNode(cmd *cobra.Command, kubeconfig string, ports []int) (nodeEntry, error) {
+	nodes, err := getNodes(kubeconfig)
+	if err != nil {
+		return nodeEntry{}, fmt.Errorf("no node specified and the nodes cannot be listed: %v", err)
+	}
+	entries := nodeEntries(nodes, ports)
+	if len(entries) == 0 {
+		return nodeEntry{}, errors.New("no node specified and no node with an address found")
+	}
+
+	nameWidth := len("NAME")
+	for _, entry := range entries {
+		if len(entry.name) > nameWidth {
+			nameWidth = len(entry.name)
+		}
+	}
+	rows := make([]string, len(entries))
+	for i, entry := range entries {
+		proxy := "unreachable"
+		switch n := len(entry.proxies); {
+		case n == 1:
+			proxy = "reachable"
+		case n > 1:
+			proxy = fmt.Sprintf("reachable (%d instances)", n)
+		}
+		rows[i] = fmt.Sprintf("%-*s  %-39s  proxy %s", nameWidth, entry.name, entry.ip, proxy)
+	}
+	selected, err := selectRow(cmd, "Select a node (Enter to connect, Esc to quit)", rows)
+	if err != nil {
+		return nodeEntry{}, err
+	}
+	return entries[selected], nil
+}
+
+// nodeEntries returns the nodes with an address sorted by their names,
+// the proxies of the nodes are probed concurrently.
+func nodeEntries(nodes []v1.Node, ports []int) []nodeEntry {
+	var entries []nodeEntry
+	for _, node := range nodes {
+		if ip, ok := nodeAddress(node); ok {
+			entries = append(entries, nodeEntry{name: node.Name, ip: ip})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	var wg sync.WaitGroup
+	for i := range entries {
+		wg.Add(1)
+		go func(entry *nodeEntry) {
+			defer wg.Done()
+			entry.proxies = probeProxies(entry.ip, ports)
+		}(&entries[i])
+	}
+	wg.Wait()
+	return entries
+}
+
+// selectInstance returns the address of the proxy instance, the user
+// selects one if there are more instances.
+func selectInstance(cmd *cobra.Command, instances []proxyInstance) (string, error) {
+	switch len(instances) {
+	case 0:
+		return "", errors.New("no vpptop proxy found")
+	case 1:
+		return instances[0].Addr, nil
+	}
+	rows := make([]string, len(instances))
+	for i, instance := range instances {
+		rows[i] = instance.Addr
+		if instance.Node != "" {
+			rows[i] = instance.Node + "  " + rows[i]
+		}
+		if instance.Name != "" {
+			rows[i] += "  " + instance.Name
+		}
+	}
+	selected, err := selectRow(cmd, "Select a VPP instance (Enter to connect, Esc to quit)", rows)
+	if err != nil {
+		return "", err
+	}
+	return instances[selected].Addr, nil
+}
+
+// addrInstances returns the instances of the proxies at the addresses. If no
+// proxy was reachable, the first port is used since the proxy may not be
+// started yet and the connection is retried.
+func addrInstances(node, ip string, addrs []string, ports []int) []proxyInstance {
+	if len(addrs) == 0 {
+		addrs = []string{net.JoinHostPort(ip, strconv.Itoa(ports[0]))}
+	}
+	instances := make([]proxyInstance, len(addrs))
+	for i, addr := range addrs {
+		instances[i] = proxyInstance{Node: node, Addr: addr}
+	}
+	return instances
+}
+
+// selectRow lets the user select one of the rows in a full screen list.
+func selectRow(cmd *cobra.Command, title string, rows []string) (int, error) {
+	theme, err := loadTheme(cmd)
+	if err != nil {
+		return -1, err
+	}
+	gui.SetTheme(theme)
+	selected, err := gui.SelectItem(title, rows)
+	if err != nil {
+		return -1, err
+	}
+	if selected < 0 {
+		return -1, errors.New("nothing selected")
+	}
+	return selected, nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"git.fd.io/govpp.git/adapter/socketclient"
+	"git.fd.io/govpp.git/adapter/statsclient"
+	"git.fd.io/govpp.git/proxy"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Runs the proxy server serving vpp statistics to remote vpptop clients",
+	Long: `Runs only the proxy server on the VPP host. Remote vpptop clients
+connect to it by the 'node' command with the --addr flag set to the
+address of the server.
+
+With --batch (on by default), the proxy polls the tabs refreshed every
+second itself and serves their data in a single round-trip, the clients
+fall back to the request per request polling with older proxies.
+
+When started by systemd, the proxy notifies systemd once it is ready and
+pings the watchdog (WatchdogSec) while the stats segment is readable. With
+socket activation, the socket passed by systemd is used instead of --addr.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logs, err := openLog(cmd, "proxy.log")
+		if err != nil {
+			return err
+		}
+
+		defer logs.Close()
+
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return err
+		}
+		binapiSocket, err := cmd.Flags().GetString("binapi-socket")
+		if err != nil {
+			return err
+		}
+		statsSocket, err := cmd.Flags().GetString("stats-socket")
+		if err != nil {
+			return err
+		}
+
+		listener, err := sdListener()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		batch, err := cmd.Flags().GetBool("batch")
+		if err != nil {
+			return err
+		}
+		if batch {
+			if err := serveBatch(ctx, cmd, binapiSocket, statsSocket, logs); err != nil {
+				return err
+			}
+		}
+
+		if listener == nil {
+			return runProxy(ctx, addr, binapiSocket, statsSocket)
+		}
+		return runActivatedProxy(ctx, listener, binapiSocket, statsSocket)
+	},
+}
+
+func init() {
+	proxyCmd.Flags().String("addr", ":9191", "Address on which proxy serves RPC.")
+	proxyCmd.Flags().String("binapi-socket", socketclient.DefaultSocketName, "Path to VPP binapi socket")
+	proxyCmd.Flags().String("stats-socket", statsclient.DefaultSocketName, "Path to VPP stats socket")
+	rootCmd.AddCommand(proxyCmd)
+}
+
+// runProxy is a blocking call serving vpp statistics and binapi on the address
+// until the context is cancelled or the server fails.
+func runProxy(ctx context.Context, addr, binapiSocket, statsSocket string) error {
+	p, err := proxy.NewServer()
+	if err != nil {
+		return fmt.Errorf("creating proxy server failed: %v", err)
+	}
+
+	statsClient := statsclient.NewStatsClient(statsSocket)
+	if err := p.ConnectStats(statsClient); err != nil {
+		return fmt.Errorf("connecting to stats failed: %v", err)
+	}
+	defer p.DisconnectStats()
+
+	if err := p.ConnectBinapi(socketclient.NewVppClient(binapiSocket)); err != nil {
+		return fmt.Errorf("connecting to binapi failed: %v", err)
+	}
+	defer p.DisconnectBinapi()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- p.ListenAndServe(addr)
+	}()
+	logrus.Infoln("proxy server listening at:", addr)
+
+	sdNotify(sdStateReady)
+	go sdWatchdog(ctx, func() error {
+		_, err := statsClient.DumpStats("/sys/heartbeat")
+		return err
+	})
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("proxy server failed: %v", err)
+	case <-ctx.Done():
+		sdNotify(sdStateStopping)
+		logrus.Infoln("proxy server stopped")
+		return nil
+	}
+}
+
+// runActivatedProxy is a blocking call serving vpp statistics and binapi
+// on the listener passed by the systemd socket activation. The proxy server
+// listens only on an address, so it is started on a loopback address and
+// connections accepted by the listener are relayed to it.
+func runActivatedProxy(ctx context.Context, listener net.Listener, binapiSocket, statsSocket string) error {
+	defer listener.Close()
+
+	// reserve a free loopback port for the proxy server
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("reserving loopback address failed: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	logrus.Infoln("proxy server socket activated at:", listener.Addr())
+	go relay(listener, addr)
+	return runProxy(ctx, addr, binapiSocket, statsSocket)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/client"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String("push-url", "", "Prometheus Pushgateway URL the collected metrics are pushed to (disabled if empty)")
+	rootCmd.PersistentFlags().String("push-job", "vpptop", "Job label of the pushed metrics")
+	rootCmd.PersistentFlags().String("push-instance", "", "Instance label of the pushed metrics (hostname if empty)")
+	rootCmd.PersistentFlags().Duration("push-interval", 15*time.Second, "Interval between pushes of the metrics")
+	rootCmd.PersistentFlags().String("influx-url", "", "InfluxDB endpoint the collected stats are written to in the line protocol: udp://host:port, HTTP write URL or file path (disabled if empty)")
+	rootCmd.PersistentFlags().String("influx-token", "", "Token authorizing the writes to the InfluxDB 2 HTTP endpoint")
+	rootCmd.PersistentFlags().String("influx-host", "", "Host tag of the exported points (hostname if empty)")
+	rootCmd.PersistentFlags().Duration("influx-interval", 10*time.Second, "Interval between exports of the points")
+}
+
+// pushConfig returns the metrics push configuration set by the flags.
+func pushConfig(cmd *cobra.Command) (client.PushConfig, error) {
+	flags := cmd.Flags()
+	var cfg client.PushConfig
+	var err error
+	if cfg.URL, err = flags.GetString("push-url"); err != nil {
+		return cfg, err
+	}
+	if cfg.Job, err = flags.GetString("push-job"); err != nil {
+		return cfg, err
+	}
+	if cfg.Instance, err = flags.GetString("push-instance"); err != nil {
+		return cfg, err
+	}
+	if cfg.Interval, err = flags.GetDuration("push-interval"); err != nil {
+		return cfg, err
+	}
+	if cfg.URL != "" && cfg.Interval <= 0 {
+		return cfg, fmt.Errorf("invalid push interval: %v", cfg.Interval)
+	}
+	return cfg, nil
+}
+
+// influxConfig returns the InfluxDB export configuration set by the flags.
+func influxConfig(cmd *cobra.Command) (client.InfluxConfig, error) {
+	flags := cmd.Flags()
+	var cfg client.InfluxConfig
+	var err error
+	if cfg.URL, err = flags.GetString("influx-url"); err != nil {
+		return cfg, err
+	}
+	if cfg.Token, err = flags.GetString("influx-token"); err != nil {
+		return cfg, err
+	}
+	if cfg.Host, err = flags.GetString("influx-host"); err != nil {
+		return cfg, err
+	}
+	if cfg.Interval, err = flags.GetDuration("influx-interval"); err != nil {
+		return cfg, err
+	}
+	if cfg.URL != "" && cfg.Interval <= 0 {
+		return cfg, fmt.Errorf("invalid influx interval: %v", cfg.Interval)
+	}
+	return cfg, nil
+}
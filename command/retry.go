@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/stats"
+)
+
+func init() {
+	rootCmd.PersistentFlags().Int("retry-attempts", 0, "Number of attempts to connect to the VPP (unlimited if zero, 3 for the remote VPP)")
+	rootCmd.PersistentFlags().Duration("retry-interval", time.Second, "Interval between the attempts to connect to the VPP")
+	rootCmd.PersistentFlags().Duration("connect-timeout", 0, "Timeout of connecting to the VPP including all attempts (no timeout if zero)")
+	rootCmd.PersistentFlags().Duration("request-timeout", stats.DefaultRequestTimeout, "Timeout of a single VPP request and of polling a tab, slower polls are skipped (no timeout if zero)")
+}
+
+// retryConfig returns the VPP connection attempts configuration set by the flags.
+func retryConfig(cmd *cobra.Command) (stats.RetryConfig, error) {
+	flags := cmd.Flags()
+	var cfg stats.RetryConfig
+	var err error
+	if cfg.Attempts, err = flags.GetInt("retry-attempts"); err != nil {
+		return cfg, err
+	}
+	if cfg.Interval, err = flags.GetDuration("retry-interval"); err != nil {
+		return cfg, err
+	}
+	if cfg.Timeout, err = flags.GetDuration("connect-timeout"); err != nil {
+		return cfg, err
+	}
+	if cfg.Attempts < 0 {
+		return cfg, fmt.Errorf("invalid number of retry attempts: %d", cfg.Attempts)
+	}
+	if cfg.Interval <= 0 {
+		return cfg, fmt.Errorf("invalid retry interval: %v", cfg.Interval)
+	}
+	return cfg, nil
+}
+
+// requestTimeout returns the timeout of a single VPP request set by the flag.
+func requestTimeout(cmd *cobra.Command) (time.Duration, error) {
+	timeout, err := cmd.Flags().GetDuration("request-timeout")
+	if err != nil {
+		return 0, err
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid request timeout: %v", timeout)
+	}
+	return timeout, nil
+}
@@ -17,11 +17,13 @@
 package command
 
 import (
-	"fmt"
+	"strings"
+
 	"git.fd.io/govpp.git/adapter"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"log"
-	"os"
+	"go.pantheon.tech/vpptop/client"
+	"go.pantheon.tech/vpptop/gui"
 )
 
 var rootCmd = &cobra.Command{
@@ -34,37 +36,69 @@ Interface:      stats: RX/TX packets/bytes, packet errors/drops/punts/IPv4...
 Node stats:     clocks, vectors, calls, suspends...
 Error counters: node, reason...
 GetMemory usage:   free, used...
-Thread info:    name, type, PID...`,
+Thread info:    name, type, PID, CPU affinity, scheduling...
+Drops/Punts:    drops by node and reason, punts by reason, rates...
+Tunnels:        endpoints, VNI/TEID, rates...
+Sessions:       host-stack sessions by protocol and state, apps by namespace...
+Features:       feature arcs and enabled features per interface...
+Bonds:          bond members, LACP state, rx/tx distribution...
+Policers:       rates, conform/exceed/violate counters...
+FIB:            routes per VRF, FIB memory...
+Neighbors:      ARP/ND entries, MAC, age, static/dynamic...
+SRv6:           policies, segment lists, steering, local SID counters...
+MPLS:           FIB entries, tunnels, labels, next-hops, packet/byte rates...
+API Trace:      binary API messages, trace on/off/save...
+API Clients:    shm/socket clients, queue lengths, message rings, API segment...
+Capture:        pcap and dispatch trace start/stop, status, output file...
+Info:           version, uptime, PID, plugins...`,
+
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return selectHandler(cmd)
+	},
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		socket, err := cmd.Flags().GetString("socket")
+		socket, err := resolveSocket(cmd)
 		if err != nil {
 			return err
 		}
 
-		logFile, err := cmd.Flags().GetString("log")
+		logs, err := openLog(cmd, "vpptop.log")
 		if err != nil {
 			return err
 		}
 
-		logs, err := os.Create(logFile)
-		if err != nil {
-			return fmt.Errorf("error occured while creating file: %v", err)
-		}
-
 		defer logs.Close()
 
-		return startClient(socket, "", logs)
+		return startClient(cmd, socket, "", logs)
 	},
 }
 
 func init() {
-	rootCmd.Flags().StringP("socket", "s", adapter.DefaultStatsSocket, "vpp stats segment socket")
-	rootCmd.Flags().StringP("log", "l", "vpptop.log", "Log file")
+	rootCmd.PersistentFlags().String("handler", client.HandlerAuto, "VPP handler to use (local, agent or auto to probe them in order)")
+	rootCmd.PersistentFlags().Bool("demo", false, "Show synthetic counters of a demo VPP instead of connecting to the VPP")
+	rootCmd.PersistentFlags().Bool("stats-only", false, "Read the interface, node and error counters from the stats segment only, without the binary API or the CLI")
+	rootCmd.PersistentFlags().Bool("diagnostics", false, "Show the diagnostics tab with the resource footprint of vpptop and the durations of the polls")
+	rootCmd.PersistentFlags().Bool("hide-zero-nodes", false, "Hide nodes with zero calls and vectors since the last clear (toggled by Ctrl-E)")
+	rootCmd.PersistentFlags().Bool("highlight-spikes", false, "Highlight interface rates deviating from their moving average (toggled by s)")
+	rootCmd.PersistentFlags().Float64("spike-sigma", client.DefaultSpikeSigma, "Deviation of an interface rate from its moving average in standard deviations from which the rate is highlighted")
+	rootCmd.PersistentFlags().Bool("compact-interfaces", false, "Show a single row per interface in the interfaces tab (toggled by c)")
+	rootCmd.PersistentFlags().Float64("util-threshold", client.DefaultUtilThreshold, "Link utilization in percent from which interface rates are highlighted")
+	rootCmd.PersistentFlags().String("layout", client.DefaultLayoutFile(), "File persisting the column widths resized by the user (disabled if empty)")
+	rootCmd.PersistentFlags().String("watchlist", client.DefaultWatchlistFile(), "File persisting the interfaces and nodes pinned by the user (disabled if empty)")
+	rootCmd.PersistentFlags().Duration("talkers-window", client.DefaultTalkersWindow, "Window of the interface rates the top talkers are ranked by")
+	rootCmd.PersistentFlags().Duration("memory-trend-window", client.DefaultMemoryTrendWindow, "Window of the main heap usage the memory growth rate is estimated from")
+	rootCmd.PersistentFlags().Duration("notification-duration", gui.DefaultNotificationDuration, "Time the info notifications are shown for, warnings are shown 5 times and errors 10 times longer")
+	rootCmd.PersistentFlags().Duration("render-interval", gui.DefaultRenderInterval, "Minimum interval between the renders of the polled data, e.g. 1s to cut the CPU usage on small devices (each poll is rendered if zero)")
+	rootCmd.PersistentFlags().StringSlice("manual-refresh", nil, "Tabs polled only on demand by F5 instead of periodically, e.g. memory,features (toggled by a)")
+	rootCmd.PersistentFlags().Int("notification-history", gui.DefaultNotificationHistory, "Number of the notifications kept for the n key")
+	rootCmd.PersistentFlags().Int("events-limit", client.DefaultEventsLimit, "Number of the interface events (address, MTU and state changes) kept for the e key")
+	rootCmd.PersistentFlags().Duration("measure-window", client.DefaultMeasureWindow, "Duration of the timed measurement started by the m key")
+	rootCmd.PersistentFlags().String("theme", gui.DefaultTheme, "Color theme, either a preset ("+strings.Join(gui.ThemeNames(), ", ")+") or a JSON theme file (light if not set and VPPTOP_THEME_LIGHT is set)")
+	rootCmd.Flags().StringP("socket", "s", adapter.DefaultStatsSocket, "vpp stats segment socket (discovered if not set)")
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+		logrus.Fatal(err)
 	}
 }
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// systemd notification states
+const (
+	sdStateReady    = "READY=1"
+	sdStateStopping = "STOPPING=1"
+	sdStateWatchdog = "WATCHDOG=1"
+)
+
+// sdListenFdsStart is the first file descriptor passed by the socket activation.
+const sdListenFdsStart = 3
+
+// sdNotify sends the state to the systemd notification socket. It is
+// a no-op if the process was not started by systemd with Type=notify.
+func sdNotify(state string) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		logrus.Warnf("failed to connect to the systemd notification socket: %v", err)
+		return
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		logrus.Warnf("failed to notify systemd: %v", err)
+	}
+}
+
+// sdWatchdogInterval returns the interval of the systemd watchdog pings,
+// which is half of the watchdog timeout. Zero is returned if the watchdog
+// is not enabled for this process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
+
+// sdWatchdog pings the systemd watchdog until the context is cancelled,
+// as long as the check succeeds. A failing check stops the pings,
+// so systemd restarts the service once the watchdog times out.
+func sdWatchdog(ctx context.Context, check func() error) {
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := check(); err != nil {
+				logrus.Warnf("watchdog check failed: %v", err)
+				continue
+			}
+			sdNotify(sdStateWatchdog)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sdListener returns the first listener passed by the systemd socket
+// activation, or nil if the process was not socket activated.
+func sdListener() (net.Listener, error) {
+	defer os.Unsetenv("LISTEN_PID")
+	defer os.Unsetenv("LISTEN_FDS")
+	defer os.Unsetenv("LISTEN_FDNAMES")
+
+	if pid := os.Getenv("LISTEN_PID"); pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+	if fds > 1 {
+		logrus.Warnf("%d sockets passed by systemd, only the first one is used", fds)
+	}
+	file := os.NewFile(uintptr(sdListenFdsStart), "systemd-socket")
+	defer file.Close()
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid socket passed by systemd: %v", err)
+	}
+	return listener, nil
+}
+
+// relay forwards connections accepted by the listener to the address
+// until the listener is closed.
+func relay(listener net.Listener, addr string) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			target, err := net.Dial("tcp", addr)
+			if err != nil {
+				logrus.Warnf("failed to relay connection to %s: %v", addr, err)
+				return
+			}
+			defer target.Close()
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			copyConn := func(dst, src net.Conn) {
+				defer wg.Done()
+				io.Copy(dst, src)
+				if c, ok := dst.(interface{ CloseWrite() error }); ok {
+					c.CloseWrite()
+				}
+			}
+			go copyConn(target, conn)
+			go copyConn(conn, target)
+			wg.Wait()
+		}()
+	}
+}
@@ -17,15 +17,23 @@
 package command
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"git.fd.io/govpp.git/adapter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 	"go.pantheon.tech/vpptop/client"
 	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/stats"
+	"go.pantheon.tech/vpptop/stats/demo"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -34,17 +42,200 @@ import (
 
 // startClient is a blocking call that starts
 // the terminal frontend for displaying VPP metrics.
-func startClient(socket, rAddr string, logFile io.Writer) error {
-	var lightTheme bool
-	if _, lightTheme = os.LookupEnv("VPPTOP_THEME_LIGHT"); lightTheme {
-		gui.SetLightTheme()
+func startClient(cmd *cobra.Command, socket, rAddr string, logFile io.Writer) error {
+	if err := setLocale(cmd); err != nil {
+		return err
 	}
+	theme, err := loadTheme(cmd)
+	if err != nil {
+		return err
+	}
+	gui.SetTheme(theme)
 
-	log.SetOutput(logFile)
-	app, err := client.NewApp(lightTheme, logFile)
+	retry, err := retryConfig(cmd)
+	if err != nil {
+		return err
+	}
+	timeout, err := requestTimeout(cmd)
+	if err != nil {
+		return err
+	}
+	instances, err := statsInstances(cmd, socket)
+	if err != nil {
+		return err
+	}
+	counters, err := interfaceCounters(cmd)
+	if err != nil {
+		return err
+	}
+	compression, err := batchCompression(cmd)
+	if err != nil {
+		return err
+	}
+	app, err := client.NewApp(logFile, stats.WithRetry(retry), stats.WithRequestTimeout(timeout), instances, counters, compression)
 	if err != nil {
 		return fmt.Errorf("error occurred during client init: %v", err)
 	}
+	app.SetPollTimeout(timeout)
+	push, err := pushConfig(cmd)
+	if err != nil {
+		return err
+	}
+	app.SetPush(push)
+	influx, err := influxConfig(cmd)
+	if err != nil {
+		return err
+	}
+	app.SetInflux(influx)
+	httpCfg, err := httpConfig(cmd)
+	if err != nil {
+		return err
+	}
+	app.SetHTTP(httpCfg)
+	controlSocket, err := cmd.Flags().GetString("control-socket")
+	if err != nil {
+		return err
+	}
+	app.SetControlSocket(controlSocket)
+	alerts, err := alertConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if err = app.SetAlerts(alerts); err != nil {
+		return err
+	}
+	capture, err := captureConfig(cmd)
+	if err != nil {
+		return err
+	}
+	app.SetCapture(capture)
+	diagnostics, err := cmd.Flags().GetBool("diagnostics")
+	if err != nil {
+		return err
+	}
+	if diagnostics {
+		app.EnableDiagnostics()
+	}
+	hideZeroNodes, err := cmd.Flags().GetBool("hide-zero-nodes")
+	if err != nil {
+		return err
+	}
+	app.SetHideZeroNodes(hideZeroNodes)
+	utilThreshold, err := cmd.Flags().GetFloat64("util-threshold")
+	if err != nil {
+		return err
+	}
+	app.SetUtilThreshold(utilThreshold)
+	highlightSpikes, err := cmd.Flags().GetBool("highlight-spikes")
+	if err != nil {
+		return err
+	}
+	spikeSigma, err := cmd.Flags().GetFloat64("spike-sigma")
+	if err != nil {
+		return err
+	}
+	if spikeSigma <= 0 {
+		return fmt.Errorf("invalid spike sigma: %v", spikeSigma)
+	}
+	app.SetSpikeHighlight(highlightSpikes, spikeSigma)
+	compactInterfaces, err := cmd.Flags().GetBool("compact-interfaces")
+	if err != nil {
+		return err
+	}
+	app.SetCompactInterfaces(compactInterfaces)
+	talkersWindow, err := cmd.Flags().GetDuration("talkers-window")
+	if err != nil {
+		return err
+	}
+	if talkersWindow <= 0 {
+		return fmt.Errorf("invalid talkers window: %v", talkersWindow)
+	}
+	app.SetTalkersWindow(talkersWindow)
+	eventsLimit, err := cmd.Flags().GetInt("events-limit")
+	if err != nil {
+		return err
+	}
+	if eventsLimit <= 0 {
+		return fmt.Errorf("invalid events limit: %d", eventsLimit)
+	}
+	app.SetEventsLimit(eventsLimit)
+	notificationDuration, err := cmd.Flags().GetDuration("notification-duration")
+	if err != nil {
+		return err
+	}
+	if notificationDuration <= 0 {
+		return fmt.Errorf("invalid notification duration: %v", notificationDuration)
+	}
+	notificationHistory, err := cmd.Flags().GetInt("notification-history")
+	if err != nil {
+		return err
+	}
+	if notificationHistory <= 0 {
+		return fmt.Errorf("invalid notification history: %d", notificationHistory)
+	}
+	app.SetNotifications(notificationDuration, notificationHistory)
+	renderInterval, err := cmd.Flags().GetDuration("render-interval")
+	if err != nil {
+		return err
+	}
+	if renderInterval < 0 {
+		return fmt.Errorf("invalid render interval: %v", renderInterval)
+	}
+	app.SetRenderInterval(renderInterval)
+	manualRefresh, err := cmd.Flags().GetStringSlice("manual-refresh")
+	if err != nil {
+		return err
+	}
+	if err := app.SetManualRefresh(manualRefresh); err != nil {
+		return fmt.Errorf("invalid manual refresh: %v", err)
+	}
+	measureWindow, err := cmd.Flags().GetDuration("measure-window")
+	if err != nil {
+		return err
+	}
+	if measureWindow <= 0 {
+		return fmt.Errorf("invalid measure window: %v", measureWindow)
+	}
+	app.SetMeasureWindow(measureWindow)
+	memoryWindow, err := cmd.Flags().GetDuration("memory-trend-window")
+	if err != nil {
+		return err
+	}
+	if memoryWindow <= 0 {
+		return fmt.Errorf("invalid memory trend window: %v", memoryWindow)
+	}
+	memoryLeakAlert, err := cmd.Flags().GetDuration("memory-leak-alert")
+	if err != nil {
+		return err
+	}
+	app.SetMemoryTrendWindow(memoryWindow, memoryLeakAlert)
+	layoutFile, err := cmd.Flags().GetString("layout")
+	if err != nil {
+		return err
+	}
+	app.SetLayoutFile(layoutFile)
+	watchlistFile, err := cmd.Flags().GetString("watchlist")
+	if err != nil {
+		return err
+	}
+	app.SetWatchlistFile(watchlistFile)
+	demoMode, err := cmd.Flags().GetBool("demo")
+	if err != nil {
+		return err
+	}
+	if demoMode {
+		app.SetHandler(demo.NewHandler())
+	}
+	statsOnly, err := cmd.Flags().GetBool("stats-only")
+	if err != nil {
+		return err
+	}
+	if statsOnly {
+		if rAddr != "" {
+			return fmt.Errorf("the stats-only mode reads the stats segment of the local VPP, it cannot be used with the node command")
+		}
+		app.SetStatsOnly()
+	}
 	if err = app.Init(socket, rAddr); err != nil {
 		return fmt.Errorf("error occurred during client init: %v", err)
 	}
@@ -53,23 +244,132 @@ func startClient(socket, rAddr string, logFile io.Writer) error {
 	return nil
 }
 
+// loadTheme returns the color theme set by the flag. The light theme
+// is used if the flag was not set and VPPTOP_THEME_LIGHT is set.
+func loadTheme(cmd *cobra.Command) (gui.Theme, error) {
+	theme, err := cmd.Flags().GetString("theme")
+	if err != nil {
+		return gui.Theme{}, err
+	}
+	if _, light := os.LookupEnv("VPPTOP_THEME_LIGHT"); light && !cmd.Flags().Changed("theme") {
+		theme = "light"
+	}
+	return gui.LoadTheme(theme)
+}
+
+// selectHandler restricts the handler definitions to the one set by the flag.
+func selectHandler(cmd *cobra.Command) error {
+	handler, err := cmd.Flags().GetString("handler")
+	if err != nil {
+		return err
+	}
+	return client.SelectHandler(handler)
+}
+
+// statsSocketPatterns are paths probed during the stats socket
+// discovery, including per-instance run directories.
+var statsSocketPatterns = []string{
+	"/run/vpp/stats.sock",
+	"/var/run/vpp/stats.sock",
+	"/run/vpp/*/stats.sock",
+	"/var/run/vpp/*/stats.sock",
+}
+
+// resolveSocket returns the stats socket set by the flag. If the flag
+// was not set, the socket is discovered. When multiple sockets are found,
+// the user is asked to choose one.
+func resolveSocket(cmd *cobra.Command) (string, error) {
+	socket, err := cmd.Flags().GetString("socket")
+	if err != nil {
+		return "", err
+	}
+	if cmd.Flags().Changed("socket") {
+		return socket, nil
+	}
+
+	sockets := discoverStatsSockets()
+	switch len(sockets) {
+	case 0:
+		return socket, nil
+	case 1:
+		return sockets[0], nil
+	}
+	return chooseSocket(cmd.InOrStdin(), cmd.OutOrStdout(), sockets)
+}
+
+// discoverStatsSockets returns all stats sockets found at the probed paths.
+// Symlinks are resolved, so every socket is returned only once.
+func discoverStatsSockets() []string {
+	var sockets []string
+	found := make(map[string]bool)
+	for _, pattern := range statsSocketPatterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || info.Mode()&os.ModeSocket == 0 {
+				continue
+			}
+			resolved, err := filepath.EvalSymlinks(match)
+			if err != nil {
+				resolved = match
+			}
+			if found[resolved] {
+				continue
+			}
+			found[resolved] = true
+			sockets = append(sockets, match)
+		}
+	}
+	return sockets
+}
+
+// chooseSocket lets the user pick one of the sockets.
+func chooseSocket(in io.Reader, out io.Writer, sockets []string) (string, error) {
+	fmt.Fprintln(out, "Multiple VPP stats sockets found:")
+	for i, socket := range sockets {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, socket)
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprintf(out, "Choose a socket [1-%d]: ", len(sockets))
+		line, err := reader.ReadString('\n')
+		if choice, convErr := strconv.Atoi(strings.TrimSpace(line)); convErr == nil && choice >= 1 && choice <= len(sockets) {
+			return sockets[choice-1], nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("no stats socket chosen (use -s to set one, default %s)", adapter.DefaultStatsSocket)
+		}
+	}
+}
+
 // resolveNode resolves an ip address from a given nodeName/ip-addr.
 func resolveNode(kubeconfig string, name string) (string, bool) {
 	if ip := net.ParseIP(name); ip != nil {
 		return name, true
 	}
 
-	node, found := findNode(getNodes(kubeconfig), name)
+	nodes, err := getNodes(kubeconfig)
+	if err != nil {
+		logrus.Debugf("failed to list nodes: %v", err)
+	}
+	node, found := findNode(nodes, name)
 	if !found {
 		return "", false
 	}
+	return nodeAddress(node)
+}
 
+// nodeAddress returns the external or internal ip address of the node.
+func nodeAddress(node v1.Node) (string, bool) {
 	for _, addr := range node.Status.Addresses {
 		if addr.Type == v1.NodeExternalIP || addr.Type == v1.NodeInternalIP {
 			return addr.Address, true
 		}
 	}
-
 	return "", false
 }
 
@@ -86,24 +386,28 @@ func findNode(nodes []v1.Node, name string) (v1.Node, bool) {
 	return v1.Node{}, false
 }
 
-// getNodes returns all k8s nodes in the cluster.
-func getNodes(kubeconfig string) []v1.Node {
-	ctx := context.Background()
+// kubeClient returns the k8s client configured by the kubeconfig.
+func kubeClient(kubeconfig string) (*kubernetes.Clientset, error) {
 	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
-		return nil
+		return nil, err
 	}
+	return kubernetes.NewForConfig(config)
+}
 
-	clientset, err := kubernetes.NewForConfig(config)
+// getNodes returns all k8s nodes in the cluster.
+func getNodes(kubeconfig string) ([]v1.Node, error) {
+	ctx := context.Background()
+	clientset, err := kubeClient(kubeconfig)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	nodeList, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	return nodeList.Items
+	return nodeList.Items, nil
 }
 
 func homeDir() string {
@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"git.fd.io/govpp.git/adapter"
+	"github.com/spf13/cobra"
+	"go.pantheon.tech/vpptop/client"
+	"go.pantheon.tech/vpptop/stats"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/demo"
+)
+
+// tabs supported by the watch command
+const (
+	watchInterfaces = "interfaces"
+	watchNodes      = "nodes"
+	watchErrors     = "errors"
+	watchDrops      = "drops"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Prints VPP counters of the selected tab as a text stream",
+	Long: `watch periodically polls counters of the selected tab (interfaces, nodes,
+errors or drops) and prints them without the terminal user interface.
+With --changed-only only counters changed since the last interval are printed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket, err := resolveSocket(cmd)
+		if err != nil {
+			return err
+		}
+		tab, err := cmd.Flags().GetString("tab")
+		if err != nil {
+			return err
+		}
+		changedOnly, err := cmd.Flags().GetBool("changed-only")
+		if err != nil {
+			return err
+		}
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			return err
+		}
+		demoMode, err := cmd.Flags().GetBool("demo")
+		if err != nil {
+			return err
+		}
+		retry, err := retryConfig(cmd)
+		if err != nil {
+			return err
+		}
+		timeout, err := requestTimeout(cmd)
+		if err != nil {
+			return err
+		}
+		counters, err := interfaceCounters(cmd)
+		if err != nil {
+			return err
+		}
+		var handler api.HandlerAPI
+		if demoMode {
+			handler = demo.NewHandler()
+		}
+		switch tab {
+		case watchInterfaces, watchNodes, watchErrors, watchDrops:
+		default:
+			return fmt.Errorf("unsupported tab %q (use %s, %s, %s or %s)", tab,
+				watchInterfaces, watchNodes, watchErrors, watchDrops)
+		}
+
+		logs, err := openLog(cmd, "vpptop.log")
+		if err != nil {
+			return err
+		}
+
+		defer logs.Close()
+
+		return startWatch(socket, handler, retry, timeout, counters, tab, changedOnly, interval, logs, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringP("socket", "s", adapter.DefaultStatsSocket, "vpp stats segment socket (discovered if not set)")
+	watchCmd.Flags().StringP("tab", "t", watchErrors, "Tab to watch (interfaces, nodes, errors, drops)")
+	watchCmd.Flags().Bool("changed-only", false, "Print only counters changed since the last interval")
+	watchCmd.Flags().Duration("interval", 1*time.Second, "Polling interval")
+	rootCmd.AddCommand(watchCmd)
+}
+
+// startWatch is a blocking call printing counters of the tab
+// to the out writer until interrupted. If the handler is set, it is used
+// instead of connecting to the VPP.
+func startWatch(socket string, handler api.HandlerAPI, retry stats.RetryConfig, timeout time.Duration, counters stats.ProviderOption, tab string, changedOnly bool, interval time.Duration, logFile io.Writer, out io.Writer) error {
+	provider, err := connectProvider(socket, handler, retry, timeout, counters, logFile)
+	if err != nil {
+		return err
+	}
+	defer provider.Disconnect()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// the watchdog is pinged as long as the polling succeeds
+	lastPoll := time.Now().UnixNano()
+	sdNotify(sdStateReady)
+	go sdWatchdog(ctx, func() error {
+		if since := time.Since(time.Unix(0, atomic.LoadInt64(&lastPoll))); since > 2*interval {
+			return fmt.Errorf("no successful poll for %v", since.Round(time.Second))
+		}
+		return nil
+	})
+
+	var last map[string]uint64
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		counters, err := watchCounters(ctx, provider, tab)
+		if err != nil {
+			fmt.Fprintf(out, "%s %s error: %v\n", time.Now().Format("15:04:05"), tab, err)
+		} else {
+			atomic.StoreInt64(&lastPoll, time.Now().UnixNano())
+			printCounters(out, tab, counters, last, changedOnly)
+			last = counters
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// connectProvider connects a VPP provider to the stats socket, or to
+// the handler if set, for the commands running without the user interface.
+func connectProvider(socket string, handler api.HandlerAPI, retry stats.RetryConfig, timeout time.Duration, counters stats.ProviderOption, logFile io.Writer) (api.VppProviderAPI, error) {
+	if len(client.Defs) == 0 {
+		return nil, fmt.Errorf("no VPP handler definition was provided")
+	}
+	provider := stats.NewVppProvider(client.Defs, logFile, stats.WithRetry(retry), stats.WithRequestTimeout(timeout), counters)
+	connect := func() error { return provider.Connect(socket) }
+	if handler != nil {
+		connect = func() error { return provider.ConnectHandler(handler) }
+	}
+	if err := connect(); err != nil {
+		return nil, fmt.Errorf("error occurred during connect: %v", err)
+	}
+	return provider, nil
+}
+
+// watchCounters polls the tab and returns its counters by name.
+func watchCounters(ctx context.Context, provider api.VppProviderAPI, tab string) (map[string]uint64, error) {
+	counters := make(map[string]uint64)
+	switch tab {
+	case watchInterfaces:
+		ifaces, err := provider.GetInterfaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, iface := range ifaces {
+			counters[iface.InterfaceName+"/rx-packets"] = iface.Rx.Packets
+			counters[iface.InterfaceName+"/rx-bytes"] = iface.Rx.Bytes
+			counters[iface.InterfaceName+"/rx-errors"] = iface.RxErrors
+			counters[iface.InterfaceName+"/tx-packets"] = iface.Tx.Packets
+			counters[iface.InterfaceName+"/tx-bytes"] = iface.Tx.Bytes
+			counters[iface.InterfaceName+"/tx-errors"] = iface.TxErrors
+			counters[iface.InterfaceName+"/drops"] = iface.Drops
+			counters[iface.InterfaceName+"/punts"] = iface.Punts
+		}
+	case watchNodes:
+		nodes, err := provider.GetNodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			counters[node.Name+"/calls"] += node.Calls
+			counters[node.Name+"/vectors"] += node.Vectors
+			counters[node.Name+"/suspends"] += node.Suspends
+		}
+	case watchErrors:
+		errors, err := provider.GetErrors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, errorC := range errors {
+			counters[errorC.Node+"/"+errorC.Reason] = errorC.Count
+		}
+	case watchDrops:
+		dropsPunts, err := provider.GetDropsPunts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, dropPunt := range dropsPunts {
+			counters[dropPunt.Type+"/"+dropPunt.Node+"/"+dropPunt.Reason] = dropPunt.Count
+		}
+	}
+	return counters, nil
+}
+
+// printCounters prints counters in a compact format, one counter per line
+// sorted by name. If changedOnly is set, counters equal to the last value
+// are skipped.
+func printCounters(out io.Writer, tab string, counters, last map[string]uint64, changedOnly bool) {
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now().Format("15:04:05")
+	for _, name := range names {
+		value := counters[name]
+		lastValue, seen := last[name]
+		if changedOnly && (last == nil || seen && lastValue == value) {
+			continue
+		}
+		if seen {
+			fmt.Fprintf(out, "%s %s %s %d (%+d)\n", now, tab, name, value, int64(value-lastValue))
+		} else {
+			fmt.Fprintf(out, "%s %s %s %d\n", now, tab, name, value)
+		}
+	}
+}
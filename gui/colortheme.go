@@ -17,77 +17,238 @@
 package gui
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
 	tui "github.com/gizak/termui/v3"
 )
 
-// gui lightTheme settings.
-var (
-	lightTheme = tui.RootTheme{
-		Default: tui.NewStyle(tui.ColorBlack),
-
-		Block: tui.BlockTheme{
-			Title:  tui.NewStyle(tui.ColorBlack),
-			Border: tui.NewStyle(tui.ColorBlack),
-		},
-
-		BarChart: tui.BarChartTheme{
-			Bars:   tui.StandardColors,
-			Nums:   tui.StandardStyles,
-			Labels: tui.StandardStyles,
-		},
-
-		Paragraph: tui.ParagraphTheme{
-			Text: tui.NewStyle(tui.ColorBlack),
-		},
-
-		PieChart: tui.PieChartTheme{
-			Slices: tui.StandardColors,
-		},
-
-		List: tui.ListTheme{
-			Text: tui.NewStyle(tui.ColorBlack),
-		},
-
-		StackedBarChart: tui.StackedBarChartTheme{
-			Bars:   tui.StandardColors,
-			Nums:   tui.StandardStyles,
-			Labels: tui.StandardStyles,
-		},
-
-		Gauge: tui.GaugeTheme{
-			Bar:   tui.ColorBlack,
-			Label: tui.NewStyle(tui.ColorBlack),
-		},
-
-		Sparkline: tui.SparklineTheme{
-			Title: tui.NewStyle(tui.ColorBlack),
-			Line:  tui.ColorBlack,
-		},
-
-		Plot: tui.PlotTheme{
-			Lines: tui.StandardColors,
-			Axes:  tui.ColorBlack,
-		},
-
-		Table: tui.TableTheme{
-			Text: tui.NewStyle(tui.ColorBlack),
-		},
-
-		Tab: tui.TabTheme{
-			Active:   tui.NewStyle(tui.ColorRed),
-			Inactive: tui.NewStyle(tui.ColorBlack),
-		},
+// DefaultTheme is the name of the theme used if no theme is set.
+const DefaultTheme = "dark"
+
+// colorNames are the names of colors recognized in theme files.
+var colorNames = map[string]tui.Color{
+	"default": tui.ColorClear,
+	"black":   tui.ColorBlack,
+	"red":     tui.ColorRed,
+	"green":   tui.ColorGreen,
+	"yellow":  tui.ColorYellow,
+	"blue":    tui.ColorBlue,
+	"magenta": tui.ColorMagenta,
+	"cyan":    tui.ColorCyan,
+	"white":   tui.ColorWhite,
+}
+
+// Color is a terminal color, in theme files it is either a color name
+// (see colorNames) or a number of the 256 color palette.
+type Color tui.Color
+
+// UnmarshalJSON parses the color name or number.
+func (c *Color) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
 	}
+	text := strings.ToLower(strings.TrimSpace(fmt.Sprint(value)))
+	if color, ok := colorNames[text]; ok {
+		*c = Color(color)
+		return nil
+	}
+	number, err := strconv.Atoi(text)
+	if err != nil || number < 0 || number > 255 {
+		return fmt.Errorf("invalid color %s", data)
+	}
+	*c = Color(number)
+	return nil
+}
 
-	textStyle        = tui.ColorWhite
-	filterBackground = tui.ColorBlue
-)
+// MarshalJSON writes the color name, or number if the color has no name.
+func (c Color) MarshalJSON() ([]byte, error) {
+	for name, color := range colorNames {
+		if Color(color) == c {
+			return json.Marshal(name)
+		}
+	}
+	return json.Marshal(int(c))
+}
+
+// ColorPair is a foreground and background color of a widget.
+type ColorPair struct {
+	Fg Color `json:"fg"`
+	Bg Color `json:"bg"`
+}
+
+// Theme defines the colors of the gui widgets.
+type Theme struct {
+	// Text is the default text color.
+	Text Color `json:"text"`
+	// Border is the color of borders and titles of the widgets.
+	Border Color `json:"border"`
+	// ActiveTab and InactiveTab are the colors of the tab names.
+	ActiveTab   Color `json:"active_tab"`
+	InactiveTab Color `json:"inactive_tab"`
+	// DisabledTab is the color of the names of tabs whose data is not available.
+	DisabledTab Color `json:"disabled_tab"`
+	// SelectedRow is the selected row of the tables.
+	SelectedRow ColorPair `json:"selected_row"`
+	// Header is the header row of the tables.
+	Header ColorPair `json:"header"`
+	// Panel is the sort menu, the help and the notifications.
+	Panel ColorPair `json:"panel"`
+	// PanelSelected is the selected item of the sort menu.
+	PanelSelected Color `json:"panel_selected"`
+	// Filter is the filter input.
+	Filter ColorPair `json:"filter"`
+	// Exit is the closing screen.
+	Exit ColorPair `json:"exit"`
+	// Severity colors of the table cells (interface state, errors,
+	// utilization...), only the basic colors (0-7) are supported.
+	OK       Color `json:"ok"`
+	Warning  Color `json:"warning"`
+	Critical Color `json:"critical"`
+}
+
+// ThemePresets are the themes available by their names.
+var ThemePresets = map[string]Theme{
+	"dark": {
+		Text:          Color(tui.ColorWhite),
+		Border:        Color(tui.ColorWhite),
+		ActiveTab:     Color(tui.ColorRed),
+		InactiveTab:   Color(tui.ColorWhite),
+		DisabledTab:   Color(242),
+		SelectedRow:   ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorGreen)},
+		Header:        ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorRed)},
+		Panel:         ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorBlue)},
+		PanelSelected: Color(tui.ColorYellow),
+		Filter:        ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorBlue)},
+		Exit:          ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorBlack)},
+		OK:            Color(tui.ColorGreen),
+		Warning:       Color(tui.ColorYellow),
+		Critical:      Color(tui.ColorRed),
+	},
+	// darker colors which are better visible on lighter background
+	"light": {
+		Text:          Color(tui.ColorBlack),
+		Border:        Color(tui.ColorBlack),
+		ActiveTab:     Color(tui.ColorRed),
+		InactiveTab:   Color(tui.ColorBlack),
+		DisabledTab:   Color(248),
+		SelectedRow:   ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorGreen)},
+		Header:        ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorRed)},
+		Panel:         ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorBlue)},
+		PanelSelected: Color(tui.ColorYellow),
+		Filter:        ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorCyan)},
+		Exit:          ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorBlack)},
+		OK:            Color(tui.ColorGreen),
+		Warning:       Color(tui.ColorYellow),
+		Critical:      Color(tui.ColorRed),
+	},
+	// the terminal default colors, with black on white selections
+	"high-contrast": {
+		Text:          Color(tui.ColorClear),
+		Border:        Color(tui.ColorClear),
+		ActiveTab:     Color(tui.ColorYellow),
+		InactiveTab:   Color(tui.ColorClear),
+		DisabledTab:   Color(8),
+		SelectedRow:   ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorWhite)},
+		Header:        ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorYellow)},
+		Panel:         ColorPair{Fg: Color(tui.ColorWhite), Bg: Color(tui.ColorBlack)},
+		PanelSelected: Color(tui.ColorYellow),
+		Filter:        ColorPair{Fg: Color(tui.ColorBlack), Bg: Color(tui.ColorWhite)},
+		Exit:          ColorPair{Fg: Color(tui.ColorClear), Bg: Color(tui.ColorClear)},
+		OK:            Color(tui.ColorGreen),
+		Warning:       Color(tui.ColorYellow),
+		Critical:      Color(tui.ColorRed),
+	},
+	// solarized dark palette approximated by the 256 colors
+	"solarized": {
+		Text:          Color(244),
+		Border:        Color(240),
+		ActiveTab:     Color(136),
+		InactiveTab:   Color(244),
+		DisabledTab:   Color(239),
+		SelectedRow:   ColorPair{Fg: Color(234), Bg: Color(37)},
+		Header:        ColorPair{Fg: Color(230), Bg: Color(33)},
+		Panel:         ColorPair{Fg: Color(254), Bg: Color(235)},
+		PanelSelected: Color(136),
+		Filter:        ColorPair{Fg: Color(254), Bg: Color(24)},
+		Exit:          ColorPair{Fg: Color(254), Bg: Color(234)},
+		OK:            Color(tui.ColorGreen),
+		Warning:       Color(tui.ColorYellow),
+		Critical:      Color(tui.ColorRed),
+	},
+}
 
-// SetLightTheme changes the basic colors of the tui lib to
-// darker colors which are better visible on lighter background.
+// activeTheme is the theme of the widgets.
+var activeTheme = ThemePresets[DefaultTheme]
+
+// ThemeNames returns the names of the theme presets.
+func ThemeNames() []string {
+	return []string{"dark", "light", "high-contrast", "solarized"}
+}
+
+// LoadTheme returns the theme preset with the name, or the theme loaded
+// from the JSON file at the path. Colors missing in the file are taken
+// from the preset set by the 'base' key (the default theme if not set).
+func LoadTheme(name string) (Theme, error) {
+	if theme, ok := ThemePresets[name]; ok {
+		return theme, nil
+	}
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return Theme{}, fmt.Errorf("unknown theme %q (presets: %s): %v", name, strings.Join(ThemeNames(), ", "), err)
+	}
+	var base struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme file %s: %v", name, err)
+	}
+	if base.Base == "" {
+		base.Base = DefaultTheme
+	}
+	theme, ok := ThemePresets[base.Base]
+	if !ok {
+		return Theme{}, fmt.Errorf("invalid theme file %s: unknown base theme %q", name, base.Base)
+	}
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme file %s: %v", name, err)
+	}
+	for _, color := range []Color{theme.OK, theme.Warning, theme.Critical} {
+		if tui.Color(color) < tui.ColorBlack || tui.Color(color) > tui.ColorWhite {
+			return Theme{}, fmt.Errorf("invalid theme file %s: severity colors have to be basic colors", name)
+		}
+	}
+	return theme, nil
+}
+
+// SetTheme changes the colors of the widgets to the theme.
 // This should be called before any tui widget created.
-func SetLightTheme() {
-	textStyle = tui.ColorBlack
-	filterBackground = tui.ColorCyan
-	tui.Theme = lightTheme
+func SetTheme(theme Theme) {
+	activeTheme = theme
+
+	text := tui.NewStyle(tui.Color(theme.Text))
+	border := tui.NewStyle(tui.Color(theme.Border))
+	tui.Theme.Default = text
+	tui.Theme.Block = tui.BlockTheme{Title: border, Border: border}
+	tui.Theme.Paragraph.Text = text
+	tui.Theme.List.Text = text
+	tui.Theme.Table.Text = text
+	tui.Theme.Gauge.Bar = tui.Color(theme.Text)
+	tui.Theme.Gauge.Label = text
+	tui.Theme.Sparkline.Title = text
+	tui.Theme.Sparkline.Line = tui.Color(theme.Text)
+	tui.Theme.Plot.Axes = tui.Color(theme.Text)
+	tui.Theme.Tab = tui.TabTheme{
+		Active:   tui.NewStyle(tui.Color(theme.ActiveTab)),
+		Inactive: tui.NewStyle(tui.Color(theme.InactiveTab)),
+	}
+}
+
+// ActiveTheme returns the theme of the widgets.
+func ActiveTheme() Theme {
+	return activeTheme
 }
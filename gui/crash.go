@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	tui "github.com/gizak/termui/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// crashExitCode is the exit code of the process after a recovered panic.
+const crashExitCode = 2
+
+// terminal tracks whether the terminal is initialized, so that it is
+// restored after a panic only if it was switched to the raw mode.
+var terminal struct {
+	sync.Mutex
+	initialized bool
+	// crashed is set once a panic is being handled, other goroutines
+	// panicking meanwhile wait for the process to exit.
+	crashed bool
+}
+
+// initTerminal initializes the terminal, switching it to the raw mode.
+func initTerminal() error {
+	terminal.Lock()
+	defer terminal.Unlock()
+	if err := tui.Init(); err != nil {
+		return fmt.Errorf("error occured while initializing tui: %v", err)
+	}
+	terminal.initialized = true
+	return nil
+}
+
+// closeTerminal restores the terminal if it is initialized.
+func closeTerminal() {
+	terminal.Lock()
+	defer terminal.Unlock()
+	if terminal.initialized {
+		tui.Close()
+		terminal.initialized = false
+	}
+}
+
+// RecoverPanic restores the terminal, logs the panic together with the stacks
+// of all goroutines and exits the process. It has to be deferred directly
+// by every goroutine which may panic while the terminal is in the raw mode,
+// otherwise the terminal stays unusable and the panic is lost.
+func RecoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	terminal.Lock()
+	if terminal.crashed {
+		terminal.Unlock()
+		select {}
+	}
+	terminal.crashed = true
+	if terminal.initialized {
+		tui.Close()
+		terminal.initialized = false
+	}
+	terminal.Unlock()
+
+	stack := make([]byte, 1<<20)
+	stack = stack[:runtime.Stack(stack, true)]
+	// the stacks are written as they are, the log formatter would escape the new lines
+	logrus.Errorf("panic: %v", r)
+	logrus.StandardLogger().Out.Write(append(stack, '\n'))
+	fmt.Fprintf(os.Stderr, "vpptop crashed: %v (the stacks of all goroutines are in the log file)\n", r)
+	os.Exit(crashExitCode)
+}
@@ -16,6 +16,109 @@
 
 package gui
 
+import (
+	"sync"
+)
+
+// EventType identifies the events published by the gui.
+type EventType int
+
+// Events published by the gui. Unless stated otherwise, the Event payload
+// is the tab at which the event occurred.
+const (
+	// ExitEvent is published on gui exit.
+	ExitEvent EventType = iota
+	// SortEvent is published on sort, the payload is of type SortMetadata.
+	SortEvent
+	// ClearEvent is published when the counters of the tab are cleared.
+	ClearEvent
+	// RefreshEvent is published when the data of the tab are re-dumped.
+	RefreshEvent
+	// TabSwitchEvent is published on each tab switch, the payload is the new tab.
+	TabSwitchEvent
+	// UnitsEvent is published on units toggle, the payload is true
+	// if human readable units are used.
+	UnitsEvent
+	// TraceEvent is published when the API trace or a packet capture is toggled.
+	TraceEvent
+	// SaveEvent is published when the tab is saved.
+	SaveEvent
+	// FilterEvent is published on filter change, the payload is of type FilterMetadata.
+	FilterEvent
+	// GroupEvent is published when grouping of table entries is toggled.
+	GroupEvent
+	// HideZeroEvent is published when hiding of zero table entries is toggled.
+	HideZeroEvent
+	// BaselineEvent is published when the baseline of counters is marked or reset.
+	BaselineEvent
+	// LayoutEvent is published when a column is resized, the payload is of type LayoutMetadata.
+	LayoutEvent
+	// SelectEvent is published when the selected table entry is chosen.
+	SelectEvent
+	// SplitEvent is published when the split view is toggled or the tab of the
+	// unfocused pane changes, the payload is of type SplitMetadata.
+	SplitEvent
+	// PauseEvent is published when the updates of the tabs are paused
+	// or resumed, the payload is true if paused.
+	PauseEvent
+	// ExportEvent is published when the data of the tab are exported.
+	ExportEvent
+	// HelpEvent is published when the help is opened or closed,
+	// the payload is true if opened.
+	HelpEvent
+	// DetailEvent is published when the details of the selected table entry
+	// are requested, the subscriber shows them by TermWindow.ShowPopup.
+	DetailEvent
+	// MeasureEvent is published when a timed measurement is started, the
+	// subscriber freezes the tabs by TermWindow.SetPaused once it ends.
+	MeasureEvent
+	// EventLogEvent is published when the events of the tab are requested,
+	// the subscriber shows them by TermWindow.ShowPopup.
+	EventLogEvent
+	// QuickFilterEvent is published when the quick filter of the tab is
+	// cycled, the subscriber sets the next filter by TermWindow.SetFilter.
+	QuickFilterEvent
+	// PinEvent is published when the selected table entry is pinned or unpinned.
+	PinEvent
+	// SpikeEvent is published when the highlighting of rate spikes is toggled.
+	SpikeEvent
+	// CompactEvent is published when the compact layout of the interfaces is toggled.
+	CompactEvent
+	// AutoRefreshEvent is published when the auto-refresh of the tab is toggled,
+	// the payload is of type AutoRefreshMetadata.
+	AutoRefreshEvent
+)
+
+// eventBus dispatches the published events to all subscribers of the event type.
+type eventBus struct {
+	sync.RWMutex
+	subscribers map[EventType][]func(Event)
+}
+
+// newEventBus returns an instance of <*eventBus> without subscribers.
+func newEventBus() *eventBus {
+	return &eventBus{
+		subscribers: make(map[EventType][]func(Event)),
+	}
+}
+
+// subscribe adds the function to the subscribers of the event type.
+func (b *eventBus) subscribe(t EventType, f func(Event)) {
+	b.Lock()
+	defer b.Unlock()
+	b.subscribers[t] = append(b.subscribers[t], f)
+}
+
+// publish calls the subscribers of the event type in the order they subscribed.
+func (b *eventBus) publish(t EventType, event Event) {
+	b.RLock()
+	subscribers := b.subscribers[t]
+	b.RUnlock()
+	for _, f := range subscribers {
+		f(event)
+	}
+}
+
 type (
 	// Event is used on passing data to callback functions.
 	// Usually the payload is the key at which the event occurred.
@@ -26,9 +129,39 @@ type (
 	}
 
 	// SortMetadata is the payload for event used on sort.
-	// Carries extra information for the sort event.
+	// Carries extra information for the sort event, the rows
+	// are not sorted if the row is negative.
 	SortMetadata struct {
 		CurrTab int
 		CurrRow int
+		Asc     bool
+	}
+
+	// FilterMetadata is the payload for event used on filter change.
+	// Carries the filter of the tab.
+	FilterMetadata struct {
+		CurrTab int
+		Filter  string
+	}
+
+	// LayoutMetadata is the payload for event used on column resize.
+	// Carries the column widths of the tab.
+	LayoutMetadata struct {
+		CurrTab int
+		Widths  []int
+	}
+
+	// SplitMetadata is the payload for event used on split view change.
+	// Carries the tab shown by the pane which is not focused.
+	SplitMetadata struct {
+		Enabled  bool
+		OtherTab int
+	}
+
+	// AutoRefreshMetadata is the payload for event used on auto-refresh toggle.
+	// Tabs without the auto-refresh are refreshed on demand only.
+	AutoRefreshMetadata struct {
+		Tab     int
+		Enabled bool
 	}
 )
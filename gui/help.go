@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gizak/termui/v3/widgets"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// modeNames are the names of the gui states shown in the help title.
+var modeNames = map[viewType]string{
+	def:     "default",
+	sort:    "sort",
+	filter:  "filter",
+	palette: "palette",
+}
+
+// helpState stores the gui state the help view was opened from.
+type helpState struct {
+	view        viewType
+	keybindings []*Binding
+}
+
+// handleHelp changes the main view to the help view, listing
+// the keybindings of the current state.
+func (w *TermWindow) handleHelp(_ Event) {
+	w.help = helpState{
+		view:        w.view,
+		keybindings: w.keybindings,
+	}
+	w.helpPanel.Title = i18n.T("Help: %s (%s)", w.tabPane.TabNames[w.currentTab()], i18n.T(modeNames[w.view]))
+	bindings := append(append([]*Binding{}, w.keybindings...), w.helpKeybindings()...)
+	w.helpPanel.Rows = w.helpRows(bindings)
+	w.helpPanel.SelectedRow = 0
+	placePanel(w.helpPanel, w.width, w.height)
+	w.view = help
+	w.keybindings = w.helpKeybindings()
+	w.bus.publish(HelpEvent, Event{
+		Payload: true,
+	})
+}
+
+// handleHelpClose restores the gui state the help view was opened from.
+func (w *TermWindow) handleHelpClose(_ Event) {
+	w.view = w.help.view
+	w.keybindings = w.help.keybindings
+	w.help = helpState{}
+	w.bus.publish(HelpEvent, Event{
+		Payload: false,
+	})
+}
+
+// handleHelpScroll is called when the help view is scrolled.
+func (w *TermWindow) handleHelpScroll(event Event) {
+	switch event.Payload.(string) {
+	case KeyScrollDown:
+		w.helpPanel.ScrollDown()
+	case KeyScrollUp:
+		w.helpPanel.ScrollUp()
+	}
+}
+
+// helpRows returns a row for each distinct help text of the bindings
+// available for the current tab, keys sharing the text are joined.
+func (w *TermWindow) helpRows(bindings []*Binding) []string {
+	var texts []string
+	keys := make(map[string][]string)
+	for _, binding := range bindings {
+		if binding.help == "" {
+			continue
+		}
+		if binding.available != nil && !binding.available(w.currentTab()) {
+			continue
+		}
+		text := i18n.T(binding.help)
+		if _, ok := keys[text]; !ok {
+			texts = append(texts, text)
+		}
+		keys[text] = append(keys[text], keyName(binding.key))
+	}
+
+	width := 0
+	for _, text := range texts {
+		if l := len(strings.Join(keys[text], "/")); l > width {
+			width = l
+		}
+	}
+	rows := make([]string, 0, len(texts))
+	for _, text := range texts {
+		rows = append(rows, fmt.Sprintf("%-*s  %s", width, strings.Join(keys[text], "/"), text))
+	}
+	return rows
+}
+
+// keyName returns a human readable name of the key,
+// e.g. Ctrl-Space for <C-<Space>>.
+func keyName(key string) string {
+	if key == Any {
+		return i18n.T("any key")
+	}
+	if len(key) < 2 || key[0] != '<' || key[len(key)-1] != '>' {
+		return key
+	}
+	key = key[1 : len(key)-1]
+	if strings.HasPrefix(key, "C-") {
+		name := keyName(key[2:])
+		if len(name) == 1 {
+			name = strings.ToUpper(name)
+		}
+		return "Ctrl-" + name
+	}
+	return key
+}
+
+// placePanel places the panel (help or popup) to the middle of the terminal,
+// sized to fit its rows.
+func placePanel(panel *widgets.List, termWidth, termHeight int) {
+	width := HelpPanelMinWidth
+	for _, row := range panel.Rows {
+		if len(row)+4 > width {
+			width = len(row) + 4
+		}
+	}
+	if len(panel.Title)+4 > width {
+		width = len(panel.Title) + 4
+	}
+	if width > termWidth {
+		width = termWidth
+	}
+	bottom := HelpPanelTopY + len(panel.Rows) + 2
+	if bottom > termHeight {
+		bottom = termHeight
+	}
+	left := (termWidth - width) / 2
+	panel.SetRect(left, HelpPanelTopY, left+width, bottom)
+}
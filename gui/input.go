@@ -24,6 +24,21 @@ const (
 	KeyScrollUp   = "<Up>"
 	KeyQuit       = "q"
 	KeyFilter     = "/"
+	KeyWiden      = "+"
+	KeyNarrow     = "-"
+	KeyHelp       = "h"
+	KeyPause      = "p"
+	KeyDetails    = "d"
+	KeyMeasure    = "m"
+	KeyEventLog   = "e"
+	KeyVrfFilter  = "v"
+	KeyPin        = "P"
+	KeyHistory    = "n"
+	KeySpikes     = "s"
+	KeyCompact    = "c"
+	KeyAuto       = "a"
+	KeySortPrev   = "<"
+	KeySortNext   = ">"
 	KeyCancel     = "<Escape>"
 	KeyDeleteChar = "<Backspace>"
 	KeyF1         = "<F1>"
@@ -82,48 +97,138 @@ const (
 type Binding struct {
 	key      string
 	callback func(Event)
+	// help describes the binding in the help view, bindings
+	// without help are not listed (e.g. the help itself).
+	help string
+	// available (optional) returns true if the binding
+	// is supported by the tab.
+	available func(tab int) bool
 }
 
 // DefaultKeybindings are keybindings for the default view.
 func (w *TermWindow) defaultKeybindings() []*Binding {
 	return []*Binding{
-		{key: KeyQuit, callback: w.handleExit},
-		{key: KeyCtrlSpace, callback: w.handleSortMenu},
-		{key: KeyScrollDown, callback: w.handleScroll},
-		{key: KeyScrollUp, callback: w.handleScroll},
-		{key: KeyPgup, callback: w.handleScroll},
-		{key: KeyPgdn, callback: w.handleScroll},
-		{key: KeyTabLeft, callback: w.handleTabSwitch},
-		{key: KeyTabRight, callback: w.handleTabSwitch},
-		{key: KeyFilter, callback: w.handleFilterMenu},
-		{key: KeyCtrlC, callback: w.handleClear},
+		{key: KeyQuit, callback: w.handleExit, help: "quit"},
+		{key: KeyCtrlSpace, callback: w.handleSortMenu, help: "open the menu to sort by a column"},
+		{key: "1", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "2", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "3", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "4", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "5", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "6", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "7", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "8", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: "9", callback: w.handleHeaderSort, help: "sort by the n-th column of the header (reverse the order if sorted by it already)"},
+		{key: KeySortPrev, callback: w.handleHeaderSortMove, help: "sort by the previous/next column of the header"},
+		{key: KeySortNext, callback: w.handleHeaderSortMove, help: "sort by the previous/next column of the header"},
+		{key: KeyScrollDown, callback: w.handleScroll, help: "scroll the table"},
+		{key: KeyScrollUp, callback: w.handleScroll, help: "scroll the table"},
+		{key: KeyPgup, callback: w.handleScroll, help: "skip pages of the table"},
+		{key: KeyPgdn, callback: w.handleScroll, help: "skip pages of the table"},
+		{key: KeyTabLeft, callback: w.handleTabSwitch, help: "switch tabs"},
+		{key: KeyTabRight, callback: w.handleTabSwitch, help: "switch tabs"},
+		{key: KeyFilter, callback: w.handleFilterMenu, help: "filter the table"},
+		{key: KeyCtrlC, callback: w.handleClear, help: "clear counters", available: w.isClearTab},
+		{key: KeyCtrlR, callback: w.handleRefresh, help: "refresh (re-dump) the data"},
+		{key: KeyF5, callback: w.handleRefresh, help: "refresh the tab (with the auto-refresh off)"},
+		{key: KeyAuto, callback: w.handleAutoRefreshToggle, help: "toggle the auto-refresh of the tab (refreshed by F5 only if off)"},
+		{key: KeyCtrlU, callback: w.handleUnitsToggle, help: "toggle human readable units"},
+		{key: KeyCtrlT, callback: w.handleTraceToggle, help: "toggle the VPP binary API trace (the selected packet capture at the capture tab)", available: w.isTraceTab},
+		{key: KeyCtrlO, callback: w.handleSave, help: "save the table", available: w.isSaveTab},
+		{key: KeyCtrlG, callback: w.handleGroupToggle, help: "toggle grouping (sub-interfaces, errors by node/thread)", available: w.isGroupTab},
+		{key: KeyCtrlE, callback: w.handleHideZeroToggle, help: "hide/show nodes with zero calls and vectors"},
+		{key: KeyCtrlB, callback: w.handleBaselineToggle, help: "mark/reset the baseline the node counters are compared to"},
+		{key: KeyEnter, callback: w.handleSelect, help: "expand/collapse the selected group when grouping is enabled", available: w.isGroupTab},
+		{key: KeyTab, callback: w.handleColumnSelect, help: "select a column to be resized"},
+		{key: KeyWiden, callback: w.handleColumnResize, help: "widen/narrow the selected column"},
+		{key: KeyNarrow, callback: w.handleColumnResize, help: "widen/narrow the selected column"},
+		{key: KeyCtrlV, callback: w.handleSplitToggle, help: "split the screen to show two tabs side by side"},
+		{key: KeyCtrlW, callback: w.handleSplitFocus, help: "move the focus to the other pane of the split screen"},
+		{key: KeyPause, callback: w.handlePauseToggle, help: "pause/resume the updates of the tabs"},
+		{key: KeyMeasure, callback: w.handleMeasure, help: "clear the counters and measure them for a time window"},
+		{key: KeyCtrlX, callback: w.handleExport, help: "export the data of the table as JSON"},
+		{key: KeyDetails, callback: w.handleDetails, help: "show the error details of the selected entry", available: w.isDetailTab},
+		{key: KeyEventLog, callback: w.handleEventLog, help: "show/hide the events of the tab", available: w.isEventTab},
+		{key: KeyVrfFilter, callback: w.handleQuickFilter, help: "filter the interfaces of the next VRF", available: w.isQuickFilterTab},
+		{key: KeyPin, callback: w.handlePin, help: "pin/unpin the selected entry to the top of the table", available: w.isPinTab},
+		{key: KeyHistory, callback: w.handleNotificationHistory, help: "show the recent notifications"},
+		{key: KeySpikes, callback: w.handleSpikeToggle, help: "toggle highlighting of the interface rate spikes"},
+		{key: KeyCompact, callback: w.handleCompactToggle, help: "toggle the compact layout of the interfaces (one row per interface)"},
+		{key: KeyCtrlP, callback: w.handlePalette, help: "open the command palette"},
+		{key: KeyHelp, callback: w.handleHelp},
+		{key: KeyF1, callback: w.handleHelp},
 	}
 }
 
 // FilterKeybindings are keybindings for the filter view.
+// The last binding is called for keys without a binding.
 func (w *TermWindow) filterKeybindings() []*Binding {
 	return []*Binding{
-		{key: KeyCancel, callback: w.handleFilter},
-		{key: KeyScrollUp, callback: w.handleDefaultMenu},
-		{key: KeyScrollDown, callback: w.handleDefaultMenu},
-		{key: KeyTabLeft, callback: w.handleDefaultMenu},
-		{key: KeyTabRight, callback: w.handleDefaultMenu},
-		{key: KeyEnter, callback: w.handleFilter},
-		{key: KeyTab, callback: w.handleDefaultMenu},
-		{key: KeyDeleteChar, callback: w.handleReduceFilter},
-		{key: Any, callback: w.handleAppendToFilter},
+		{key: KeyCancel, callback: w.handleFilter, help: "keep the filter and close the filter bar"},
+		{key: KeyScrollUp, callback: w.handleDefaultMenu, help: "cancel the filter"},
+		{key: KeyScrollDown, callback: w.handleDefaultMenu, help: "cancel the filter"},
+		{key: KeyTabLeft, callback: w.handleDefaultMenu, help: "cancel the filter"},
+		{key: KeyTabRight, callback: w.handleDefaultMenu, help: "cancel the filter"},
+		{key: KeyEnter, callback: w.handleFilter, help: "keep the filter and close the filter bar"},
+		{key: KeyTab, callback: w.handleDefaultMenu, help: "cancel the filter"},
+		{key: KeyDeleteChar, callback: w.handleReduceFilter, help: "delete the last character"},
+		{key: KeyF1, callback: w.handleHelp},
+		{key: Any, callback: w.handleAppendToFilter, help: "append to the filter"},
 	}
 }
 
 // SortKeybindings are keybindings for the sort view.
 func (w *TermWindow) sortKeybindings() []*Binding {
 	return []*Binding{
-		{key: KeyCancel, callback: w.handleDefaultMenu},
-		{key: KeyCtrlSpace, callback: w.handleDefaultMenu},
-		{key: KeyEnter, callback: w.handleSort},
-		{key: KeyScrollDown, callback: w.handleSortPanelScroll},
-		{key: KeyScrollUp, callback: w.handleSortPanelScroll},
-		{key: KeyPgup, callback: w.handleSortPanelScroll},
-		{key: KeyPgdn, callback: w.handleSortPanelScroll},
+		{key: KeyCancel, callback: w.handleDefaultMenu, help: "close the menu and restore the applied sort"},
+		{key: KeyCtrlSpace, callback: w.handleDefaultMenu, help: "close the menu and restore the applied sort"},
+		{key: KeyEnter, callback: w.handleSort, help: "apply the sort by the selected column (reverse the order if sorted by it already)"},
+		{key: KeyScrollDown, callback: w.handleSortPanelScroll, help: "select a column and preview the sort"},
+		{key: KeyScrollUp, callback: w.handleSortPanelScroll, help: "select a column and preview the sort"},
+		{key: KeyPgup, callback: w.handleSortPanelScroll, help: "select the last/first column and preview the sort"},
+		{key: KeyPgdn, callback: w.handleSortPanelScroll, help: "select the last/first column and preview the sort"},
+		{key: KeyHelp, callback: w.handleHelp},
+		{key: KeyF1, callback: w.handleHelp},
+	}
+}
+
+// PopupKeybindings are keybindings for the popup view.
+func (w *TermWindow) popupKeybindings() []*Binding {
+	return []*Binding{
+		{key: KeyCancel, callback: w.handlePopupClose, help: "close the popup"},
+		{key: KeyDetails, callback: w.handlePopupClose, help: "close the popup"},
+		{key: KeyEventLog, callback: w.handlePopupClose, help: "close the popup"},
+		{key: KeyHistory, callback: w.handlePopupClose, help: "close the popup"},
+		{key: KeyQuit, callback: w.handlePopupClose, help: "close the popup"},
+		{key: KeyEnter, callback: w.handlePopupClose, help: "close the popup"},
+		{key: KeyScrollDown, callback: w.handlePopupScroll, help: "scroll the popup"},
+		{key: KeyScrollUp, callback: w.handlePopupScroll, help: "scroll the popup"},
+	}
+}
+
+// PaletteKeybindings are keybindings for the command palette.
+// The last binding is called for keys without a binding.
+func (w *TermWindow) paletteKeybindings() []*Binding {
+	return []*Binding{
+		{key: KeyCancel, callback: w.handlePaletteClose, help: "close the palette"},
+		{key: KeyCtrlP, callback: w.handlePaletteClose, help: "close the palette"},
+		{key: KeyEnter, callback: w.handlePaletteRun, help: "run the selected action"},
+		{key: KeyScrollDown, callback: w.handlePaletteScroll, help: "select an action"},
+		{key: KeyScrollUp, callback: w.handlePaletteScroll, help: "select an action"},
+		{key: KeyDeleteChar, callback: w.handlePaletteDelete, help: "delete the last character"},
+		{key: KeyF1, callback: w.handleHelp},
+		{key: Any, callback: w.handlePaletteAppend, help: "type to search the actions"},
+	}
+}
+
+// HelpKeybindings are keybindings for the help view.
+func (w *TermWindow) helpKeybindings() []*Binding {
+	return []*Binding{
+		{key: KeyCancel, callback: w.handleHelpClose, help: "close the help"},
+		{key: KeyHelp, callback: w.handleHelpClose, help: "close the help"},
+		{key: KeyF1, callback: w.handleHelpClose, help: "close the help"},
+		{key: KeyQuit, callback: w.handleHelpClose, help: "close the help"},
+		{key: KeyScrollDown, callback: w.handleHelpScroll, help: "scroll the help"},
+		{key: KeyScrollUp, callback: w.handleHelpScroll, help: "scroll the help"},
 	}
 }
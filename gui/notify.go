@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	tui "github.com/gizak/termui/v3"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+const (
+	// DefaultNotificationDuration is the time info notifications are shown for,
+	// warnings and errors are shown longer (see severityFactor).
+	DefaultNotificationDuration = time.Second
+	// DefaultNotificationHistory is the number of notifications kept in the history.
+	DefaultNotificationHistory = 100
+)
+
+// Severity of a notification.
+type Severity int
+
+// Severities of the notifications.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// severityFactor multiplies the duration of the notifications per severity.
+var severityFactor = [...]time.Duration{
+	SeverityInfo:    1,
+	SeverityWarning: 5,
+	SeverityError:   10,
+}
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "info"
+}
+
+// notification is a single notification shown in the notification area.
+type notification struct {
+	at       time.Time
+	severity Severity
+	text     string
+}
+
+// notifications keeps the notification shown in the notification area and
+// the history of the recent notifications. Notifications are pushed by the
+// gui and by the subscribers of the gui events, even from other goroutines.
+type notifications struct {
+	sync.Mutex
+	// current is the shown notification, empty once timed out.
+	current notification
+	// history of the notifications, the oldest first.
+	history []notification
+	// limit of the notifications kept in the history.
+	limit int
+	// duration the info notifications are shown for.
+	duration time.Duration
+	// timer fires once the shown notification times out.
+	timer *time.Timer
+}
+
+// newNotifications returns notifications with the default duration and history.
+func newNotifications() *notifications {
+	return &notifications{
+		limit:    DefaultNotificationHistory,
+		duration: DefaultNotificationDuration,
+		timer:    time.NewTimer(DefaultNotificationDuration),
+	}
+}
+
+// push shows the notification and appends it to the history.
+func (n *notifications) push(severity Severity, text string) {
+	n.Lock()
+	defer n.Unlock()
+	n.current = notification{at: time.Now(), severity: severity, text: text}
+	n.timer.Reset(n.timeout(severity))
+	n.history = append(n.history, n.current)
+	n.trim()
+}
+
+// timeout returns the time the notification of the severity is shown for.
+func (n *notifications) timeout(severity Severity) time.Duration {
+	return n.duration * severityFactor[severity]
+}
+
+// trim removes the oldest notifications over the limit from the history.
+func (n *notifications) trim() {
+	if len(n.history) > n.limit {
+		n.history = append([]notification(nil), n.history[len(n.history)-n.limit:]...)
+	}
+}
+
+// expire hides the shown notification if it timed out, otherwise the timer
+// is reset to the rest of its time (the timer of a replaced notification
+// may fire before the shown one times out).
+func (n *notifications) expire() {
+	n.Lock()
+	defer n.Unlock()
+	if left := n.timeout(n.current.severity) - time.Since(n.current.at); left > 0 {
+		n.timer.Reset(left)
+		return
+	}
+	n.current = notification{}
+}
+
+// shown returns the shown notification.
+func (n *notifications) shown() notification {
+	n.Lock()
+	defer n.Unlock()
+	return n.current
+}
+
+// rows returns the notifications of the history, the latest first.
+func (n *notifications) rows() []string {
+	n.Lock()
+	defer n.Unlock()
+	if len(n.history) == 0 {
+		return []string{i18n.T("no notifications")}
+	}
+	rows := make([]string, 0, len(n.history))
+	for i := len(n.history) - 1; i >= 0; i-- {
+		note := n.history[i]
+		rows = append(rows, fmt.Sprintf("%s %-7s %s", note.at.Format("15:04:05"), i18n.T(note.severity.String()), note.text))
+	}
+	return rows
+}
+
+// SetNotifications sets the time the info notifications are shown for (warnings
+// are shown 5 times and errors 10 times longer) and the number of notifications
+// kept in the history.
+func (w *TermWindow) SetNotifications(duration time.Duration, history int) {
+	w.notes.Lock()
+	w.notes.duration = duration
+	w.notes.limit = history
+	w.notes.trim()
+	w.notes.Unlock()
+}
+
+// Notify shows the text in the notification area until the notification
+// times out, the notification is kept in the history as well.
+func (w *TermWindow) Notify(severity Severity, text string) {
+	w.notes.push(severity, text)
+}
+
+// pushNotification shows the info notification.
+func (w *TermWindow) pushNotification(text string) {
+	w.notes.push(SeverityInfo, text)
+}
+
+// updateNotification sets the text and the color
+// of the shown notification to the notification area.
+func (w *TermWindow) updateNotification() {
+	note := w.notes.shown()
+	fg := activeTheme.Panel.Fg
+	switch note.severity {
+	case SeverityWarning:
+		fg = activeTheme.Warning
+	case SeverityError:
+		fg = activeTheme.Critical
+	}
+	w.notification.Text = note.text
+	w.notification.TextStyle = tui.NewStyle(tui.Color(fg), tui.Color(activeTheme.Panel.Bg), tui.ModifierBold)
+}
+
+// handleNotificationHistory is called when the history of the notifications is requested.
+func (w *TermWindow) handleNotificationHistory(_ Event) {
+	w.ShowPopup(i18n.T("Notifications (Esc to close)"), w.notes.rows())
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"strings"
+	"unicode"
+
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// paletteAction is an action listed by the command palette.
+type paletteAction struct {
+	name string
+	run  func()
+}
+
+// paletteState stores the query and the actions of the command palette,
+// and the keybindings of the default view it was opened from.
+type paletteState struct {
+	query       string
+	actions     []paletteAction
+	matches     []paletteAction
+	keybindings []*Binding
+}
+
+// handlePalette opens the command palette listing the actions
+// available for the current tab.
+func (w *TermWindow) handlePalette(_ Event) {
+	w.palette = paletteState{
+		actions:     w.paletteActions(w.keybindings),
+		keybindings: w.keybindings,
+	}
+	w.view = palette
+	w.keybindings = w.paletteKeybindings()
+	w.placePalette()
+	w.updatePalette()
+}
+
+// paletteActions returns the actions of the bindings available for the
+// current tab, the switch to each of the other tabs and the sort by each
+// column of the current tab. Bindings sharing the help text (e.g. scrolling
+// up and down) depend on the key and are not listed.
+func (w *TermWindow) paletteActions(bindings []*Binding) []paletteAction {
+	shared := make(map[string]int)
+	for _, binding := range bindings {
+		shared[binding.help]++
+	}
+	var actions []paletteAction
+	for _, binding := range bindings {
+		if binding.help == "" || shared[binding.help] > 1 || binding.key == KeyCtrlP {
+			continue
+		}
+		if binding.available != nil && !binding.available(w.currentTab()) {
+			continue
+		}
+		binding := binding
+		actions = append(actions, paletteAction{
+			name: i18n.T(binding.help) + " (" + keyName(binding.key) + ")",
+			run: func() {
+				binding.callback(Event{
+					Payload: binding.key,
+				})
+			},
+		})
+	}
+	for tab, name := range w.tabPane.TabNames {
+		if tab == w.currentTab() {
+			continue
+		}
+		tab := tab
+		actions = append(actions, paletteAction{
+			name: i18n.T("switch to the tab %s", name),
+			run:  func() { w.switchToTab(tab) },
+		})
+	}
+	if w.mainView != nil {
+		for row, column := range w.mainView.ItemsList() {
+			if column == "" {
+				continue
+			}
+			row := row
+			actions = append(actions, paletteAction{
+				name: i18n.T("sort by %s", column),
+				run: func() {
+					column, asc := w.currentSort()
+					w.publishSort(row, sortDirection(row, column, asc))
+				},
+			})
+		}
+	}
+	return actions
+}
+
+// switchToTab makes the tab the active one, the focus is moved to the
+// other pane of the split view if the pane shows the tab.
+func (w *TermWindow) switchToTab(tab int) {
+	if w.split.enabled && tab == w.split.other {
+		w.handleSplitFocus(Event{})
+		return
+	}
+	if w.filter.Text != "" {
+		w.filter.Text = ""
+		w.notifyFilter(w.currentTab())
+	}
+	w.showTab(tab)
+}
+
+// updatePalette lists the actions matching the query, the best matches first.
+func (w *TermWindow) updatePalette() {
+	w.palette.matches = matchActions(w.palette.query, w.palette.actions)
+	w.palettePanel.Title = i18n.T("Command: %s", w.palette.query+"_")
+	w.palettePanel.Rows = make([]string, 0, len(w.palette.matches))
+	for _, action := range w.palette.matches {
+		w.palettePanel.Rows = append(w.palettePanel.Rows, action.name)
+	}
+	if len(w.palette.matches) == 0 {
+		w.palettePanel.Rows = []string{i18n.T("no matching action")}
+	}
+	w.palettePanel.SelectedRow = 0
+}
+
+// placePalette places the palette panel, it is sized to fit all actions
+// so that it keeps the size while the query is typed.
+func (w *TermWindow) placePalette() {
+	rows := w.palettePanel.Rows
+	w.palettePanel.Rows = make([]string, 0, len(w.palette.actions))
+	for _, action := range w.palette.actions {
+		w.palettePanel.Rows = append(w.palettePanel.Rows, action.name)
+	}
+	placePanel(w.palettePanel, w.width, w.height)
+	w.palettePanel.Rows = rows
+}
+
+// handlePaletteClose restores the default view the palette was opened from.
+func (w *TermWindow) handlePaletteClose(_ Event) {
+	w.view = def
+	w.keybindings = w.palette.keybindings
+	w.palette = paletteState{}
+}
+
+// handlePaletteRun closes the palette and runs the selected action.
+func (w *TermWindow) handlePaletteRun(event Event) {
+	if len(w.palette.matches) == 0 {
+		return
+	}
+	action := w.palette.matches[w.palettePanel.SelectedRow]
+	w.handlePaletteClose(event)
+	action.run()
+}
+
+// handlePaletteScroll is called when an action of the palette is selected.
+func (w *TermWindow) handlePaletteScroll(event Event) {
+	switch event.Payload.(string) {
+	case KeyScrollDown:
+		if len(w.palette.matches) != 0 {
+			w.palettePanel.ScrollDown()
+		}
+	case KeyScrollUp:
+		w.palettePanel.ScrollUp()
+	}
+}
+
+// handlePaletteAppend appends the typed character to the query,
+// other keys (e.g. <F5>) are ignored.
+func (w *TermWindow) handlePaletteAppend(event Event) {
+	payload := event.Payload.(string)
+	if payload == "<Space>" {
+		payload = " "
+	}
+	if strings.HasPrefix(payload, "<") && len(payload) > 1 {
+		return
+	}
+	w.palette.query += payload
+	w.updatePalette()
+}
+
+// handlePaletteDelete deletes the last character of the query.
+func (w *TermWindow) handlePaletteDelete(_ Event) {
+	query := []rune(w.palette.query)
+	if len(query) == 0 {
+		return
+	}
+	w.palette.query = string(query[:len(query)-1])
+	w.updatePalette()
+}
+
+// matchActions returns the actions fuzzy matching the query ordered by the
+// score, actions with the same score keep their order.
+func matchActions(query string, actions []paletteAction) []paletteAction {
+	type match struct {
+		action paletteAction
+		score  int
+	}
+	var matches []match
+	for _, action := range actions {
+		score, ok := fuzzyScore(query, action.name)
+		if !ok {
+			continue
+		}
+		// insertion after the matches with the same or higher score
+		i := len(matches)
+		for i > 0 && matches[i-1].score < score {
+			i--
+		}
+		matches = append(matches, match{})
+		copy(matches[i+1:], matches[i:])
+		matches[i] = match{action: action, score: score}
+	}
+	result := make([]paletteAction, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.action)
+	}
+	return result
+}
+
+// fuzzyScore returns true if all characters of the query (spaces excluded)
+// are found in the text in the same order, ignoring the case. The score
+// favours characters matched at the start of words and consecutive
+// characters, e.g. "sbn" scores higher for "sort by name" than for "subnet",
+// texts containing the query score the highest.
+func fuzzyScore(query, text string) (int, bool) {
+	query, text = strings.ToLower(query), strings.ToLower(text)
+	pattern := []rune(strings.ReplaceAll(query, " ", ""))
+	if len(pattern) == 0 {
+		return 0, true
+	}
+	var (
+		score, p  int
+		prev      rune = ' '
+		matchPrev bool
+	)
+	if strings.Contains(text, query) {
+		score += 6 * len(pattern)
+	}
+	for _, r := range text {
+		matched := p < len(pattern) && r == pattern[p]
+		if matched {
+			score++
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 3
+			}
+			if matchPrev {
+				score += 2
+			}
+			p++
+		}
+		matchPrev = matched
+		prev = r
+	}
+	return score, p == len(pattern)
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+// popupState stores the keybindings of the default view the popup was opened from.
+type popupState struct {
+	keybindings []*Binding
+}
+
+// SetDetailTabs sets the tabs showing details of the selected entry.
+func (w *TermWindow) SetDetailTabs(tabs ...int) {
+	w.detailTabs = tabs
+}
+
+// isDetailTab returns true if the tab shows details of the selected entry.
+func (w *TermWindow) isDetailTab(tab int) bool {
+	return isPresent(w.detailTabs, tab)
+}
+
+// handleDetails is called when the details of the selected entry are requested.
+func (w *TermWindow) handleDetails(_ Event) {
+	if !w.isDetailTab(w.currentTab()) {
+		return
+	}
+	w.bus.publish(DetailEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// SetEventTabs sets the tabs keeping a log of events.
+func (w *TermWindow) SetEventTabs(tabs ...int) {
+	w.eventTabs = tabs
+}
+
+// isEventTab returns true if the tab keeps a log of events.
+func (w *TermWindow) isEventTab(tab int) bool {
+	return isPresent(w.eventTabs, tab)
+}
+
+// handleEventLog is called when the events of the tab are requested.
+func (w *TermWindow) handleEventLog(_ Event) {
+	if !w.isEventTab(w.currentTab()) {
+		return
+	}
+	w.bus.publish(EventLogEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// ShowPopup shows the rows in a panel on top of the current tab until
+// it is closed. It has to be called by a DetailEvent or EventLogEvent subscriber
+// (or by the gui itself).
+func (w *TermWindow) ShowPopup(title string, rows []string) {
+	if w.view != def {
+		return
+	}
+	w.popup = popupState{keybindings: w.keybindings}
+	w.popupPanel.Title = title
+	w.popupPanel.Rows = rows
+	w.popupPanel.SelectedRow = 0
+	placePanel(w.popupPanel, w.width, w.height)
+	w.view = popup
+	w.keybindings = w.popupKeybindings()
+}
+
+// handlePopupClose restores the default view the popup was opened from.
+func (w *TermWindow) handlePopupClose(_ Event) {
+	w.view = def
+	w.keybindings = w.popup.keybindings
+	w.popup = popupState{}
+}
+
+// handlePopupScroll is called when the popup is scrolled.
+func (w *TermWindow) handlePopupScroll(event Event) {
+	switch event.Payload.(string) {
+	case KeyScrollDown:
+		w.popupPanel.ScrollDown()
+	case KeyScrollUp:
+		w.popupPanel.ScrollUp()
+	}
+}
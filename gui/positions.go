@@ -38,10 +38,17 @@ const (
 	FilterExitBottomX = 24
 	FilterExitBottomY = 7
 
+	SplitTitleTopX    = 0
+	SplitTitleTopY    = 2
+	SplitTitleBottomY = 5
+
 	SortPanelTopX    = 0
 	SortPanelTopY    = 8
 	SortPanelBottomX = 23
 
+	HelpPanelTopY     = 6
+	HelpPanelMinWidth = 40
+
 	NotificationBottomX = 75
 	NotificationBottomY = 75
 )
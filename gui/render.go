@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"time"
+)
+
+// DefaultRenderInterval is the minimum interval between the renders of data updates.
+const DefaultRenderInterval = 16 * time.Millisecond
+
+// renderState limits the rate of the renders of data updates. The gui is
+// rendered only on data updates, input and resizes, the data updates coming
+// within the interval since the last render are rendered together once the
+// interval passes. Input and resizes are rendered immediately.
+type renderState struct {
+	interval time.Duration
+	// time of the last render.
+	last time.Time
+	// timer fires once the postponed render is due.
+	timer *time.Timer
+	// set while a render is postponed.
+	pending bool
+}
+
+// newRenderState returns the render state with the default interval.
+func newRenderState() renderState {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	return renderState{
+		interval: DefaultRenderInterval,
+		timer:    timer,
+	}
+}
+
+// SetRenderInterval sets the minimum interval between the renders of data
+// updates, each update is rendered if zero. It has to be set before Start.
+func (w *TermWindow) SetRenderInterval(interval time.Duration) {
+	w.rendering.interval = interval
+}
+
+// renderUpdate renders the data update, or postpones the render until
+// the render interval since the last render passes.
+func (w *TermWindow) renderUpdate() {
+	if w.rendering.pending {
+		return
+	}
+	wait := w.rendering.interval - time.Since(w.rendering.last)
+	if wait <= 0 {
+		w.render()
+		return
+	}
+	w.rendering.pending = true
+	w.rendering.timer.Reset(wait)
+}
+
+// renderPostponed renders the postponed data updates.
+func (w *TermWindow) renderPostponed() {
+	w.rendering.pending = false
+	w.render()
+}
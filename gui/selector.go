@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"sync"
+
+	tui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+var (
+	eventsOnce sync.Once
+	events     <-chan tui.Event
+)
+
+// pollEvents returns the channel of terminal events. The channel is shared
+// by the selector and the window, since the polling cannot be stopped and
+// a separate poller would steal the events of the other.
+func pollEvents() <-chan tui.Event {
+	eventsOnce.Do(func() {
+		events = tui.PollEvents()
+	})
+	return events
+}
+
+// SelectItem shows a full screen list of the items and returns the index of
+// the item selected by Enter, or -1 if the selection is cancelled (Esc, q).
+// The terminal is initialized for the selection and closed afterwards.
+func SelectItem(title string, items []string) (int, error) {
+	if err := initTerminal(); err != nil {
+		return -1, err
+	}
+	defer closeTerminal()
+
+	list := widgets.NewList()
+	list.Title = title
+	list.Rows = items
+	list.WrapText = false
+	list.TextStyle = tui.NewStyle(tui.Color(activeTheme.Text))
+	list.SelectedRowStyle = tui.NewStyle(tui.Color(activeTheme.SelectedRow.Fg), tui.Color(activeTheme.SelectedRow.Bg), tui.ModifierBold)
+	width, height := tui.TerminalDimensions()
+	list.SetRect(0, 0, width, height)
+	tui.Render(list)
+
+	for e := range pollEvents() {
+		switch e.ID {
+		case KeyScrollUp:
+			list.ScrollUp()
+		case KeyScrollDown:
+			list.ScrollDown()
+		case KeyPgup:
+			list.ScrollPageUp()
+		case KeyPgdn:
+			list.ScrollPageDown()
+		case KeyEnter:
+			return list.SelectedRow, nil
+		case KeyCancel, KeyQuit, KeyCtrlC:
+			return -1, nil
+		case "<Resize>":
+			payload := e.Payload.(tui.Resize)
+			list.SetRect(0, 0, payload.Width, payload.Height)
+			tui.Clear()
+		}
+		tui.Render(list)
+	}
+	return -1, nil
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// sortMenu is the state of the sort menu. The table is re-sorted by the
+// selected column as a preview while the selection moves, the sort is
+// applied by enter and the applied sort is restored if the menu is closed.
+type sortMenu struct {
+	// applied sort column (-1 if not sorted) and direction.
+	column int
+	asc    bool
+	// sort column and direction shown by the table.
+	shownColumn int
+	shownAsc    bool
+}
+
+// currentSort returns the sort column (the index into the items list)
+// and the direction of the current view, the column is -1 if not sorted.
+func (w *TermWindow) currentSort() (column int, asc bool) {
+	if view, ok := w.mainView.(SortView); ok {
+		return view.Sort()
+	}
+	return -1, false
+}
+
+// sortDirection returns the direction of the sort by the column against
+// the applied sort: the order is reversed if the rows are sorted by the
+// column already, other columns keep the direction (descending if not sorted).
+func sortDirection(column, applied int, asc bool) bool {
+	if column == applied {
+		return !asc
+	}
+	return applied >= 0 && asc
+}
+
+// publishSort sorts the rows of the current tab by the column.
+func (w *TermWindow) publishSort(column int, asc bool) {
+	w.bus.publish(SortEvent, Event{
+		Payload: SortMetadata{
+			CurrRow: column,
+			CurrTab: w.currentTab(),
+			Asc:     asc,
+		},
+	})
+}
+
+// previewSort sorts the rows by the selected column unless they are sorted
+// by it already. The selected applied column is previewed in the applied
+// direction, other columns in the direction they would be applied in.
+func (w *TermWindow) previewSort() {
+	column, asc := w.sortPanel.SelectedRow, w.sortMenu.asc
+	if column != w.sortMenu.column {
+		asc = sortDirection(column, w.sortMenu.column, w.sortMenu.asc)
+	}
+	w.showSort(column, asc)
+}
+
+// showSort sorts the rows by the column if they are not shown sorted by it
+// already, and updates the sort panel.
+func (w *TermWindow) showSort(column int, asc bool) {
+	if column != w.sortMenu.shownColumn || asc != w.sortMenu.shownAsc {
+		w.sortMenu.shownColumn, w.sortMenu.shownAsc = column, asc
+		w.publishSort(column, asc)
+	}
+	w.updateSortPanel()
+}
+
+// updateSortPanel lists the columns of the current view, the applied sort
+// column is marked by its direction and the title shows the sort of the table.
+func (w *TermWindow) updateSortPanel() {
+	items := w.mainView.ItemsList()
+	rows := make([]string, len(items))
+	for i, item := range items {
+		rows[i] = item
+		if i == w.sortMenu.column {
+			rows[i] = item + " " + sortArrow(w.sortMenu.asc)
+		}
+	}
+	w.sortPanel.Rows = rows
+	w.sortPanel.Title = i18n.T("Sort by")
+	if column := w.sortMenu.shownColumn; column >= 0 && column < len(items) {
+		w.sortPanel.Title = i18n.T("Sort: %s", items[column]+" "+sortArrow(w.sortMenu.shownAsc))
+	}
+}
+
+// sortArrow returns the arrow of the sort direction.
+func sortArrow(asc bool) string {
+	if asc {
+		return "↑"
+	}
+	return "↓"
+}
+
+// handleHeaderSort is called when a number key is pressed, the rows are
+// sorted by the n-th column of the header without opening the sort menu.
+func (w *TermWindow) handleHeaderSort(event Event) {
+	view, ok := w.mainView.(SortView)
+	if !ok {
+		return
+	}
+	col := int(event.Payload.(string)[0] - '1')
+	items := view.HeaderItems()
+	if col >= len(items) || items[col] < 0 {
+		w.pushNotification(i18n.T("the rows cannot be sorted by column %d", col+1))
+		return
+	}
+	w.sortByHeader(view, items[col])
+}
+
+// handleHeaderSortMove is called when the sort is moved to the previous
+// or the next column of the header, the columns the rows cannot be sorted
+// by are skipped. The direction of the sort is kept.
+func (w *TermWindow) handleHeaderSortMove(event Event) {
+	view, ok := w.mainView.(SortView)
+	if !ok {
+		return
+	}
+	dir := 1
+	if event.Payload.(string) == KeySortPrev {
+		dir = -1
+	}
+	items := view.HeaderItems()
+	applied, _ := view.Sort()
+	col := -1
+	if dir < 0 {
+		col = len(items)
+	}
+	for i, item := range items {
+		if item >= 0 && item == applied {
+			col = i
+			break
+		}
+	}
+	for col += dir; col >= 0 && col < len(items); col += dir {
+		if items[col] >= 0 {
+			w.sortByHeader(view, items[col])
+			return
+		}
+	}
+}
+
+// sortByHeader sorts the rows by the item of the header column, the order
+// is reversed if the rows are sorted by it already.
+func (w *TermWindow) sortByHeader(view SortView, item int) {
+	applied, asc := view.Sort()
+	asc = sortDirection(item, applied, asc)
+	w.publishSort(item, asc)
+	if names := w.mainView.ItemsList(); item < len(names) {
+		w.pushNotification(i18n.T("sorted by %s", names[item]+" "+sortArrow(asc)))
+	}
+}
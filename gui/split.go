@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gui
+
+import (
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// splitPane is the state of the split view, where two tabs are rendered
+// side by side. The focused pane shows the active tab of the tab pane,
+// all events (scroll, filter, sort...) are applied on the focused pane.
+type splitPane struct {
+	enabled bool
+	// tab shown by the pane which is not focused.
+	other int
+	// set if the right pane is focused.
+	focusRight bool
+}
+
+// splitTabs returns the tabs shown by the left and the right pane.
+func (w *TermWindow) splitTabs() (left, right int) {
+	if w.split.focusRight {
+		return w.split.other, w.currentTab()
+	}
+	return w.currentTab(), w.split.other
+}
+
+// canSplit returns true if all views can be placed in a pane.
+func (w *TermWindow) canSplit() bool {
+	if len(w.views) < 2 {
+		return false
+	}
+	for _, view := range w.views {
+		if _, ok := view.(PaneView); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// notifySplit notifies the listener about the split view change.
+func (w *TermWindow) notifySplit() {
+	w.bus.publish(SplitEvent, Event{
+		Payload: SplitMetadata{
+			Enabled:  w.split.enabled,
+			OtherTab: w.split.other,
+		},
+	})
+}
+
+// handleSplitToggle is called when the split view is toggled. The tab next
+// to the current one is shown in the right pane.
+func (w *TermWindow) handleSplitToggle(_ Event) {
+	if w.split.enabled {
+		w.split.enabled = false
+		w.pushNotification(i18n.T("split view: off"))
+	} else {
+		if !w.canSplit() {
+			return
+		}
+		w.split = splitPane{
+			enabled: true,
+			other:   (w.currentTab() + 1) % len(w.views),
+		}
+		left, right := w.splitTabs()
+		w.pushNotification(i18n.T("split view: %s | %s", w.tabPane.TabNames[left], w.tabPane.TabNames[right]))
+	}
+	w.resize(w.width, w.height)
+	w.notifySplit()
+}
+
+// handleSplitFocus is called when the focus is moved to the other pane
+// of the split view.
+func (w *TermWindow) handleSplitFocus(_ Event) {
+	if !w.split.enabled {
+		return
+	}
+	if w.filter.Text != "" {
+		w.filter.Text = ""
+		w.notifyFilter(w.currentTab())
+	}
+	w.mainView.Filter(Event{
+		Payload: "",
+	})
+	curr := w.currentTab()
+	w.tabPane.ActiveTabIndex = w.split.other
+	w.split.other = curr
+	w.split.focusRight = !w.split.focusRight
+	w.mainView = w.views[w.tabPane.ActiveTabIndex]
+
+	w.notifySplit()
+	w.bus.publish(TabSwitchEvent, Event{
+		Payload: w.tabPane.ActiveTabIndex,
+	})
+}
+
+// placePanes places the views of the split view next to each other,
+// separated by a single column.
+func (w *TermWindow) placePanes() {
+	left, right := w.splitTabs()
+	middle := w.width / 2
+	w.views[left].(PaneView).Place(0, middle, w.height)
+	w.views[right].(PaneView).Place(middle+1, w.width, w.height)
+}
+
+// splitTitleText returns the names of the tabs shown by the panes,
+// the focused one is highlighted.
+func (w *TermWindow) splitTitleText() string {
+	left, right := w.splitTabs()
+	names := []string{w.tabPane.TabNames[left], w.tabPane.TabNames[right]}
+	focused := 0
+	if w.split.focusRight {
+		focused = 1
+	}
+	names[focused] = "[" + names[focused] + "](mod:reverse)"
+	return i18n.T("split: %s | %s", names[0], names[1])
+}
@@ -17,24 +17,34 @@
 package gui
 
 import (
-	"fmt"
 	"time"
 
 	tui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
+	"go.pantheon.tech/vpptop/gui/xtui"
+	"go.pantheon.tech/vpptop/i18n"
 )
 
 // viewType represents the current state of the gui.
-// As of now it supports only 3 views.
+// As of now it supports only 4 views.
 // 1 - default (where only the tabPane Version, and tabViews are rendered).
 // 2 - sort (where on top of the default widgets a sort panel is rendered).
 // 3 - filter (where on top of the default widgets a filter is rendered).
+// 4 - help (where on top of the current view the keybindings are listed).
+// 5 - popup (where on top of the default widgets details of an entry are listed).
+// 6 - palette (where on top of the default widgets the matching actions are listed).
 type viewType uint
 
+// columnResizeStep is the number of cells a column is resized by.
+const columnResizeStep = 2
+
 const (
 	sort viewType = iota
 	filter
 	def
+	help
+	popup
+	palette
 )
 
 // TermWindow represents terminal gui handling multiple tabs
@@ -46,6 +56,22 @@ type TermWindow struct {
 	// indexes for tabs which can be cleared.
 	// (for these tabs a notification will be displayed).
 	clearTabs []int
+	// indexes for tabs supporting the save event.
+	saveTabs []int
+	// indexes for tabs supporting the trace toggle.
+	traceTabs []int
+	// indexes for tabs supporting the grouping of entries.
+	groupTabs []int
+	// indexes for tabs showing details of the selected entry.
+	detailTabs []int
+	// indexes for tabs keeping a log of events.
+	eventTabs []int
+	// indexes for tabs with a quick filter.
+	quickFilterTabs []int
+	// indexes for tabs supporting pinned entries.
+	pinTabs []int
+	// tabs with the auto-refresh turned off.
+	manualTabs map[int]bool
 
 	// gui components.
 	mainView TabView
@@ -53,27 +79,54 @@ type TermWindow struct {
 
 	exitView     TabView
 	sortPanel    *widgets.List
-	tabPane      *widgets.TabPane
+	tabPane      *xtui.TabPane
 	filter       *widgets.Paragraph
 	filterExit   *widgets.Paragraph
 	state        *widgets.Paragraph
 	notification *widgets.Paragraph
+	splitTitle   *widgets.Paragraph
+	helpPanel    *widgets.List
+	popupPanel   *widgets.List
+	palettePanel *widgets.List
+
+	// split view state.
+	split splitPane
+	// state the help view was opened from.
+	help helpState
+	// state the popup was opened from.
+	popup popupState
+	// query and actions of the command palette.
+	palette paletteState
+	// applied and previewed sort of the sort menu.
+	sortMenu sortMenu
+	// rate limit of the renders of data updates.
+	rendering renderState
+
+	// terminal dimensions.
+	width, height int
 
 	// keybidings
 	keybindings []*Binding
 
-	timerDuration     time.Duration
-	notificationTimer *time.Timer
+	// notes are the shown notification and the history of notifications.
+	notes *notifications
 
 	// channels & callbacks.
 	stop         chan struct{}
 	onDataUpdate <-chan struct{}
 	windowEvents <-chan tui.Event
 
-	onExit      func(Event)
-	onSort      func(Event)
-	onClear     func(Event)
-	onTabswitch func(Event)
+	// bus dispatches the gui events to the subscribers.
+	bus *eventBus
+
+	// isExpression returns true if the filter is an expression applied
+	// by the user of the gui, rather than a filter of the table rows.
+	isExpression func(tab int, filter string) bool
+
+	// humanUnits is set if counters are shown in human readable units.
+	humanUnits bool
+	// paused is set while the updates of the tabs are paused.
+	paused bool
 }
 
 // NewTermWindow returns an instance of <*TermWindow>
@@ -82,17 +135,19 @@ type TermWindow struct {
 func NewTermWindow(onDataUpdate <-chan struct{}, views []TabView, viewNames []string, clearTabs []int, exitView TabView) *TermWindow {
 	window := new(TermWindow)
 
-	window.windowEvents = tui.PollEvents()
+	window.windowEvents = pollEvents()
 	window.stop = make(chan struct{})
 	window.onDataUpdate = onDataUpdate
+	window.bus = newEventBus()
 
-	window.timerDuration = 1 * time.Second
-	window.notificationTimer = time.NewTimer(window.timerDuration)
+	window.notes = newNotifications()
+	window.rendering = newRenderState()
 
 	window.keybindings = window.defaultKeybindings()
 	window.view = def
 
 	window.clearTabs = clearTabs
+	window.manualTabs = make(map[int]bool)
 
 	window.views = views
 	if len(window.views) != 0 {
@@ -103,64 +158,95 @@ func NewTermWindow(onDataUpdate <-chan struct{}, views []TabView, viewNames []st
 
 	window.sortPanel = widgets.NewList()
 	window.sortPanel.Border = true
-	window.sortPanel.TextStyle = tui.NewStyle(textStyle, tui.ColorBlue, tui.ModifierBold)
-	window.sortPanel.SelectedRowStyle = tui.NewStyle(tui.ColorYellow, tui.ColorBlue, tui.ModifierBold)
-	window.sortPanel.Title = "Sort by"
-
-	window.tabPane = widgets.NewTabPane(viewNames...)
-	window.tabPane.SetRect(TabPaneTopX, TabPaneTopY, TabPaneBottomX, TabPaneBottomY)
+	window.sortPanel.TextStyle = tui.NewStyle(tui.Color(activeTheme.Panel.Fg), tui.Color(activeTheme.Panel.Bg), tui.ModifierBold)
+	window.sortPanel.SelectedRowStyle = tui.NewStyle(tui.Color(activeTheme.PanelSelected), tui.Color(activeTheme.Panel.Bg), tui.ModifierBold)
+	window.sortPanel.Title = i18n.T("Sort by")
+
+	window.helpPanel = widgets.NewList()
+	window.helpPanel.Border = true
+	window.helpPanel.TextStyle = tui.NewStyle(tui.Color(activeTheme.Panel.Fg), tui.Color(activeTheme.Panel.Bg))
+	window.helpPanel.SelectedRowStyle = window.helpPanel.TextStyle
+
+	window.popupPanel = widgets.NewList()
+	window.popupPanel.Border = true
+	window.popupPanel.TextStyle = window.helpPanel.TextStyle
+	window.popupPanel.SelectedRowStyle = window.helpPanel.TextStyle
+
+	window.palettePanel = widgets.NewList()
+	window.palettePanel.Border = true
+	window.palettePanel.TextStyle = window.helpPanel.TextStyle
+	window.palettePanel.SelectedRowStyle = tui.NewStyle(tui.Color(activeTheme.PanelSelected), tui.Color(activeTheme.Panel.Bg), tui.ModifierBold)
+
+	window.tabPane = xtui.NewTabPane(viewNames...)
 	window.tabPane.Border = false
+	window.tabPane.DisabledTabStyle = tui.NewStyle(tui.Color(activeTheme.DisabledTab))
 
 	window.filter = widgets.NewParagraph()
 	window.filter.SetRect(FilterTopX, FilterTopY, FilterBottomX, FilterBottomY)
 	window.filter.Border = false
 	window.filter.WrapText = false
-	window.filter.TextStyle = tui.NewStyle(textStyle, filterBackground, tui.ModifierBold)
+	window.filter.TextStyle = tui.NewStyle(tui.Color(activeTheme.Filter.Fg), tui.Color(activeTheme.Filter.Bg), tui.ModifierBold)
 
 	window.filterExit = widgets.NewParagraph()
 	window.filterExit.SetRect(FilterExitTopX, FilterExitTopY, FilterExitBottomX, FilterExitBottomY)
 	window.filterExit.Border = false
 	window.filterExit.WrapText = false
-	window.filterExit.Text = fmt.Sprintf("Exit:%v filter:", KeyCancel)
-	window.filterExit.TextStyle = tui.NewStyle(textStyle, filterBackground, tui.ModifierBold)
+	window.filterExit.Text = i18n.T("Exit:%v filter:", KeyCancel)
+	window.filterExit.TextStyle = window.filter.TextStyle
 
 	window.state = widgets.NewParagraph()
-	window.state.SetRect(VersionTopX, VersionTopY, VersionBottomX, VersionBottomY)
 	window.state.Border = false
 	window.state.WrapText = true
 
+	window.splitTitle = widgets.NewParagraph()
+	window.splitTitle.Border = false
+	window.splitTitle.WrapText = false
+	window.placeTabPane()
+
 	window.notification = widgets.NewParagraph()
 	window.notification.Border = false
 	window.notification.WrapText = false
-	window.notification.TextStyle = tui.NewStyle(textStyle, tui.ColorBlue, tui.ModifierBold)
 
 	widgets.NewTabPane()
 	return window
 }
 
-// AddOnExitCallback registers a single function that will be called
-// on gui exit.
-func (w *TermWindow) AddOnExitCallback(f func(Event)) {
-	w.onExit = f
+// tabPaneWidth returns the width required to render all tab names
+// (each name is followed by a separator and padding), at least TabPaneBottomX.
+func tabPaneWidth(viewNames []string) int {
+	width := 0
+	for _, name := range viewNames {
+		width += len(name) + 3
+	}
+	if width < TabPaneBottomX {
+		return TabPaneBottomX
+	}
+	return width
 }
 
-// AddOnClearCallback registers a single function that will be called
-// on clear event. The Event payload is the tab at which the event occurred.
-func (w *TermWindow) AddOnClearCallback(f func(Event)) {
-	w.onClear = f
+// placeTabPane sizes the tab pane to fit the tab names, the state is shifted
+// to the right of the tab pane.
+func (w *TermWindow) placeTabPane() {
+	tabPaneBottomX := tabPaneWidth(w.tabPane.TabNames)
+	w.tabPane.SetRect(TabPaneTopX, TabPaneTopY, tabPaneBottomX, TabPaneBottomY)
+	versionShift := tabPaneBottomX - TabPaneBottomX
+	w.state.SetRect(VersionTopX+versionShift, VersionTopY, VersionBottomX+versionShift, VersionBottomY)
+	w.splitTitle.SetRect(SplitTitleTopX, SplitTitleTopY, tabPaneBottomX, SplitTitleBottomY)
 }
 
-// AddOnClearCallback registers a single function that will be called
-// on sort event. The Event payload is of type SortMetadata.
-func (w *TermWindow) AddOnSortCallback(f func(Event)) {
-	w.onSort = f
+// AddTab appends the view as a new tab (e.g. an optional tab),
+// it has to be called before the gui is started.
+func (w *TermWindow) AddTab(name string, view TabView) {
+	w.views = append(w.views, view)
+	w.tabPane.TabNames = append(w.tabPane.TabNames, name)
+	w.placeTabPane()
 }
 
-// AddOnTabSwitchCallback registers a single function that will be called
-// on each tab switch (left or right). The payload of the Event is the new
-// tab index.
-func (w *TermWindow) AddOnTabSwitchCallback(f func(Event)) {
-	w.onTabswitch = f
+// Subscribe registers a function that will be called on each event of the type,
+// the functions of the type are called in the order they were registered. See
+// the EventType constants for the payloads of the events.
+func (w *TermWindow) Subscribe(t EventType, f func(Event)) {
+	w.bus.subscribe(t, f)
 }
 
 // SetState sets the connection state, version and build date text to the state
@@ -170,38 +256,25 @@ func (w *TermWindow) SetState(s string) {
 }
 
 // handleExit changes the main view to the exit screen, and notifies
-// all listeners for the onExit event.
+// all subscribers of the exit event.
 func (w *TermWindow) handleExit(event Event) {
 	close(w.stop)
+	w.split.enabled = false
 	w.mainView = w.exitView
-	if w.onExit != nil {
-		w.onExit(event)
-	}
-}
-
-// pushNotification resets the timer for the displayed
-// notification and updates the text.
-func (w *TermWindow) pushNotification(text string) {
-	isPresent := func(tabs []int, currTab int) bool {
-		for _, tab := range tabs {
-			if tab == currTab {
-				return true
-			}
-		}
-		return false
-	}
-
-	currTab := w.currentTab()
-	if isPresent(w.clearTabs, currTab) {
-		w.notificationTimer.Reset(w.timerDuration)
-		w.notification.Text = text
-	}
+	w.bus.publish(ExitEvent, event)
 }
 
-// handleSortMenu changes the main view to the sort menu.
+// handleSortMenu changes the main view to the sort menu,
+// the column the rows are sorted by is selected.
 func (w *TermWindow) handleSortMenu(_ Event) {
 	w.view = sort
-	w.sortPanel.Rows = w.mainView.ItemsList()
+	column, asc := w.currentSort()
+	w.sortMenu = sortMenu{column: column, asc: asc, shownColumn: column, shownAsc: asc}
+	w.updateSortPanel()
+	w.sortPanel.SelectedRow = 0
+	if column >= 0 && column < len(w.sortPanel.Rows) {
+		w.sortPanel.SelectedRow = column
+	}
 	w.keybindings = w.sortKeybindings()
 }
 
@@ -215,9 +288,12 @@ func (w *TermWindow) handleFilterMenu(_ Event) {
 func (w *TermWindow) handleDefaultMenu(event Event) {
 	switch w.view {
 	case sort:
+		// drop the preview
+		w.showSort(w.sortMenu.column, w.sortMenu.asc)
 		w.sortPanel.Rows = []string{""}
 	case filter:
 		w.filter.Text = ""
+		w.notifyFilter(w.currentTab())
 	}
 	w.handleFilter(event)
 }
@@ -235,17 +311,33 @@ func (w *TermWindow) handleScroll(event Event) {
 
 // handlePreviousTab is called when a tab switch event occurs.
 func (w *TermWindow) handleTabSwitch(event Event) {
-	switch event.Payload.(string) {
-	case KeyTabLeft:
-		w.tabPane.FocusLeft()
-	case KeyTabRight:
-		w.tabPane.FocusRight()
-	}
 	if w.filter.Text != "" {
 		w.filter.Text = ""
+		w.notifyFilter(w.currentTab())
+	}
+	dir := 1
+	if event.Payload.(string) == KeyTabLeft {
+		dir = -1
+	}
+	tab := w.tabPane.ActiveTabIndex + dir
+	// the tab shown by the other pane is skipped
+	if w.split.enabled && tab == w.split.other {
+		tab += dir
+	}
+	if tab < 0 || tab >= len(w.tabPane.TabNames) {
+		return
 	}
+	w.showTab(tab)
+}
+
+// showTab makes the tab the active one.
+func (w *TermWindow) showTab(tab int) {
+	w.tabPane.ActiveTabIndex = tab
 	w.mainView = w.views[w.tabPane.ActiveTabIndex]
-	w.onTabswitch(Event{
+	if w.split.enabled {
+		w.resize(w.width, w.height)
+	}
+	w.bus.publish(TabSwitchEvent, Event{
 		Payload: w.tabPane.ActiveTabIndex,
 	})
 }
@@ -253,11 +345,315 @@ func (w *TermWindow) handleTabSwitch(event Event) {
 // handleClear is called when an on clear event occurs.
 func (w *TermWindow) handleClear(_ Event) {
 	currTab := w.currentTab()
-	w.pushNotification(fmt.Sprintf("clearing tab: %s", w.tabPane.TabNames[currTab]))
-	if w.onClear != nil {
-		w.onClear(Event{
-			Payload: currTab,
-		})
+	if isPresent(w.clearTabs, currTab) {
+		w.pushNotification(i18n.T("clearing tab: %s", w.tabPane.TabNames[currTab]))
+	}
+	w.bus.publish(ClearEvent, Event{
+		Payload: currTab,
+	})
+}
+
+// isPresent returns true if the tab is in the tabs.
+func isPresent(tabs []int, currTab int) bool {
+	for _, tab := range tabs {
+		if tab == currTab {
+			return true
+		}
+	}
+	return false
+}
+
+// isClearTab returns true if the tab can be cleared.
+func (w *TermWindow) isClearTab(tab int) bool {
+	return isPresent(w.clearTabs, tab)
+}
+
+// isSaveTab returns true if the tab supports the save event.
+func (w *TermWindow) isSaveTab(tab int) bool {
+	return isPresent(w.saveTabs, tab)
+}
+
+// isGroupTab returns true if the tab supports the grouping of entries.
+func (w *TermWindow) isGroupTab(tab int) bool {
+	return isPresent(w.groupTabs, tab)
+}
+
+// SetGroupTabs sets the tabs supporting the grouping of entries.
+func (w *TermWindow) SetGroupTabs(tabs ...int) {
+	w.groupTabs = tabs
+}
+
+// SetDisabledTabs greys out the names of the tabs whose data is not available
+// (e.g. not supported by the connected VPP), the tabs can still be shown.
+func (w *TermWindow) SetDisabledTabs(tabs ...int) {
+	w.tabPane.SetDisabled(tabs...)
+}
+
+// handleRefresh is called when an on refresh event occurs.
+func (w *TermWindow) handleRefresh(_ Event) {
+	currTab := w.currentTab()
+	w.pushNotification(i18n.T("refreshing tab: %s", w.tabPane.TabNames[currTab]))
+	w.bus.publish(RefreshEvent, Event{
+		Payload: currTab,
+	})
+}
+
+// handleAutoRefreshToggle is called when the auto-refresh of the tab is toggled.
+func (w *TermWindow) handleAutoRefreshToggle(_ Event) {
+	currTab := w.currentTab()
+	if w.manualTabs[currTab] {
+		delete(w.manualTabs, currTab)
+		w.pushNotification(i18n.T("auto-refresh of %s: on", w.tabPane.TabNames[currTab]))
+	} else {
+		w.manualTabs[currTab] = true
+		w.pushNotification(i18n.T("auto-refresh of %s: off (F5 to refresh)", w.tabPane.TabNames[currTab]))
+	}
+	w.bus.publish(AutoRefreshEvent, Event{
+		Payload: AutoRefreshMetadata{
+			Tab:     currTab,
+			Enabled: !w.manualTabs[currTab],
+		},
+	})
+}
+
+// SetManualRefreshTabs turns off the auto-refresh of the tabs,
+// they are refreshed on demand until toggled back.
+func (w *TermWindow) SetManualRefreshTabs(tabs ...int) {
+	for _, tab := range tabs {
+		w.manualTabs[tab] = true
+	}
+}
+
+// handleUnitsToggle is called when the counter units are toggled.
+func (w *TermWindow) handleUnitsToggle(_ Event) {
+	w.humanUnits = !w.humanUnits
+	if w.humanUnits {
+		w.pushNotification(i18n.T("units: human readable"))
+	} else {
+		w.pushNotification(i18n.T("units: raw"))
+	}
+	w.bus.publish(UnitsEvent, Event{
+		Payload: w.humanUnits,
+	})
+}
+
+// handleTraceToggle is called when an API trace or a packet capture toggle event occurs.
+func (w *TermWindow) handleTraceToggle(_ Event) {
+	if !w.isTraceTab(w.currentTab()) {
+		return
+	}
+	w.pushNotification(i18n.T("toggling trace"))
+	w.bus.publish(TraceEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// handleGroupToggle is called when a group toggle event occurs.
+func (w *TermWindow) handleGroupToggle(_ Event) {
+	w.pushNotification(i18n.T("toggling grouping"))
+	w.bus.publish(GroupEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// handleHideZeroToggle is called when a hide zero toggle event occurs.
+func (w *TermWindow) handleHideZeroToggle(_ Event) {
+	w.pushNotification(i18n.T("toggling zero entries"))
+	w.bus.publish(HideZeroEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// handleBaselineToggle is called when a baseline toggle event occurs.
+func (w *TermWindow) handleBaselineToggle(_ Event) {
+	w.pushNotification(i18n.T("toggling baseline"))
+	w.bus.publish(BaselineEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// handlePauseToggle is called when the updates of the tabs are paused or resumed.
+func (w *TermWindow) handlePauseToggle(_ Event) {
+	w.paused = !w.paused
+	if w.paused {
+		w.pushNotification(i18n.T("updates: paused"))
+	} else {
+		w.pushNotification(i18n.T("updates: resumed"))
+	}
+	w.bus.publish(PauseEvent, Event{
+		Payload: w.paused,
+	})
+}
+
+// handleMeasure is called when a timed measurement is started,
+// the updates are resumed until the measurement ends.
+func (w *TermWindow) handleMeasure(_ Event) {
+	w.paused = false
+	w.pushNotification(i18n.T("measurement: started"))
+	w.bus.publish(MeasureEvent, Event{})
+}
+
+// SetPaused sets whether the updates of the tabs are paused, e.g. once
+// a measurement ends. The updates are resumed by the pause key.
+func (w *TermWindow) SetPaused(paused bool) {
+	w.paused = paused
+	if paused {
+		w.pushNotification(i18n.T("measurement: done"))
+	}
+}
+
+// handleExport is called when an export event occurs.
+func (w *TermWindow) handleExport(_ Event) {
+	currTab := w.currentTab()
+	w.pushNotification(i18n.T("exporting tab: %s", w.tabPane.TabNames[currTab]))
+	w.bus.publish(ExportEvent, Event{
+		Payload: currTab,
+	})
+}
+
+// handleColumnSelect is called when the next column to be resized is selected.
+func (w *TermWindow) handleColumnSelect(_ Event) {
+	view, ok := w.mainView.(ColumnView)
+	if !ok {
+		return
+	}
+	w.pushNotification(i18n.T("column: %s (+/- to resize)", view.SelectNextColumn()))
+}
+
+// handleColumnResize is called when the selected column is resized.
+func (w *TermWindow) handleColumnResize(event Event) {
+	view, ok := w.mainView.(ColumnView)
+	if !ok {
+		return
+	}
+	delta := columnResizeStep
+	if event.Payload.(string) == KeyNarrow {
+		delta = -delta
+	}
+	view.ResizeColumn(delta)
+	w.bus.publish(LayoutEvent, Event{
+		Payload: LayoutMetadata{
+			CurrTab: w.currentTab(),
+			Widths:  view.ColumnWidths(),
+		},
+	})
+}
+
+// handleSelect is called when the selected table entry is chosen.
+func (w *TermWindow) handleSelect(_ Event) {
+	w.bus.publish(SelectEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// isTraceTab returns true if the tab supports the trace toggle.
+func (w *TermWindow) isTraceTab(tab int) bool {
+	return isPresent(w.traceTabs, tab)
+}
+
+// SetTraceTabs sets the tabs supporting the trace toggle.
+func (w *TermWindow) SetTraceTabs(tabs ...int) {
+	w.traceTabs = tabs
+}
+
+// SetSaveTabs sets the tabs supporting the save event.
+func (w *TermWindow) SetSaveTabs(tabs ...int) {
+	w.saveTabs = tabs
+}
+
+// handleSave is called when an on save event occurs.
+func (w *TermWindow) handleSave(_ Event) {
+	currTab := w.currentTab()
+	if !isPresent(w.saveTabs, currTab) {
+		return
+	}
+	w.pushNotification(i18n.T("saving tab: %s", w.tabPane.TabNames[currTab]))
+	w.bus.publish(SaveEvent, Event{
+		Payload: currTab,
+	})
+}
+
+// SetExpressionFilter sets the function deciding whether the filter is an
+// expression. Expressions are not applied on the table rows by the gui,
+// it is up to the filter callback to apply them.
+func (w *TermWindow) SetExpressionFilter(isExpression func(tab int, filter string) bool) {
+	w.isExpression = isExpression
+}
+
+// notifyFilter is called when the filter of the tab changes.
+func (w *TermWindow) notifyFilter(tab int) {
+	w.bus.publish(FilterEvent, Event{
+		Payload: FilterMetadata{
+			CurrTab: tab,
+			Filter:  w.filter.Text,
+		},
+	})
+}
+
+// SetQuickFilterTabs sets the tabs whose filter can be cycled through
+// the values given by the QuickFilterEvent subscriber.
+func (w *TermWindow) SetQuickFilterTabs(tabs ...int) {
+	w.quickFilterTabs = tabs
+}
+
+// isQuickFilterTab returns true if the tab has a quick filter.
+func (w *TermWindow) isQuickFilterTab(tab int) bool {
+	return isPresent(w.quickFilterTabs, tab)
+}
+
+// handleQuickFilter is called when the quick filter of the tab is cycled.
+func (w *TermWindow) handleQuickFilter(_ Event) {
+	if !w.isQuickFilterTab(w.currentTab()) {
+		return
+	}
+	w.bus.publish(QuickFilterEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// SetPinTabs sets the tabs whose entries can be pinned to the top of the table.
+func (w *TermWindow) SetPinTabs(tabs ...int) {
+	w.pinTabs = tabs
+}
+
+// isPinTab returns true if the entries of the tab can be pinned.
+func (w *TermWindow) isPinTab(tab int) bool {
+	return isPresent(w.pinTabs, tab)
+}
+
+// handlePin is called when the selected table entry is pinned or unpinned.
+func (w *TermWindow) handlePin(_ Event) {
+	if !w.isPinTab(w.currentTab()) {
+		return
+	}
+	w.bus.publish(PinEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// handleSpikeToggle is called when the highlighting of rate spikes is toggled.
+func (w *TermWindow) handleSpikeToggle(_ Event) {
+	w.bus.publish(SpikeEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// handleCompactToggle is called when the compact layout of the interfaces is toggled.
+func (w *TermWindow) handleCompactToggle(_ Event) {
+	w.bus.publish(CompactEvent, Event{
+		Payload: w.currentTab(),
+	})
+}
+
+// SetFilter replaces the filter of the current tab. It has to be
+// called by a QuickFilterEvent subscriber.
+func (w *TermWindow) SetFilter(text string) {
+	w.filter.Text = text
+	w.notifyFilter(w.currentTab())
+	if text == "" {
+		w.pushNotification(i18n.T("filter: off"))
+	} else {
+		w.pushNotification(i18n.T("filter: %s", text))
 	}
 }
 
@@ -265,6 +661,7 @@ func (w *TermWindow) handleClear(_ Event) {
 func (w *TermWindow) handleReduceFilter(_ Event) {
 	if len(w.filter.Text) != 0 {
 		w.filter.Text = w.filter.Text[:len(w.filter.Text)-1]
+		w.notifyFilter(w.currentTab())
 	}
 }
 
@@ -275,18 +672,19 @@ func (w *TermWindow) handleAppendToFilter(event Event) {
 		payload = " "
 	}
 	w.filter.Text = w.filter.Text + payload
+	w.notifyFilter(w.currentTab())
 }
 
-// handleSort is called when an sort event occurs.
-func (w *TermWindow) handleSort(_ Event) {
-	if w.onSort != nil {
-		w.onSort(Event{
-			Payload: SortMetadata{
-				CurrRow: w.sortPanel.SelectedRow,
-				CurrTab: w.currentTab(),
-			},
-		})
+// handleSort applies the sort by the selected column and closes the sort
+// menu, the order is reversed if the rows are sorted by the column already.
+func (w *TermWindow) handleSort(event Event) {
+	if len(w.sortPanel.Rows) != 0 {
+		column := w.sortPanel.SelectedRow
+		asc := sortDirection(column, w.sortMenu.column, w.sortMenu.asc)
+		w.showSort(column, asc)
+		w.sortMenu.column, w.sortMenu.asc = column, asc
 	}
+	w.handleDefaultMenu(event)
 }
 
 // handleSortPanelScrollDown is called in sort state of the gui
@@ -304,6 +702,9 @@ func (w *TermWindow) handleSortPanelScroll(event Event) {
 	case KeyPgup:
 		w.sortPanel.SelectedRow = len(w.sortPanel.Rows) - 1
 	}
+	if len(w.sortPanel.Rows) != 0 {
+		w.previewSort()
+	}
 }
 
 // processInput is called when a keyboard event occurs.
@@ -317,7 +718,7 @@ func (w *TermWindow) processInput(key string) {
 		return false
 	}
 
-	if w.view == filter && !isPresent(w.keybindings, key) {
+	if (w.view == filter || w.view == palette) && !isPresent(w.keybindings, key) {
 		w.keybindings[len(w.keybindings)-1].callback(Event{
 			Payload: key,
 		})
@@ -335,6 +736,8 @@ func (w *TermWindow) processInput(key string) {
 
 // render is called on gui refresh.
 func (w *TermWindow) render() {
+	w.rendering.last = time.Now()
+	w.updateNotification()
 	widgts := []tui.Drawable{
 		w.tabPane,
 		w.state,
@@ -342,16 +745,44 @@ func (w *TermWindow) render() {
 	}
 
 	if w.mainView != nil {
+		text := w.filter.Text
+		if w.isExpression != nil && w.isExpression(w.currentTab(), text) {
+			text = ""
+		}
 		w.mainView.Filter(Event{
-			Payload: w.filter.Text,
+			Payload: text,
 		})
 		widgts = append(widgts, w.mainView.Widgets()...)
 
+		if w.split.enabled {
+			other := w.views[w.split.other]
+			other.Filter(Event{
+				Payload: "",
+			})
+			w.splitTitle.Text = w.splitTitleText()
+			widgts = append(widgts, other.Widgets()...)
+			widgts = append(widgts, w.splitTitle)
+		}
+
 		switch w.view {
 		case sort:
 			widgts = append(widgts, w.sortPanel)
 		case filter:
 			widgts = append(widgts, w.filter, w.filterExit)
+		case help:
+			switch w.help.view {
+			case sort:
+				widgts = append(widgts, w.sortPanel)
+			case filter:
+				widgts = append(widgts, w.filter, w.filterExit)
+			case palette:
+				widgts = append(widgts, w.palettePanel)
+			}
+			widgts = append(widgts, w.helpPanel)
+		case popup:
+			widgts = append(widgts, w.popupPanel)
+		case palette:
+			widgts = append(widgts, w.palettePanel)
 		}
 	}
 	tui.Clear()
@@ -360,10 +791,7 @@ func (w *TermWindow) render() {
 
 // Init initializes the gui.
 func (w *TermWindow) Init() error {
-	if err := tui.Init(); err != nil {
-		return fmt.Errorf("error occured while initializing tui: %v", err)
-	}
-	return nil
+	return initTerminal()
 }
 
 // CurrentTab returns the index of the current tab.
@@ -385,7 +813,7 @@ func (w *TermWindow) Start() {
 	for {
 		select {
 		case <-w.onDataUpdate:
-			w.render()
+			w.renderUpdate()
 		case e := <-w.windowEvents:
 			switch e.Type {
 			case tui.KeyboardEvent:
@@ -395,9 +823,11 @@ func (w *TermWindow) Start() {
 				w.resize(payload.Width, payload.Height)
 			}
 			w.render()
-		case <-w.notificationTimer.C:
-			w.notification.Text = ""
+		case <-w.notes.timer.C:
+			w.notes.expire()
 			w.render()
+		case <-w.rendering.timer.C:
+			w.renderPostponed()
 		case <-w.stop:
 			return
 		}
@@ -406,15 +836,22 @@ func (w *TermWindow) Start() {
 
 // Destroy de-initializes gui.
 func (w *TermWindow) Destroy() {
-	tui.Close()
+	closeTerminal()
 }
 
 // resize resizes all widgets.
 func (w *TermWindow) resize(width, height int) {
+	w.width, w.height = width, height
 	for i := range w.views {
 		w.views[i].Resize(width, height)
 	}
+	if w.split.enabled {
+		w.placePanes()
+	}
 	w.exitView.Resize(width, height)
 	w.sortPanel.SetRect(SortPanelTopX, SortPanelTopY, SortPanelBottomX, height)
+	placePanel(w.helpPanel, w.width, w.height)
+	placePanel(w.popupPanel, w.width, w.height)
+	w.placePalette()
 	w.notification.SetRect(SortPanelTopX, height-2, NotificationBottomX, NotificationBottomY)
 }
@@ -53,4 +53,46 @@ type (
 		// ItemsList returns the list of items to be sorted.
 		ItemsList() []string
 	}
+
+	// ColumnView is a TabView with columns resizable by the user.
+	ColumnView interface {
+		TabView
+
+		// SelectNextColumn selects the next column to be resized
+		// and returns its name.
+		SelectNextColumn() string
+
+		// ResizeColumn changes the width of the selected column by delta.
+		ResizeColumn(delta int)
+
+		// ColumnWidths returns the widths of the columns, columns
+		// resized with the terminal window have negative widths.
+		ColumnWidths() []int
+
+		// SetColumnWidths replaces the widths of the columns.
+		SetColumnWidths([]int)
+	}
+
+	// SortView is a TabView showing the sort of its rows.
+	SortView interface {
+		TabView
+
+		// Sort returns the sort column (the index into the items list)
+		// and direction, the column is -1 if the rows are not sorted.
+		Sort() (column int, asc bool)
+
+		// HeaderItems returns the index into the items list of each column
+		// of the header, -1 for the columns the rows cannot be sorted by.
+		HeaderItems() []int
+	}
+
+	// PaneView is a TabView which can be placed in a part of the terminal
+	// window, e.g. in a pane of the split view.
+	PaneView interface {
+		TabView
+
+		// Place places the view between the left and right column
+		// of the terminal with the given height.
+		Place(left, right, height int)
+	}
 )
\ No newline at end of file
@@ -18,6 +18,7 @@ package views
 
 import (
 	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/i18n"
 	tui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
 )
@@ -49,8 +50,9 @@ func NewExitView() *exitView {
 	}
 	s.exitScreen.Border = false
 	s.exitScreen.WrapText = true
-	s.exitScreen.Text = "Closing.."
-	s.exitScreen.TextStyle = tui.NewStyle(tui.ColorWhite, tui.ColorBlack, tui.ModifierBold)
+	s.exitScreen.Text = i18n.T("Closing..")
+	theme := gui.ActiveTheme()
+	s.exitScreen.TextStyle = tui.NewStyle(tui.Color(theme.Exit.Fg), tui.Color(theme.Exit.Bg), tui.ModifierBold)
 
 	return s
 }
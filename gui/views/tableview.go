@@ -17,9 +17,14 @@
 package views
 
 import (
+	"strings"
+	"time"
+
 	"go.pantheon.tech/vpptop/gui"
+	"go.pantheon.tech/vpptop/i18n"
 	"go.pantheon.tech/vpptop/gui/xtui"
 	tui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
 )
 
 const (
@@ -28,6 +33,9 @@ const (
 	Resize = -1
 )
 
+// tickerStep is the duration the ticker text is scrolled by a character in.
+const tickerStep = 250 * time.Millisecond
+
 // Table positions to match sort panel
 // provided by the gui.
 const (
@@ -39,43 +47,143 @@ const (
 	tableHeaderBottomY = gui.TabPaneBottomY + 4
 )
 
+// tableFooter shows the position in the table and the sort column below
+// the table, e.g. "rows 21–40 of 1234 (filtered from 5678) | sort: Name ↓".
+type tableFooter struct {
+	*widgets.Paragraph
+	table *xtui.Table
+	// sort column and direction, empty if not sorted.
+	sort string
+	// index of the sort column into the items list (-1 if not sorted)
+	// and the sort direction.
+	sortColumn int
+	sortAsc    bool
+	// ticker text shown after the position, scrolled if it does not fit.
+	ticker string
+	// time the ticker text was set, the scrolling starts from.
+	tickerSince time.Time
+	// banner shown before the position in the critical color,
+	// e.g. the last error of the stats shown by the table.
+	banner string
+}
+
+// Draw updates the text by the position in the table drawn before the footer.
+func (f *tableFooter) Draw(buf *tui.Buffer) {
+	f.table.Lock()
+	first, last, shown, total := f.table.Position()
+	f.table.Unlock()
+
+	text := i18n.T("rows %d–%d of %d", first, last, shown)
+	if shown == 0 {
+		text = i18n.T("rows 0 of 0")
+	}
+	prefix := ""
+	if f.banner != "" {
+		prefix = f.banner + " | "
+	}
+	if shown != total {
+		text += " " + i18n.T("(filtered from %d)", total)
+	}
+	if f.sort != "" {
+		text += " | " + i18n.T("sort: %s", f.sort)
+	}
+	if f.ticker != "" {
+		text += " | "
+		text += scrollTicker(f.ticker, f.Inner.Dx()-len([]rune(prefix+text)), time.Since(f.tickerSince))
+	}
+	if f.banner != "" {
+		text = xtui.Colored(f.banner, tui.Color(gui.ActiveTheme().Critical)) + " | " + text
+	}
+	f.Text = text
+	f.Paragraph.Draw(buf)
+}
+
+// scrollTicker returns the part of the ticker shown within the width. The
+// ticker is shown whole if it fits, otherwise it is scrolled in a loop by
+// a character per tickerStep.
+func scrollTicker(ticker string, width int, elapsed time.Duration) string {
+	runes := []rune(ticker)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return ticker
+	}
+	loop := append(runes, []rune("   ")...)
+	offset := int(elapsed/tickerStep) % len(loop)
+	shown := make([]rune, width)
+	for i := range shown {
+		shown[i] = loop[(offset+i)%len(loop)]
+	}
+	return string(shown)
+}
+
 // TableView implements the view interface. It is a table build on xtui.Table.
 type TableView struct {
 	table  *xtui.Table
 	header *xtui.Table
+	footer *tableFooter
 
-	itemsList []string
-	colWidth  []int
+	itemsList  []string
+	colWidth   []int
+	headerRows xtui.TableRows
 
 	tw      int
 	resized []int
+	// terminal width
+	width int
+	// column selected to be resized (-1 if none)
+	selectedCol int
 }
 
 // NewTableView returns a new instance of <*TableView>
-func NewTableView(itemsList []string, headerRows xtui.TableRows, filterCol, rowsPerEntry int, colWidths []int, light bool) *TableView {
+func NewTableView(itemsList []string, headerRows xtui.TableRows, filterCol, rowsPerEntry int, colWidths []int) *TableView {
+	theme := gui.ActiveTheme()
 	v := &TableView{
-		table:     xtui.NewTable(light),
-		header:    xtui.NewTable(light),
-		itemsList: itemsList,
+		table:       xtui.NewTable(),
+		header:      xtui.NewTable(),
+		itemsList:   itemsList,
+		headerRows:  headerRows,
+		selectedCol: -1,
+	}
+	v.footer = &tableFooter{
+		Paragraph:  widgets.NewParagraph(),
+		table:      v.table,
+		sortColumn: -1,
 	}
+	v.footer.Border = false
+	v.footer.WrapText = false
+	v.footer.TextStyle = tui.NewStyle(tui.Color(theme.Text))
+
 	v.table.TextAlignment = tui.AlignLeft
 	v.table.Border = false
 	v.table.RowSeparator = false
 	v.table.FillRow = true
+	v.table.Colors.Text = tui.Color(theme.Text)
+	v.table.Colors.SelectedRowFg = tui.Color(theme.SelectedRow.Fg)
+	v.table.Colors.SelectedRowBg = tui.Color(theme.SelectedRow.Bg)
 
 	v.header.TextAlignment = tui.AlignLeft
 	v.header.Border = false
 	v.header.RowSeparator = false
 	v.header.FillRow = true
-	v.header.Colors.SelectedRowFg = tui.ColorWhite
-	v.header.Colors.SelectedRowBg = tui.ColorRed
+	v.header.Colors.Text = tui.Color(theme.Text)
+	v.header.Colors.SelectedRowFg = tui.Color(theme.Header.Fg)
+	v.header.Colors.SelectedRowBg = tui.Color(theme.Header.Bg)
 
 	v.header.Rows = headerRows
 
 	v.table.InitFilter(filterCol, rowsPerEntry)
 
-	v.colWidth = colWidths
+	v.setColumnWidths(colWidths)
+	return v
+}
 
+// setColumnWidths sets the column widths, columns with the Resize width
+// share the width left by the other columns.
+func (v *TableView) setColumnWidths(colWidths []int) {
+	v.colWidth = colWidths
+	v.resized, v.tw = nil, 0
 	for i, val := range v.colWidth {
 		if val == Resize {
 			v.resized = append(v.resized, i)
@@ -83,24 +191,198 @@ func NewTableView(itemsList []string, headerRows xtui.TableRows, filterCol, rows
 			v.tw += v.colWidth[i]
 		}
 	}
-	return v
 }
 
-// Resize resizes the tableView.
-func (v *TableView) Resize(w, h int) {
-	v.table.SetRect(tableTopX, tableTopY, w, h-1)
-	v.header.SetRect(tableHeaderTopX, tableHeaderTopY, w, tableHeaderBottomY)
-
-	if v.colWidth != nil {
-		cw := (w - v.tw) / len(v.resized)
-
+// layout recalculates the widths of the resized columns for the terminal width.
+func (v *TableView) layout() {
+	if v.colWidth == nil {
+		return
+	}
+	if len(v.resized) != 0 {
+		cw := (v.width - v.tw) / len(v.resized)
+		if cw < 1 {
+			cw = 1
+		}
 		for _, i := range v.resized {
 			v.colWidth[i] = cw
 		}
+	}
+
+	v.table.Table.ColumnWidths = v.colWidth
+	v.header.Table.ColumnWidths = v.colWidth
+}
+
+// columnCount returns the number of columns given by the header.
+func (v *TableView) columnCount() int {
+	if len(v.headerRows) == 0 {
+		return 0
+	}
+	return len(v.headerRows[len(v.headerRows)-1])
+}
+
+// styledHeader returns the header rows with the selected column highlighted
+// and the sort column marked by the sort direction.
+func (v *TableView) styledHeader() xtui.TableRows {
+	v.footer.Lock()
+	sortItem, asc := v.footer.sortColumn, v.footer.sortAsc
+	v.footer.Unlock()
+	sortCol := v.headerColumn(sortItem)
+	if (v.selectedCol < 0 || v.selectedCol >= v.columnCount()) && sortCol < 0 {
+		return v.headerRows
+	}
+	rows := make(xtui.TableRows, len(v.headerRows))
+	copy(rows, v.headerRows)
+	last := append([]string(nil), rows[len(rows)-1]...)
+	if sortCol >= 0 {
+		arrow := " ↓"
+		if asc {
+			arrow = " ↑"
+		}
+		last[sortCol] = "[" + last[sortCol] + arrow + "](mod:bold)"
+	}
+	if v.selectedCol >= 0 && v.selectedCol < len(last) {
+		last[v.selectedCol] = "[" + v.headerRows[len(rows)-1][v.selectedCol] + "](mod:reverse)"
+	}
+	rows[len(rows)-1] = last
+	return rows
+}
+
+// HeaderItems returns the index into the items list of each column of the
+// header, the columns are matched to the items by their names, which may be
+// followed by a summary in parentheses (e.g. "Reason (all: 5/s)"). Columns
+// without an item of the same name (e.g. the counters of the interfaces
+// listed in a single column) are -1.
+func (v *TableView) HeaderItems() []int {
+	if len(v.headerRows) == 0 {
+		return nil
+	}
+	header := v.headerRows[len(v.headerRows)-1]
+	items := make([]int, len(header))
+	for col, name := range header {
+		items[col] = -1
+		for item, itemName := range v.itemsList {
+			if name == itemName || strings.HasPrefix(name, itemName+" (") {
+				items[col] = item
+				break
+			}
+		}
+	}
+	return items
+}
+
+// headerColumn returns the column of the header named as the item, -1 if none.
+func (v *TableView) headerColumn(item int) int {
+	if item < 0 {
+		return -1
+	}
+	for col, headerItem := range v.HeaderItems() {
+		if headerItem == item {
+			return col
+		}
+	}
+	return -1
+}
 
-		v.table.Table.ColumnWidths = v.colWidth
-		v.header.Table.ColumnWidths = v.colWidth
+// SelectNextColumn selects the next column to be resized and returns its name.
+func (v *TableView) SelectNextColumn() string {
+	count := v.columnCount()
+	if count == 0 {
+		return ""
 	}
+	v.selectedCol = (v.selectedCol + 1) % count
+
+	v.header.Lock()
+	v.header.Rows = v.styledHeader()
+	v.header.Unlock()
+	return v.headerRows[len(v.headerRows)-1][v.selectedCol]
+}
+
+// ResizeColumn changes the width of the selected column by delta. The
+// resized column keeps its width when the terminal window is resized.
+func (v *TableView) ResizeColumn(delta int) {
+	if v.selectedCol < 0 {
+		return
+	}
+	if v.colWidth == nil {
+		// the columns share the width equally
+		count := v.columnCount()
+		widths := make([]int, count)
+		for i := range widths {
+			widths[i] = v.width / count
+		}
+		v.setColumnWidths(widths)
+	}
+	if v.selectedCol >= len(v.colWidth) {
+		return
+	}
+
+	for j, i := range v.resized {
+		if i == v.selectedCol {
+			v.resized = append(v.resized[:j], v.resized[j+1:]...)
+			v.tw += v.colWidth[i]
+			break
+		}
+	}
+	width := v.colWidth[v.selectedCol] + delta
+	if width < 1 {
+		width = 1
+	}
+	v.tw += width - v.colWidth[v.selectedCol]
+	v.colWidth[v.selectedCol] = width
+	v.layout()
+}
+
+// ColumnWidths returns the widths of the columns, the widths of columns
+// resized with the terminal window are Resize.
+func (v *TableView) ColumnWidths() []int {
+	if v.colWidth == nil {
+		return nil
+	}
+	widths := append([]int(nil), v.colWidth...)
+	for _, i := range v.resized {
+		widths[i] = Resize
+	}
+	return widths
+}
+
+// SetColumnWidths replaces the widths of the columns. Widths
+// not matching the number of columns are ignored.
+func (v *TableView) SetColumnWidths(widths []int) {
+	if len(widths) == 0 || len(widths) != v.columnCount() {
+		return
+	}
+	v.setColumnWidths(append([]int(nil), widths...))
+	v.layout()
+}
+
+// SetCellStyler sets the function used to style individual table cells.
+func (v *TableView) SetCellStyler(styler xtui.CellStyler) {
+	v.table.Lock()
+	v.table.CellStyler = styler
+	v.table.Unlock()
+}
+
+// SetHeader replaces the header rows of the table.
+func (v *TableView) SetHeader(rows xtui.TableRows) {
+	v.header.Lock()
+	v.headerRows = rows
+	v.header.Rows = v.styledHeader()
+	v.header.Unlock()
+}
+
+// Resize resizes the tableView.
+func (v *TableView) Resize(w, h int) {
+	v.Place(tableTopX, w, h)
+}
+
+// Place places the tableView between the left and right column.
+func (v *TableView) Place(left, right, h int) {
+	v.table.SetRect(left, tableTopY, right, h-1)
+	v.header.SetRect(left, tableHeaderTopY, right, tableHeaderBottomY)
+	v.footer.SetRect(left, h-1, right, h)
+
+	v.width = right - left
+	v.layout()
 }
 
 // Filter applies the filter from the gui.Event to the xtui.Table.
@@ -137,12 +419,94 @@ func (v *TableView) Update(payload interface{}) {
 
 	v.table.Lock()
 	v.table.Rows = rows
+	v.table.Source = nil
 	v.table.Unlock()
 
 }
 
+// UpdateSource replaces the table rows by the source, the rows
+// are built only for the part of the table which is visible.
+// The lock from the table is used.
+func (v *TableView) UpdateSource(source xtui.RowSource) {
+	v.table.Lock()
+	v.table.Rows = nil
+	v.table.Source = source
+	v.table.Unlock()
+}
+
+// SetRowsPerEntry changes the number of rows of each table entry.
+func (v *TableView) SetRowsPerEntry(rowsPerEntry int) {
+	v.table.Lock()
+	v.table.SetRowsPerEntry(rowsPerEntry)
+	v.table.Unlock()
+}
+
+// SetPinned sets the number of the pinned entries at the top of the rows
+// (or the source) of the next update. The lock from the table is used.
+func (v *TableView) SetPinned(entries int) {
+	v.table.Lock()
+	v.table.SetPinned(entries)
+	v.table.Unlock()
+}
+
+// SetSort shows the sort column (the index into the items list) and direction
+// in the footer. The sort is not shown if the column is out of the list.
+func (v *TableView) SetSort(column int, asc bool) {
+	v.footer.Lock()
+	if column < 0 || column >= len(v.itemsList) {
+		v.footer.sort = ""
+		v.footer.sortColumn, v.footer.sortAsc = -1, false
+	} else {
+		v.footer.sortColumn, v.footer.sortAsc = column, asc
+		v.footer.sort = v.itemsList[column] + " ↓"
+		if asc {
+			v.footer.sort = v.itemsList[column] + " ↑"
+		}
+	}
+	v.footer.Unlock()
+
+	// the sort column is marked in the header
+	v.header.Lock()
+	v.header.Rows = v.styledHeader()
+	v.header.Unlock()
+}
+
+// Sort returns the sort column (the index into the items list) and direction
+// shown in the footer, the column is -1 if the rows are not sorted.
+func (v *TableView) Sort() (column int, asc bool) {
+	v.footer.Lock()
+	defer v.footer.Unlock()
+	return v.footer.sortColumn, v.footer.sortAsc
+}
+
+// SetTicker sets the text shown by a ticker in the footer, it is scrolled
+// if it does not fit. The ticker is hidden if the text is empty.
+func (v *TableView) SetTicker(text string) {
+	v.footer.Lock()
+	defer v.footer.Unlock()
+	if text != v.footer.ticker {
+		v.footer.ticker = text
+		v.footer.tickerSince = time.Now()
+	}
+}
+
+// SetBanner sets the text shown in the critical color at the start of the
+// footer, e.g. why the rows are stale. The banner is hidden if the text is empty.
+func (v *TableView) SetBanner(text string) {
+	v.footer.Lock()
+	defer v.footer.Unlock()
+	v.footer.banner = text
+}
+
+// SelectedKey returns the value of the filter column of the selected entry.
+func (v *TableView) SelectedKey() string {
+	v.table.Lock()
+	defer v.table.Unlock()
+	return v.table.SelectedFilterValue()
+}
+
 // Widgets returns all widgets to be drawn by this view.
-func (v *TableView) Widgets() []tui.Drawable { return []tui.Drawable{v.table, v.header} }
+func (v *TableView) Widgets() []tui.Drawable { return []tui.Drawable{v.table, v.header, v.footer} }
 
 // ItemsList returns a list with names based on which the table can be sorted.
 func (v *TableView) ItemsList() []string { return v.itemsList }
@@ -28,6 +28,8 @@ import (
 const (
 	// EmptyCell represents an empty cell in the table.
 	EmptyCell = ""
+	// PinMarker prefixes the filter column of the pinned entries.
+	PinMarker = "* "
 )
 
 const (
@@ -39,6 +41,52 @@ const (
 // TableRows represent the rows of the table.
 type TableRows [][]string
 
+// CellStyler returns the foreground color for the cell at the column col
+// of the row, and whether the cell should be styled at all. The entryRow
+// is the index of the row within its entry (see rowsPerEntry).
+type CellStyler func(entryRow int, row []string, col int) (termui.Color, bool)
+
+// CellColorer is implemented by a RowSource styling the cells by the data
+// of their entry (e.g. by values not shown in the table). Cells which are
+// not styled by the source are styled by the CellStyler.
+type CellColorer interface {
+	// CellColor returns the foreground color for the cell at the column col
+	// of the entryRow-th row of the entry, and whether it should be styled.
+	CellColor(entry, entryRow, col int) (termui.Color, bool)
+}
+
+// RowSource provides the rows of the table lazily. Only the rows
+// of the entries visible in the table are requested on draw.
+type RowSource interface {
+	// Len returns the number of entries.
+	Len() int
+	// FilterValue returns the value of the entry the filter is applied on.
+	FilterValue(entry int) string
+	// EntryRows returns the rows of the entry (see rowsPerEntry).
+	EntryRows(entry int) TableRows
+}
+
+// colorNames maps colors to names recognized by termui.ParseStyles.
+var colorNames = map[termui.Color]string{
+	termui.ColorBlack:   "black",
+	termui.ColorRed:     "red",
+	termui.ColorGreen:   "green",
+	termui.ColorYellow:  "yellow",
+	termui.ColorBlue:    "blue",
+	termui.ColorMagenta: "magenta",
+	termui.ColorCyan:    "cyan",
+	termui.ColorWhite:   "white",
+}
+
+// Colored returns the text styled by the color for termui.ParseStyles,
+// the text is returned as is if the color is not a basic color.
+func Colored(text string, color termui.Color) string {
+	if name, known := colorNames[color]; known {
+		return "[" + text + "](fg:" + name + ")"
+	}
+	return text
+}
+
 // Table is extending the Table in the termui/v3/widgets/ package
 // to support scrolling/filtering.
 type Table struct {
@@ -47,6 +95,10 @@ type Table struct {
 	out TableRows
 	//Rows are the rows of the table.
 	Rows TableRows
+	// Source (optional) provides the rows lazily, Rows are ignored if set.
+	Source RowSource
+	// entries are the indexes of the source entries that are going to be rendered.
+	entries []int
 	// offset is the offset from the first row of the table.
 	offset int
 	// visibleRows is the number of rows that will be displayed.
@@ -63,6 +115,15 @@ type Table struct {
 	filterColumn int
 	// number of rows per entry in the table
 	rowsPerEntry int
+	// number of entries at the top of the table shown regardless of the filter
+	pinned int
+	// number of entries matching the filter and of all entries, updated on draw
+	shown, total int
+	// selected is the filter value of the selected entry, the selection
+	// follows the entry when the rows are re-sorted or updated.
+	selected string
+	// CellStyler (optional) is used to style individual cells on draw.
+	CellStyler CellStyler
 
 	// colors which will be used to paint the table rows.
 	Colors struct {
@@ -76,7 +137,7 @@ type Table struct {
 }
 
 // NewTable returns a default instance of xtui.Table.
-func NewTable(lightTheme bool) *Table {
+func NewTable() *Table {
 	t := &Table{
 		Table:        widgets.NewTable(),
 		out:          nil,
@@ -91,11 +152,7 @@ func NewTable(lightTheme bool) *Table {
 		rowsPerEntry: 1,
 	}
 	// Default colors
-	if lightTheme {
-		t.Colors.Text = termui.ColorBlack
-	} else {
-		t.Colors.Text = termui.ColorWhite
-	}
+	t.Colors.Text = termui.ColorWhite
 	t.Colors.SelectedRowFg = termui.ColorBlack
 	t.Colors.SelectedRowBg = termui.ColorGreen
 	return t
@@ -147,11 +204,109 @@ func (t *Table) ColumnWidths() ([]int, error) {
 	return columnWidths, nil
 }
 
+// SelectedFilterValue returns the value of the filter column
+// of the entry at the selected row.
+func (t *Table) SelectedFilterValue() string {
+	if t.filterColumn < 0 {
+		return ""
+	}
+	entry := (t.offset + t.curr) / t.rowsPerEntry
+	if t.out == nil && t.Source != nil {
+		if entry >= len(t.entries) {
+			return ""
+		}
+		return t.Source.FilterValue(t.entries[entry])
+	}
+	if row := entry * t.rowsPerEntry; row < len(t.out) && t.filterColumn < len(t.out[row]) {
+		return t.out[row][t.filterColumn]
+	}
+	return ""
+}
+
+// Position returns the range of the entries rendered in the table counted from
+// one (zero if there are none), the number of entries matching the filter and
+// the number of all entries. The position is updated on draw.
+func (t *Table) Position() (first, last, shown, total int) {
+	if t.shown == 0 || t.visibleRows == 0 {
+		return 0, 0, t.shown, t.total
+	}
+	first = t.offset/t.rowsPerEntry + 1
+	last = (t.offset+t.visibleRows-1)/t.rowsPerEntry + 1
+	if last > t.shown {
+		last = t.shown
+	}
+	return first, last, t.shown, t.total
+}
+
+// countEntries returns the number of entries of the rows. Entries with
+// the first row empty (e.g. the row of an empty table) are not counted.
+func (t *Table) countEntries(rows TableRows) int {
+	var count int
+	for i := 0; i < len(rows); i += t.rowsPerEntry {
+		for _, cell := range rows[i] {
+			if cell != EmptyCell {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
 // resetPositions resets the positions into the table.
 func (t *Table) resetPositions() {
 	t.offset = 0
 	t.prev = t.curr
 	t.curr = 0
+	t.selected = ""
+}
+
+// findEntry returns the index of the first rendered entry
+// with the filter value, or -1 if there is none.
+func (t *Table) findEntry(value string) int {
+	if t.out == nil && t.Source != nil {
+		for i, entry := range t.entries {
+			if t.Source.FilterValue(entry) == value {
+				return i
+			}
+		}
+		return -1
+	}
+	for i := 0; i < len(t.out); i += t.rowsPerEntry {
+		if t.filterColumn < len(t.out[i]) && t.out[i][t.filterColumn] == value {
+			return i / t.rowsPerEntry
+		}
+	}
+	return -1
+}
+
+// followSelected moves the selection to the row of the entry selected before
+// the rows changed, the view is scrolled to keep the row visible. The selection
+// is kept at its position if the entry is not rendered anymore.
+func (t *Table) followSelected() {
+	if t.selected == "" || t.filterColumn < 0 {
+		return
+	}
+	entry := t.findEntry(t.selected)
+	if entry < 0 {
+		return
+	}
+	row := entry*t.rowsPerEntry + (t.offset+t.curr)%t.rowsPerEntry
+	if row == t.offset+t.curr {
+		return
+	}
+	height := t.height - skipRows
+	if height < 1 {
+		height = 1
+	}
+	switch {
+	case row < t.offset:
+		t.offset = row
+	case row >= t.offset+height:
+		t.offset = row - height + 1
+	}
+	t.prev = t.curr
+	t.curr = row - t.offset
 }
 
 // SetRect resize the table, and correctly sets the height of the table.
@@ -189,6 +344,8 @@ func (t *Table) ScrollUp() {
 	}
 
 	t.paintActiveRow()
+	// the moved selection is tracked on the next draw.
+	t.selected = ""
 }
 
 // ScrollDown scrolls the table one row down
@@ -197,12 +354,14 @@ func (t *Table) ScrollDown() {
 		t.prev = t.curr
 		t.curr++
 	} else {
-		if t.offset+t.visibleRows < len(t.out) {
+		if t.offset+t.visibleRows < t.rowCount() {
 			t.offset++
 		}
 	}
 
 	t.paintActiveRow()
+	// the moved selection is tracked on the next draw.
+	t.selected = ""
 }
 
 // PageDown skips to the next page
@@ -227,9 +386,51 @@ func (t *Table) InitFilter(column, rowsPerEntry int) {
 	t.rowsPerEntry = rowsPerEntry
 }
 
+// SetRowsPerEntry changes the number of rows of each entry, the table
+// is scrolled back to the top.
+func (t *Table) SetRowsPerEntry(rowsPerEntry int) {
+	if rowsPerEntry < 1 || rowsPerEntry == t.rowsPerEntry {
+		return
+	}
+	t.rowsPerEntry = rowsPerEntry
+	t.resetPositions()
+}
+
+// SetPinned sets the number of entries at the top of the rows (or the source)
+// which are pinned. Pinned entries are marked and not filtered out.
+func (t *Table) SetPinned(entries int) {
+	t.pinned = entries
+}
+
+// rowCount returns the number of rows that are going to be rendered.
+func (t *Table) rowCount() int {
+	if t.out == nil && t.Source != nil {
+		return len(t.entries) * t.rowsPerEntry
+	}
+	return len(t.out)
+}
+
+// rows returns the rows to be rendered in the range [from, to),
+// the rows of the source are built only for the entries in the range.
+func (t *Table) rows(from, to int) TableRows {
+	if t.out != nil || t.Source == nil {
+		return t.out[from:to]
+	}
+	rows := make(TableRows, 0, to-from)
+	for e := from / t.rowsPerEntry; e*t.rowsPerEntry < to; e++ {
+		for r, row := range t.Source.EntryRows(t.entries[e]) {
+			if i := e*t.rowsPerEntry + r; i >= from && i < to {
+				rows = append(rows, row)
+			}
+		}
+	}
+	return rows
+}
+
 // reCalcView recalculates the view into the table, handling any out of bounds errors.
 func (t *Table) reCalcView() {
-	if len(t.out) == 0 {
+	count := t.rowCount()
+	if count == 0 {
 		return
 	}
 	// Adjust the visible rows based on the available
@@ -239,23 +440,126 @@ func (t *Table) reCalcView() {
 	}
 	// Avoid overflow if the number of displayed rows
 	// is greater than the number of available rows.
-	if t.offset+t.visibleRows > len(t.out) {
-		t.visibleRows = len(t.out) - t.offset
+	if t.offset+t.visibleRows > count {
+		t.visibleRows = count - t.offset
 	}
 	// Avoid underflow if the table height is less than the top left
 	// corner of the table.
 	if t.visibleRows < 0 {
 		t.visibleRows = 0
 	}
-	t.Table.Rows = t.out[t.offset : t.offset+t.visibleRows]
+	t.Table.Rows = t.styleRows(t.markPinned(t.rows(t.offset, t.offset+t.visibleRows), t.offset), t.offset)
+}
+
+// markPinned returns rows with the filter column of the pinned entries
+// prefixed by the PinMarker. The offset is the index of the first row in the table.
+func (t *Table) markPinned(rows TableRows, offset int) TableRows {
+	if t.pinned == 0 || t.filterColumn < 0 || offset >= t.pinned*t.rowsPerEntry {
+		return rows
+	}
+	marked := make(TableRows, len(rows))
+	copy(marked, rows)
+	for i := range marked {
+		row := offset + i
+		if row >= t.pinned*t.rowsPerEntry {
+			break
+		}
+		if row%t.rowsPerEntry != 0 || t.filterColumn >= len(marked[i]) {
+			continue
+		}
+		marked[i] = append([]string(nil), marked[i]...)
+		marked[i][t.filterColumn] = PinMarker + marked[i][t.filterColumn]
+	}
+	return marked
+}
+
+// styleRows returns a copy of rows with cells styled by the source
+// (if it is a CellColorer) and the CellStyler. The offset is the index
+// of the first row in the table.
+func (t *Table) styleRows(rows TableRows, offset int) TableRows {
+	var colorer CellColorer
+	if t.out == nil && t.Source != nil {
+		colorer, _ = t.Source.(CellColorer)
+	}
+	if t.CellStyler == nil && colorer == nil {
+		return rows
+	}
+	styled := make(TableRows, len(rows))
+	for i, row := range rows {
+		entryRow := (offset + i) % t.rowsPerEntry
+		styled[i] = make([]string, len(row))
+		for col, cell := range row {
+			styled[i][col] = cell
+			if cell == EmptyCell {
+				continue
+			}
+			if colorer != nil {
+				entry := t.entries[(offset+i)/t.rowsPerEntry]
+				if color, ok := colorer.CellColor(entry, entryRow, col); ok {
+					styled[i][col] = Colored(cell, color)
+					continue
+				}
+			}
+			if t.CellStyler == nil {
+				continue
+			}
+			color, ok := t.CellStyler(entryRow, row, col)
+			if !ok {
+				continue
+			}
+			styled[i][col] = Colored(cell, color)
+		}
+	}
+	return styled
 }
 
 // Draw extends the method Draw from tui.Table to also include filtering.
 func (t *Table) Draw(buf *termui.Buffer) {
+	if t.Source != nil {
+		t.filterSource()
+	} else {
+		t.filterRows()
+	}
+	t.followSelected()
+
+	t.reCalcView()
+	// Avoid panic in the termui/table draw method, if no rows are supplied by the user.
+	if len(t.Table.Rows) == 0 {
+		return
+	}
+
+	t.paintActiveRow()
+	t.selected = t.SelectedFilterValue()
+	t.Table.Draw(buf)
+}
+
+// filterSource applies the filter on the entries of the source. The rows
+// are not built here, only the indexes of the matching entries are kept.
+func (t *Table) filterSource() {
+	t.out = nil
+	t.entries = t.entries[:0]
+	filter := t.filter.String()
+	for i := 0; i < t.Source.Len(); i++ {
+		if i < t.pinned || filter == "" || t.filterColumn < 0 || strings.Contains(t.Source.FilterValue(i), filter) {
+			t.entries = append(t.entries, i)
+		}
+	}
+
+	t.shown, t.total = len(t.entries), t.Source.Len()
+
+	// if no match against the filter, make an empty table
+	// based on the number of columns of the last render.
+	if len(t.entries) == 0 && filter != "" && len(t.Table.Rows) != 0 {
+		t.out = TableRows{make([]string, len(t.Table.Rows[0]))}
+	}
+}
+
+// filterRows applies the filter on the table rows.
+func (t *Table) filterRows() {
 	if t.filter.String() != "" && t.filterColumn >= 0 {
 		var filteredRows [][]string
 		for i := 0; i < len(t.Rows); i += t.rowsPerEntry {
-			if strings.Contains(t.Rows[i][t.filterColumn], t.filter.String()) {
+			if i < t.pinned*t.rowsPerEntry || strings.Contains(t.Rows[i][t.filterColumn], t.filter.String()) {
 				for r := 0; r < t.rowsPerEntry; r++ {
 					filteredRows = append(filteredRows, t.Rows[i+r])
 				}
@@ -279,13 +583,5 @@ func (t *Table) Draw(buf *termui.Buffer) {
 	} else {
 		t.out = t.Rows
 	}
-
-	t.reCalcView()
-	// Avoid panic in the termui/table draw method, if no rows are supplied by the user.
-	if len(t.Table.Rows) == 0 {
-		return
-	}
-
-	t.paintActiveRow()
-	t.Table.Draw(buf)
+	t.shown, t.total = t.countEntries(t.out), t.countEntries(t.Rows)
 }
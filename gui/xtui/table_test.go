@@ -18,6 +18,8 @@ package xtui
 
 import (
 	"testing"
+
+	"github.com/gizak/termui/v3"
 )
 
 func TestTable_AppendToFilter(t *testing.T) {
@@ -26,10 +28,10 @@ func TestTable_AppendToFilter(t *testing.T) {
 		input string
 		want  string
 	}{
-		{T: NewTable(false), input: "node", want: "node"},
-		{T: NewTable(false), input: "", want: ""},
-		{T: NewTable(false), input: "dpdk-65", want: "dpdk-65"},
-		{T: NewTable(false), input: "arm-pc", want: "arm-pc"},
+		{T: NewTable(), input: "node", want: "node"},
+		{T: NewTable(), input: "", want: ""},
+		{T: NewTable(), input: "dpdk-65", want: "dpdk-65"},
+		{T: NewTable(), input: "arm-pc", want: "arm-pc"},
 	}
 
 	for _, test := range tests {
@@ -50,14 +52,14 @@ func TestTable_ReduceFilter(t *testing.T) {
 		n     int
 		want  string
 	}{
-		{T: NewTable(false), input: "node", n: 1, want: "nod"},
-		{T: NewTable(false), input: "", n: 1, want: ""},
-		{T: NewTable(false), input: "", n: 2, want: ""},
-		{T: NewTable(false), input: "", n: -1, want: ""},
-		{T: NewTable(false), input: "arm-pc", n: 3, want: "arm"},
-		{T: NewTable(false), input: "arm-pc", n: 5, want: "a"},
-		{T: NewTable(false), input: "arm-pc", n: 6, want: ""},
-		{T: NewTable(false), input: "arm-pc", n: 7, want: "arm-pc"},
+		{T: NewTable(), input: "node", n: 1, want: "nod"},
+		{T: NewTable(), input: "", n: 1, want: ""},
+		{T: NewTable(), input: "", n: 2, want: ""},
+		{T: NewTable(), input: "", n: -1, want: ""},
+		{T: NewTable(), input: "arm-pc", n: 3, want: "arm"},
+		{T: NewTable(), input: "arm-pc", n: 5, want: "a"},
+		{T: NewTable(), input: "arm-pc", n: 6, want: ""},
+		{T: NewTable(), input: "arm-pc", n: 7, want: "arm-pc"},
 	}
 
 	for _, test := range tests {
@@ -85,12 +87,12 @@ func TestTable_ScrollUp(t *testing.T) {
 		wantPrev   int
 		wantOffset int
 	}{
-		{T: NewTable(false), visibleRows: 2, curr: 0, prev: 0, offset: 0, wantCurr: 0, wantPrev: 0, wantOffset: 0},
-		{T: NewTable(false), visibleRows: 2, curr: 1, prev: 0, offset: 0, wantCurr: 0, wantPrev: 1, wantOffset: 0},
-		{T: NewTable(false), visibleRows: 5, curr: 0, prev: 0, offset: 3, wantCurr: 0, wantPrev: 0, wantOffset: 2},
-		{T: NewTable(false), visibleRows: 10, curr: 2, prev: 1, offset: 5, wantCurr: 1, wantPrev: 2, wantOffset: 5},
-		{T: NewTable(false), visibleRows: 10, curr: 0, prev: 1, offset: 5, wantCurr: 0, wantPrev: 1, wantOffset: 4},
-		{T: NewTable(false), visibleRows: 10, curr: 0, prev: 1, offset: 0, wantCurr: 0, wantPrev: 1, wantOffset: 0},
+		{T: NewTable(), visibleRows: 2, curr: 0, prev: 0, offset: 0, wantCurr: 0, wantPrev: 0, wantOffset: 0},
+		{T: NewTable(), visibleRows: 2, curr: 1, prev: 0, offset: 0, wantCurr: 0, wantPrev: 1, wantOffset: 0},
+		{T: NewTable(), visibleRows: 5, curr: 0, prev: 0, offset: 3, wantCurr: 0, wantPrev: 0, wantOffset: 2},
+		{T: NewTable(), visibleRows: 10, curr: 2, prev: 1, offset: 5, wantCurr: 1, wantPrev: 2, wantOffset: 5},
+		{T: NewTable(), visibleRows: 10, curr: 0, prev: 1, offset: 5, wantCurr: 0, wantPrev: 1, wantOffset: 4},
+		{T: NewTable(), visibleRows: 10, curr: 0, prev: 1, offset: 0, wantCurr: 0, wantPrev: 1, wantOffset: 0},
 	}
 
 	for _, test := range tests {
@@ -129,11 +131,11 @@ func TestTable_ScrollDown(t *testing.T) {
 		wantPrev   int
 		wantOffset int
 	}{
-		{T: NewTable(false), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 0, prev: 0, offset: 0, wantCurr: 1, wantPrev: 0, wantOffset: 0},
-		{T: NewTable(false), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 1, prev: 0, offset: 0, wantCurr: 1, wantPrev: 0, wantOffset: 1},
-		{T: NewTable(false), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 0, prev: 0, offset: 3, wantCurr: 1, wantPrev: 0, wantOffset: 3},
-		{T: NewTable(false), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 1, prev: 0, offset: 3, wantCurr: 1, wantPrev: 0, wantOffset: 4},
-		{T: NewTable(false), visibleRows: 1, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 2, prev: 1, offset: 5, wantCurr: 2, wantPrev: 1, wantOffset: 5},
+		{T: NewTable(), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 0, prev: 0, offset: 0, wantCurr: 1, wantPrev: 0, wantOffset: 0},
+		{T: NewTable(), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 1, prev: 0, offset: 0, wantCurr: 1, wantPrev: 0, wantOffset: 1},
+		{T: NewTable(), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 0, prev: 0, offset: 3, wantCurr: 1, wantPrev: 0, wantOffset: 3},
+		{T: NewTable(), visibleRows: 2, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 1, prev: 0, offset: 3, wantCurr: 1, wantPrev: 0, wantOffset: 4},
+		{T: NewTable(), visibleRows: 1, out: TableRows{{""}, {""}, {""}, {""}, {""}, {""}}, curr: 2, prev: 1, offset: 5, wantCurr: 2, wantPrev: 1, wantOffset: 5},
 	}
 
 	for _, test := range tests {
@@ -158,3 +160,278 @@ func TestTable_ScrollDown(t *testing.T) {
 		}
 	}
 }
+
+func TestTable_styleRows(t *testing.T) {
+	styler := func(entryRow int, row []string, col int) (termui.Color, bool) {
+		if entryRow == 0 && col == 1 && row[col] == "down" {
+			return termui.ColorRed, true
+		}
+		return termui.ColorClear, false
+	}
+
+	tests := []struct {
+		rows         TableRows
+		rowsPerEntry int
+		offset       int
+		want         TableRows
+	}{
+		{rows: TableRows{{"a", "up"}, {"b", "down"}}, rowsPerEntry: 1, offset: 0, want: TableRows{{"a", "up"}, {"b", "[down](fg:red)"}}},
+		{rows: TableRows{{"a", "down"}, {"", "down"}}, rowsPerEntry: 2, offset: 0, want: TableRows{{"a", "[down](fg:red)"}, {"", "down"}}},
+		{rows: TableRows{{"", "down"}, {"b", "down"}}, rowsPerEntry: 2, offset: 1, want: TableRows{{"", "down"}, {"b", "[down](fg:red)"}}},
+		{rows: TableRows{{"a", ""}}, rowsPerEntry: 1, offset: 0, want: TableRows{{"a", ""}}},
+	}
+
+	for _, test := range tests {
+		table := NewTable()
+		table.InitFilter(0, test.rowsPerEntry)
+		table.CellStyler = styler
+
+		got := table.styleRows(test.rows, test.offset)
+
+		for i := range test.want {
+			for j := range test.want[i] {
+				if got[i][j] != test.want[i][j] {
+					t.Errorf("Error occured got:%v; want:%v", got[i][j], test.want[i][j])
+				}
+			}
+		}
+		if test.rows[0][1] == "[down](fg:red)" {
+			t.Errorf("Error occured, original rows were modified")
+		}
+	}
+}
+
+type testSource []string
+
+func (s testSource) Len() int                     { return len(s) }
+func (s testSource) FilterValue(entry int) string { return s[entry] }
+func (s testSource) EntryRows(entry int) TableRows {
+	return TableRows{{s[entry], "1"}, {"", "2"}}
+}
+
+func TestTable_sourceRows(t *testing.T) {
+	tests := []struct {
+		source  testSource
+		filter  string
+		from    int
+		to      int
+		want    TableRows
+		wantLen int
+	}{
+		{source: testSource{"a", "b", "c"}, from: 0, to: 2, want: TableRows{{"a", "1"}, {"", "2"}}, wantLen: 6},
+		{source: testSource{"a", "b", "c"}, from: 1, to: 4, want: TableRows{{"", "2"}, {"b", "1"}, {"", "2"}}, wantLen: 6},
+		{source: testSource{"a", "b", "c"}, filter: "c", from: 0, to: 1, want: TableRows{{"c", "1"}}, wantLen: 2},
+		{source: testSource{"ab", "b", "cb"}, filter: "b", from: 3, to: 6, want: TableRows{{"", "2"}, {"cb", "1"}, {"", "2"}}, wantLen: 6},
+	}
+
+	for _, test := range tests {
+		table := NewTable()
+		table.InitFilter(0, 2)
+		table.AppendToFilter(test.filter)
+		table.Source = test.source
+		table.filterSource()
+
+		if got := table.rowCount(); got != test.wantLen {
+			t.Errorf("Error occured row count do not match got:%v; want:%v\n", got, test.wantLen)
+		}
+
+		got := table.rows(test.from, test.to)
+		if len(got) != len(test.want) {
+			t.Fatalf("Error occured rows do not match got:%v; want:%v\n", got, test.want)
+		}
+		for i := range test.want {
+			for j := range test.want[i] {
+				if got[i][j] != test.want[i][j] {
+					t.Errorf("Error occured got:%v; want:%v", got[i][j], test.want[i][j])
+				}
+			}
+		}
+	}
+}
+
+type testColorSource struct {
+	testSource
+}
+
+func (s testColorSource) CellColor(entry, entryRow, col int) (termui.Color, bool) {
+	if s.testSource[entry] == "b" && entryRow == 1 && col == 1 {
+		return termui.ColorYellow, true
+	}
+	return termui.ColorClear, false
+}
+
+func TestTable_sourceStyleRows(t *testing.T) {
+	styler := func(entryRow int, row []string, col int) (termui.Color, bool) {
+		if col == 1 {
+			return termui.ColorRed, true
+		}
+		return termui.ColorClear, false
+	}
+
+	tests := []struct {
+		filter string
+		styler CellStyler
+		from   int
+		to     int
+		want   TableRows
+	}{
+		{from: 2, to: 4, want: TableRows{{"b", "1"}, {"", "[2](fg:yellow)"}}},
+		{from: 1, to: 4, styler: styler, want: TableRows{{"", "[2](fg:red)"}, {"b", "[1](fg:red)"}, {"", "[2](fg:yellow)"}}},
+		{filter: "b", from: 0, to: 2, want: TableRows{{"b", "1"}, {"", "[2](fg:yellow)"}}},
+		{filter: "c", from: 0, to: 2, want: TableRows{{"c", "1"}, {"", "2"}}},
+	}
+
+	for _, test := range tests {
+		table := NewTable()
+		table.InitFilter(0, 2)
+		table.AppendToFilter(test.filter)
+		table.Source = testColorSource{testSource{"a", "b", "c"}}
+		table.CellStyler = test.styler
+		table.filterSource()
+
+		got := table.styleRows(table.rows(test.from, test.to), test.from)
+		if len(got) != len(test.want) {
+			t.Fatalf("Error occured rows do not match got:%v; want:%v\n", got, test.want)
+		}
+		for i := range test.want {
+			for j := range test.want[i] {
+				if got[i][j] != test.want[i][j] {
+					t.Errorf("Error occured got:%v; want:%v", got[i][j], test.want[i][j])
+				}
+			}
+		}
+	}
+}
+
+func TestTable_Position(t *testing.T) {
+	tests := []struct {
+		rows         TableRows
+		rowsPerEntry int
+		filter       string
+		visibleRows  int
+		offset       int
+		// output (want)
+		wantFirst, wantLast, wantShown, wantTotal int
+	}{
+		{rows: TableRows{{"a"}, {"b"}, {"c"}, {"d"}}, rowsPerEntry: 1, visibleRows: 2, offset: 1,
+			wantFirst: 2, wantLast: 3, wantShown: 4, wantTotal: 4},
+		{rows: TableRows{{"ab"}, {"b"}, {"c"}, {"cb"}}, rowsPerEntry: 1, filter: "b", visibleRows: 5,
+			wantFirst: 1, wantLast: 3, wantShown: 3, wantTotal: 4},
+		{rows: TableRows{{"a"}, {""}, {"b"}, {""}, {"c"}, {""}}, rowsPerEntry: 2, visibleRows: 3, offset: 2,
+			wantFirst: 2, wantLast: 3, wantShown: 3, wantTotal: 3},
+		{rows: TableRows{{"a"}, {"b"}}, rowsPerEntry: 1, filter: "x", visibleRows: 5,
+			wantFirst: 0, wantLast: 0, wantShown: 0, wantTotal: 2},
+		{rows: TableRows{{"", ""}}, rowsPerEntry: 1, visibleRows: 5,
+			wantFirst: 0, wantLast: 0, wantShown: 0, wantTotal: 0},
+	}
+
+	for _, test := range tests {
+		table := NewTable()
+		table.InitFilter(0, test.rowsPerEntry)
+		table.AppendToFilter(test.filter)
+		table.Rows = test.rows
+		table.filterRows()
+		table.visibleRows = test.visibleRows
+		table.offset = test.offset
+
+		first, last, shown, total := table.Position()
+		if first != test.wantFirst || last != test.wantLast || shown != test.wantShown || total != test.wantTotal {
+			t.Errorf("Error occured got:%v-%v of %v (%v); want:%v-%v of %v (%v)", first, last, shown, total,
+				test.wantFirst, test.wantLast, test.wantShown, test.wantTotal)
+		}
+	}
+}
+
+func TestTable_SetRowsPerEntry(t *testing.T) {
+	table := NewTable()
+	table.InitFilter(0, 2)
+	table.Rows = TableRows{{"a"}, {""}, {"b"}, {""}, {"c"}, {""}}
+	table.filterRows()
+	table.visibleRows = 4
+	table.offset, table.curr = 2, 1
+
+	table.SetRowsPerEntry(1)
+	table.Rows = TableRows{{"a"}, {"b"}, {"c"}}
+	table.filterRows()
+	if table.offset != 0 || table.curr != 0 {
+		t.Errorf("Error occured got offset:%d curr:%d; want:0 0", table.offset, table.curr)
+	}
+	first, last, shown, total := table.Position()
+	if first != 1 || last != 3 || shown != 3 || total != 3 {
+		t.Errorf("Error occured got:%v-%v of %v (%v); want:1-3 of 3 (3)", first, last, shown, total)
+	}
+}
+
+func TestTable_followSelected(t *testing.T) {
+	tests := []struct {
+		rows         TableRows
+		rowsPerEntry int
+		height       int
+		selected     string
+		// input
+		curr   int
+		offset int
+		// output (want)
+		wantCurr   int
+		wantOffset int
+	}{
+		{rows: TableRows{{"b"}, {"a"}, {"c"}}, rowsPerEntry: 1, height: 5, selected: "a", curr: 0,
+			wantCurr: 1, wantOffset: 0},
+		{rows: TableRows{{"a"}, {"b"}, {"c"}}, rowsPerEntry: 1, height: 5, selected: "x", curr: 2,
+			wantCurr: 2, wantOffset: 0},
+		{rows: TableRows{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}, rowsPerEntry: 1, height: 4, selected: "e", curr: 0,
+			wantCurr: 1, wantOffset: 3},
+		{rows: TableRows{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}, rowsPerEntry: 1, height: 4, selected: "a", curr: 1, offset: 3,
+			wantCurr: 0, wantOffset: 0},
+		{rows: TableRows{{"c"}, {""}, {"a"}, {""}, {"b"}, {""}}, rowsPerEntry: 2, height: 6, selected: "a", curr: 1,
+			wantCurr: 3, wantOffset: 0},
+		{rows: TableRows{{"a"}, {"b"}}, rowsPerEntry: 1, height: 5, curr: 1,
+			wantCurr: 1, wantOffset: 0},
+	}
+
+	for _, test := range tests {
+		table := NewTable()
+		table.InitFilter(0, test.rowsPerEntry)
+		table.Rows = test.rows
+		table.filterRows()
+		table.height = test.height
+		table.selected = test.selected
+		table.curr = test.curr
+		table.offset = test.offset
+
+		table.followSelected()
+
+		if table.curr != test.wantCurr {
+			t.Errorf("Error occured curr do not match got:%v; want:%v\n", table.curr, test.wantCurr)
+		}
+
+		if table.offset != test.wantOffset {
+			t.Errorf("Error occured offset do not match got:%v; want:%v\n", table.offset, test.wantOffset)
+		}
+	}
+}
+
+func TestTable_pinned(t *testing.T) {
+	table := NewTable()
+	table.InitFilter(0, 2)
+	table.AppendToFilter("tap")
+	table.SetPinned(1)
+	table.Rows = TableRows{{"loop0", "1"}, {"", "2"}, {"tap0", "1"}, {"", "2"}, {"local0", "1"}, {"", "2"}}
+	table.filterRows()
+
+	got := table.markPinned(table.out, 0)
+	want := TableRows{{PinMarker + "loop0", "1"}, {"", "2"}, {"tap0", "1"}, {"", "2"}}
+	if len(got) != len(want) {
+		t.Fatalf("Error occured rows do not match got:%v; want:%v\n", got, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("Error occured got:%v; want:%v", got[i][j], want[i][j])
+			}
+		}
+	}
+	if table.Rows[0][0] != "loop0" {
+		t.Errorf("Error occured rows modified got:%v; want:%v", table.Rows[0][0], "loop0")
+	}
+}
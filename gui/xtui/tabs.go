@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xtui
+
+import (
+	"image"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+)
+
+// TabPane is a widgets.TabPane with tabs which can be disabled (e.g. the data
+// of the tab is not available), names of the disabled tabs are greyed out.
+type TabPane struct {
+	*widgets.TabPane
+	// DisabledTabStyle is the style of the names of the disabled tabs.
+	DisabledTabStyle termui.Style
+	// disabled tabs by their indexes
+	disabled map[int]bool
+}
+
+// NewTabPane returns a tab pane with the tab names, no tab is disabled.
+func NewTabPane(names ...string) *TabPane {
+	return &TabPane{
+		TabPane:          widgets.NewTabPane(names...),
+		DisabledTabStyle: termui.Theme.Tab.Inactive,
+		disabled:         make(map[int]bool),
+	}
+}
+
+// SetDisabled replaces the disabled tabs.
+func (t *TabPane) SetDisabled(tabs ...int) {
+	t.Lock()
+	defer t.Unlock()
+	t.disabled = make(map[int]bool, len(tabs))
+	for _, tab := range tabs {
+		t.disabled[tab] = true
+	}
+}
+
+// IsDisabled returns true if the tab is disabled.
+func (t *TabPane) IsDisabled(tab int) bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.disabled[tab]
+}
+
+// Draw draws the tab pane, the names of the disabled tabs (except for
+// the active one) are drawn over in the disabled style.
+func (t *TabPane) Draw(buf *termui.Buffer) {
+	t.TabPane.Draw(buf)
+	x := t.Inner.Min.X
+	for i, name := range t.TabNames {
+		if x >= t.Inner.Max.X {
+			break
+		}
+		if t.disabled[i] && i != t.ActiveTabIndex {
+			buf.SetString(termui.TrimString(name, t.Inner.Max.X-x), t.DisabledTabStyle, image.Pt(x, t.Inner.Min.Y))
+		}
+		// the name is followed by a separator between two spaces
+		x += len(name) + 3
+	}
+}
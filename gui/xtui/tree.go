@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xtui
+
+import (
+	"strings"
+)
+
+// TreeNode is an entry of the TreeRows, its children are shown
+// below the node while the node is expanded.
+type TreeNode struct {
+	// Key identifies the node across the updates of the rows.
+	Key string
+	// Row of the node.
+	Row []string
+	// Children of the node.
+	Children []TreeNode
+}
+
+// treeEntry is a visible node of the tree.
+type treeEntry struct {
+	node     *TreeNode
+	depth    int
+	expanded bool
+	// root is the key of the top level node of the entry.
+	root string
+}
+
+// TreeRows is a RowSource showing the nodes as a tree of single row entries.
+// The tree column shows whether the nodes with children are expanded (-)
+// or collapsed (+), the children are indented below their parent.
+type TreeRows struct {
+	col     int
+	entries []treeEntry
+}
+
+// NewTreeRows returns the rows of the visible nodes with the tree drawn
+// in the column col. The children of a node are visible if the node
+// is visible and expanded returns true for its key.
+func NewTreeRows(nodes []TreeNode, col int, expanded func(key string) bool) *TreeRows {
+	r := &TreeRows{col: col}
+	var walk func(nodes []TreeNode, depth int, root string)
+	walk = func(nodes []TreeNode, depth int, root string) {
+		for i := range nodes {
+			node := &nodes[i]
+			if depth == 0 {
+				root = node.Key
+			}
+			entry := treeEntry{node: node, depth: depth, root: root}
+			entry.expanded = len(node.Children) != 0 && expanded(node.Key)
+			r.entries = append(r.entries, entry)
+			if entry.expanded {
+				walk(node.Children, depth+1, root)
+			}
+		}
+	}
+	walk(nodes, 0, "")
+	return r
+}
+
+// Len returns the number of visible nodes.
+func (r *TreeRows) Len() int { return len(r.entries) }
+
+// FilterValue returns the key of the top level node of the entry, so that
+// the children are filtered together with their parent and the selected
+// key of a child is the key of its parent.
+func (r *TreeRows) FilterValue(entry int) string { return r.entries[entry].root }
+
+// EntryRows returns the row of the node with the tree column decorated.
+func (r *TreeRows) EntryRows(entry int) TableRows {
+	e := r.entries[entry]
+	row := make([]string, len(e.node.Row))
+	copy(row, e.node.Row)
+	if r.col >= len(row) {
+		return TableRows{row}
+	}
+	prefix := strings.Repeat("  ", e.depth)
+	switch {
+	case len(e.node.Children) == 0:
+		prefix += "  "
+	case e.expanded:
+		prefix += "- "
+	default:
+		prefix += "+ "
+	}
+	row[r.col] = prefix + row[r.col]
+	return TableRows{row}
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xtui
+
+import (
+	"testing"
+)
+
+func TestTreeRows_EntryRows(t *testing.T) {
+	nodes := []TreeNode{
+		{Key: "ip4-input", Row: []string{"12", "ip4-input"}, Children: []TreeNode{
+			{Key: "ip4-input/ttl", Row: []string{"10", ""}},
+			{Key: "ip4-input/checksum", Row: []string{"2", ""}},
+		}},
+		{Key: "arp-reply", Row: []string{"1", "arp-reply"}, Children: []TreeNode{
+			{Key: "arp-reply/sent", Row: []string{"1", ""}},
+		}},
+		{Key: "dpdk-input", Row: []string{"0", "dpdk-input"}},
+	}
+	tests := []struct {
+		expanded map[string]bool
+		want     []string
+		roots    []string
+	}{
+		{
+			expanded: map[string]bool{},
+			want:     []string{"+ ip4-input", "+ arp-reply", "  dpdk-input"},
+			roots:    []string{"ip4-input", "arp-reply", "dpdk-input"},
+		},
+		{
+			expanded: map[string]bool{"ip4-input": true},
+			want:     []string{"- ip4-input", "    ", "    ", "+ arp-reply", "  dpdk-input"},
+			roots:    []string{"ip4-input", "ip4-input", "ip4-input", "arp-reply", "dpdk-input"},
+		},
+	}
+
+	for _, test := range tests {
+		rows := NewTreeRows(nodes, 1, func(key string) bool { return test.expanded[key] })
+		if rows.Len() != len(test.want) {
+			t.Fatalf("Error occured got:%v entries; want:%v", rows.Len(), len(test.want))
+		}
+		for i, want := range test.want {
+			if got := rows.EntryRows(i)[0][1]; got != want {
+				t.Errorf("Error occured got:%q; want:%q", got, want)
+			}
+			if got := rows.FilterValue(i); got != test.roots[i] {
+				t.Errorf("Error occured got:%v; want:%v", got, test.roots[i])
+			}
+		}
+	}
+}
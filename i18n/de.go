@@ -0,0 +1,321 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n
+
+// german is the German catalog.
+var german = map[string]string{
+	// tabs
+	"Interfaces":  "Schnittstellen",
+	"Nodes":       "Knoten",
+	"Errors":      "Fehler",
+	"Memory":      "Speicher",
+	"Tunnels":     "Tunnel",
+	"Sessions":    "Sitzungen",
+	"Neighbors":   "Nachbarn",
+	"API Trace":   "API-Trace",
+	"Capture":     "Mitschnitt",
+	"Diagnostics": "Diagnose",
+
+	// columns
+	"State":                             "Zustand",
+	"Admin":                             "Verwaltung",
+	"Calls":                             "Aufrufe",
+	"Vectors":                           "Vektoren",
+	"Clocks":                            "Takte",
+	"Vectors/Calls":                     "Vektoren/Aufrufe",
+	"Calls/s":                           "Aufrufe/s",
+	"Vectors/s":                         "Vektoren/s",
+	"MaxClocks":                         "MaxTakte",
+	"Vectors@Max":                       "Vektoren@Max",
+	"Clocks%":                           "Takte%",
+	"+Calls":                            "+Aufrufe",
+	"+Vectors":                          "+Vektoren",
+	"+Clocks":                           "+Takte",
+	"Clocks/V (base -> now)":            "Takte/V (Basis -> jetzt)",
+	"MTU(L3/IP4/IP6/MPLS)/Device":       "MTU(L3/IP4/IP6/MPLS)/Gerät",
+	"RxCounters":                        "RxZähler",
+	"RxCount":                           "RxAnzahl",
+	"TxCounters":                        "TxZähler",
+	"TxCount":                           "TxAnzahl",
+	"Instance":                          "Instanz",
+	"Counter":                           "Zähler",
+	"Node":                              "Knoten",
+	"Reason":                            "Grund",
+	"Severity":                          "Schweregrad",
+	"Current memory usage per Thread":   "Aktuelle Speichernutzung pro Thread",
+	"Type":                              "Typ",
+	"Core":                              "Kern",
+	"Affinity":                          "Affinität",
+	"Rx Interfaces (queues, packets/s)": "Rx-Schnittstellen (Queues, Pakete/s)",
+	"Count":                             "Anzahl",
+	"Count/s":                           "Anzahl/s",
+	"Source":                            "Quelle",
+	"Destination":                       "Ziel",
+	"Protocol":                          "Protokoll",
+	"Interface":                         "Schnittstelle",
+	"Member":                            "Mitglied",
+	"Mode/LB":                           "Modus/LB",
+	"Active":                            "Aktiv",
+	"Mux State":                         "Mux-Zustand",
+	"Rx Share":                          "Rx-Anteil",
+	"Tx Share":                          "Tx-Anteil",
+	"Actions (conform/exceed/violate)":  "Aktionen (conform/exceed/violate)",
+	"Routes":                            "Routen",
+	"HostRoutes":                        "Host-Routen",
+	"Change":                            "Änderung",
+	"Host Routes (FIB memory: %s)":      "Host-Routen (FIB-Speicher: %s)",
+	"IPv4 %s incl. mtrie, IPv6 %s":      "IPv4 %s inkl. mtrie, IPv6 %s",
+	"Age":                               "Alter",
+	"Kind":                              "Art",
+	"Behavior":                          "Verhalten",
+	"Segment Lists":                     "Segmentlisten",
+	"Steering":                          "Steuerung",
+	"Packets/s":                         "Pakete/s",
+	"Bad":                               "Fehlerhaft",
+	"Message":                           "Nachricht",
+	"Details (trace: %s, Ctrl-T to toggle, Ctrl-O to save)": "Details (Trace: %s, Ctrl-T zum Umschalten, Ctrl-O zum Speichern)",
+	"Packets": "Pakete",
+	"File":    "Datei",
+	"Status (Ctrl-T to start/stop the selected capture)": "Status (Ctrl-T startet/stoppt den ausgewählten Mitschnitt)",
+	"Description": "Beschreibung",
+	"unknown":     "unbekannt",
+	"on":          "an",
+	"off":         "aus",
+
+	// notification history
+	"Notifications (Esc to close)": "Benachrichtigungen (Esc zum Schließen)",
+	"no notifications":             "keine Benachrichtigungen",
+	"info":                         "Info",
+	"warning":                      "Warnung",
+	"error":                        "Fehler",
+
+	// background requests
+	"toggling %s capture failed: %v": "Umschalten des Mitschnitts %s fehlgeschlagen: %v",
+	"toggling api trace failed: %v":  "Umschalten des API-Trace fehlgeschlagen: %v",
+	"saving api trace failed: %v":    "Speichern des API-Trace fehlgeschlagen: %v",
+	"api trace saved to %s":          "API-Trace gespeichert in %s",
+	"exporting tab %s failed: %v":    "Export des Tabs %s fehlgeschlagen: %v",
+	"tab %s exported to %s":          "Tab %s exportiert nach %s",
+
+	// failed polls
+	"polling failed %d× since %s: %v, %s": "Abfrage %d× fehlgeschlagen seit %s: %v, %s",
+	"last update %s":                      "letzte Aktualisierung %s",
+	"no data polled yet":                  "noch keine Daten abgefragt",
+
+	// alerts
+	"alert %q fired, %d %s stats match": "Alarm %q ausgelöst, %d %s-Statistiken passen",
+
+	// cleared counters
+	"cleared: %s":                    "Geleert: %s",
+	"clear failed: %s (see the log)": "Leeren fehlgeschlagen: %s (siehe Log)",
+	"interface stats":                "Schnittstellenstatistiken",
+	"node stats":                     "Knotenstatistiken",
+	"error stats":                    "Fehlerstatistiken",
+	"api trace":                      "API-Trace",
+
+	// notifications
+	"clearing tab: %s":           "Tab wird geleert: %s",
+	"refreshing tab: %s":         "Tab wird aktualisiert: %s",
+	"exporting tab: %s":          "Tab wird exportiert: %s",
+	"saving tab: %s":             "Tab wird gespeichert: %s",
+	"units: human readable":      "Einheiten: lesbar",
+	"units: raw":                 "Einheiten: roh",
+	"toggling trace":             "Trace wird umgeschaltet",
+	"toggling grouping":          "Gruppierung wird umgeschaltet",
+	"toggling zero entries":      "Null-Einträge werden umgeschaltet",
+	"toggling baseline":          "Basislinie wird umgeschaltet",
+	"updates: paused":            "Aktualisierung: angehalten",
+	"updates: resumed":           "Aktualisierung: fortgesetzt",
+	"measurement: started":       "Messung: gestartet",
+	"measurement: done":          "Messung: fertig",
+	"column: %s (+/- to resize)": "Spalte: %s (+/- ändert die Breite)",
+	"split view: off":            "geteilte Ansicht: aus",
+	"split view: %s | %s":        "geteilte Ansicht: %s | %s",
+	"split: %s | %s":             "geteilt: %s | %s",
+	"Sort by":                    "Sortieren nach",
+	"Exit:%v filter:":            "Verlassen:%v Filter:",
+	"Closing..":                  "Wird beendet..",
+	"rows %d–%d of %d":           "Zeilen %d–%d von %d",
+	"rows 0 of 0":                "Zeilen 0 von 0",
+	"(filtered from %d)":         "(gefiltert aus %d)",
+	"sort: %s":                   "sortiert: %s",
+	"Connection failed":          "Verbindung fehlgeschlagen",
+	"Disconnected":               "Getrennt",
+	"Not responding":             "Reagiert nicht",
+	"Connected":                  "Verbunden",
+	"VPP version: %s":            "VPP-Version: %s",
+	"%s failed":                  "%s fehlgeschlagen",
+
+	// proxy
+	"Proxy: %s":                      "Proxy: %s",
+	"Proxy unreachable, retry in %v": "Proxy nicht erreichbar, neuer Versuch in %v",
+
+	// measurement
+	"Measuring: %v left":                "Messung: noch %v",
+	"Measured: %v window (p to resume)": "Gemessen: Fenster %v (p setzt fort)",
+
+	// details
+	"Errors: %s (Esc to close)":                                      "Fehler: %s (Esc zum Schließen)",
+	"per thread counters are not available":                          "Zähler pro Thread sind nicht verfügbar",
+	"per thread counters (summed up over the queues of the thread):": "Zähler pro Thread (über die Queues des Threads summiert):",
+	"no error counters of the interface nodes":                       "keine Fehlerzähler der Knoten der Schnittstelle",
+
+	// LLDP
+	"Peer: never heard (%s)":                 "Gegenstelle: nie empfangen (%s)",
+	"Peer: %s port %s (%s, heard %.1fs ago)": "Gegenstelle: %s Port %s (%s, empfangen vor %.1fs)",
+
+	// diagnostics
+	"Last":                        "Zuletzt",
+	"Avg":                         "Mittel",
+	"Skipped":                     "Übersprungen",
+	"Heap allocated":              "Heap belegt",
+	"Heap reserved":               "Heap reserviert",
+	"Memory obtained from the OS": "Speicher vom OS",
+	"Goroutines":                  "Goroutinen",
+	"GC pause":                    "GC-Pause",
+	"poll: %s":                    "Abfrage: %s",
+	"request: %s":                 "Anfrage: %s",
+
+	// events
+	"Interface events (Esc to close)": "Schnittstellenereignisse (Esc zum Schließen)",
+	"no interface events":             "keine Schnittstellenereignisse",
+	"%s: state %s → %s":               "%s: Zustand %s → %s",
+	"%s: link %s → %s":                "%s: Link %s → %s",
+	"%s: MTU %s → %s":                 "%s: MTU %s → %s",
+	"%s: address %s added":            "%s: Adresse %s hinzugefügt",
+	"%s: address %s removed":          "%s: Adresse %s entfernt",
+
+	// quick filter
+	"filter: off": "Filter: aus",
+	"filter: %s":  "Filter: %s",
+
+	// help
+	"Help: %s (%s)":                     "Hilfe: %s (%s)",
+	"default":                           "Standard",
+	"sort":                              "Sortieren",
+	"filter":                            "Filter",
+	"palette":                           "Befehlspalette",
+	"any key":                           "beliebige Taste",
+	"quit":                              "beenden",
+	"open the menu to sort by a column": "Menü zum Sortieren nach einer Spalte öffnen",
+	"scroll the table":                  "Tabelle scrollen",
+	"skip pages of the table":           "Seiten der Tabelle überspringen",
+	"switch tabs":                       "Tabs wechseln",
+	"filter the table":                  "Tabelle filtern",
+	"clear counters":                    "Zähler zurücksetzen",
+	"refresh (re-dump) the data":        "Daten aktualisieren (neu abfragen)",
+	"toggle human readable units":       "lesbare Einheiten umschalten",
+	"toggle the VPP binary API trace (the selected packet capture at the capture tab)": "Trace der binären VPP-API umschalten (im Mitschnitt-Tab den ausgewählten Mitschnitt)",
+	"save the table": "Tabelle speichern",
+	"toggle grouping (sub-interfaces, errors by node/thread)":                           "Gruppierung umschalten (Sub-Schnittstellen, Fehler nach Knoten/Thread)",
+	"hide/show nodes with zero calls and vectors":                                       "Knoten ohne Aufrufe und Vektoren aus-/einblenden",
+	"mark/reset the baseline the node counters are compared to":                         "Basislinie für den Vergleich der Knotenzähler setzen/zurücksetzen",
+	"expand/collapse the selected group when grouping is enabled":                       "ausgewählte Gruppe auf-/zuklappen, wenn die Gruppierung aktiv ist",
+	"select a column to be resized":                                                     "Spalte zum Ändern der Breite auswählen",
+	"widen/narrow the selected column":                                                  "ausgewählte Spalte verbreitern/verschmälern",
+	"split the screen to show two tabs side by side":                                    "Bildschirm teilen, um zwei Tabs nebeneinander anzuzeigen",
+	"move the focus to the other pane of the split screen":                              "Fokus in die andere Hälfte des geteilten Bildschirms verschieben",
+	"pause/resume the updates of the tabs":                                              "Aktualisierung der Tabs anhalten/fortsetzen",
+	"clear the counters and measure them for a time window":                             "Zähler löschen und für ein Zeitfenster messen",
+	"export the data of the table as JSON":                                              "Daten der Tabelle als JSON exportieren",
+	"keep the filter and close the filter bar":                                          "Filter behalten und Filterleiste schließen",
+	"cancel the filter":                                                                 "Filter verwerfen",
+	"delete the last character":                                                         "letztes Zeichen löschen",
+	"append to the filter":                                                              "an den Filter anhängen",
+	"close the menu and restore the applied sort":                                       "Menü schließen und die angewendete Sortierung wiederherstellen",
+	"apply the sort by the selected column (reverse the order if sorted by it already)": "Sortierung nach der ausgewählten Spalte anwenden (Reihenfolge umkehren, wenn bereits danach sortiert)",
+	"select a column and preview the sort":                                              "Spalte auswählen und Sortierung vorschauen",
+	"select the last/first column and preview the sort":                                 "letzte/erste Spalte auswählen und Sortierung vorschauen",
+	"close the help":  "Hilfe schließen",
+	"scroll the help": "Hilfe scrollen",
+	"show the error details of the selected entry":         "Fehlerdetails des ausgewählten Eintrags anzeigen",
+	"show/hide the events of the tab":                      "Ereignisse des Tabs ein-/ausblenden",
+	"filter the interfaces of the next VRF":                "Schnittstellen des nächsten VRF filtern",
+	"pin/unpin the selected entry to the top of the table": "Ausgewählten Eintrag oben in der Tabelle anheften/lösen",
+	"show the recent notifications":                        "Letzte Benachrichtigungen anzeigen",
+	"toggle highlighting of the interface rate spikes":     "Hervorhebung von Spitzen der Schnittstellenraten umschalten",
+	"close the popup":                                      "Popup schließen",
+	"scroll the popup":                                     "Popup scrollen",
+
+	// errors totals
+	"Reason (error: %s, warn: %s, info: %s, all: %s/s)": "Grund (error: %s, warn: %s, info: %s, alle: %s/s)",
+
+	// spike highlighting
+	"spike highlighting: on (%g σ)": "Hervorhebung von Spitzen: an (%g σ)",
+	"spike highlighting: off":       "Hervorhebung von Spitzen: aus",
+
+	// command palette
+	"open the command palette":   "Befehlspalette öffnen",
+	"close the palette":          "Palette schließen",
+	"run the selected action":    "ausgewählte Aktion ausführen",
+	"select an action":           "Aktion auswählen",
+	"type to search the actions": "tippen, um Aktionen zu suchen",
+	"switch to the tab %s":       "zum Tab %s wechseln",
+	"sort by %s":                 "nach %s sortieren",
+	"Command: %s":                "Befehl: %s",
+	"no matching action":         "keine passende Aktion",
+
+	// mpls tab
+	"Label (FIB entries: %d, tunnels: %d)": "Label (FIB-Einträge: %d, Tunnel: %d)",
+	"Table":                                "Tabelle",
+	"Next Hops":                            "Next-Hops",
+	"Out Labels":                           "Ausgehende Labels",
+
+	// stats-only mode
+	"not available in the stats-only mode": "im Nur-Statistik-Modus nicht verfügbar",
+
+	// compact interfaces
+	"toggle the compact layout of the interfaces (one row per interface)": "kompaktes Layout der Schnittstellen umschalten (eine Zeile pro Schnittstelle)",
+	"compact interfaces: on":  "kompakte Schnittstellen: an",
+	"compact interfaces: off": "kompakte Schnittstellen: aus",
+
+	// handler capabilities
+	"not supported by the connected VPP": "von der verbundenen VPP nicht unterstützt",
+	"Reason (all: %s/s)":                 "Grund (alle: %s/s)",
+
+	// api clients tab
+	"API Clients": "API-Clients",
+	"Queue":       "Warteschlange",
+	"Health (API segment: %s, to VPP: %s msg/s, to clients: %s msg/s, ring misses: %s)": "Zustand (API-Segment: %s, an VPP: %s Nachr./s, an Clients: %s Nachr./s, Ring-Fehlgriffe: %s)",
+	"%s of %s": "%s von %s",
+
+	// sort preview
+	"Sort: %s": "Sortierung: %s",
+	// interfaces with missing data
+	"(no stats)":   "(keine Statistik)",
+	"(no details)": "(keine Details)",
+	// manual refresh
+	"refresh the tab (with the auto-refresh off)":                      "Tab aktualisieren (bei ausgeschalteter automatischer Aktualisierung)",
+	"toggle the auto-refresh of the tab (refreshed by F5 only if off)": "automatische Aktualisierung des Tabs umschalten (wenn aus, nur mit F5 aktualisiert)",
+	"auto-refresh of %s: on":                                           "automatische Aktualisierung von %s: an",
+	"auto-refresh of %s: off (F5 to refresh)":                          "automatische Aktualisierung von %s: aus (F5 zum Aktualisieren)",
+	"auto-refresh off, last update %s (F5 to refresh)":                 "automatische Aktualisierung aus, letzte Aktualisierung %s (F5 zum Aktualisieren)",
+	"auto-refresh off (F5 to refresh)":                                 "automatische Aktualisierung aus (F5 zum Aktualisieren)",
+	// header sort
+	"sort by the n-th column of the header (reverse the order if sorted by it already)": "nach der n-ten Spalte der Kopfzeile sortieren (umgekehrt, wenn bereits danach sortiert)",
+	"sort by the previous/next column of the header":                                    "nach der vorherigen/nächsten Spalte der Kopfzeile sortieren",
+	"the rows cannot be sorted by column %d":                                            "die Zeilen können nicht nach Spalte %d sortiert werden",
+	"sorted by %s":                                                                      "sortiert nach %s",
+	// memif rings
+	"Ring":       "Ring",
+	"Size":       "Größe",
+	"Occupancy":  "Belegung",
+	"Full polls": "Voll bei Abfragen",
+	"%s full":    "%s voll",
+	// api trace polls
+	"%s, %d CLI polls hidden": "%s, %d CLI-Abfragen ausgeblendet",
+}
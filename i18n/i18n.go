@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package i18n translates the texts shown by the terminal user interface.
+// The English texts are the message IDs, the catalogs of other locales map
+// them to their translations. Texts missing in a catalog are shown in English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale of the message IDs.
+const DefaultLocale = "en"
+
+// catalogs are the translations of the messages per locale.
+var catalogs = map[string]map[string]string{
+	DefaultLocale: {},
+	"de":          german,
+}
+
+var (
+	mu      sync.RWMutex
+	current = catalogs[DefaultLocale]
+)
+
+// Locales returns the supported locales.
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// SetLocale selects the catalog the messages are translated by. The locale
+// is either a language code (e.g. de) or a POSIX locale (e.g. de_DE.UTF-8).
+func SetLocale(locale string) error {
+	catalog, ok := catalogs[language(locale)]
+	if !ok {
+		return fmt.Errorf("unsupported locale %q (supported: %s)", locale, strings.Join(Locales(), ", "))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current = catalog
+	return nil
+}
+
+// DetectLocale returns the locale set by the LC_ALL, LC_MESSAGES or LANG
+// environment variables, or the default locale if it is not supported.
+func DetectLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		if _, ok := catalogs[language(value)]; ok {
+			return language(value)
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// language returns the language code of the locale, e.g. de for de_DE.UTF-8.
+// The C and POSIX locales are English.
+func language(locale string) string {
+	if i := strings.IndexAny(locale, "_.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ToLower(locale)
+	if locale == "c" || locale == "posix" {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// T returns the translation of the message. If arguments are given,
+// the translated message is a format they are formatted by.
+func T(msg string, args ...interface{}) string {
+	mu.RLock()
+	translated, ok := current[msg]
+	mu.RUnlock()
+	if !ok {
+		translated = msg
+	}
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
+
+// Slice returns the translations of the messages.
+func Slice(msgs []string) []string {
+	translated := make([]string, len(msgs))
+	for i, msg := range msgs {
+		translated[i] = T(msg)
+	}
+	return translated
+}
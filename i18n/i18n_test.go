@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+// verbRe matches the formatting verbs of a message.
+var verbRe = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z]`)
+
+func TestCatalogs_Verbs(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for msg, translated := range catalog {
+			want := verbRe.FindAllString(msg, -1)
+			got := verbRe.FindAllString(translated, -1)
+			if len(got) != len(want) {
+				t.Errorf("Error occured locale:%s msg:%q got verbs:%v; want:%v", locale, msg, got, want)
+				continue
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Error occured locale:%s msg:%q got verbs:%v; want:%v", locale, msg, got, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+	tests := []struct {
+		locale string
+		want   string
+		err    bool
+	}{
+		{locale: "en", want: "saving tab: Nodes"},
+		{locale: "C", want: "saving tab: Nodes"},
+		{locale: "de_DE.UTF-8", want: "Tab wird gespeichert: Nodes"},
+		{locale: "xx", err: true},
+	}
+
+	for _, test := range tests {
+		err := SetLocale(test.locale)
+		if (err != nil) != test.err {
+			t.Fatalf("Error occured locale:%s got err:%v; want err:%v", test.locale, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		if got := T("saving tab: %s", "Nodes"); got != test.want {
+			t.Errorf("Error occured locale:%s got:%q; want:%q", test.locale, got, test.want)
+		}
+	}
+}
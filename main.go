@@ -18,12 +18,13 @@ package main
 
 import (
 	"go.pantheon.tech/vpptop/client"
+	"go.pantheon.tech/vpptop/collect"
 	"go.pantheon.tech/vpptop/command"
-	"go.pantheon.tech/vpptop/stats/local"
-	"go.pantheon.tech/vpptop/stats/vpp"
+	"go.pantheon.tech/vpptop/gui"
 )
 
 func main() {
-	client.Defs = append(client.Defs, &local.HandlerDef{}, &vpp.HandlerDef{})
+	defer gui.RecoverPanic()
+	client.Defs = append(client.Defs, collect.DefaultHandlers()...)
 	command.Execute()
 }
@@ -18,6 +18,9 @@ package api
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	govppapi "git.fd.io/govpp.git/api"
 	"git.fd.io/govpp.git/core"
 )
@@ -30,6 +33,12 @@ type VppProviderAPI interface {
 	// with help of remote Address
 	Connect(soc string) error
 	ConnectRemote(rAddr string) error
+	// ConnectHandler uses the handler directly without connecting
+	// to the VPP (e.g. the demo handler)
+	ConnectHandler(handler HandlerAPI) error
+	// ConnectStats connects only the stats socket, the interface, node
+	// and error counters are read directly from the stats segment
+	ConnectStats(soc string) error
 
 	// Disconnect from the VPP
 	Disconnect()
@@ -44,11 +53,52 @@ type VppProviderAPI interface {
 	GetErrors(ctx context.Context) ([]Error, error)
 	GetMemory(ctx context.Context) ([]string, error)
 	GetThreads(ctx context.Context) ([]ThreadData, error)
+	GetDropsPunts(ctx context.Context) ([]DropPunt, error)
+	GetInfo(ctx context.Context) (*VPPInfo, error)
+	GetTunnels(ctx context.Context) ([]TunnelCounters, error)
+	GetAPITrace(ctx context.Context) (*APITrace, error)
+	GetSessions(ctx context.Context) ([]SessionStat, error)
+	GetFeatures(ctx context.Context) ([]FeatureArc, error)
+	GetBonds(ctx context.Context) ([]BondMember, error)
+	GetPolicers(ctx context.Context) ([]Policer, error)
+	GetFib(ctx context.Context) (*FibSummary, error)
+	GetNeighbors(ctx context.Context) ([]Neighbor, error)
+	GetSRv6(ctx context.Context) ([]SRv6SID, error)
+	GetMpls(ctx context.Context) ([]MplsEntry, error)
+	GetAPIClients(ctx context.Context) (*APIClients, error)
+
+	// WatchNeighbors calls the onChange whenever the VPP reports a change of
+	// the neighbor table, until the context is cancelled. ErrNotSupported is
+	// returned if the neighbor events cannot be subscribed.
+	WatchNeighbors(ctx context.Context, onChange func()) error
+
+	// RunCli executes the CLI command and returns its raw output
+	RunCli(ctx context.Context, cmd string) (string, error)
+
+	// Control the binary API trace
+	ToggleAPITrace(ctx context.Context) (enabled bool, err error)
+	SaveAPITrace(ctx context.Context, file string) error
+	ClearAPITrace(ctx context.Context) error
+
+	// Control the packet captures, StopCapture returns the VPP reply
+	// reporting the number of packets written to the output file
+	GetCaptures(ctx context.Context) ([]PacketCapture, error)
+	StartCapture(ctx context.Context, kind CaptureKind, opts CaptureOptions) error
+	StopCapture(ctx context.Context, kind CaptureKind) (string, error)
 
-	// Clear VPP counters
+	// Clear VPP counters, error counters are cleared only
+	// if they match (all of them if match is nil)
 	ClearInterfaceCounters(ctx context.Context) error
 	ClearRuntimeCounters(ctx context.Context) error
-	ClearErrorCounters(ctx context.Context) error
+	ClearErrorCounters(ctx context.Context, match func(Error) bool) error
+
+	// GetRequestStats returns the durations of the handler requests
+	// made since the provider was created, ordered by the request name
+	GetRequestStats() []RequestStats
+
+	// GetCapabilities returns the capabilities of the connected handler,
+	// none before the provider is connected
+	GetCapabilities() Capabilities
 }
 
 // HandlerAPI uses appropriate underlying implementation (either local
@@ -58,6 +108,9 @@ type HandlerAPI interface {
 	// RunCli sends CLI command to VPP
 	RunCli(ctx context.Context, cmd string) (string, error)
 
+	// Ping sends a control ping to the VPP
+	Ping(ctx context.Context) error
+
 	// DumpInterfaces retrieves VPP interface data and returns them as
 	// a northbound interface data
 	DumpInterfaces(ctx context.Context) (map[uint32]*InterfaceDetails, error)
@@ -83,14 +136,93 @@ type HandlerAPI interface {
 	// DumpThreads retrieves info about VPP threads
 	DumpThreads(context.Context) ([]ThreadData, error)
 
+	// DumpPuntStats retrieves punt counters per punt reason
+	DumpPuntStats(context.Context) ([]PuntStat, error)
+
+	// DumpTunnels retrieves vxlan, gtpu and geneve tunnels
+	DumpTunnels(context.Context) ([]Tunnel, error)
+
+	// DumpPolicers retrieves policers with their conform/exceed/violate counters
+	DumpPolicers(context.Context) ([]Policer, error)
+
+	// DumpFibTables retrieves IPv4 and IPv6 FIB tables (VRFs) without their routes
+	DumpFibTables(context.Context) ([]FibTable, error)
+
+	// DumpRxPlacement retrieves the placement of interface rx queues on threads,
+	// ErrNotSupported is returned if the CLI has to be used instead
+	DumpRxPlacement(context.Context) ([]RxPlacement, error)
+
+	// DumpNeighbors retrieves the IPv4 (ARP) and IPv6 (ND) neighbors,
+	// ErrNotSupported is returned if the CLI has to be used instead
+	DumpNeighbors(context.Context) ([]Neighbor, error)
+
+	// WatchNeighbors subscribes to the neighbor events, the onChange is called
+	// for each event until the context is cancelled
+	WatchNeighbors(ctx context.Context, onChange func()) error
+
+	// DumpLLDPNeighbors retrieves the LLDP peers of the physical interfaces,
+	// ErrNotSupported is returned if the lldp plugin is not loaded
+	DumpLLDPNeighbors(context.Context) ([]LLDPNeighbor, error)
+
+	// DumpAPITraceStatus retrieves the status of the binary API trace,
+	// DumpAPITrace the traced messages
+	DumpAPITraceStatus(context.Context) (*APITrace, error)
+	DumpAPITrace(context.Context) ([]APITraceMessage, error)
+
+	// SetAPITrace enables or disables the binary API trace, SaveAPITrace
+	// saves it to the file and FreeAPITrace frees the traced messages
+	SetAPITrace(ctx context.Context, enable bool) error
+	SaveAPITrace(ctx context.Context, file string) error
+	FreeAPITrace(context.Context) error
+
+	// DumpMemifRings retrieves the state of the rings of the memif interfaces,
+	// ErrNotSupported is returned if the memif plugin is not loaded
+	DumpMemifRings(context.Context) ([]MemifRing, error)
+
+	// DumpSRv6 retrieves the SRv6 policies with their steering and the local SIDs,
+	// ErrNotSupported is returned if the VPP has no SRv6 support
+	DumpSRv6(context.Context) ([]SRv6SID, error)
+
+	// DumpMpls retrieves the MPLS tunnels and the MPLS FIB entries with their counters,
+	// ErrNotSupported is returned if the VPP has no MPLS support
+	DumpMpls(context.Context) ([]MplsEntry, error)
+
+	// ClearInterfaceCounters clears the counters of all interfaces by the binary API,
+	// ErrNotSupported is returned if the CLI has to be used instead
+	ClearInterfaceCounters(context.Context) error
+
+	// Capabilities describe the data the handler is able to retrieve
+	// from the connected VPP
+	Capabilities() Capabilities
+
 	// Close the handler gracefully
 	Close()
 }
 
+// ErrNotSupported is returned by handlers which do not support the request.
+var ErrNotSupported = errors.New("not supported by the handler")
+
+// Capabilities describe the data a handler is able to retrieve from the
+// connected VPP, data which is not available should not be requested.
+type Capabilities struct {
+	// CLI is set if the handler runs CLI commands, most of the data
+	// besides the interface, node and error counters is parsed from the CLI
+	CLI bool
+	// ErrorSeverity is set if the error counters have their severity
+	ErrorSeverity bool
+	// Threads is set if the VPP threads can be dumped
+	Threads bool
+	// WorkerStats is set if the interface counters are available per worker
+	// thread (the stats segment is accessed directly)
+	WorkerStats bool
+}
+
 // HandlerDef is a handler definition - it verifies whether the definition is compatible
 // with connected VPP version. If so, the binapi version together with the handler is returned.
 // Remote handler in addition also registers VPP API message type records.
 type HandlerDef interface {
+	// Name returns the name of the handler used to select it explicitly (e.g. local, agent).
+	Name() string
 	IsHandlerCompatible(c *VppClient, isRemote bool) (HandlerAPI, string, error)
 }
 
@@ -102,20 +234,326 @@ type InterfaceDetails struct {
 	Name         string
 	InternalName string
 	SwIfIndex    uint32
+	SupSwIfIndex uint32
 	IsEnabled    bool
+	IsLinkUp     bool
 	IPAddresses  []string
 	MTU          []uint32
+	// VrfIPv4 and VrfIPv6 are the IDs of the FIB tables
+	// the interface is bound to
+	VrfIPv4 uint32
+	VrfIPv6 uint32
+	Device  DeviceDetails
+}
+
+// DeviceDetails contains data about the device of an interface
+type DeviceDetails struct {
+	// MAC is the L2 address of the interface
+	MAC string
+	// Type is the device type (dpdk, memif, tap, af_packet...)
+	Type string
+	// LinkSpeed in kbps (zero if unknown)
+	LinkSpeed uint64
+	// LinkDuplex is either half, full or empty if unknown
+	LinkDuplex string
+}
+
+// LinkDuplexName returns the name of the VPP link duplex value.
+func LinkDuplexName(duplex uint32) string {
+	switch duplex {
+	case 1:
+		return "half"
+	case 2:
+		return "full"
+	}
+	return ""
 }
 
 // Interface contains interface data mandatory for the VPPTop
 // including interface counters
 type Interface struct {
 	govppapi.InterfaceCounters
-	IPAddresses []string
-	State       string
-	MTU         []uint32
+	// SupSwIfIndex is the index of the parent interface of
+	// a sub-interface, or the index of the interface itself
+	SupSwIfIndex uint32
+	IPAddresses  []string
+	// State is the admin state of the interface (up or down)
+	State string
+	// LinkState is the operational state of the link (up or down)
+	LinkState string
+	MTU       []uint32
+	// VrfIPv4 and VrfIPv6 are the IDs of the FIB tables
+	// the interface is bound to
+	VrfIPv4 uint32
+	VrfIPv6 uint32
+	Device  DeviceDetails
+	Workers []WorkerCounters
+	// LLDP is the peer of the physical interface (nil if unknown)
+	LLDP *LLDPNeighbor
+	// MemifRings are the rings of a connected memif interface
+	MemifRings []MemifRing
+	// Instance is the name of the VPP instance of the interface, set only
+	// if interfaces of multiple instances are merged
+	Instance string
+	// NoStats is set if the interface has no counters in the stats
+	// segment yet, all counters are zero
+	NoStats bool
+	// NoDetails is set if the interface was not dumped by the binary API,
+	// its state and details are unknown
+	NoDetails bool
+}
+
+// WorkerCounters contains interface counters of a single worker thread, the
+// counters of all the queues of the interface served by the thread
+type WorkerCounters struct {
+	Worker   int
+	Rx       govppapi.InterfaceCounterCombined
+	Tx       govppapi.InterfaceCounterCombined
+	RxNoBuf  uint64
+	RxMiss   uint64
+	RxErrors uint64
+	TxErrors uint64
+}
+
+// Tunnel contains data about a single vxlan, gtpu or geneve tunnel
+type Tunnel struct {
+	Type      string
+	SwIfIndex uint32
+	Src       string
+	Dst       string
+	// ID is the VNI of vxlan and geneve tunnels or the TEID of gtpu tunnels
+	ID uint32
+}
+
+// Policer contains the configuration and counters of a policer
+type Policer struct {
+	Name string
+	// Type is the policer algorithm, e.g. 1r2c or 2r3c-2698
+	Type string
+	// CIR and EIR are the committed and excess information rates,
+	// CB and EB the burst sizes, both in units of the RateType
+	CIR      uint64
+	EIR      uint64
+	CB       uint64
+	EB       uint64
+	RateType string
+	// actions applied on packets of each color
+	ConformAction string
+	ExceedAction  string
+	ViolateAction string
+	// counters of packets of each color
+	Conform govppapi.InterfaceCounterCombined
+	Exceed  govppapi.InterfaceCounterCombined
+	Violate govppapi.InterfaceCounterCombined
+}
+
+// UnknownTableID is the ID of a FIB table which is not known
+const UnknownTableID = ^uint32(0)
+
+// FibTable contains the summary of a FIB table (VRF) of a single address family
+type FibTable struct {
+	// TableID is the VRF ID, UnknownTableID if not known
+	TableID uint32
+	Name    string
+	IPv6    bool
+	// Routes is the number of FIB entries of the table
+	Routes uint64
+	// HostRoutes is the number of /32 (IPv4) or /128 (IPv6) entries,
+	// usually the resolved neighbors
+	HostRoutes uint64
+}
+
+// FibSummary contains the summary of FIB tables together
+// with the memory used by the FIB
+type FibSummary struct {
+	Tables []FibTable
+	// memory used by the IPv4 and IPv6 unicast FIB in bytes,
+	// the IPv4 memory includes the mtries
+	IP4Memory uint64
+	IP6Memory uint64
+}
+
+// UnknownNeighborAge is the age of a neighbor which is not known
+const UnknownNeighborAge = -1
+
+// Neighbor is a single entry of the IPv4 (ARP) or IPv6 (ND) neighbor table
+type Neighbor struct {
+	SwIfIndex uint32
+	Interface string
+	IP        string
+	MAC       string
+	// Age is the number of seconds since the neighbor
+	// was last updated, UnknownNeighborAge if not known
+	Age float64
+	// Static is set for configured neighbors, dynamic
+	// neighbors are learned from ARP/ND
+	Static bool
+	// NoFibEntry is set if the neighbor is not installed to the FIB
+	NoFibEntry bool
+}
+
+// TunnelCounters contains tunnel data joined with counters
+// of the tunnel interface
+type TunnelCounters struct {
+	Tunnel
+	InterfaceName string
+	Rx            govppapi.InterfaceCounterCombined
+	Tx            govppapi.InterfaceCounterCombined
+	Drops         uint64
+}
+
+// SessionStat is a count of host-stack sessions (or applications)
+// grouped by the app namespace, transport protocol and state
+type SessionStat struct {
+	Namespace string
+	Protocol  string
+	State     string
+	Count     uint64
+}
+
+// FeatureArc contains features enabled on the feature arc of an interface
+type FeatureArc struct {
+	Interface string
+	Arc       string
+	Features  []string
+}
+
+// BondMember contains a member link of a bond interface with its counters
+type BondMember struct {
+	Bond        string
+	Mode        string
+	LoadBalance string
+	Member      string
+	// Active is set if the member is used to transmit
+	Active bool
+	// LACP state of the member, empty if the bond mode is not lacp
+	LACP LACPState
+	Rx   govppapi.InterfaceCounterCombined
+	Tx   govppapi.InterfaceCounterCombined
+}
+
+// LACPState contains the LACP state of a bond member
+type LACPState struct {
+	// Actor and Partner are the set port state flags, e.g. 'act/agg/syn/col/dis'
+	Actor   string
+	Partner string
+	// MuxState is the state of the mux machine, e.g. COLLECTING_DISTRIBUTING
+	MuxState string
 }
 
+// APITrace contains the binary API trace status and traced messages
+type APITrace struct {
+	Enabled  bool
+	Status   string
+	Messages []APITraceMessage
+	// Hidden is the number of the messages polling the VPP by the CLI
+	// (mostly by vpptop itself) left out of the messages
+	Hidden int
+}
+
+// APITraceMessage is a single traced binary API message
+type APITraceMessage struct {
+	Index   int
+	Name    string
+	Details string
+}
+
+// APIClient is a client connected to the binary API either by the shared
+// memory or by the socket
+type APIClient struct {
+	Name   string
+	Socket bool
+	// PID of the shared memory client
+	PID uint32
+	// FD is the file descriptor of the socket client
+	FD int
+	// QueueLength is the number of messages waiting in the input
+	// queue of the shared memory client
+	QueueLength uint64
+	// Health of the shared memory client, "questionable" if it does
+	// not answer the pings of the VPP
+	Health string
+}
+
+// APIRing is a message ring of the API segment, messages are allocated
+// from the ring with the smallest sufficient message size
+type APIRing struct {
+	// Owner of the ring, "vlib" for the messages sent to the VPP,
+	// "clnt" for the messages sent to the clients
+	Owner string
+	// Size of the messages of the ring in bytes
+	Size     uint64
+	Elements uint64
+	// Hits and Misses of the allocations from the ring, the messages
+	// are allocated from the segment heap on a miss
+	Hits   uint64
+	Misses uint64
+}
+
+// APIClients contains the binary API clients together with the message
+// rings and the memory usage of the API segment
+type APIClients struct {
+	Clients []APIClient
+	Rings   []APIRing
+	// RingMissFallbacks is the number of messages allocated from the heap
+	// of the API segment since their ring was exhausted
+	RingMissFallbacks uint64
+	// SegmentSize and SegmentUsed is the memory of the API segment in bytes,
+	// zero if not known
+	SegmentSize uint64
+	SegmentUsed uint64
+}
+
+// CaptureKind is the kind of the VPP packet capture
+type CaptureKind string
+
+// Packet captures controlled by the 'pcap trace' and 'pcap dispatch trace' CLI
+const (
+	PcapTrace     CaptureKind = "pcap"
+	DispatchTrace CaptureKind = "dispatch"
+)
+
+// CaptureKinds are all kinds of the packet captures
+var CaptureKinds = []CaptureKind{PcapTrace, DispatchTrace}
+
+// PacketCapture is the status of a packet capture
+type PacketCapture struct {
+	Kind     CaptureKind
+	Enabled  bool
+	Captured uint64
+	// MaxPackets is the number of packets to capture
+	MaxPackets uint64
+	// File is the path of the output file, if reported by the VPP
+	File   string
+	Status string
+}
+
+// CaptureOptions configure a started packet capture
+type CaptureOptions struct {
+	// Interface restricts the pcap trace to the interface (all interfaces
+	// if empty), the dispatch trace captures packets of all interfaces.
+	Interface  string
+	MaxPackets uint32
+	// File name of the output file, VPP writes it to /tmp
+	File string
+}
+
+// InterfaceCounterSource selects where the interface counters are read from
+type InterfaceCounterSource string
+
+// Sources of the interface counters
+const (
+	// CountersFromStats reads the counters from the stats segment
+	CountersFromStats InterfaceCounterSource = "stats"
+	// CountersFromCLI parses the counters from the 'show interface' CLI
+	// run over the binary API, for VPPs whose stats socket is not accessible.
+	// The VPP 21.01 binary API has no per-interface counter dump.
+	CountersFromCLI InterfaceCounterSource = "cli"
+)
+
+// InterfaceCounterSources are all sources of the interface counters
+var InterfaceCounterSources = []InterfaceCounterSource{CountersFromStats, CountersFromCLI}
+
 // VPPInfo basic information about the connected VPP
 type VPPInfo struct {
 	Connected   bool
@@ -159,6 +597,9 @@ type NodeCounter struct {
 	Node     string `json:"node"`
 	Reason   string `json:"reason"`
 	Severity string `json:"severity"`
+	// PerThread are the counts per thread indexed by the thread index
+	// (0 is the main thread), set only if read from the stats segment
+	PerThread []uint64 `json:"per_thread,omitempty"`
 }
 
 // RuntimeInfo contains telemetry data about VPP runtime
@@ -186,12 +627,22 @@ type RuntimeThread struct {
 type RuntimeItem struct {
 	Index          uint    `json:"index"`
 	Name           string  `json:"name"`
+	Thread         uint    `json:"thread"`
 	State          string  `json:"state"`
 	Calls          uint64  `json:"calls"`
 	Vectors        uint64  `json:"vectors"`
 	Suspends       uint64  `json:"suspends"`
 	Clocks         float64 `json:"clocks"`
 	VectorsPerCall float64 `json:"vectors_per_call"`
+	// MaxClocks are the max clocks per vector of a single call,
+	// VectorsAtMax is the number of vectors of that call
+	MaxClocks    float64 `json:"max_clocks"`
+	VectorsAtMax uint64  `json:"vectors_at_max"`
+	// ClocksPercent is the share of the node in clocks of its thread
+	ClocksPercent float64 `json:"clocks_percent"`
+	// TotalClocks are the clocks spent in the node (Clocks are per vector,
+	// or per call for nodes not processing vectors)
+	TotalClocks float64 `json:"total_clocks"`
 }
 
 // ThreadData wraps all thread data counters.
@@ -203,4 +654,63 @@ type ThreadData struct {
 	CPUID     uint32
 	Core      uint32
 	CPUSocket uint32
+	// Utilization is the estimated CPU utilization in percent
+	// (negative if it could not be estimated)
+	Utilization float64
+	// host data of the thread read from /proc (empty if
+	// not available, i.e. connected via remote proxy)
+	Affinity               string
+	SchedPolicy            string
+	SchedPriority          int
+	VoluntaryCtxSwitches   uint64
+	InvoluntaryCtxSwitches uint64
+	// RxInterfaces are the interfaces with rx queues placed on the thread
+	RxInterfaces []RxInterface
+}
+
+// RxPlacement is a single interface rx queue placed on a thread
+type RxPlacement struct {
+	SwIfIndex uint32
+	// Interface name, set if the SwIfIndex is not known
+	Interface string
+	QueueID   uint32
+	// ThreadID is the index of the thread (0 is the main thread)
+	ThreadID uint32
+}
+
+// RxInterface is an interface with rx queues placed on a thread
+type RxInterface struct {
+	SwIfIndex uint32
+	Name      string
+	Queues    []uint32
+	// RxPackets received by the thread on the interface (zero if the
+	// stats segment is not accessible directly, i.e. via remote proxy)
+	RxPackets uint64
+}
+
+// PuntStat is a single punt reason counter entry
+type PuntStat struct {
+	Index   uint32
+	Reason  string
+	Packets uint64
+	Bytes   uint64
+}
+
+// DropPunt is a single drop or punt counter entry broken down by reason
+type DropPunt struct {
+	Type   string
+	Node   string
+	Reason string
+	Count  uint64
+}
+
+// RequestStats are the durations of the handler requests of the same kind,
+// e.g. DumpInterfaces or RunCli(show memory)
+type RequestStats struct {
+	Request string
+	Calls   uint64
+	Errors  uint64
+	Last    time.Duration
+	Avg     time.Duration
+	Max     time.Duration
 }
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 'api trace' CLI commands
+const (
+	apiTraceOn     = "api trace on"
+	apiTraceOff    = "api trace off"
+	apiTraceStatus = "api trace status"
+	apiTraceDump   = "api trace dump"
+	apiTraceSave   = "api trace save"
+	apiTraceFree   = "api trace free"
+)
+
+var (
+	// first line of a traced message, e.g. "[3]: vl_api_sw_interface_dump_t:"
+	apiTraceMsgRe = regexp.MustCompile(`^\s*(?:\[(\d+)\]:?\s*)?vl_api_(\w+)_t(?::|\s|$)\s*(.*)$`)
+	// traced message in the custom dump format, e.g. "SCRIPT: sw_interface_dump name_filter"
+	apiTraceScriptRe = regexp.MustCompile(`^\s*SCRIPT:\s*(\S+)\s*(.*)$`)
+)
+
+// DumpAPITraceStatus returns the status of the binary API trace parsed
+// from the 'api trace status' output, without the traced messages.
+func DumpAPITraceStatus(ctx context.Context, runCli func(context.Context, string) (string, error)) (*APITrace, error) {
+	status, err := runCli(ctx, apiTraceStatus)
+	if err != nil {
+		return nil, err
+	}
+	return &APITrace{
+		Enabled: isAPITraceEnabled(status),
+		Status:  CompactCliOutput(status),
+	}, nil
+}
+
+// DumpAPITrace returns the traced messages parsed from the 'api trace dump' output.
+func DumpAPITrace(ctx context.Context, runCli func(context.Context, string) (string, error)) ([]APITraceMessage, error) {
+	dump, err := runCli(ctx, apiTraceDump)
+	if err != nil {
+		return nil, err
+	}
+	return parseAPITrace(dump), nil
+}
+
+// SetAPITrace enables or disables the binary API trace.
+func SetAPITrace(ctx context.Context, runCli func(context.Context, string) (string, error), enable bool) error {
+	cmd := apiTraceOff
+	if enable {
+		cmd = apiTraceOn
+	}
+	_, err := runCli(ctx, cmd)
+	return err
+}
+
+// SaveAPITrace saves the binary API trace to the file (VPP saves it to /tmp).
+func SaveAPITrace(ctx context.Context, runCli func(context.Context, string) (string, error), file string) error {
+	reply, err := runCli(ctx, apiTraceSave+" "+file)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(strings.ToLower(reply), "error") {
+		return fmt.Errorf("saving api trace failed: %s", CompactCliOutput(reply))
+	}
+	return nil
+}
+
+// FreeAPITrace frees the traced messages, which disables the trace.
+func FreeAPITrace(ctx context.Context, runCli func(context.Context, string) (string, error)) error {
+	_, err := runCli(ctx, apiTraceFree)
+	return err
+}
+
+// isAPITraceEnabled returns true if the 'api trace status' output
+// reports an enabled trace.
+func isAPITraceEnabled(status string) bool {
+	status = strings.ToLower(status)
+	return strings.Contains(status, "enabled") &&
+		!strings.Contains(status, "disabled") && !strings.Contains(status, "not enabled")
+}
+
+// CompactCliOutput joins the non-empty lines of the CLI output.
+func CompactCliOutput(out string) string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, ", ")
+}
+
+// parseAPITrace parses the 'api trace dump' output. Lines following
+// the first line of a message are joined to its details.
+func parseAPITrace(dump string) []APITraceMessage {
+	var messages []APITraceMessage
+	var details []string
+	flush := func() {
+		if len(messages) != 0 {
+			messages[len(messages)-1].Details = strings.Join(details, " ")
+		}
+		details = nil
+	}
+
+	for _, line := range strings.Split(dump, "\n") {
+		var index, name, rest string
+		if matches := apiTraceMsgRe.FindStringSubmatch(line); matches != nil {
+			index, name, rest = matches[1], matches[2], matches[3]
+		} else if matches := apiTraceScriptRe.FindStringSubmatch(line); matches != nil {
+			name, rest = matches[1], matches[2]
+		} else {
+			if line = strings.TrimSpace(line); line != "" && len(messages) != 0 {
+				details = append(details, line)
+			}
+			continue
+		}
+
+		flush()
+		idx, err := strconv.Atoi(index)
+		if err != nil {
+			idx = len(messages)
+		}
+		messages = append(messages, APITraceMessage{Index: idx, Name: name})
+		if rest = strings.TrimSpace(rest); rest != "" {
+			details = append(details, rest)
+		}
+	}
+	flush()
+
+	return messages
+}
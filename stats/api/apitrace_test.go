@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAPITrace(t *testing.T) {
+	tests := []struct {
+		name string
+		dump string
+		want []APITraceMessage
+	}{
+		{
+			name: "empty trace",
+			dump: "",
+		},
+		{
+			name: "messages",
+			dump: `[0]: vl_api_sw_interface_dump_t:
+  client_index: 0
+  context: 16777216
+  sw_if_index: 4294967295
+  name_filter_valid: 0
+[1]: vl_api_control_ping_t:
+  client_index: 0
+  context: 16777217
+[2]: vl_api_cli_inband_t:
+  client_index: 0
+  context: 16777218
+  cmd: show runtime max
+[3]: vl_api_sw_interface_set_flags_t:
+  client_index: 0
+  context: 16777219
+  sw_if_index: 1
+  flags: 1
+`,
+			want: []APITraceMessage{
+				{Index: 0, Name: "sw_interface_dump", Details: "client_index: 0 context: 16777216 sw_if_index: 4294967295 name_filter_valid: 0"},
+				{Index: 1, Name: "control_ping", Details: "client_index: 0 context: 16777217"},
+				{Index: 2, Name: "cli_inband", Details: "client_index: 0 context: 16777218 cmd: show runtime max"},
+				{Index: 3, Name: "sw_interface_set_flags", Details: "client_index: 0 context: 16777219 sw_if_index: 1 flags: 1"},
+			},
+		},
+		{
+			name: "custom dump",
+			dump: `SCRIPT: sw_interface_dump name_filter
+SCRIPT: cli_inband cmd 'show interface'
+SCRIPT: sw_interface_add_del_address sw_if_index 1 10.0.0.1/24
+`,
+			want: []APITraceMessage{
+				{Index: 0, Name: "sw_interface_dump", Details: "name_filter"},
+				{Index: 1, Name: "cli_inband", Details: "cmd 'show interface'"},
+				{Index: 2, Name: "sw_interface_add_del_address", Details: "sw_if_index 1 10.0.0.1/24"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseAPITrace(test.dump)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("messages: got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
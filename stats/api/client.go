@@ -18,6 +18,7 @@ package api
 
 import (
 	"context"
+	"git.fd.io/govpp.git/adapter"
 	govppapi "git.fd.io/govpp.git/api"
 	"git.fd.io/govpp.git/core"
 	"git.fd.io/govpp.git/proxy"
@@ -28,9 +29,12 @@ import (
 type VppClient struct {
 	vppConn   *core.Connection
 	statsConn govppapi.StatsProvider
+	statsAPI  adapter.StatsAPI
 	client    *proxy.Client
 	vppInfo   VPPInfo
 	apiChan   govppapi.Channel
+	// source of the interface counters read by the handlers
+	ifCounters InterfaceCounterSource
 }
 
 // NewVppClient returns VPP client connected to the VPP via the shared memory
@@ -79,6 +83,32 @@ func (c *VppClient) Stats() govppapi.StatsProvider {
 	return c.statsConn
 }
 
+// SetStatsAPI sets the client of the stats segment, used to read
+// counters directly from the stats segment.
+func (c *VppClient) SetStatsAPI(statsAPI adapter.StatsAPI) {
+	c.statsAPI = statsAPI
+}
+
+// StatsAPI returns the client of the stats segment, or nil
+// if the stats segment is not accessible directly.
+func (c *VppClient) StatsAPI() adapter.StatsAPI {
+	return c.statsAPI
+}
+
+// SetInterfaceCounterSource sets where the handlers read the interface counters from.
+func (c *VppClient) SetInterfaceCounterSource(source InterfaceCounterSource) {
+	c.ifCounters = source
+}
+
+// InterfaceCounterSource returns where the handlers read the interface
+// counters from, the stats segment if not set.
+func (c *VppClient) InterfaceCounterSource() InterfaceCounterSource {
+	if c.ifCounters == "" {
+		return CountersFromStats
+	}
+	return c.ifCounters
+}
+
 func (c *VppClient) IsPluginLoaded(plugin string) bool {
 	for _, p := range c.vppInfo.Plugins {
 		if p.Name == plugin {
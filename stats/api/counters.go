@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	govppapi "git.fd.io/govpp.git/api"
+)
+
+// Regular expressions used to parse the 'show interface' output
+var (
+	// interface line, e.g. "loop0     1     up     9000/0/0/0     rx packets     5"
+	showIfaceRe = regexp.MustCompile(`^(\S+)\s+(\d+)\s+(?:up|down)\s+\d+/\d+/\d+/\d+(.*)$`)
+	// counter and its value, e.g. "rx-unicast packets     5"
+	showIfaceCounterRe = regexp.MustCompile(`^\s*(\S+(?: packets| bytes)?)\s+(\d+)\s*$`)
+)
+
+// ParseInterfaceCounters parses the interface counters from the 'show interface'
+// output. The first counter follows the interface columns, other counters are
+// on separate lines, counters which are zero are omitted by the VPP:
+//
+//	              Name               Idx    State  MTU (L3/IP4/IP6/MPLS)     Counter          Count
+//	loop0                             1      up          9000/0/0/0     rx packets                     5
+//	                                                                    rx bytes                     420
+//	                                                                    drops                          5
+//	local0                            0     down          0/0/0/0
+func ParseInterfaceCounters(out string) *govppapi.InterfaceStats {
+	stats := &govppapi.InterfaceStats{}
+	var iface *govppapi.InterfaceCounters
+	for _, line := range strings.Split(out, "\n") {
+		if m := showIfaceRe.FindStringSubmatch(line); m != nil {
+			idx, _ := strconv.ParseUint(m[2], 10, 32)
+			stats.Interfaces = append(stats.Interfaces, govppapi.InterfaceCounters{
+				InterfaceIndex: uint32(idx),
+				InterfaceName:  m[1],
+			})
+			iface = &stats.Interfaces[len(stats.Interfaces)-1]
+			line = m[3]
+		}
+		m := showIfaceCounterRe.FindStringSubmatch(line)
+		if m == nil || iface == nil {
+			continue
+		}
+		value, _ := strconv.ParseUint(m[2], 10, 64)
+		setInterfaceCounter(iface, m[1], value)
+	}
+	return stats
+}
+
+// setInterfaceCounter sets the counter of the interface by its name
+// in the 'show interface' output, unknown counters are ignored.
+func setInterfaceCounter(iface *govppapi.InterfaceCounters, name string, value uint64) {
+	combined := map[string]*govppapi.InterfaceCounterCombined{
+		"rx":           &iface.Rx,
+		"tx":           &iface.Tx,
+		"rx-unicast":   &iface.RxUnicast,
+		"rx-multicast": &iface.RxMulticast,
+		"rx-broadcast": &iface.RxBroadcast,
+		"tx-unicast":   &iface.TxUnicast,
+		"tx-multicast": &iface.TxMulticast,
+		"tx-broadcast": &iface.TxBroadcast,
+	}
+	if i := strings.IndexByte(name, ' '); i > 0 {
+		counter, ok := combined[name[:i]]
+		if !ok {
+			return
+		}
+		if name[i+1:] == "packets" {
+			counter.Packets = value
+		} else {
+			counter.Bytes = value
+		}
+		return
+	}
+	switch name {
+	case "drops":
+		iface.Drops = value
+	case "punt":
+		iface.Punts = value
+	case "ip4":
+		iface.IP4 = value
+	case "ip6":
+		iface.IP6 = value
+	case "rx-no-buf":
+		iface.RxNoBuf = value
+	case "rx-miss":
+		iface.RxMiss = value
+	case "rx-error":
+		iface.RxErrors = value
+	case "tx-error":
+		iface.TxErrors = value
+	case "mpls":
+		iface.Mpls = value
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	govppapi "git.fd.io/govpp.git/api"
+)
+
+func TestParseInterfaceCounters(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want []govppapi.InterfaceCounters
+	}{
+		{
+			name: "no interfaces",
+			out:  "",
+		},
+		{
+			name: "no counters",
+			out: `              Name               Idx    State  MTU (L3/IP4/IP6/MPLS)     Counter          Count     
+local0                            0     down          0/0/0/0       
+loop0                             1     down         9000/0/0/0     
+`,
+			want: []govppapi.InterfaceCounters{
+				{InterfaceIndex: 0, InterfaceName: "local0"},
+				{InterfaceIndex: 1, InterfaceName: "loop0"},
+			},
+		},
+		{
+			name: "counters",
+			out: `              Name               Idx    State  MTU (L3/IP4/IP6/MPLS)     Counter          Count     
+GigabitEthernet0/8/0              1      up          9000/0/0/0     rx packets                  1529
+                                                                    rx bytes                  146268
+                                                                    tx packets                   915
+                                                                    tx bytes                   88130
+                                                                    drops                        617
+                                                                    punt                           3
+                                                                    ip4                         1516
+                                                                    ip6                           12
+                                                                    rx-unicast packets          1490
+                                                                    rx-unicast bytes          142936
+                                                                    rx-broadcast packets          39
+                                                                    rx-broadcast bytes          3332
+                                                                    tx-unicast packets           915
+                                                                    tx-unicast bytes           88130
+                                                                    rx-miss                        2
+local0                            0     down          0/0/0/0       
+tap0                              2      up          9000/0/0/0     rx packets                     6
+                                                                    rx bytes                     516
+                                                                    drops                          6
+                                                                    ip6                            6
+                                                                    rx-no-buf                      4
+                                                                    tx-error                       1
+`,
+			want: []govppapi.InterfaceCounters{
+				{
+					InterfaceIndex: 1,
+					InterfaceName:  "GigabitEthernet0/8/0",
+					Rx:             govppapi.InterfaceCounterCombined{Packets: 1529, Bytes: 146268},
+					Tx:             govppapi.InterfaceCounterCombined{Packets: 915, Bytes: 88130},
+					RxUnicast:      govppapi.InterfaceCounterCombined{Packets: 1490, Bytes: 142936},
+					RxBroadcast:    govppapi.InterfaceCounterCombined{Packets: 39, Bytes: 3332},
+					TxUnicast:      govppapi.InterfaceCounterCombined{Packets: 915, Bytes: 88130},
+					Drops:          617,
+					Punts:          3,
+					IP4:            1516,
+					IP6:            12,
+					RxMiss:         2,
+				},
+				{InterfaceIndex: 0, InterfaceName: "local0"},
+				{
+					InterfaceIndex: 2,
+					InterfaceName:  "tap0",
+					Rx:             govppapi.InterfaceCounterCombined{Packets: 6, Bytes: 516},
+					Drops:          6,
+					IP6:            6,
+					RxNoBuf:        4,
+					TxErrors:       1,
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ParseInterfaceCounters(test.out).Interfaces
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("interfaces: got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
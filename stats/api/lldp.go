@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LLDPNeighbor is the peer of a physical interface learned by the LLDP.
+type LLDPNeighbor struct {
+	// Interface is the name of the local hardware interface
+	Interface string
+	// ChassisID and PortID identify the peer (e.g. the switch port),
+	// both are empty if no LLDP packet was received
+	ChassisID string
+	PortID    string
+	// LastHeard are the seconds since the last LLDP packet of the peer
+	// (negative if the peer was never heard)
+	LastHeard float64
+	Active    bool
+}
+
+// neighbor line of 'show lldp', e.g.
+// "GigabitEthernet0/8/0   52:54:00:aa:bb:01   Ethernet1/1   12.5   3.0   active"
+var showLLDPRe = regexp.MustCompile(`^(\S+)\s+(.*?)\s*(never|\d+(?:\.\d+)?)\s+\d+(?:\.\d+)?\s+(active|inactive)\s*$`)
+
+// ParseLLDPNeighbors parses the LLDP neighbors from the 'show lldp' output.
+// ErrNotSupported is returned if the lldp plugin is not loaded:
+//
+//	Local interface           Peer chassis ID           Remote port ID             Last heard      Last sent     Status
+//	GigabitEthernet0/8/0      52:54:00:aa:bb:01         Ethernet1/1                   12.5            3.0        active
+//	GigabitEthernet0/9/0                                                             never            3.0       inactive
+func ParseLLDPNeighbors(out string) ([]LLDPNeighbor, error) {
+	if strings.Contains(out, "unknown input") {
+		return nil, ErrNotSupported
+	}
+	var neighbors []LLDPNeighbor
+	for _, line := range strings.Split(out, "\n") {
+		m := showLLDPRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		neighbor := LLDPNeighbor{
+			Interface: m[1],
+			LastHeard: -1,
+			Active:    m[4] == "active",
+		}
+		// the port ID may contain spaces, the chassis ID is mostly a MAC or an IP
+		if peer := strings.Fields(m[2]); len(peer) > 0 {
+			neighbor.ChassisID = peer[0]
+			neighbor.PortID = strings.Join(peer[1:], " ")
+		}
+		if m[3] != "never" {
+			neighbor.LastHeard, _ = strconv.ParseFloat(m[3], 64)
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, nil
+}
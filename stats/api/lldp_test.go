@@ -0,0 +1,65 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLLDPNeighbors(t *testing.T) {
+	const header = "Local interface                Peer chassis ID            Remote port ID         Last heard      Last sent      Status  \n"
+	tests := []struct {
+		name    string
+		out     string
+		want    []LLDPNeighbor
+		wantErr error
+	}{
+		{
+			name: "no neighbors",
+			out:  header,
+		},
+		{
+			name: "neighbors",
+			out: header +
+				"GigabitEthernet0/8/0          52:54:00:aa:bb:01            Ethernet1/1              12.5            3.0         active  \n" +
+				"GigabitEthernet0/9/0              10.20.0.1                 Gi 1/0/24              130.2            3.0        inactive \n" +
+				"TenGigabitEthernet5/0/0                                                            never            3.0        inactive \n",
+			want: []LLDPNeighbor{
+				{Interface: "GigabitEthernet0/8/0", ChassisID: "52:54:00:aa:bb:01", PortID: "Ethernet1/1", LastHeard: 12.5, Active: true},
+				{Interface: "GigabitEthernet0/9/0", ChassisID: "10.20.0.1", PortID: "Gi 1/0/24", LastHeard: 130.2},
+				{Interface: "TenGigabitEthernet5/0/0", LastHeard: -1},
+			},
+		},
+		{
+			name:    "lldp plugin not loaded",
+			out:     "show: unknown input `lldp'\n",
+			wantErr: ErrNotSupported,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseLLDPNeighbors(test.out)
+			if err != test.wantErr {
+				t.Fatalf("error: got %v, want %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("neighbors: got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MemifRing is the state of a shared memory ring of a memif interface.
+type MemifRing struct {
+	// Interface is the name of the memif interface, e.g. memif0/0
+	Interface string
+	// Direction is either slave-to-master or master-to-slave
+	Direction string
+	ID        uint32
+	Size      uint32
+	// Head and Tail are the free running indexes of the producer
+	// and the consumer of the ring
+	Head uint32
+	Tail uint32
+	// FullPolls is the number of polls the ring was found full at
+	// since the interface was first seen, the plugin does not count
+	// the ring full events (set by the provider)
+	FullPolls uint64
+}
+
+// Occupancy returns the number of descriptors filled by the producer
+// and not consumed yet, the indexes of the ring wrap around at 16 bits.
+func (r MemifRing) Occupancy() uint32 {
+	used := uint32(uint16(r.Head - r.Tail))
+	if r.Size != 0 && used > r.Size {
+		return r.Size
+	}
+	return used
+}
+
+// Full returns true if all descriptors of the ring are filled.
+func (r MemifRing) Full() bool {
+	return r.Size != 0 && r.Occupancy() == r.Size
+}
+
+// Regular expressions used to parse the 'show memif' output
+var (
+	// interface header, e.g. "interface memif0/0"
+	memifInterfaceRe = regexp.MustCompile(`^interface (\S+)`)
+	// ring header, e.g. "  master-to-slave ring 0:"
+	memifRingRe = regexp.MustCompile(`^\s+(\S+) ring (\d+):`)
+	// ring region, e.g. "    region 0 offset 16512 ring-size 1024 int-fd 33"
+	memifRingSizeRe = regexp.MustCompile(`\sring-size (\d+)`)
+	// ring descriptor, e.g. "    head 1024 tail 0 flags 0x0001 interrupts 0"
+	memifRingHeadRe = regexp.MustCompile(`^\s+head (\d+) tail (\d+)`)
+)
+
+// ParseMemifRings parses the rings of the memif interfaces from the
+// 'show memif' output, rings of disconnected interfaces are skipped
+// (they have no head and tail). ErrNotSupported is returned if the memif
+// plugin is not loaded. The binary API of the plugin dumps only the ring
+// sizes, not their state:
+//
+//	interface memif0/0
+//	  socket-id 0 id 0 mode ethernet
+//	  flags admin-up connected
+//	  num-s2m-rings 1 num-m2s-rings 1 buffer-size 0 num-regions 1
+//	    master-to-slave ring 0:
+//	      region 0 offset 16512 ring-size 1024 int-fd 33
+//	      head 1024 tail 0 flags 0x0001 interrupts 0
+func ParseMemifRings(out string) ([]MemifRing, error) {
+	if strings.Contains(out, "unknown input") {
+		return nil, ErrNotSupported
+	}
+	var (
+		rings []MemifRing
+		iface string
+		ring  *MemifRing
+	)
+	for _, line := range strings.Split(out, "\n") {
+		if m := memifInterfaceRe.FindStringSubmatch(line); m != nil {
+			iface, ring = m[1], nil
+			continue
+		}
+		if m := memifRingRe.FindStringSubmatch(line); m != nil && iface != "" {
+			id, _ := strconv.ParseUint(m[2], 10, 32)
+			ring = &MemifRing{Interface: iface, Direction: m[1], ID: uint32(id)}
+			continue
+		}
+		if ring == nil {
+			continue
+		}
+		if m := memifRingSizeRe.FindStringSubmatch(line); m != nil {
+			size, _ := strconv.ParseUint(m[1], 10, 32)
+			ring.Size = uint32(size)
+		} else if m := memifRingHeadRe.FindStringSubmatch(line); m != nil {
+			head, _ := strconv.ParseUint(m[1], 10, 32)
+			tail, _ := strconv.ParseUint(m[2], 10, 32)
+			ring.Head, ring.Tail = uint32(head), uint32(tail)
+			rings = append(rings, *ring)
+			ring = nil
+		}
+	}
+	return rings, nil
+}
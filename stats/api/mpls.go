@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	govppapi "git.fd.io/govpp.git/api"
+)
+
+// MplsEntry is an entry of the MPLS FIB (a local label), or an MPLS tunnel
+// which is the head-end of an LSP.
+type MplsEntry struct {
+	// Tunnel is the name of the tunnel interface, empty for FIB entries
+	Tunnel    string
+	SwIfIndex uint32
+	// Label and the end of stack bit of the FIB entry
+	Label uint32
+	EOS   bool
+	// TableID is the MPLS table of the FIB entry
+	TableID uint32
+	// NextHops of the paths, e.g. "10.0.0.2 GigabitEthernet0/8/0"
+	NextHops []string
+	// OutLabels are the labels imposed by the paths
+	OutLabels []uint32
+	// Counter are the packets forwarded by the FIB entry,
+	// or transmitted by the tunnel interface
+	Counter govppapi.InterfaceCounterCombined
+}
+
+// Regular expressions used to parse the MPLS CLI outputs
+var (
+	// table of 'show mpls fib', e.g. "MPLS-VRF:0, fib_index:0 locks:[...]"
+	mplsTableRe = regexp.MustCompile(`^MPLS-VRF:(\d+),`)
+	// FIB entry, e.g. "33:eos/21 fib:0 index:37 locks:2" (special labels are named)
+	mplsEntryRe = regexp.MustCompile(`^(\d+):(eos|neos)/\d+ fib:\d+`)
+	// tunnel of 'show mpls tunnel', e.g. "[@0] mpls_tunnel0: sw_if_index:5 hw_if_index:5"
+	mplsTunnelRe = regexp.MustCompile(`^\[@\d+\] (\S+): sw_if_index:(\d+)`)
+	// adjacency of a path, e.g. "[@0]: arp-ipv4: via 10.0.0.2 GigabitEthernet0/8/0"
+	mplsViaRe = regexp.MustCompile(`\[@\d+\]: [\w-]+:? via (\S+) ([^:\s]+)`)
+	// labels of the path extensions, e.g. "path:75  labels:[[34 pipe ttl:0 exp:0][35 uniform ttl:0 exp:0]]"
+	mplsLabelsRe = regexp.MustCompile(`^\s+path:\d+\s+labels:(.*)$`)
+	mplsLabelRe  = regexp.MustCompile(`\[(\d+) `)
+)
+
+// DumpMpls retrieves the MPLS tunnels and the entries of the MPLS FIB by the CLI,
+// using runCli to run the commands. The counters of the tunnels are not set,
+// the tunnels are counted as interfaces. ErrNotSupported is returned if the
+// VPP has no MPLS CLI.
+func DumpMpls(ctx context.Context, runCli func(context.Context, string) (string, error)) ([]MplsEntry, error) {
+	out, err := runCli(ctx, "show mpls tunnel")
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(out, "unknown input") {
+		return nil, ErrNotSupported
+	}
+	entries := ParseMplsTunnels(out)
+
+	if out, err = runCli(ctx, "show mpls fib"); err != nil {
+		return nil, err
+	}
+	return append(entries, ParseMplsFib(out)...), nil
+}
+
+// ParseMplsFib parses the labeled entries of the MPLS FIB tables with the
+// counters of their load-balance from the 'show mpls fib' output, special
+// labels (e.g. ip4-explicit-null) are skipped:
+//
+//	MPLS-VRF:0, fib_index:0 locks:[interface:4, CLI:1, ]
+//	33:eos/21 fib:0 index:37 locks:2
+//	  CLI refs:1 src-flags:added,contributing,active,
+//	    path-list:[57] locks:2 flags:shared, uPRF-list:53 len:1 itfs:[1, ]
+//	      path:[75] pl-index:57 ip4 weight=1 pref=0 attached-nexthop:  oper-flags:resolved,
+//	        10.0.0.2 GigabitEthernet0/8/0
+//	      [@0]: arp-ipv4: via 10.0.0.2 GigabitEthernet0/8/0
+//	    Extensions:
+//	     path:75  labels:[[34 pipe ttl:0 exp:0]]
+//	 forwarding:   mpls-eos-chain
+//	  [@0]: dpo-load-balance: [proto:mpls index:40 buckets:1 uRPF:53 to:[1200:96000]]
+func ParseMplsFib(out string) []MplsEntry {
+	var entries []MplsEntry
+	var tableID uint32
+	var entry *MplsEntry
+	// set once the counter of the entry is parsed
+	var counted bool
+	for _, line := range strings.Split(out, "\n") {
+		if m := mplsTableRe.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.ParseUint(m[1], 10, 32)
+			tableID, entry = uint32(id), nil
+			continue
+		}
+		if m := mplsEntryRe.FindStringSubmatch(line); m != nil {
+			label, _ := strconv.ParseUint(m[1], 10, 32)
+			entries = append(entries, MplsEntry{
+				Label:   uint32(label),
+				EOS:     m[2] == "eos",
+				TableID: tableID,
+			})
+			entry, counted = &entries[len(entries)-1], false
+			continue
+		}
+		if len(line) > 0 && line[0] != ' ' {
+			// special label
+			entry = nil
+		}
+		if entry == nil {
+			continue
+		}
+		if m := srFibToRe.FindStringSubmatch(line); m != nil {
+			// the load-balance of the entry precedes the chained ones
+			if !counted {
+				entry.Counter.Packets, _ = strconv.ParseUint(m[1], 10, 64)
+				entry.Counter.Bytes, _ = strconv.ParseUint(m[2], 10, 64)
+				counted = true
+			}
+			continue
+		}
+		parseMplsPath(entry, line)
+	}
+	return entries
+}
+
+// ParseMplsTunnels parses the MPLS tunnels with their paths
+// from the 'show mpls tunnel' output:
+//
+//	[@0] mpls_tunnel0: sw_if_index:5 hw_if_index:5
+//	 flags: L2,
+//	 via:
+//	  path-list:[42] locks:1 flags:shared, uPRF-list:40 len:1 itfs:[1, ]
+//	    path:[48] pl-index:42 ip4 weight=1 pref=0 attached-nexthop:
+//	        10.0.0.2 GigabitEthernet0/8/0
+//	      [@0]: ipv4 via 10.0.0.2 GigabitEthernet0/8/0: mtu:9000 next:3
+//	    Extensions:
+//	     path:48  labels:[[33 pipe ttl:0 exp:0]]
+func ParseMplsTunnels(out string) []MplsEntry {
+	var tunnels []MplsEntry
+	for _, line := range strings.Split(out, "\n") {
+		if m := mplsTunnelRe.FindStringSubmatch(line); m != nil {
+			swIfIndex, _ := strconv.ParseUint(m[2], 10, 32)
+			tunnels = append(tunnels, MplsEntry{
+				Tunnel:    m[1],
+				SwIfIndex: uint32(swIfIndex),
+			})
+			continue
+		}
+		if len(tunnels) > 0 {
+			parseMplsPath(&tunnels[len(tunnels)-1], line)
+		}
+	}
+	return tunnels
+}
+
+// parseMplsPath adds the next-hop or the out labels of the path line to the entry.
+func parseMplsPath(entry *MplsEntry, line string) {
+	if m := mplsViaRe.FindStringSubmatch(line); m != nil {
+		nextHop := m[1] + " " + m[2]
+		for _, nh := range entry.NextHops {
+			if nh == nextHop {
+				return
+			}
+		}
+		entry.NextHops = append(entry.NextHops, nextHop)
+		return
+	}
+	if m := mplsLabelsRe.FindStringSubmatch(line); m != nil {
+		for _, label := range mplsLabelRe.FindAllStringSubmatch(m[1], -1) {
+			value, _ := strconv.ParseUint(label[1], 10, 32)
+			entry.OutLabels = append(entry.OutLabels, uint32(value))
+		}
+	}
+}
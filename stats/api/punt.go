@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// punt reason counters, e.g. "   [0] ipsec4-spi-0                   packets:0 bytes:0"
+var puntStatsRe = regexp.MustCompile(`^\s*\[(\d+)\]\s+(\S+).*packets:(\d+)\s+bytes:(\d+)`)
+
+// ParsePuntStats parses the counters of the punt reasons
+// from the 'show punt stats' output.
+func ParsePuntStats(out string) ([]PuntStat, error) {
+	var stats []PuntStat
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		matches := puntStatsRe.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, fmt.Errorf("`show punt stats` parsing failed line: %q", line)
+		}
+		idx, _ := strconv.ParseUint(matches[1], 10, 32)
+		packets, _ := strconv.ParseUint(matches[3], 10, 64)
+		bytes, _ := strconv.ParseUint(matches[4], 10, 64)
+		stats = append(stats, PuntStat{
+			Index:   uint32(idx),
+			Reason:  matches[2],
+			Packets: packets,
+			Bytes:   bytes,
+		})
+	}
+	return stats, nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePuntStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    []PuntStat
+		wantErr bool
+	}{
+		{
+			name: "no punt reasons",
+			out:  "",
+		},
+		{
+			name: "punt reasons",
+			out: `   [0] ipsec4-spi-0                   packets:0 bytes:0
+   [1] ipsec6-spi-0                   packets:0 bytes:0
+   [2] ipsec6-no-such-tunnel          packets:3 bytes:312
+   [3] ipsec4-no-such-tunnel          packets:0 bytes:0
+   [4] VXLAN-GBP-no-such-v4-tunnel    packets:17 bytes:1734
+   [5] VXLAN-GBP-no-such-v6-tunnel    packets:0 bytes:0
+`,
+			want: []PuntStat{
+				{Index: 0, Reason: "ipsec4-spi-0"},
+				{Index: 1, Reason: "ipsec6-spi-0"},
+				{Index: 2, Reason: "ipsec6-no-such-tunnel", Packets: 3, Bytes: 312},
+				{Index: 3, Reason: "ipsec4-no-such-tunnel"},
+				{Index: 4, Reason: "VXLAN-GBP-no-such-v4-tunnel", Packets: 17, Bytes: 1734},
+				{Index: 5, Reason: "VXLAN-GBP-no-such-v6-tunnel"},
+			},
+		},
+		{
+			name:    "unknown command",
+			out:     "show: unknown input `punt stats'\n",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParsePuntStats(test.out)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error: got %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("punt stats: got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
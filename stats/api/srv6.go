@@ -0,0 +1,261 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	govppapi "git.fd.io/govpp.git/api"
+)
+
+// SRv6SID is an SRv6 policy identified by its binding SID (BSID),
+// or a local SID (segment endpoint) instantiated by the VPP.
+type SRv6SID struct {
+	SID string
+	// Policy is set for SR policies, unset for local SIDs
+	Policy bool
+	// Behavior of the policy (Encapsulation, SRH insertion)
+	// or of the local SID (End, End.DX4...)
+	Behavior string
+	// Type of the policy (Default, Spray)
+	Type string
+	// FibTable is the table of the policy BSID
+	FibTable     uint32
+	SegmentLists []SRv6SegmentList
+	// Steering is the traffic steered into the policy,
+	// e.g. "L3 10.0.0.0/24" or "L2 GigabitEthernet0/8/0"
+	Steering []string
+	// Good are the packets forwarded by the local SID, or by the FIB entries
+	// of the policy BSID and of the L3 steering prefixes. Bad are the packets
+	// dropped by the local SID.
+	Good govppapi.InterfaceCounterCombined
+	Bad  govppapi.InterfaceCounterCombined
+}
+
+// SRv6SegmentList is a weighted segment list of an SR policy.
+type SRv6SegmentList struct {
+	Segments []string
+	Weight   uint32
+}
+
+// Regular expressions used to parse the SRv6 CLI outputs
+var (
+	// policy of 'show sr policies', e.g. "[0].-	BSID: 2001:db8::1"
+	srPolicyRe = regexp.MustCompile(`^\[\d+\]\.-\s+BSID:\s+(\S+)`)
+	// policy attribute, e.g. "	Behavior: Encapsulation"
+	srAttrRe = regexp.MustCompile(`^\s+(Behavior|Type|FIB table):\s+(.*?)\s*$`)
+	// segment list, e.g. "  [0].- < a::1, b::1 > weight: 1"
+	srSegmentListRe = regexp.MustCompile(`^\s+\[\d+\]\.-\s+<\s*(.*?)\s*>\s+weight:\s+(\d+)`)
+	// 'show sr steering-policies' entry, e.g. "L3 10.0.0.0/24	2001:db8::1"
+	srSteeringRe = regexp.MustCompile(`^(L[23]) (\S+)\s+(\S+)\s*$`)
+	// local SID attribute of 'show sr localsids', e.g. "	Address: 	a1::/128"
+	srLocalSIDRe = regexp.MustCompile(`^\s+(Address|Behavior|Good traffic|Bad traffic):\s+(.*?)\s*$`)
+	// local SID counter, e.g. "[10 packets : 1200 bytes]"
+	srTrafficRe = regexp.MustCompile(`\[(\d+) packets : (\d+) bytes\]`)
+	// counters of a FIB entry, e.g. "[@0]: dpo-load-balance: [proto:ip6 index:12 buckets:1 uRPF:11 to:[42:5040]]"
+	srFibToRe = regexp.MustCompile(`dpo-load-balance: \[.*?to:\[(\d+):(\d+)\]`)
+)
+
+// DumpSRv6 retrieves the SRv6 policies with the traffic steered into them and
+// the local SIDs by the CLI, using runCli to run the commands. The counters
+// of a policy are the sum of the FIB counters of the policy BSID and of its
+// L3 steering prefixes (looked up in the default table), the VPP does not
+// count packets per policy. ErrNotSupported is returned if the VPP has no
+// SRv6 CLI.
+func DumpSRv6(ctx context.Context, runCli func(context.Context, string) (string, error)) ([]SRv6SID, error) {
+	out, err := runCli(ctx, "show sr policies")
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(out, "unknown input") {
+		return nil, ErrNotSupported
+	}
+	policies := ParseSRv6Policies(out)
+	if len(policies) > 0 {
+		if out, err = runCli(ctx, "show sr steering-policies"); err != nil {
+			return nil, err
+		}
+		policies = WithSRv6Steering(policies, out)
+	}
+	for i := range policies {
+		prefixes := map[string]string{
+			policies[i].SID + "/128": fmt.Sprintf("show ip6 fib table %d %s/128", policies[i].FibTable, policies[i].SID),
+		}
+		for _, steering := range policies[i].Steering {
+			if prefix := strings.TrimPrefix(steering, "L3 "); prefix != steering {
+				af := "ip"
+				if strings.Contains(prefix, ":") {
+					af = "ip6"
+				}
+				prefixes[prefix] = fmt.Sprintf("show %s fib %s", af, prefix)
+			}
+		}
+		for prefix, cmd := range prefixes {
+			if out, err = runCli(ctx, cmd); err != nil {
+				return nil, err
+			}
+			counter := ParseFibEntryCounter(out, prefix)
+			policies[i].Good.Packets += counter.Packets
+			policies[i].Good.Bytes += counter.Bytes
+		}
+	}
+
+	out, err = runCli(ctx, "show sr localsids")
+	if err != nil {
+		return nil, err
+	}
+	return append(policies, ParseSRv6LocalSIDs(out)...), nil
+}
+
+// ParseSRv6Policies parses the SR policies from the 'show sr policies' output:
+//
+//	SR policies:
+//	[0].-	BSID: 2001:db8::1
+//		Behavior: Encapsulation
+//		Type: Default
+//		FIB table: 0
+//		Segment Lists:
+//	  [0].- < a::1, b::1 > weight: 1
+//	-----------
+func ParseSRv6Policies(out string) []SRv6SID {
+	var policies []SRv6SID
+	for _, line := range strings.Split(out, "\n") {
+		if m := srPolicyRe.FindStringSubmatch(line); m != nil {
+			policies = append(policies, SRv6SID{SID: m[1], Policy: true})
+			continue
+		}
+		if len(policies) == 0 {
+			continue
+		}
+		policy := &policies[len(policies)-1]
+		if m := srSegmentListRe.FindStringSubmatch(line); m != nil {
+			weight, _ := strconv.ParseUint(m[2], 10, 32)
+			policy.SegmentLists = append(policy.SegmentLists, SRv6SegmentList{
+				Segments: strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' }),
+				Weight:   uint32(weight),
+			})
+			continue
+		}
+		m := srAttrRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		switch m[1] {
+		case "Behavior":
+			policy.Behavior = m[2]
+		case "Type":
+			policy.Type = m[2]
+		case "FIB table":
+			table, _ := strconv.ParseUint(m[2], 10, 32)
+			policy.FibTable = uint32(table)
+		}
+	}
+	return policies
+}
+
+// WithSRv6Steering adds the traffic steered into the policies
+// parsed from the 'show sr steering-policies' output:
+//
+//	SR steering policies:
+//	Traffic		SR policy BSID
+//	L3 10.0.0.0/24	2001:db8::1
+//	L2 GigabitEthernet0/8/0	2001:db8::2
+func WithSRv6Steering(policies []SRv6SID, out string) []SRv6SID {
+	bsids := make(map[string]int, len(policies))
+	for i, policy := range policies {
+		bsids[policy.SID] = i
+	}
+	for _, line := range strings.Split(out, "\n") {
+		m := srSteeringRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if i, ok := bsids[m[3]]; ok {
+			policies[i].Steering = append(policies[i].Steering, m[1]+" "+m[2])
+		}
+	}
+	return policies
+}
+
+// ParseSRv6LocalSIDs parses the local SIDs with their counters
+// from the 'show sr localsids' output:
+//
+//	SRv6 - My LocalSID Table:
+//	=========================
+//		Address: 	a1::/128
+//		Behavior: 	End
+//		Good traffic: 	[10 packets : 1200 bytes]
+//		Bad traffic:  	[0 packets : 0 bytes]
+//	--------------------
+func ParseSRv6LocalSIDs(out string) []SRv6SID {
+	var sids []SRv6SID
+	for _, line := range strings.Split(out, "\n") {
+		m := srLocalSIDRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if m[1] == "Address" {
+			sids = append(sids, SRv6SID{SID: strings.TrimSuffix(m[2], "/128")})
+			continue
+		}
+		if len(sids) == 0 {
+			continue
+		}
+		sid := &sids[len(sids)-1]
+		switch m[1] {
+		case "Behavior":
+			sid.Behavior = m[2]
+		case "Good traffic":
+			sid.Good = parseSRv6Traffic(m[2])
+		case "Bad traffic":
+			sid.Bad = parseSRv6Traffic(m[2])
+		}
+	}
+	return sids
+}
+
+// parseSRv6Traffic parses a local SID counter, e.g. "[10 packets : 1200 bytes]".
+func parseSRv6Traffic(s string) govppapi.InterfaceCounterCombined {
+	var counter govppapi.InterfaceCounterCombined
+	if m := srTrafficRe.FindStringSubmatch(s); m != nil {
+		counter.Packets, _ = strconv.ParseUint(m[1], 10, 64)
+		counter.Bytes, _ = strconv.ParseUint(m[2], 10, 64)
+	}
+	return counter
+}
+
+// ParseFibEntryCounter parses the packets forwarded by the FIB entry of the
+// prefix from the 'show ip fib <prefix>' output, the counter of the first
+// load-balance is returned. The counter is zero if the VPP shows none (nothing
+// was forwarded), or if the output is of a less specific entry matching the
+// prefix.
+func ParseFibEntryCounter(out, prefix string) govppapi.InterfaceCounterCombined {
+	var counter govppapi.InterfaceCounterCombined
+	start := strings.Index("\n"+out, "\n"+prefix+" ")
+	if start < 0 {
+		return counter
+	}
+	if m := srFibToRe.FindStringSubmatch(out[start:]); m != nil {
+		counter.Packets, _ = strconv.ParseUint(m[1], 10, 64)
+		counter.Bytes, _ = strconv.ParseUint(m[2], 10, 64)
+	}
+	return counter
+}
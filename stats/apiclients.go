@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Regular expressions used to parse the API clients and rings
+var (
+	// shared memory client, e.g. "vpp-agent     1234        0 0x00000001301a1b40 OK"
+	apiShmClientRe = regexp.MustCompile(`^\s*(.+?)\s+(\d+)\s+(\d+)\s+0x[0-9a-fA-F]+\s+(\S+)\s*$`)
+	// socket client, e.g. "vpptop          27"
+	apiSocketClientRe = regexp.MustCompile(`^\s*(.+?)\s+(-?\d+)\s*$`)
+	// ring of the main API segment, e.g. "vlib   256   1024   2345   0"
+	apiRingRe = regexp.MustCompile(`^\s*(vlib|clnt)\s+(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s*$`)
+	// e.g. "0 ring miss fallback allocations"
+	apiRingMissRe = regexp.MustCompile(`^\s*(\d+) ring miss fallback allocations`)
+	// memory of the API segment, e.g. "total: 63.99M, used: 1.93M, free: ..."
+	apiSegmentMemoryRe = regexp.MustCompile(`total:\s+([0-9.]+[KMGT]?),\s+used:\s+([0-9.]+[KMGT]?)`)
+)
+
+// GetAPIClients returns the clients of the binary API sorted by their name,
+// the message rings of the main API segment and the memory used by the segment.
+func (p *vppProvider) GetAPIClients(ctx context.Context) (*api.APIClients, error) {
+	out, err := p.handler.RunCli(ctx, "show api clients")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	clients := &api.APIClients{Clients: parseAPIClients(out)}
+	sort.Slice(clients.Clients, func(i, j int) bool {
+		return clients.Clients[i].Name < clients.Clients[j].Name
+	})
+
+	out, err = p.handler.RunCli(ctx, "show api ring-stats")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	clients.Rings, clients.RingMissFallbacks = parseAPIRings(out)
+
+	out, err = p.handler.RunCli(ctx, "show memory api-segment")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	clients.SegmentSize, clients.SegmentUsed = parseAPISegmentMemory(out)
+	return clients, nil
+}
+
+// parseAPIClients parses the 'show api clients' output, the shared memory
+// clients are listed before the socket clients:
+//
+//	Shared memory clients
+//	                Name      PID   Queue Length           Queue VA Health
+//	           vpp-agent     1234              0 0x00000001301a1b40 OK
+//	Socket clients
+//	                Name  Fildesc
+//	              vpptop       27
+func parseAPIClients(out string) []api.APIClient {
+	var clients []api.APIClient
+	socket := false
+	for _, line := range strings.Split(out, "\n") {
+		switch trimmed := strings.TrimSpace(line); {
+		case trimmed == "Shared memory clients":
+			socket = false
+			continue
+		case trimmed == "Socket clients":
+			socket = true
+			continue
+		case strings.HasPrefix(trimmed, "Name "):
+			continue
+		}
+		if socket {
+			m := apiSocketClientRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			fd, _ := strconv.Atoi(m[2])
+			clients = append(clients, api.APIClient{Name: m[1], Socket: true, FD: fd})
+			continue
+		}
+		m := apiShmClientRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		pid, _ := strconv.ParseUint(m[2], 10, 32)
+		queue, _ := strconv.ParseUint(m[3], 10, 64)
+		clients = append(clients, api.APIClient{
+			Name:        m[1],
+			PID:         uint32(pid),
+			QueueLength: queue,
+			Health:      m[4],
+		})
+	}
+	return clients
+}
+
+// parseAPIRings parses the rings of the main API segment and the number
+// of the fallback allocations from the 'show api ring-stats' output.
+// Rings of the private segments of the clients are skipped:
+//
+//	Main API segment rings:
+//	Owner       Size     Nitems       Hits     Misses
+//	vlib         256       1024       2345          0
+//	clnt         256       1024       2345          0
+//	0 ring miss fallback allocations
+//	vpp-agent segment rings:
+//	...
+func parseAPIRings(out string) (rings []api.APIRing, fallbacks uint64) {
+	main := true
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), "segment rings:") {
+			main = strings.HasPrefix(strings.TrimSpace(line), "Main API segment")
+			continue
+		}
+		if !main {
+			continue
+		}
+		if m := apiRingMissRe.FindStringSubmatch(line); m != nil {
+			fallbacks, _ = strconv.ParseUint(m[1], 10, 64)
+			continue
+		}
+		m := apiRingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ring := api.APIRing{Owner: m[1]}
+		ring.Size, _ = strconv.ParseUint(m[2], 10, 64)
+		ring.Elements, _ = strconv.ParseUint(m[3], 10, 64)
+		ring.Hits, _ = strconv.ParseUint(m[4], 10, 64)
+		ring.Misses, _ = strconv.ParseUint(m[5], 10, 64)
+		rings = append(rings, ring)
+	}
+	return rings, fallbacks
+}
+
+// parseAPISegmentMemory parses the size and the used memory of the API
+// segment in bytes from the 'show memory api-segment' output.
+func parseAPISegmentMemory(out string) (size, used uint64) {
+	m := apiSegmentMemoryRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, 0
+	}
+	return parseMemorySize(m[1]), parseMemorySize(m[2])
+}
+
+// parseMemorySize parses the memory size printed by the VPP, e.g. "1.93M".
+func parseMemorySize(s string) uint64 {
+	multiplier := 1.0
+	if i := strings.IndexAny(s, "KMGT"); i > 0 {
+		for _, suffix := range "KMGT" {
+			multiplier *= 1024
+			if suffix == rune(s[i]) {
+				break
+			}
+		}
+		s = s[:i]
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return uint64(value * multiplier)
+}
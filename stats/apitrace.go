@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// pollCliRe matches the details of the cli_inband messages running the
+// read-only commands the tabs are polled by, e.g. "cmd: show runtime max".
+var pollCliRe = regexp.MustCompile(`\bcmd:?\s*['"]?(?:show|api trace)\b`)
+
+// GetAPITrace returns the binary API trace status together with the traced
+// messages. The messages polling the VPP by the CLI are left out, see withoutPolls.
+func (p *vppProvider) GetAPITrace(ctx context.Context) (*api.APITrace, error) {
+	trace, err := p.handler.DumpAPITraceStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	messages, err := p.handler.DumpAPITrace(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	trace.Messages, trace.Hidden = withoutPolls(messages)
+	return trace, nil
+}
+
+// withoutPolls returns the messages without the cli_inband messages (and
+// their replies) running the read-only commands vpptop polls the VPP by,
+// together with the number of the left out messages. The trace does not
+// record the client of a message, so the polls of other clients running
+// the same commands are left out as well, the configuration changes made
+// by the binary API (e.g. by an agent) are kept.
+func withoutPolls(messages []api.APITraceMessage) (result []api.APITraceMessage, hidden int) {
+	result = make([]api.APITraceMessage, 0, len(messages))
+	polled := false
+	for _, msg := range messages {
+		switch {
+		case msg.Name == "cli_inband" && pollCliRe.MatchString(msg.Details):
+			polled = true
+			hidden++
+			continue
+		case msg.Name == "cli_inband_reply" && polled:
+			polled = false
+			hidden++
+			continue
+		}
+		polled = false
+		result = append(result, msg)
+	}
+	return result, hidden
+}
+
+// ToggleAPITrace enables the binary API trace if it is disabled and vice
+// versa, the current state is dumped first. The new state is returned.
+func (p *vppProvider) ToggleAPITrace(ctx context.Context) (bool, error) {
+	status, err := p.handler.DumpAPITraceStatus(ctx)
+	if err != nil {
+		return false, fmt.Errorf("request failed: %v", err)
+	}
+	if err := p.handler.SetAPITrace(ctx, !status.Enabled); err != nil {
+		return status.Enabled, fmt.Errorf("request failed: %v", err)
+	}
+	return !status.Enabled, nil
+}
+
+// SaveAPITrace saves the binary API trace to the file (VPP saves it to /tmp).
+func (p *vppProvider) SaveAPITrace(ctx context.Context, file string) error {
+	if err := p.handler.SaveAPITrace(ctx, file); err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	return nil
+}
+
+// ClearAPITrace frees the traced messages, the trace is re-enabled
+// if it was enabled before.
+func (p *vppProvider) ClearAPITrace(ctx context.Context) error {
+	status, err := p.handler.DumpAPITraceStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	if err := p.handler.FreeAPITrace(ctx); err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	if status.Enabled {
+		if err := p.handler.SetAPITrace(ctx, true); err != nil {
+			return fmt.Errorf("request failed: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// apiTraceDump is an 'api trace dump' captured while vpptop polled the VPP,
+// an agent configured an interface in between.
+const apiTraceDump = `[0]: vl_api_cli_inband_t:
+  client_index: 0
+  context: 16777216
+  cmd: show runtime max
+[1]: vl_api_cli_inband_reply_t:
+  context: 16777216
+  retval: 0
+[2]: vl_api_sw_interface_set_flags_t:
+  client_index: 1
+  context: 33554432
+  sw_if_index: 1
+  flags: 1
+[3]: vl_api_sw_interface_set_flags_reply_t:
+  context: 33554432
+  retval: 0
+[4]: vl_api_cli_inband_t:
+  client_index: 0
+  context: 16777217
+  cmd: api trace status
+[5]: vl_api_cli_inband_reply_t:
+  context: 16777217
+  retval: 0
+[6]: vl_api_cli_inband_t:
+  client_index: 1
+  context: 33554433
+  cmd: set interface mtu 9000 GigabitEthernet0/8/0
+[7]: vl_api_cli_inband_reply_t:
+  context: 33554433
+  retval: 0
+[8]: vl_api_cli_inband_t:
+  client_index: 0
+  context: 16777218
+  cmd: show hardware-interfaces
+[9]: vl_api_control_ping_t:
+  client_index: 0
+  context: 16777219
+`
+
+func TestWithoutPolls(t *testing.T) {
+	runCli := func(context.Context, string) (string, error) { return apiTraceDump, nil }
+	messages, err := api.DumpAPITrace(context.Background(), runCli)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, hidden := withoutPolls(messages)
+	var got []int
+	for _, msg := range result {
+		got = append(got, msg.Index)
+	}
+	// the polls and their replies are left out, the configuration
+	// by the CLI is kept as well as a ping following a poll
+	if want := []int{2, 3, 6, 7, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("messages: got %v, want %v", got, want)
+	}
+	if hidden != 5 {
+		t.Errorf("hidden: got %d, want 5", hidden)
+	}
+}
+
+func TestPollCliRe(t *testing.T) {
+	tests := []struct {
+		details string
+		want    bool
+	}{
+		{details: "client_index: 0 context: 1 cmd: show interface", want: true},
+		{details: "cmd 'show runtime max'", want: true},
+		{details: "cmd: api trace dump", want: true},
+		{details: "cmd: set interface state GigabitEthernet0/8/0 up", want: false},
+		{details: "cmd: showcase", want: false},
+		{details: "cmd: clear interfaces", want: false},
+	}
+	for _, test := range tests {
+		if got := pollCliRe.MatchString(test.details); got != test.want {
+			t.Errorf("%q: got %v, want %v", test.details, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/rpc"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// BatchRPCPath is the HTTP path of the batch RPC. The GoVPP proxy serves its
+// RPC by the default HTTP mux, the batch RPC is served by the same listener.
+const BatchRPCPath = "/_vpptop_batch_"
+
+// Tabs fetched by the batch RPC, the tabs polled every second or two.
+const (
+	BatchInterfaces = "interfaces"
+	BatchNodes      = "nodes"
+	BatchErrors     = "errors"
+	BatchDropsPunts = "dropspunts"
+	BatchThreads    = "threads"
+	BatchTunnels    = "tunnels"
+)
+
+const (
+	// batchWindow is the age up to which the fetched batch serves the tabs
+	// not served by it yet, so the tabs polled together share a round-trip.
+	batchWindow = 500 * time.Millisecond
+	// batchIdle is the time after which a tab not polled anymore
+	// (e.g. with the auto-refresh off) is not fetched by the batches.
+	batchIdle = 10 * time.Second
+	// batchRedial is the interval of the attempts to dial the batch RPC
+	// of a proxy which does not serve it (e.g. an older proxy).
+	batchRedial = 30 * time.Second
+	// batchDialTimeout is the timeout of dialing the batch RPC.
+	batchDialTimeout = 5 * time.Second
+	// batchConnected is the HTTP status of the RPC connection, see net/rpc.
+	batchConnected = "200 Connected to Go RPC"
+)
+
+// errNoBatch is returned if the batch RPC is not available,
+// the data is requested from the handler instead.
+var errNoBatch = errors.New("batch RPC not available")
+
+// BatchRequest requests the data of the tabs in a single round-trip.
+type BatchRequest struct {
+	Tabs []string
+	// Compress requests the gzip compression of the payload.
+	Compress bool
+}
+
+// BatchReply carries the gob encoded BatchData.
+type BatchReply struct {
+	Payload    []byte
+	Compressed bool
+}
+
+// BatchData holds the data of the requested tabs. The node counters are
+// sent as dumped, the clients subtract the baselines of their cleared counters.
+type BatchData struct {
+	Interfaces   []api.Interface
+	Nodes        []api.Node
+	NodeCounters *api.NodeCounterInfo
+	Punts        []api.PuntStat
+	Threads      []api.ThreadData
+	Tunnels      []api.TunnelCounters
+	// Failed are the errors of the tabs failed to be polled by the tab.
+	Failed map[string]string
+}
+
+// WithBatchCompression sets whether the payload of the batch RPC
+// is gzip compressed, which pays off on slow links.
+func WithBatchCompression(compress bool) ProviderOption {
+	return func(p *vppProvider) {
+		p.compressBatch = compress
+	}
+}
+
+// BatchRPC serves the data of the tabs polled by the provider connected
+// to the local VPP. The batches are served one at a time, the provider
+// keeps the state of the last poll (e.g. the thread clocks).
+type BatchRPC struct {
+	mu       sync.Mutex
+	provider *vppProvider
+}
+
+// ServeBatch registers the batch RPC of the provider at the BatchRPCPath
+// of the default HTTP mux.
+func ServeBatch(provider api.VppProviderAPI) error {
+	p, ok := provider.(*vppProvider)
+	if !ok {
+		return fmt.Errorf("batch RPC is not supported by the provider %T", provider)
+	}
+	server := rpc.NewServer()
+	if err := server.Register(&BatchRPC{provider: p}); err != nil {
+		return fmt.Errorf("registering batch RPC failed: %v", err)
+	}
+	http.Handle(BatchRPCPath, server)
+	return nil
+}
+
+// Fetch polls the requested tabs in parallel and replies their data.
+func (b *BatchRPC) Fetch(req BatchRequest, reply *BatchReply) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ctx := context.Background()
+	if b.provider.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.provider.requestTimeout)
+		defer cancel()
+	}
+	payload, err := encodeBatch(b.fetch(ctx, req.Tabs), req.Compress)
+	if err != nil {
+		return fmt.Errorf("encoding batch failed: %v", err)
+	}
+	reply.Payload, reply.Compressed = payload, req.Compress
+	return nil
+}
+
+// fetch polls the tabs in parallel, the node counters are dumped
+// once for both the errors and the drops.
+func (b *BatchRPC) fetch(ctx context.Context, tabs []string) *BatchData {
+	p := b.provider
+	data := &BatchData{Failed: make(map[string]string)}
+	requested := make(map[string]bool, len(tabs))
+	for _, tab := range tabs {
+		requested[tab] = true
+	}
+
+	var mu sync.Mutex
+	wg := new(sync.WaitGroup)
+	run := func(tabs []string, poll func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := poll(); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, tab := range tabs {
+					data.Failed[tab] = err.Error()
+				}
+			}
+		}()
+	}
+	for tab := range requested {
+		switch tab {
+		case BatchInterfaces:
+			run([]string{tab}, func() (err error) {
+				data.Interfaces, err = p.GetInterfaces(ctx)
+				return err
+			})
+		case BatchNodes:
+			run([]string{tab}, func() (err error) {
+				data.Nodes, err = p.GetNodes(ctx)
+				return err
+			})
+		case BatchThreads:
+			run([]string{tab}, func() (err error) {
+				data.Threads, err = p.GetThreads(ctx)
+				return err
+			})
+		case BatchTunnels:
+			run([]string{tab}, func() (err error) {
+				data.Tunnels, err = p.GetTunnels(ctx)
+				return err
+			})
+		case BatchDropsPunts:
+			run([]string{tab}, func() (err error) {
+				// the drops are shown even if the punts are not available
+				if data.Punts, err = p.handler.DumpPuntStats(ctx); err != nil {
+					logrus.Warnf("failed to dump punt stats: %v", err)
+				}
+				return nil
+			})
+		case BatchErrors:
+		default:
+			mu.Lock()
+			data.Failed[tab] = "unknown tab"
+			mu.Unlock()
+		}
+	}
+	if requested[BatchErrors] || requested[BatchDropsPunts] {
+		run([]string{BatchErrors, BatchDropsPunts}, func() (err error) {
+			data.NodeCounters, err = p.dumpNodeCounters(ctx)
+			return err
+		})
+	}
+	wg.Wait()
+	return data
+}
+
+// encodeBatch returns the gob encoding of the data, gzip compressed if set.
+func encodeBatch(data *BatchData, compress bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if !compress {
+		err := gob.NewEncoder(&buf).Encode(data)
+		return buf.Bytes(), err
+	}
+	zw := gzip.NewWriter(&buf)
+	if err := gob.NewEncoder(zw).Encode(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeBatch returns the data of the reply.
+func decodeBatch(reply *BatchReply) (*BatchData, error) {
+	var r io.Reader = bytes.NewReader(reply.Payload)
+	if reply.Compressed {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	data := new(BatchData)
+	if err := gob.NewDecoder(r).Decode(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// batchPolls are the polls of a tab fetched by the batches.
+type batchPolls struct {
+	last time.Time
+	// interval between the last two polls (zero if polled once)
+	interval time.Duration
+}
+
+// batchClient fetches the data of the tabs polled together from the batch
+// RPC of the proxy in a single round-trip. Each batch fetches the tabs whose
+// next poll is due within the batchWindow, the other tabs polled within the
+// window are served by the batch too.
+type batchClient struct {
+	mu       sync.Mutex
+	addr     string
+	compress bool
+	timeout  time.Duration
+	timings  *requestTimings
+	// connection to the batch RPC (nil if not dialed yet or lost)
+	conn     *rpc.Client
+	dialedAt time.Time
+	// polls of the tabs by the tab
+	polls map[string]*batchPolls
+	// last fetched data, the tabs it was fetched for and the tabs served
+	data      *BatchData
+	fetchedAt time.Time
+	fetched   map[string]bool
+	served    map[string]bool
+}
+
+// newBatchClient returns the batch client of the proxy at the address,
+// the batch RPC is dialed by the first fetch.
+func newBatchClient(addr string, compress bool, timeout time.Duration, timings *requestTimings) *batchClient {
+	return &batchClient{
+		addr:     addr,
+		compress: compress,
+		timeout:  timeout,
+		timings:  timings,
+		polls:    make(map[string]*batchPolls),
+	}
+}
+
+// fetch returns the data of the batch serving the tab, errNoBatch if
+// the proxy does not serve the batch RPC (or b is nil).
+func (b *batchClient) fetch(ctx context.Context, tab string) (*BatchData, error) {
+	if b == nil {
+		return nil, errNoBatch
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	polls, ok := b.polls[tab]
+	if !ok {
+		polls = new(batchPolls)
+		b.polls[tab] = polls
+	} else {
+		polls.interval = now.Sub(polls.last)
+	}
+	polls.last = now
+	if b.data != nil && now.Sub(b.fetchedAt) < batchWindow && b.fetched[tab] && !b.served[tab] {
+		b.served[tab] = true
+		return b.data, b.failure(tab)
+	}
+
+	if err := b.dial(ctx); err != nil {
+		return nil, err
+	}
+	tabs := b.dueTabs(tab, now)
+	start := time.Now()
+	data, err := b.call(ctx, tabs)
+	b.timings.record("Batch", time.Since(start), err)
+	if err != nil {
+		b.data = nil
+		return nil, fmt.Errorf("batch request failed: %v", err)
+	}
+	b.data, b.fetchedAt = data, time.Now()
+	b.fetched = make(map[string]bool, len(tabs))
+	for _, name := range tabs {
+		b.fetched[name] = true
+	}
+	b.served = map[string]bool{tab: true}
+	return data, b.failure(tab)
+}
+
+// close closes the connection to the batch RPC.
+func (b *batchClient) close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+}
+
+// dueTabs returns the tab together with the tabs whose next poll is due
+// within the batchWindow, tabs not polled for batchIdle are forgotten.
+func (b *batchClient) dueTabs(tab string, now time.Time) []string {
+	tabs := []string{tab}
+	for name, polls := range b.polls {
+		switch {
+		case name == tab:
+		case now.Sub(polls.last) > batchIdle:
+			delete(b.polls, name)
+		case !polls.last.Add(polls.interval).After(now.Add(batchWindow)):
+			tabs = append(tabs, name)
+		}
+	}
+	sort.Strings(tabs)
+	return tabs
+}
+
+// failure returns the error of the tab failed to be polled by the proxy.
+func (b *batchClient) failure(tab string) error {
+	if msg, ok := b.data.Failed[tab]; ok {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// dial connects the batch RPC if not connected, a proxy not serving
+// it is dialed again after batchRedial.
+func (b *batchClient) dial(ctx context.Context) error {
+	if b.conn != nil {
+		return nil
+	}
+	if time.Since(b.dialedAt) < batchRedial {
+		return errNoBatch
+	}
+	b.dialedAt = time.Now()
+	conn, err := dialBatch(ctx, b.addr)
+	if err != nil {
+		logrus.Infof("batch RPC of the proxy %s not available, polling per request: %v", b.addr, err)
+		return errNoBatch
+	}
+	logrus.Infof("batch RPC of the proxy %s connected (compression: %v)", b.addr, b.compress)
+	b.conn = conn
+	return nil
+}
+
+// call fetches the tabs by the batch RPC, the connection
+// is dropped if it is broken.
+func (b *batchClient) call(ctx context.Context, tabs []string) (*BatchData, error) {
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+	reply := new(BatchReply)
+	call := b.conn.Go("BatchRPC.Fetch", BatchRequest{Tabs: tabs, Compress: b.compress}, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.Error != nil {
+		if _, ok := call.Error.(rpc.ServerError); !ok {
+			b.conn.Close()
+			b.conn = nil
+		}
+		return nil, call.Error
+	}
+	logrus.Debugf("batch of %v fetched, payload %d bytes", tabs, len(reply.Payload))
+	return decodeBatch(reply)
+}
+
+// dialBatch connects the batch RPC served at the BatchRPCPath of the
+// address, like rpc.DialHTTPPath but within the timeout.
+func dialBatch(ctx context.Context, addr string) (*rpc.Client, error) {
+	dialer := net.Dialer{Timeout: batchDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(batchDialTimeout))
+	if _, err := io.WriteString(conn, "CONNECT "+BatchRPCPath+" HTTP/1.0\n\n"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: "CONNECT"})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.Status != batchConnected {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected HTTP response: %s", resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	return rpc.NewClient(conn), nil
+}
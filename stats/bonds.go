@@ -0,0 +1,191 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// lacpStateFlags are the LACP port state flags in the order of the 'show lacp' columns.
+var lacpStateFlags = []string{"exp", "def", "dis", "col", "syn", "agg", "tim", "act"}
+
+// GetBonds returns members of bond interfaces with their counters and LACP state.
+// The local binapi does not include the bond messages, so the bonds are read
+// from the 'show bond details' and 'show lacp' output.
+func (p *vppProvider) GetBonds(ctx context.Context) ([]api.BondMember, error) {
+	out, err := p.handler.RunCli(ctx, "show bond details")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	members := parseBonds(out)
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	hasLACP := false
+	for _, member := range members {
+		hasLACP = hasLACP || member.Mode == "lacp"
+	}
+	if hasLACP {
+		out, err := p.handler.RunCli(ctx, "show lacp")
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		states := parseLACP(out)
+		for i := range members {
+			if state, ok := states[members[i].Member]; ok {
+				members[i].LACP = state
+			}
+		}
+	}
+
+	ifDetails, err := p.handler.DumpInterfaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	ifStats, err := p.handler.DumpInterfaceStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	indexes := make(map[string]uint32, len(ifDetails))
+	for idx, details := range ifDetails {
+		indexes[details.InternalName] = idx
+	}
+	for i := range members {
+		idx, ok := indexes[members[i].Member]
+		if !ok {
+			continue
+		}
+		for _, iface := range ifStats.Interfaces {
+			if iface.InterfaceIndex == idx {
+				members[i].Rx, members[i].Tx = iface.Rx, iface.Tx
+				break
+			}
+		}
+	}
+	return members, nil
+}
+
+// parseBonds parses the 'show bond details' output into bond members,
+// older VPPs call the members slaves:
+//
+//	BondEthernet0
+//	  mode: lacp
+//	  load balance: l2
+//	  number of active members: 1
+//	    GigabitEthernet0/8/0
+//	  number of members: 2
+//	    GigabitEthernet0/8/0
+//	    GigabitEthernet0/9/0
+//	  device instance: 0
+func parseBonds(out string) []api.BondMember {
+	var result []api.BondMember
+	var bond api.BondMember
+	var active map[string]bool
+	// list is the member list being parsed, if any
+	var list string
+
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			bond = api.BondMember{Bond: trimmed}
+			active, list = make(map[string]bool), ""
+			continue
+		}
+		if bond.Bond == "" {
+			continue
+		}
+		key, value := trimmed, ""
+		if i := strings.Index(trimmed, ":"); i >= 0 {
+			key, value = trimmed[:i], strings.TrimSpace(trimmed[i+1:])
+		}
+		switch {
+		case key == "mode":
+			bond.Mode, list = value, ""
+		case key == "load balance":
+			bond.LoadBalance, list = value, ""
+		case strings.HasPrefix(key, "number of active"):
+			list = "active"
+		case strings.HasPrefix(key, "number of"):
+			list = "members"
+		case value == "" && list == "active":
+			active[trimmed] = true
+		case value == "" && list == "members":
+			member := bond
+			member.Member, member.Active = trimmed, active[trimmed]
+			result = append(result, member)
+		default:
+			list = ""
+		}
+	}
+	return result
+}
+
+// parseLACP parses the LACP state of members from the 'show lacp' output:
+//
+//	                                                        actor state                      partner state
+//	interface name            sw_if_index  bond interface   exp/def/dis/col/syn/agg/tim/act  exp/def/dis/col/syn/agg/tim/act
+//	GigabitEthernet0/8/0      1            BondEthernet0      0   0   1   1   1   1   1   1    0   0   1   1   1   1   1   1
+//	  LAG ID: [(ffff,02-fe-...,0001,00ff,0001), (ffff,02-fe-...,0001,00ff,0001)]
+//	  RX-state: CURRENT, TX-state: TRANSMIT, MUX-state: COLLECTING_DISTRIBUTING, PTX-state: PERIODIC_TX
+func parseLACP(out string) map[string]api.LACPState {
+	result := make(map[string]api.LACPState)
+	var member string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if line[0] != ' ' && len(fields) == 3+2*len(lacpStateFlags) {
+			member = fields[0]
+			result[member] = api.LACPState{
+				Actor:   lacpFlags(fields[3 : 3+len(lacpStateFlags)]),
+				Partner: lacpFlags(fields[3+len(lacpStateFlags):]),
+			}
+			continue
+		}
+		state, ok := result[member]
+		if !ok || !strings.Contains(line, "MUX-state:") {
+			continue
+		}
+		for _, part := range strings.Split(strings.TrimSpace(line), ",") {
+			if kv := strings.SplitN(strings.TrimSpace(part), ":", 2); len(kv) == 2 && kv[0] == "MUX-state" {
+				state.MuxState = strings.TrimSpace(kv[1])
+			}
+		}
+		result[member] = state
+	}
+	return result
+}
+
+// lacpFlags returns the names of the set flags of the LACP state, e.g. 'act/agg/syn/col/dis'.
+func lacpFlags(bits []string) string {
+	var flags []string
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bits[i] == "1" {
+			flags = append(flags, lacpStateFlags[i])
+		}
+	}
+	return strings.Join(flags, "/")
+}
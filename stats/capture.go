@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Regular expressions used to parse the packet capture status
+var (
+	// enabled capture, e.g. "pcap rx tx capture is on..." or "pcap dispatch capture enabled"
+	captureOnRe = regexp.MustCompile(`\b(?:on|enabled)\b`)
+	// disabled capture, e.g. "pcap rx/tx/drop capture is off..."
+	captureOffRe = regexp.MustCompile(`\b(?:off|disabled)\b`)
+	// captured packets, e.g. "capture is 10 of 1000 pkts..."
+	captureCountRe = regexp.MustCompile(`(\d+) of (\d+) pkts`)
+	// output file, e.g. "capture to file /tmp/vpptop.pcap"
+	captureFileRe = regexp.MustCompile(`(?:file|packets to)\s+(/\S*?)[,.]?(?:\s|$)`)
+)
+
+// captureCli returns the 'pcap' CLI command of the capture kind.
+func captureCli(kind api.CaptureKind) (string, error) {
+	switch kind {
+	case api.PcapTrace:
+		return "pcap trace", nil
+	case api.DispatchTrace:
+		return "pcap dispatch trace", nil
+	}
+	return "", fmt.Errorf("unknown capture %q", kind)
+}
+
+// GetCaptures returns the status of the pcap and the dispatch trace.
+func (p *vppProvider) GetCaptures(ctx context.Context) ([]api.PacketCapture, error) {
+	captures := make([]api.PacketCapture, 0, len(api.CaptureKinds))
+	for _, kind := range api.CaptureKinds {
+		cmd, _ := captureCli(kind)
+		status, err := p.handler.RunCli(ctx, cmd+" status")
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		captures = append(captures, parseCaptureStatus(kind, status))
+	}
+	return captures, nil
+}
+
+// StartCapture starts the packet capture. The pcap trace captures received
+// and transmitted packets, the dispatch trace captures the vectors of packets
+// processed by the graph nodes.
+func (p *vppProvider) StartCapture(ctx context.Context, kind api.CaptureKind, opts api.CaptureOptions) error {
+	cmd, err := captureCli(kind)
+	if err != nil {
+		return err
+	}
+	if kind == api.PcapTrace {
+		cmd += " rx tx"
+	} else {
+		cmd += " on"
+	}
+	if opts.MaxPackets != 0 {
+		cmd += fmt.Sprintf(" max %d", opts.MaxPackets)
+	}
+	if opts.Interface != "" && kind == api.PcapTrace {
+		cmd += " intfc " + opts.Interface
+	}
+	if opts.File != "" {
+		cmd += " file " + opts.File
+	}
+
+	reply, err := p.handler.RunCli(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	if isCliError(reply) {
+		return fmt.Errorf("starting %s capture failed: %s", kind, api.CompactCliOutput(reply))
+	}
+	return nil
+}
+
+// StopCapture stops the packet capture, the captured packets are written
+// to the output file. The reply of the VPP is returned.
+func (p *vppProvider) StopCapture(ctx context.Context, kind api.CaptureKind) (string, error) {
+	cmd, err := captureCli(kind)
+	if err != nil {
+		return "", err
+	}
+	reply, err := p.handler.RunCli(ctx, cmd+" off")
+	if err != nil {
+		return "", fmt.Errorf("request failed: %v", err)
+	}
+	if isCliError(reply) {
+		return "", fmt.Errorf("stopping %s capture failed: %s", kind, api.CompactCliOutput(reply))
+	}
+	return api.CompactCliOutput(reply), nil
+}
+
+// isCliError returns true if the CLI reply reports an error.
+func isCliError(reply string) bool {
+	reply = strings.ToLower(reply)
+	return strings.Contains(reply, "error") || strings.Contains(reply, "unknown input")
+}
+
+// parseCaptureStatus parses the 'pcap trace status' or the 'pcap dispatch
+// trace status' output, the format differs between VPP versions, e.g.:
+//
+//	pcap rx tx capture is on...
+//	capture is 10 of 1000 pkts...
+//	capture to file /tmp/vpptop.pcap
+func parseCaptureStatus(kind api.CaptureKind, status string) api.PacketCapture {
+	capture := api.PacketCapture{
+		Kind:    kind,
+		Enabled: captureOnRe.MatchString(status) && !captureOffRe.MatchString(status),
+		Status:  api.CompactCliOutput(status),
+	}
+	if m := captureCountRe.FindStringSubmatch(status); m != nil {
+		capture.Captured, _ = strconv.ParseUint(m[1], 10, 64)
+		capture.MaxPackets, _ = strconv.ParseUint(m[2], 10, 64)
+	}
+	if m := captureFileRe.FindStringSubmatch(status); m != nil {
+		capture.File = m[1]
+	}
+	return capture
+}
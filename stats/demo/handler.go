@@ -0,0 +1,948 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package demo provides a VPP handler with synthetic counters, used to run
+// vpptop without a VPP (e.g. to demo the GUI or to test the provider).
+package demo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// VPPVersion is the version reported by the demo handler
+const VPPVersion = "demo"
+
+// demoIface is an interface of the demo VPP with its traffic in packets per second.
+type demoIface struct {
+	name      string
+	index     uint32
+	supIndex  uint32
+	up        bool
+	ip        []string
+	vrf       uint32
+	rxRate    float64
+	txRate    float64
+	frameSize float64
+	device    api.DeviceDetails
+}
+
+var demoIfaces = []demoIface{
+	{name: "local0", index: 0, device: api.DeviceDetails{Type: "local"}},
+	{name: "GigabitEthernet0/8/0", index: 1, supIndex: 1, up: true, ip: []string{"10.0.0.1/24"}, rxRate: 120000, txRate: 118500, frameSize: 512,
+		device: api.DeviceDetails{MAC: "52:54:00:12:34:56", Type: "dpdk", LinkSpeed: 10000000, LinkDuplex: "full"}},
+	{name: "GigabitEthernet0/8/0.100", index: 2, supIndex: 1, up: true, ip: []string{"10.0.100.1/24"}, vrf: 10, rxRate: 20000, txRate: 19800, frameSize: 256,
+		device: api.DeviceDetails{MAC: "52:54:00:12:34:56", Type: "dpdk"}},
+	{name: "GigabitEthernet0/9/0", index: 3, supIndex: 3, up: true, ip: []string{"192.168.1.1/24", "fd00::1/64"}, rxRate: 118000, txRate: 121000, frameSize: 768,
+		device: api.DeviceDetails{MAC: "52:54:00:ab:cd:ef", Type: "dpdk", LinkSpeed: 10000000, LinkDuplex: "full"}},
+	{name: "loop0", index: 4, supIndex: 4, up: true, ip: []string{"172.16.0.1/32"}, rxRate: 10, txRate: 10, frameSize: 64,
+		device: api.DeviceDetails{MAC: "de:ad:00:00:00:00", Type: "loopback"}},
+	{name: "vxlan_tunnel0", index: 5, supIndex: 5, up: true, rxRate: 15000, txRate: 14800, frameSize: 1400,
+		device: api.DeviceDetails{Type: "vxlan"}},
+	{name: "mpls_tunnel0", index: 6, supIndex: 6, up: true, txRate: 3500, frameSize: 700,
+		device: api.DeviceDetails{Type: "mpls"}},
+	{name: "memif0/0", index: 7, supIndex: 7, up: true, ip: []string{"10.10.0.1/24"}, rxRate: 8000, txRate: 9500, frameSize: 1024,
+		device: api.DeviceDetails{MAC: "02:fe:12:34:56:78", Type: "memif"}},
+}
+
+// demoNode is a graph node of the demo VPP with its rate of calls per second
+// and the vectors per call, nodes with zero rates are registered but inactive.
+type demoNode struct {
+	name           string
+	state          string
+	callRate       float64
+	vectorsPerCall float64
+	clocks         float64
+}
+
+var demoNodes = []demoNode{
+	{name: "dpdk-input", state: "polling", callRate: 9000, vectorsPerCall: 28, clocks: 95},
+	{name: "ethernet-input", state: "active", callRate: 9000, vectorsPerCall: 28, clocks: 32},
+	{name: "ip4-input-no-checksum", state: "active", callRate: 8200, vectorsPerCall: 27, clocks: 27},
+	{name: "ip4-lookup", state: "active", callRate: 8200, vectorsPerCall: 27, clocks: 41},
+	{name: "ip4-rewrite", state: "active", callRate: 8100, vectorsPerCall: 27, clocks: 38},
+	{name: "vxlan4-input", state: "active", callRate: 600, vectorsPerCall: 25, clocks: 56},
+	{name: "interface-output", state: "active", callRate: 8100, vectorsPerCall: 27, clocks: 22},
+	{name: "error-drop", state: "active", callRate: 40, vectorsPerCall: 3, clocks: 110},
+	{name: "ip6-input", state: "active"},
+	{name: "ip6-lookup", state: "active"},
+	{name: "mpls-input", state: "active"},
+	{name: "unix-epoll-input", state: "polling", callRate: 100000, clocks: 1800},
+}
+
+// demoError is a node counter of the demo VPP with its rate per second.
+type demoError struct {
+	node     string
+	reason   string
+	severity string
+	rate     float64
+	// set if counted by the main thread instead of the worker
+	onMain bool
+}
+
+var demoErrors = []demoError{
+	{node: "ip4-input-no-checksum", reason: "ip4 ttl <= 1", severity: "error", rate: 12},
+	{node: "ip4-lookup", reason: "ip4 adjacency drop", severity: "error", rate: 25},
+	{node: "ethernet-input", reason: "l3 mac mismatch", severity: "error", rate: 3},
+	{node: "arp-reply", reason: "ARP replies sent", severity: "info", rate: 1, onMain: true},
+	{node: "vxlan4-input", reason: "no such tunnel", severity: "error", rate: 0.5},
+	{node: "dpdk-input", reason: "rx packet errors", severity: "warn", rate: 0.2},
+}
+
+var demoPunts = []api.PuntStat{
+	{Index: 0, Reason: "ipsec4-spi-0"},
+	{Index: 1, Reason: "ipsec4-no-such-tunnel"},
+	{Index: 2, Reason: "ip4-icmp-reply"},
+}
+
+// demoPolicer is a policer of the demo VPP with rates of packets per color.
+type demoPolicer struct {
+	api.Policer
+	conformRate, exceedRate, violateRate float64
+	frameSize                            float64
+}
+
+var demoPolicers = []demoPolicer{
+	{Policer: api.Policer{Name: "tenant-a-ingress", Type: "1r2c", CIR: 100000, CB: 125000, RateType: "kbps",
+		ConformAction: "transmit", ExceedAction: "drop", ViolateAction: "drop"},
+		conformRate: 8000, exceedRate: 1200, frameSize: 1024},
+	{Policer: api.Policer{Name: "voice-ef", Type: "2r3c-2698", CIR: 20000, EIR: 40000, CB: 25000, EB: 50000, RateType: "kbps",
+		ConformAction: "mark-and-transmit EF", ExceedAction: "mark-and-transmit AF11", ViolateAction: "drop"},
+		conformRate: 2500, exceedRate: 300, violateRate: 40, frameSize: 200},
+}
+
+// demoSRv6SID is an SR policy or a local SID of the demo VPP with its rates
+// of forwarded (good) and dropped (bad) packets.
+type demoSRv6SID struct {
+	api.SRv6SID
+	goodRate, badRate float64
+	frameSize         float64
+}
+
+var demoSRv6SIDs = []demoSRv6SID{
+	{SRv6SID: api.SRv6SID{SID: "2001:db8:100::1", Policy: true, Behavior: "Encapsulation", Type: "Default",
+		SegmentLists: []api.SRv6SegmentList{{Segments: []string{"2001:db8:2::1", "2001:db8:3::d4"}, Weight: 1}},
+		Steering:     []string{"L3 10.0.100.0/24", "L3 172.16.10.0/24"}},
+		goodRate: 6000, frameSize: 512},
+	{SRv6SID: api.SRv6SID{SID: "2001:db8:100::2", Policy: true, Behavior: "Encapsulation", Type: "Spray", FibTable: 10,
+		SegmentLists: []api.SRv6SegmentList{
+			{Segments: []string{"2001:db8:4::1", "2001:db8:5::d6"}, Weight: 1},
+			{Segments: []string{"2001:db8:6::1", "2001:db8:5::d6"}, Weight: 1},
+		},
+		Steering: []string{"L2 GigabitEthernet0/8/0.100"}},
+		goodRate: 800, frameSize: 1024},
+	{SRv6SID: api.SRv6SID{SID: "2001:db8:1::e", Behavior: "End"}, goodRate: 2200, frameSize: 600},
+	{SRv6SID: api.SRv6SID{SID: "2001:db8:1::d4", Behavior: "DX4 (Next-hop: 10.0.0.2, iface: GigabitEthernet0/8/0)"},
+		goodRate: 5400, badRate: 2, frameSize: 540},
+}
+
+// demoMplsEntry is an MPLS FIB entry or an MPLS tunnel of the demo VPP with its
+// rate of forwarded packets, the tunnel is counted by its demo interface.
+type demoMplsEntry struct {
+	api.MplsEntry
+	rate      float64
+	frameSize float64
+}
+
+var demoMplsEntries = []demoMplsEntry{
+	{MplsEntry: api.MplsEntry{Tunnel: "mpls_tunnel0", SwIfIndex: 6,
+		NextHops: []string{"10.0.0.2 GigabitEthernet0/8/0"}, OutLabels: []uint32{1001, 2001}}},
+	{MplsEntry: api.MplsEntry{Label: 100, EOS: true,
+		NextHops: []string{"10.0.0.2 GigabitEthernet0/8/0"}, OutLabels: []uint32{200}},
+		rate: 4200, frameSize: 600},
+	{MplsEntry: api.MplsEntry{Label: 101, EOS: true,
+		NextHops: []string{"192.168.1.2 GigabitEthernet0/9/0"}},
+		rate: 900, frameSize: 1000},
+	{MplsEntry: api.MplsEntry{Label: 300, TableID: 10,
+		NextHops: []string{"10.0.100.2 GigabitEthernet0/8/0.100"}, OutLabels: []uint32{301}},
+		rate: 300, frameSize: 400},
+}
+
+// demoFibTable is a FIB table of the demo VPP with its routes per prefix
+// length, host routes of the learned neighbors grow at the given rate.
+type demoFibTable struct {
+	api.FibTable
+	routes   map[int]uint64
+	hostRate float64
+}
+
+var demoFibTables = []demoFibTable{
+	{FibTable: api.FibTable{TableID: 0, Name: "ipv4-VRF:0"}, routes: map[int]uint64{0: 1, 4: 2, 8: 1, 24: 3, 32: 9}},
+	{FibTable: api.FibTable{TableID: 10, Name: "tenant-a"}, routes: map[int]uint64{0: 1, 16: 12, 24: 240, 32: 40}, hostRate: 0.5},
+	{FibTable: api.FibTable{TableID: 0, Name: "ipv6-VRF:0", IPv6: true}, routes: map[int]uint64{0: 1, 64: 2, 128: 6}},
+}
+
+// demoFibMemory is the 'show fib memory' output of the demo VPP
+const demoFibMemory = `FIB memory
+ Tables:
+            SAFI              Number     Bytes
+        IPv4 unicast             2     1197152
+        IPv6 unicast             1      273620
+        MPLS                     1      4202
+       IPv4 multicast            2      2416
+       IPv6 multicast            1     4194804
+`
+
+// demoHeaps are the 'show memory' outputs of the demo VPP heaps other than
+// the main heap
+var demoHeaps = map[string]string{
+	"show memory api-segment": "API segment\n" +
+		"  base 0x130000000, size 64m, locked, unmap-on-destroy, name 'api segment'\n" +
+		"    page stats: page-size 4K, total 16384, mapped 516, not-mapped 15868\n" +
+		"      numa 0: 516 pages, 2.02m bytes\n" +
+		"    total: 63.99M, used: 1.93M, free: 62.06M, trimmable: 62.06M\n",
+	"show memory stats-segment": "Stats segment\n" +
+		"  base 0x7f1000000000, size 32m, locked, unmap-on-destroy, name 'stat segment'\n" +
+		"    page stats: page-size 4K, total 8192, mapped 163, not-mapped 8029\n" +
+		"      numa 0: 163 pages, 652.00k bytes\n" +
+		"    total: 31.99M, used: 598.21K, free: 31.41M, trimmable: 31.41M\n",
+	"show memory numa-heaps": "Numa 0 uses the main heap...\n",
+	"show memory map": "StartAddr          size   FD PageSz  Pages Numa0 NotMap Name\n" +
+		"00007f0000000000     1g        4K 262144  7040 255104 main heap\n" +
+		"0000000130000000    64m        4K  16384   516  15868 api segment\n" +
+		"00007f1000000000    32m    9   4K   8192   163   8029 stat segment\n",
+}
+
+// demoNeighborRefresh is the period of the ARP/ND refresh of dynamic demo neighbors
+const demoNeighborRefresh = 30 * time.Second
+
+// demoNeighbor is a neighbor of the demo VPP, dynamic neighbors are
+// refreshed periodically, shifted by the offset.
+type demoNeighbor struct {
+	api.Neighbor
+	offset time.Duration
+}
+
+// demoLLDPNeighbors are the LLDP peers of the physical interfaces,
+// their packets are received every 30s.
+var demoLLDPNeighbors = []api.LLDPNeighbor{
+	{Interface: "GigabitEthernet0/8/0", ChassisID: "0c:42:a1:00:10:00", PortID: "Ethernet1/1", Active: true},
+	{Interface: "GigabitEthernet0/9/0", ChassisID: "0c:42:a1:00:20:00", PortID: "Ethernet1/7", Active: true},
+}
+
+// demoMemifRingSize is the size of the rings of the demo memif interface.
+const demoMemifRingSize = 1024
+
+// demoMemifFillRate is the rate the master-to-slave ring of the demo memif
+// interface fills by when the container app stalls every 20s, the ring stays
+// full for the rest of the period.
+const demoMemifFillRate = 80
+
+var demoNeighbors = []demoNeighbor{
+	{Neighbor: api.Neighbor{SwIfIndex: 1, IP: "10.0.0.2", MAC: "52:54:00:00:01:02"}, offset: 5 * time.Second},
+	{Neighbor: api.Neighbor{SwIfIndex: 1, IP: "10.0.0.3", MAC: "52:54:00:00:01:03"}, offset: 17 * time.Second},
+	{Neighbor: api.Neighbor{SwIfIndex: 1, IP: "10.0.0.254", MAC: "52:54:00:00:01:fe", Static: true, NoFibEntry: true}},
+	{Neighbor: api.Neighbor{SwIfIndex: 2, IP: "10.0.100.2", MAC: "52:54:00:00:64:02"}, offset: 11 * time.Second},
+	{Neighbor: api.Neighbor{SwIfIndex: 3, IP: "192.168.1.2", MAC: "52:54:00:ab:00:02", Static: true}},
+	{Neighbor: api.Neighbor{SwIfIndex: 3, IP: "fd00::2", MAC: "52:54:00:ab:00:02"}, offset: 23 * time.Second},
+	{Neighbor: api.Neighbor{SwIfIndex: 3, IP: "fe80::5054:ff:feab:2", MAC: "52:54:00:ab:00:02"}, offset: 2 * time.Second},
+}
+
+// demoAPIClients is the 'show api clients' output of the demo VPP
+const demoAPIClients = `Shared memory clients
+                Name      PID   Queue Length           Queue VA Health
+           vpp-agent     1207              0 0x00000001301a1b40 OK
+       stats-exporter     1311             37 0x00000001301c2e80 questionable
+Socket clients
+                Name  Fildesc
+              vpptop       27
+`
+
+// demoAPIRing is a message ring of the demo API segment, the allocations
+// are hit by the rate and missed by the missRate per second.
+type demoAPIRing struct {
+	owner          string
+	size, elements uint64
+	rate, missRate float64
+}
+
+var demoAPIRings = []demoAPIRing{
+	{owner: "vlib", size: 256, elements: 1024, rate: 120},
+	{owner: "vlib", size: 1024, elements: 256, rate: 15},
+	{owner: "vlib", size: 4096, elements: 64, rate: 2, missRate: 0.1},
+	{owner: "clnt", size: 256, elements: 1024, rate: 135},
+	{owner: "clnt", size: 1024, elements: 256, rate: 4},
+}
+
+// demo sessions as 'show session verbose' lines
+var demoSessions = []string{
+	"[0:0][T] 10.0.0.1:80->10.0.0.2:43210        ESTABLISHED    0         0",
+	"[0:1][T] 10.0.0.1:80->10.0.0.3:43211        ESTABLISHED    0         0",
+	"[0:2][T] 10.0.0.1:80->10.0.0.4:43212        CLOSE_WAIT     0         0",
+	"[0:3][T] 0.0.0.0:80->0.0.0.0:0              LISTEN         0         0",
+	"[0:4][U] 10.0.0.1:53->0.0.0.0:0             LISTEN         0         0",
+}
+
+// Handler is a VPP handler returning synthetic counters growing over time.
+// Counters are computed from the time since the handler was created,
+// so they are deterministic for a given clock.
+type Handler struct {
+	sync.Mutex
+	now   func() time.Time
+	start time.Time
+	// times of the last clear of interface and runtime counters
+	ifacesCleared  time.Time
+	runtimeCleared time.Time
+	apiTrace       bool
+	// running packet captures by their 'pcap' CLI commands
+	captures map[string]demoCapture
+}
+
+// demoCapture is a running packet capture.
+type demoCapture struct {
+	started time.Time
+	max     uint64
+	file    string
+}
+
+// NewHandler returns a new demo handler.
+func NewHandler() *Handler {
+	return newHandler(time.Now)
+}
+
+func newHandler(now func() time.Time) *Handler {
+	start := now()
+	return &Handler{
+		now:            now,
+		start:          start,
+		ifacesCleared:  start,
+		runtimeCleared: start,
+		captures:       make(map[string]demoCapture),
+	}
+}
+
+// since returns seconds elapsed since the time.
+func (h *Handler) since(t time.Time) float64 {
+	return h.now().Sub(t).Seconds()
+}
+
+// count returns the counter value of the rate after the given seconds.
+func count(rate, seconds float64) uint64 {
+	return uint64(rate * seconds)
+}
+
+func (h *Handler) RunCli(_ context.Context, cmd string) (string, error) {
+	h.Lock()
+	defer h.Unlock()
+
+	switch strings.TrimSpace(cmd) {
+	case "clear interfaces":
+		h.ifacesCleared = h.now()
+	case "clear runtime":
+		h.runtimeCleared = h.now()
+	case "clear errors":
+		// error counters of the stats segment are not cleared
+	case "show memory main-heap verbose":
+		return h.memory(), nil
+	case "show memory api-segment", "show memory stats-segment", "show memory numa-heaps", "show memory map":
+		return demoHeaps[strings.TrimSpace(cmd)], nil
+	case "show cpu":
+		return "Model name:               Demo CPU\nBase frequency:           2.50 GHz\n", nil
+	case "show runtime max":
+		return h.runtimeMax(), nil
+	case "show session verbose":
+		return "Connection                               State          Rx-f      Tx-f\n" +
+			strings.Join(demoSessions, "\n") + "\n", nil
+	case "show app":
+		return "Index     Name                Namespace\n" +
+			"0         http-server         default\n" +
+			"1         dns-proxy           default\n" +
+			"2         proxy               tenant-a\n", nil
+	case "show bond details":
+		return "BondEthernet0\n" +
+			"  mode: lacp\n" +
+			"  load balance: l34\n" +
+			"  number of active members: 2\n" +
+			"    GigabitEthernet0/8/0\n" +
+			"    GigabitEthernet0/9/0\n" +
+			"  number of members: 2\n" +
+			"    GigabitEthernet0/8/0\n" +
+			"    GigabitEthernet0/9/0\n" +
+			"  device instance: 0\n" +
+			"  interface id: 0\n", nil
+	case "show lacp":
+		return demoLACP, nil
+	case "show ip fib summary":
+		return h.fibSummary(false), nil
+	case "show ip6 fib summary":
+		return h.fibSummary(true), nil
+	case "show fib memory":
+		return demoFibMemory, nil
+	case "show api clients":
+		return demoAPIClients, nil
+	case "show api ring-stats":
+		return h.apiRingStats(), nil
+	case "api trace on", "api trace off":
+		h.apiTrace = cmd == "api trace on"
+	case "api trace status":
+		if h.apiTrace {
+			return "RX Trace: enabled, 3 of 256 items\n", nil
+		}
+		return "RX Trace: disabled\n", nil
+	case "api trace dump":
+		if !h.apiTrace {
+			return "", nil
+		}
+		return "[0]: vl_api_sw_interface_dump_t:\n  name_filter_valid: 0\n" +
+			"[1]: vl_api_show_version_t:\n" +
+			"[2]: vl_api_cli_inband_t:\n  cmd: show runtime max\n", nil
+	case "api trace free":
+		h.apiTrace = false
+	default:
+		if strings.HasPrefix(cmd, "show interface features ") {
+			return interfaceFeatures(strings.TrimPrefix(cmd, "show interface features ")), nil
+		}
+		for _, cli := range []string{"pcap dispatch trace", "pcap trace"} {
+			if strings.HasPrefix(cmd, cli+" ") {
+				return h.pcapTrace(cli, strings.Fields(strings.TrimPrefix(cmd, cli))), nil
+			}
+		}
+		if strings.HasPrefix(cmd, "api trace save") {
+			return "API trace saved to " + strings.TrimSpace(strings.TrimPrefix(cmd, "api trace save")) + "\n", nil
+		}
+		return fmt.Sprintf("unknown input `%s'\n", cmd), nil
+	}
+	return "", nil
+}
+
+// demo features enabled per interface and arc
+var demoFeatures = map[string]map[string][]string{
+	"GigabitEthernet0/8/0": {
+		"ip4-unicast": {"nat44-in2out-worker-handoff", "acl-plugin-in-ip4-fa"},
+		"ip4-output":  {"nat44-in2out-output-worker-handoff"},
+	},
+	"GigabitEthernet0/9/0": {
+		"ip4-unicast": {"nat44-out2in-worker-handoff"},
+		"ip6-unicast": {"ip6-policer-classify"},
+	},
+	"local0": {
+		"ip4-unicast": {"ip4-not-enabled"},
+		"ip6-unicast": {"ip6-not-enabled"},
+	},
+}
+
+// demoLACP is the 'show lacp' output of the demo bond members
+const demoLACP = `                                                        actor state                      partner state
+interface name            sw_if_index  bond interface   exp/def/dis/col/syn/agg/tim/act  exp/def/dis/col/syn/agg/tim/act
+GigabitEthernet0/8/0      1            BondEthernet0      0   0   1   1   1   1   1   1    0   0   1   1   1   1   1   1
+  LAG ID: [(ffff,52-54-00-12-34-56,0001,00ff,0001), (ffff,52-54-00-00-00-01,0001,00ff,0001)]
+  RX-state: CURRENT, TX-state: TRANSMIT, MUX-state: COLLECTING_DISTRIBUTING, PTX-state: PERIODIC_TX
+GigabitEthernet0/9/0      3            BondEthernet0      0   0   1   1   1   1   1   1    0   0   1   1   1   1   1   1
+  LAG ID: [(ffff,52-54-00-12-34-56,0001,00ff,0002), (ffff,52-54-00-00-00-01,0001,00ff,0002)]
+  RX-state: CURRENT, TX-state: TRANSMIT, MUX-state: COLLECTING_DISTRIBUTING, PTX-state: PERIODIC_TX
+`
+
+// pcapTrace controls the packet capture of the 'pcap' CLI command,
+// the capture captures 100 packets per second.
+func (h *Handler) pcapTrace(cli string, args []string) string {
+	capture, running := h.captures[cli]
+	var action string
+	if len(args) != 0 {
+		action = args[0]
+	}
+	switch action {
+	case "status":
+		if !running {
+			return "pcap capture is off...\n"
+		}
+		captured := count(100, h.since(capture.started))
+		if captured > capture.max {
+			captured = capture.max
+		}
+		return fmt.Sprintf("pcap capture is on...\ncapture is %d of %d pkts...\ncapture to file %s\n",
+			captured, capture.max, capture.file)
+	case "off":
+		if !running {
+			return "No packets captured...\n"
+		}
+		delete(h.captures, cli)
+		captured := count(100, h.since(capture.started))
+		if captured > capture.max {
+			captured = capture.max
+		}
+		return fmt.Sprintf("Write %d packets to %s, file is %d bytes\n", captured, capture.file, captured*128)
+	}
+
+	capture = demoCapture{started: h.now(), max: 100, file: "/tmp/" + strings.ReplaceAll(cli, " ", "_")}
+	for i := 0; i+1 < len(args); i++ {
+		switch args[i] {
+		case "max":
+			capture.max, _ = strconv.ParseUint(args[i+1], 10, 64)
+		case "file":
+			capture.file = "/tmp/" + args[i+1]
+		}
+	}
+	h.captures[cli] = capture
+	return ""
+}
+
+// apiRingStats returns the 'show api ring-stats' output.
+func (h *Handler) apiRingStats() string {
+	seconds := h.since(h.start)
+	var b strings.Builder
+	var misses uint64
+	b.WriteString("Main API segment rings:\n")
+	fmt.Fprintf(&b, "%-8s%8s%11s%11s%11s\n", "Owner", "Size", "Nitems", "Hits", "Misses")
+	for _, ring := range demoAPIRings {
+		missed := count(ring.missRate, seconds)
+		misses += missed
+		fmt.Fprintf(&b, "%-8s%8d%11d%11d%11d\n", ring.owner, ring.size, ring.elements, count(ring.rate, seconds), missed)
+	}
+	fmt.Fprintf(&b, "%d ring miss fallback allocations\n", misses)
+	b.WriteString("0 application restarts, 0 reclaimed msgs, 0 garbage collects\n")
+	return b.String()
+}
+
+// fibSummary returns the 'show ip fib summary' or 'show ip6 fib summary' output.
+func (h *Handler) fibSummary(ipv6 bool) string {
+	hostLen := 32
+	if ipv6 {
+		hostLen = 128
+	}
+	var b strings.Builder
+	for i, table := range demoFibTables {
+		if table.IPv6 != ipv6 {
+			continue
+		}
+		fmt.Fprintf(&b, "%s, fib_index:%d, flow hash:[src dst sport dport proto ] epoch:0 flags:none locks:[default-route:1, ]\n", table.Name, i)
+		fmt.Fprintf(&b, "%20s%16s\n", "Prefix length", "Count")
+		for prefixLen := 0; prefixLen <= hostLen; prefixLen++ {
+			routes, ok := table.routes[prefixLen]
+			if !ok {
+				continue
+			}
+			if prefixLen == hostLen {
+				routes += count(table.hostRate, h.since(h.start))
+			}
+			fmt.Fprintf(&b, "%20d%16d\n", prefixLen, routes)
+		}
+	}
+	return b.String()
+}
+
+// interfaceFeatures returns the 'show interface features' output of the interface.
+func interfaceFeatures(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Feature paths configured on %s...\n", name)
+	for _, arc := range []string{"ip4-unicast", "ip4-output", "ip6-unicast", "ip6-output"} {
+		fmt.Fprintf(&b, "\n%s:\n", arc)
+		features := demoFeatures[name][arc]
+		if len(features) == 0 {
+			b.WriteString("  none configured\n")
+		}
+		for _, feature := range features {
+			fmt.Fprintf(&b, "  %s\n", feature)
+		}
+	}
+	b.WriteString("\nDriver rx redirect: none\n")
+	return b.String()
+}
+
+// memory returns the 'show memory main-heap verbose' output with usage growing slowly.
+func (h *Handler) memory() string {
+	used := 24.5 + float64(int(h.since(h.start))%600)/100
+	return fmt.Sprintf("Thread 0 vpp_main\n"+
+		"  base 0x7f0000000000, size 1g, locked, unmap-on-destroy, name 'main heap'\n"+
+		"    page stats: page-size 4K, total 262144, mapped 7040, not-mapped 255104\n"+
+		"      numa 0: 7040 pages, 27.50m bytes\n"+
+		"    total: 1023.99M, used: %.2fM, free: %.2fM, trimmable: 998.39M\n", used, 1023.99-used)
+}
+
+// runtimeMax returns the 'show runtime max' output of all threads.
+func (h *Handler) runtimeMax() string {
+	var b strings.Builder
+	for _, thread := range h.runtimeThreads() {
+		fmt.Fprintf(&b, "Thread %d %s (lcore %d)\n", thread.ID, thread.Name, thread.ID+1)
+		fmt.Fprintf(&b, "%-30s%-16s%12s%12s%12s%18s%16s\n",
+			"Name", "State", "Calls", "Vectors", "Suspends", "Max Node Clocks", "Vectors at Max")
+		for _, item := range thread.Items {
+			var vectors uint64
+			if item.Calls > 0 {
+				vectors = uint64(item.VectorsPerCall*1.5) + 1
+			}
+			fmt.Fprintf(&b, "%-30s%-16s%12d%12d%12d%18.2e%16d\n",
+				item.Name, item.State, item.Calls, item.Vectors, item.Suspends, item.Clocks*4, vectors)
+		}
+	}
+	return b.String()
+}
+
+func (h *Handler) Ping(_ context.Context) error {
+	return nil
+}
+
+func (h *Handler) DumpInterfaces(_ context.Context) (map[uint32]*api.InterfaceDetails, error) {
+	result := make(map[uint32]*api.InterfaceDetails, len(demoIfaces))
+	for _, iface := range demoIfaces {
+		result[iface.index] = &api.InterfaceDetails{
+			Name:         iface.name,
+			InternalName: iface.name,
+			SwIfIndex:    iface.index,
+			SupSwIfIndex: iface.supIndex,
+			IsEnabled:    iface.up,
+			IsLinkUp:     iface.up,
+			IPAddresses:  iface.ip,
+			MTU:          []uint32{9000, 0, 0, 0},
+			VrfIPv4:      iface.vrf,
+			VrfIPv6:      iface.vrf,
+			Device:       iface.device,
+		}
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpInterfaceStats(_ context.Context) (*govppapi.InterfaceStats, error) {
+	h.Lock()
+	seconds := h.since(h.ifacesCleared)
+	h.Unlock()
+
+	stats := &govppapi.InterfaceStats{}
+	for _, iface := range demoIfaces {
+		rx, tx := count(iface.rxRate, seconds), count(iface.txRate, seconds)
+		stats.Interfaces = append(stats.Interfaces, govppapi.InterfaceCounters{
+			InterfaceIndex: iface.index,
+			InterfaceName:  iface.name,
+			Rx:             govppapi.InterfaceCounterCombined{Packets: rx, Bytes: uint64(float64(rx) * iface.frameSize)},
+			Tx:             govppapi.InterfaceCounterCombined{Packets: tx, Bytes: uint64(float64(tx) * iface.frameSize)},
+			RxUnicast:      govppapi.InterfaceCounterCombined{Packets: rx * 9 / 10, Bytes: uint64(float64(rx*9/10) * iface.frameSize)},
+			RxMulticast:    govppapi.InterfaceCounterCombined{Packets: rx / 20, Bytes: uint64(float64(rx/20) * iface.frameSize)},
+			RxBroadcast:    govppapi.InterfaceCounterCombined{Packets: rx / 20, Bytes: uint64(float64(rx/20) * iface.frameSize)},
+			TxUnicast:      govppapi.InterfaceCounterCombined{Packets: tx, Bytes: uint64(float64(tx) * iface.frameSize)},
+			RxErrors:       rx / 50000,
+			Drops:          rx / 2000,
+			Punts:          rx / 100000,
+			IP4:            rx * 8 / 10,
+			IP6:            rx / 10,
+			RxNoBuf:        rx / 1000000,
+			RxMiss:         rx / 400000,
+		})
+	}
+	return stats, nil
+}
+
+func (h *Handler) DumpNodeCounters(_ context.Context) (*api.NodeCounterInfo, error) {
+	seconds := h.since(h.start)
+
+	info := &api.NodeCounterInfo{}
+	for _, e := range demoErrors {
+		c := count(e.rate, seconds)
+		perThread := []uint64{0, c}
+		if e.onMain {
+			perThread = []uint64{c, 0}
+		}
+		info.Counters = append(info.Counters, api.NodeCounter{
+			Count:     c,
+			Node:      e.node,
+			Reason:    e.reason,
+			Severity:  e.severity,
+			PerThread: perThread,
+		})
+	}
+	return info, nil
+}
+
+func (h *Handler) DumpRuntimeInfo(_ context.Context) (*api.RuntimeInfo, error) {
+	h.Lock()
+	defer h.Unlock()
+	return &api.RuntimeInfo{Threads: h.runtimeThreads()}, nil
+}
+
+// runtimeThreads returns the runtime counters of the main thread and a single
+// worker. Input nodes run on the worker, the main thread polls the epoll input.
+func (h *Handler) runtimeThreads() []api.RuntimeThread {
+	seconds := h.since(h.runtimeCleared)
+
+	thread := func(id uint, name string, nodes []demoNode) api.RuntimeThread {
+		t := api.RuntimeThread{ID: id, Name: name, Time: seconds}
+		for i, node := range nodes {
+			calls := count(node.callRate, seconds)
+			vectors := count(node.callRate*node.vectorsPerCall, seconds)
+			var vectorsPerCall float64
+			if calls > 0 {
+				vectorsPerCall = float64(vectors) / float64(calls)
+			}
+			t.Items = append(t.Items, api.RuntimeItem{
+				Index:          uint(i),
+				Name:           node.name,
+				State:          node.state,
+				Calls:          calls,
+				Vectors:        vectors,
+				Clocks:         node.clocks,
+				VectorsPerCall: vectorsPerCall,
+			})
+		}
+		return t
+	}
+
+	var mainNodes, workerNodes []demoNode
+	for _, node := range demoNodes {
+		if node.name == "unix-epoll-input" {
+			mainNodes = append(mainNodes, node)
+			continue
+		}
+		idle := node
+		idle.callRate = 0
+		mainNodes = append(mainNodes, idle)
+		workerNodes = append(workerNodes, node)
+	}
+	return []api.RuntimeThread{
+		thread(0, "vpp_main", mainNodes),
+		thread(1, "vpp_wk_0", workerNodes),
+	}
+}
+
+func (h *Handler) DumpPlugins(_ context.Context) ([]api.PluginInfo, error) {
+	return []api.PluginInfo{
+		{Name: "dpdk_plugin.so", Path: "/usr/lib/vpp_plugins/dpdk_plugin.so", Version: VPPVersion, Description: "Data Plane Development Kit (DPDK)"},
+		{Name: "memif_plugin.so", Path: "/usr/lib/vpp_plugins/memif_plugin.so", Version: VPPVersion, Description: "Packet Memory Interface (memif)"},
+		{Name: "nat_plugin.so", Path: "/usr/lib/vpp_plugins/nat_plugin.so", Version: VPPVersion, Description: "Network Address Translation (NAT)"},
+		{Name: "vxlan_plugin.so", Path: "/usr/lib/vpp_plugins/vxlan_plugin.so", Version: VPPVersion, Description: "VxLan Tunnels"},
+	}, nil
+}
+
+func (h *Handler) DumpVersion(_ context.Context) (*api.VersionInfo, error) {
+	return &api.VersionInfo{
+		Program:        "vpe",
+		Version:        VPPVersion,
+		BuildDate:      h.start.Format(time.RFC1123),
+		BuildDirectory: "/demo",
+	}, nil
+}
+
+func (h *Handler) DumpSession(_ context.Context) (*api.SessionInfo, error) {
+	return &api.SessionInfo{
+		PID:    1,
+		Uptime: h.since(h.start),
+	}, nil
+}
+
+func (h *Handler) DumpThreads(_ context.Context) ([]api.ThreadData, error) {
+	return []api.ThreadData{
+		{ID: 0, Name: "vpp_main", PID: 1, CPUID: 1, Core: 1},
+		{ID: 1, Name: "vpp_wk_0", Type: "workers", PID: 2, CPUID: 2, Core: 2},
+	}, nil
+}
+
+func (h *Handler) DumpRxPlacement(_ context.Context) ([]api.RxPlacement, error) {
+	return []api.RxPlacement{
+		{SwIfIndex: 1, QueueID: 0, ThreadID: 1},
+		{SwIfIndex: 3, QueueID: 0, ThreadID: 1},
+		{SwIfIndex: 5, QueueID: 0, ThreadID: 0},
+	}, nil
+}
+
+func (h *Handler) DumpPuntStats(_ context.Context) ([]api.PuntStat, error) {
+	seconds := h.since(h.start)
+
+	result := make([]api.PuntStat, len(demoPunts))
+	for i, punt := range demoPunts {
+		punt.Packets = count(float64(i)*2, seconds)
+		punt.Bytes = punt.Packets * 128
+		result[i] = punt
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpPolicers(_ context.Context) ([]api.Policer, error) {
+	h.Lock()
+	seconds := h.since(h.start)
+	h.Unlock()
+
+	counter := func(rate, frameSize float64) govppapi.InterfaceCounterCombined {
+		packets := count(rate, seconds)
+		return govppapi.InterfaceCounterCombined{Packets: packets, Bytes: uint64(float64(packets) * frameSize)}
+	}
+	result := make([]api.Policer, 0, len(demoPolicers))
+	for _, policer := range demoPolicers {
+		p := policer.Policer
+		p.Conform = counter(policer.conformRate, policer.frameSize)
+		p.Exceed = counter(policer.exceedRate, policer.frameSize)
+		p.Violate = counter(policer.violateRate, policer.frameSize)
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpFibTables(_ context.Context) ([]api.FibTable, error) {
+	result := make([]api.FibTable, len(demoFibTables))
+	for i, table := range demoFibTables {
+		result[i] = table.FibTable
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpNeighbors(_ context.Context) ([]api.Neighbor, error) {
+	result := make([]api.Neighbor, len(demoNeighbors))
+	for i, neighbor := range demoNeighbors {
+		result[i] = neighbor.Neighbor
+		result[i].Age = h.neighborAge(neighbor)
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpLLDPNeighbors(_ context.Context) ([]api.LLDPNeighbor, error) {
+	result := make([]api.LLDPNeighbor, len(demoLLDPNeighbors))
+	for i, neighbor := range demoLLDPNeighbors {
+		result[i] = neighbor
+		result[i].LastHeard = math.Mod(h.since(h.start)+float64(i*11), 30)
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpAPITraceStatus(ctx context.Context) (*api.APITrace, error) {
+	return api.DumpAPITraceStatus(ctx, h.RunCli)
+}
+
+func (h *Handler) DumpAPITrace(ctx context.Context) ([]api.APITraceMessage, error) {
+	return api.DumpAPITrace(ctx, h.RunCli)
+}
+
+func (h *Handler) SetAPITrace(ctx context.Context, enable bool) error {
+	return api.SetAPITrace(ctx, h.RunCli, enable)
+}
+
+func (h *Handler) SaveAPITrace(ctx context.Context, file string) error {
+	return api.SaveAPITrace(ctx, h.RunCli, file)
+}
+
+func (h *Handler) FreeAPITrace(ctx context.Context) error {
+	return api.FreeAPITrace(ctx, h.RunCli)
+}
+
+func (h *Handler) DumpMemifRings(_ context.Context) ([]api.MemifRing, error) {
+	h.Lock()
+	seconds := h.since(h.start)
+	h.Unlock()
+
+	// the indexes of the rings are 16-bit
+	rxHead := uint32(count(8000, seconds) & 0xffff)
+	txHead := uint32(count(9500, seconds) & 0xffff)
+	txUsed := uint32(math.Min(demoMemifRingSize, math.Mod(seconds, 20)*demoMemifFillRate))
+	return []api.MemifRing{
+		{Interface: "memif0/0", Direction: "slave-to-master", Size: demoMemifRingSize,
+			Head: rxHead, Tail: (rxHead - 3) & 0xffff},
+		{Interface: "memif0/0", Direction: "master-to-slave", Size: demoMemifRingSize,
+			Head: txHead, Tail: (txHead - txUsed) & 0xffff},
+	}, nil
+}
+
+func (h *Handler) DumpSRv6(_ context.Context) ([]api.SRv6SID, error) {
+	h.Lock()
+	seconds := h.since(h.start)
+	h.Unlock()
+
+	counter := func(rate, frameSize float64) govppapi.InterfaceCounterCombined {
+		packets := count(rate, seconds)
+		return govppapi.InterfaceCounterCombined{Packets: packets, Bytes: uint64(float64(packets) * frameSize)}
+	}
+	result := make([]api.SRv6SID, 0, len(demoSRv6SIDs))
+	for _, sid := range demoSRv6SIDs {
+		s := sid.SRv6SID
+		s.Good = counter(sid.goodRate, sid.frameSize)
+		s.Bad = counter(sid.badRate, sid.frameSize)
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (h *Handler) DumpMpls(_ context.Context) ([]api.MplsEntry, error) {
+	h.Lock()
+	seconds := h.since(h.start)
+	h.Unlock()
+
+	result := make([]api.MplsEntry, 0, len(demoMplsEntries))
+	for _, entry := range demoMplsEntries {
+		e := entry.MplsEntry
+		packets := count(entry.rate, seconds)
+		e.Counter = govppapi.InterfaceCounterCombined{Packets: packets, Bytes: uint64(float64(packets) * entry.frameSize)}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func (h *Handler) ClearInterfaceCounters(_ context.Context) error {
+	h.Lock()
+	defer h.Unlock()
+	h.ifacesCleared = h.now()
+	return nil
+}
+
+// neighborAge returns seconds since the last refresh of the neighbor,
+// static neighbors are not refreshed.
+func (h *Handler) neighborAge(neighbor demoNeighbor) float64 {
+	age := h.since(h.start)
+	if neighbor.Static {
+		return age
+	}
+	return math.Mod(age+neighbor.offset.Seconds(), demoNeighborRefresh.Seconds())
+}
+
+// WatchNeighbors calls the onChange once any of the dynamic neighbors is refreshed.
+func (h *Handler) WatchNeighbors(ctx context.Context, onChange func()) error {
+	ages := make([]float64, len(demoNeighbors))
+	for i, neighbor := range demoNeighbors {
+		ages[i] = h.neighborAge(neighbor)
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refreshed := false
+				for i, neighbor := range demoNeighbors {
+					age := h.neighborAge(neighbor)
+					if age < ages[i] {
+						refreshed = true
+					}
+					ages[i] = age
+				}
+				if refreshed {
+					onChange()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (h *Handler) DumpTunnels(_ context.Context) ([]api.Tunnel, error) {
+	return []api.Tunnel{
+		{Type: "vxlan", SwIfIndex: 5, Src: "192.168.1.1", Dst: "192.168.1.2", ID: 100},
+	}, nil
+}
+
+// Capabilities of the demo handler, all data but the queue
+// counters of the stats segment are simulated.
+func (h *Handler) Capabilities() api.Capabilities {
+	return api.Capabilities{
+		CLI:           true,
+		ErrorSeverity: true,
+		Threads:       true,
+	}
+}
+
+func (h *Handler) Close() {}
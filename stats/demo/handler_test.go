@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package demo
+
+import (
+	"context"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"go.pantheon.tech/vpptop/stats"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// testClock is a clock advanced manually.
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) now() time.Time { return c.t }
+
+func TestHandler_interfaceCounters(t *testing.T) {
+	clock := &testClock{t: time.Unix(0, 0)}
+	h := newHandler(clock.now)
+	ctx := context.Background()
+
+	clock.t = clock.t.Add(10 * time.Second)
+	ifStats, err := h.DumpInterfaceStats(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := ifStats.Interfaces[1].Rx.Packets, uint64(1200000); got != want {
+		t.Errorf("rx packets after 10s: got %d, want %d", got, want)
+	}
+
+	if _, err := h.RunCli(ctx, "clear interfaces"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ifStats, err = h.DumpInterfaceStats(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ifStats.Interfaces[1].Rx.Packets; got != 0 {
+		t.Errorf("rx packets after clear: got %d, want 0", got)
+	}
+}
+
+func TestHandler_provider(t *testing.T) {
+	clock := &testClock{t: time.Unix(0, 0)}
+	h := newHandler(clock.now)
+	ctx := context.Background()
+
+	provider := stats.NewVppProvider(nil, ioutil.Discard)
+	if err := provider.ConnectHandler(h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer provider.Disconnect()
+	clock.t = clock.t.Add(time.Second)
+
+	ifaces, err := provider.GetInterfaces(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ifaces) != len(demoIfaces) {
+		t.Errorf("interfaces: got %d, want %d", len(ifaces), len(demoIfaces))
+	}
+	for _, iface := range ifaces {
+		if iface.InterfaceName != "memif0/0" {
+			continue
+		}
+		if len(iface.MemifRings) != 2 || iface.MemifRings[1].Occupancy() != demoMemifFillRate {
+			t.Errorf("memif rings: got %+v", iface.MemifRings)
+		}
+	}
+
+	nodes, err := provider.GetNodes(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var lookup *api.Node
+	for i := range nodes {
+		if nodes[i].Name == "ip4-lookup" && nodes[i].Calls > 0 {
+			lookup = &nodes[i]
+		}
+	}
+	if lookup == nil {
+		t.Fatalf("active ip4-lookup node not found")
+	}
+	if lookup.MaxClocks == 0 || lookup.ClocksPercent == 0 {
+		t.Errorf("ip4-lookup max clocks and clock share not set: %+v", *lookup)
+	}
+
+	sessions, err := provider.GetSessions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var established uint64
+	for _, s := range sessions {
+		if s.Protocol == "tcp" && s.State == "ESTABLISHED" {
+			established = s.Count
+		}
+	}
+	if established != 2 {
+		t.Errorf("established tcp sessions: got %d, want 2", established)
+	}
+
+	clients, err := provider.GetAPIClients(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clients.Clients) != 3 || clients.Clients[0].Name != "stats-exporter" ||
+		clients.Clients[0].QueueLength != 37 || !clients.Clients[2].Socket || clients.Clients[2].FD != 27 {
+		t.Errorf("api clients: got %+v", clients.Clients)
+	}
+	if len(clients.Rings) != len(demoAPIRings) || clients.Rings[0].Hits != 120 {
+		t.Errorf("api rings: got %+v", clients.Rings)
+	}
+	if clients.SegmentSize == 0 || clients.SegmentUsed == 0 {
+		t.Errorf("api segment memory not set: %+v", *clients)
+	}
+
+	opts := api.CaptureOptions{MaxPackets: 150, File: "demo.pcap"}
+	if err := provider.StartCapture(ctx, api.PcapTrace, opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clock.t = clock.t.Add(time.Second)
+	captures, err := provider.GetCaptures(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := api.PacketCapture{Kind: api.PcapTrace, Enabled: true, Captured: 100, MaxPackets: 150, File: "/tmp/demo.pcap"}
+	if got := captures[0]; got.Enabled != want.Enabled || got.Captured != want.Captured ||
+		got.MaxPackets != want.MaxPackets || got.File != want.File {
+		t.Errorf("pcap capture: got %+v, want %+v", got, want)
+	}
+	if captures[1].Enabled {
+		t.Errorf("dispatch capture enabled: %+v", captures[1])
+	}
+}
+
+// TestHandler_clearWhilePolling clears the error counters while the errors
+// and drops/punts tabs are polled, run it with -race to check the baseline
+// of the cleared counters is not accessed concurrently.
+func TestHandler_clearWhilePolling(t *testing.T) {
+	clock := &testClock{t: time.Unix(0, 0).Add(time.Minute)}
+	h := newHandler(clock.now)
+	ctx := context.Background()
+
+	provider := stats.NewVppProvider(nil, ioutil.Discard)
+	if err := provider.ConnectHandler(h); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer provider.Disconnect()
+
+	const polls = 50
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < polls; i++ {
+			if _, err := provider.GetErrors(ctx); err != nil {
+				t.Errorf("errors poll: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < polls; i++ {
+			if _, err := provider.GetDropsPunts(ctx); err != nil {
+				t.Errorf("drops/punts poll: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < polls; i++ {
+			// clear only a part of the counters, the others keep their counts
+			match := func(e api.Error) bool { return e.Node == "ip4-lookup" }
+			if err := provider.ClearErrorCounters(ctx, match); err != nil {
+				t.Errorf("clear: %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	errors, err := provider.GetErrors(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, e := range errors {
+		if e.Node == "ip4-lookup" {
+			t.Errorf("cleared counter still counted: %+v", e)
+		}
+	}
+}
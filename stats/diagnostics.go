@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+const (
+	// diagnosticFailures is the number of failures in a row of a handler
+	// request after which the diagnostic bundle is logged.
+	diagnosticFailures = 3
+	// diagnosticOutputs is the number of the last CLI outputs kept
+	// for the diagnostic bundle.
+	diagnosticOutputs = 5
+	// diagnosticOutputMax is the maximal length of a kept CLI output,
+	// longer outputs are truncated.
+	diagnosticOutputMax = 2048
+)
+
+// cliOutput is the output of a CLI command kept for the diagnostic bundle.
+type cliOutput struct {
+	cmd    string
+	output string
+	at     time.Time
+}
+
+// diagnostics collects what is needed to make a bug report actionable:
+// the connected VPP, the chosen handler and the last CLI outputs. Once
+// a handler request fails repeatedly, the bundle is logged (only once).
+type diagnostics struct {
+	sync.Mutex
+	// name of the chosen handler and its binapi version
+	handler       string
+	binapiVersion string
+	// version and plugins of the connected VPP
+	info *api.VPPInfo
+	// failures in a row by the request name
+	failures map[string]int
+	// last CLI outputs, the oldest first
+	outputs []cliOutput
+	// set once the bundle was logged
+	logged bool
+}
+
+// newDiagnostics returns an empty instance of <*diagnostics>
+func newDiagnostics() *diagnostics {
+	return &diagnostics{
+		failures: make(map[string]int),
+	}
+}
+
+// setHandler sets the handler chosen for the connected VPP.
+func (d *diagnostics) setHandler(name, binapiVersion string) {
+	d.Lock()
+	defer d.Unlock()
+	d.handler, d.binapiVersion = name, binapiVersion
+}
+
+// setInfo sets the version and plugins of the connected VPP.
+func (d *diagnostics) setInfo(info *api.VPPInfo) {
+	d.Lock()
+	defer d.Unlock()
+	d.info = info
+}
+
+// recordCli keeps the output of the CLI command.
+func (d *diagnostics) recordCli(cmd, output string) {
+	if len(output) > diagnosticOutputMax {
+		output = output[:diagnosticOutputMax] + "..."
+	}
+	d.Lock()
+	defer d.Unlock()
+	d.outputs = append(d.outputs, cliOutput{cmd: cmd, output: output, at: time.Now()})
+	if len(d.outputs) > diagnosticOutputs {
+		d.outputs = d.outputs[len(d.outputs)-diagnosticOutputs:]
+	}
+}
+
+// record counts the failures of the request in a row and logs the
+// diagnostic bundle once the request failed repeatedly. Requests
+// cancelled by the caller (e.g. on exit) are not counted.
+func (d *diagnostics) record(request string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	d.Lock()
+	if err == nil {
+		delete(d.failures, request)
+		d.Unlock()
+		return
+	}
+	d.failures[request]++
+	if d.logged || d.failures[request] < diagnosticFailures {
+		d.Unlock()
+		return
+	}
+	d.logged = true
+	bundle := d.bundle()
+	d.Unlock()
+
+	logrus.Warnf("%s failed %d times in a row: %v, attach the diagnostic bundle below to the bug report:\n%s",
+		request, diagnosticFailures, err, bundle)
+}
+
+// bundle returns the diagnostic bundle, the caller must hold the lock.
+func (d *diagnostics) bundle() string {
+	var b strings.Builder
+	handler, binapiVersion := d.handler, d.binapiVersion
+	if handler == "" {
+		handler = "-"
+	}
+	if binapiVersion == "" {
+		binapiVersion = "-"
+	}
+	fmt.Fprintf(&b, "  handler: %s (binapi version %s)\n", handler, binapiVersion)
+	if d.info == nil {
+		b.WriteString("  VPP: unknown\n")
+	} else {
+		version := d.info.VersionInfo
+		fmt.Fprintf(&b, "  VPP: %s %s (built %s in %s)\n", version.Program, version.Version, version.BuildDate, version.BuildDirectory)
+		fmt.Fprintf(&b, "  plugins (%d):\n", len(d.info.Plugins))
+		for _, plugin := range d.info.Plugins {
+			fmt.Fprintf(&b, "    %s %s\n", plugin.Name, plugin.Version)
+		}
+	}
+	if len(d.outputs) == 0 {
+		b.WriteString("  no CLI outputs\n")
+	}
+	for _, out := range d.outputs {
+		fmt.Fprintf(&b, "  %s (%s):\n", out.cmd, out.at.Format(time.RFC3339))
+		for _, line := range strings.Split(strings.TrimRight(out.output, "\n"), "\n") {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
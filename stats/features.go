@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// GetFeatures returns feature arcs with enabled features per interface.
+func (p *vppProvider) GetFeatures(ctx context.Context) ([]api.FeatureArc, error) {
+	ifDetails, err := p.handler.DumpInterfaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	indexes := make([]uint32, 0, len(ifDetails))
+	for idx := range ifDetails {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	var result []api.FeatureArc
+	for _, idx := range indexes {
+		name := ifDetails[idx].InternalName
+		out, err := p.handler.RunCli(ctx, "show interface features "+name)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		result = append(result, parseFeatures(name, out)...)
+	}
+	return result, nil
+}
+
+// parseFeatures parses the 'show interface features' output. Arcs are listed
+// with their features indented, arcs without features are skipped:
+//
+//	Feature paths configured on GigabitEthernet0/8/0...
+//
+//	ip4-unicast:
+//	  nat44-in2out-worker-handoff
+//
+//	ip6-unicast:
+//	  none configured
+func parseFeatures(iface, out string) []api.FeatureArc {
+	var result []api.FeatureArc
+	var arc *api.FeatureArc
+	flush := func() {
+		if arc != nil && len(arc.Features) != 0 {
+			result = append(result, *arc)
+		}
+		arc = nil
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "Feature paths configured on"):
+			continue
+		case line[0] != ' ' && line[0] != '\t':
+			// arc names end with a colon, other unindented lines
+			// (e.g. "Driver rx redirect: none") end the arc
+			flush()
+			if strings.HasSuffix(trimmed, ":") {
+				arc = &api.FeatureArc{Interface: iface, Arc: strings.TrimSuffix(trimmed, ":")}
+			}
+		case arc != nil && trimmed != "none configured":
+			arc.Features = append(arc.Features, strings.Fields(trimmed)[0])
+		}
+	}
+	flush()
+
+	return result
+}
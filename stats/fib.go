@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Regular expressions used to parse the FIB summary
+var (
+	// table header of 'show ip fib summary', e.g. "ipv4-VRF:0, fib_index:0, flow hash:[...] ..."
+	fibTableRe = regexp.MustCompile(`^(\S.*?), fib_index:\d+`)
+	// prefix length and the number of routes
+	fibPrefixLenRe = regexp.MustCompile(`^\s+(\d+)\s+(\d+)\s*$`)
+	// default table name, e.g. "ipv6-VRF:10"
+	fibDefaultNameRe = regexp.MustCompile(`-VRF:(\d+)$`)
+	// 'show fib memory' table memory, e.g. "IPv4 unicast     2     197152"
+	fibMemoryRe = regexp.MustCompile(`^\s*IPv([46]) unicast\s+\d+\s+(\d+)`)
+)
+
+// GetFib returns the route counts of IPv4 and IPv6 FIB tables and the memory
+// used by the FIB. The routes are counted by the 'show ip fib summary', tables
+// dumped by the handler are used to find the VRF IDs of tables with custom names.
+func (p *vppProvider) GetFib(ctx context.Context) (*api.FibSummary, error) {
+	dumped, err := p.handler.DumpFibTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+
+	var tables []api.FibTable
+	for _, af := range []struct {
+		cmd  string
+		ipv6 bool
+	}{
+		{cmd: "show ip fib summary"},
+		{cmd: "show ip6 fib summary", ipv6: true},
+	} {
+		out, err := p.handler.RunCli(ctx, af.cmd)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		tables = append(tables, parseFibSummary(out, af.ipv6)...)
+	}
+	tables = withTableIDs(tables, dumped)
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].IPv6 != tables[j].IPv6 {
+			return !tables[i].IPv6
+		}
+		return tables[i].TableID < tables[j].TableID
+	})
+
+	out, err := p.handler.RunCli(ctx, "show fib memory")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	summary := &api.FibSummary{Tables: tables}
+	summary.IP4Memory, summary.IP6Memory = parseFibMemory(out)
+	return summary, nil
+}
+
+// parseFibSummary parses the 'show ip fib summary' or 'show ip6 fib summary'
+// output, each table header is followed by the route counts per prefix length:
+//
+//	ipv4-VRF:0, fib_index:0, flow hash:[src dst sport dport proto ] epoch:0 flags:none locks:[default-route:1, ]
+//	    Prefix length         Count
+//	           0               1
+//	          32               4
+func parseFibSummary(out string, ipv6 bool) []api.FibTable {
+	hostLen := uint64(32)
+	if ipv6 {
+		hostLen = 128
+	}
+	var tables []api.FibTable
+	for _, line := range strings.Split(out, "\n") {
+		if m := fibTableRe.FindStringSubmatch(line); m != nil {
+			tables = append(tables, api.FibTable{
+				TableID: api.UnknownTableID,
+				Name:    m[1],
+				IPv6:    ipv6,
+			})
+			continue
+		}
+		m := fibPrefixLenRe.FindStringSubmatch(line)
+		if m == nil || len(tables) == 0 {
+			continue
+		}
+		prefixLen, _ := strconv.ParseUint(m[1], 10, 64)
+		count, _ := strconv.ParseUint(m[2], 10, 64)
+		table := &tables[len(tables)-1]
+		table.Routes += count
+		if prefixLen == hostLen {
+			table.HostRoutes += count
+		}
+	}
+	return tables
+}
+
+// withTableIDs sets the VRF IDs of the tables to the IDs of the dumped tables
+// with the same name. Tables which were not dumped get the ID from their
+// default name. Dumped tables missing in the summary are added without routes.
+func withTableIDs(tables, dumped []api.FibTable) []api.FibTable {
+	type tableKey struct {
+		name string
+		ipv6 bool
+	}
+	ids := make(map[tableKey]uint32, len(dumped))
+	for _, table := range dumped {
+		ids[tableKey{name: table.Name, ipv6: table.IPv6}] = table.TableID
+	}
+	for i := range tables {
+		key := tableKey{name: tables[i].Name, ipv6: tables[i].IPv6}
+		if id, ok := ids[key]; ok {
+			tables[i].TableID = id
+			delete(ids, key)
+		} else if m := fibDefaultNameRe.FindStringSubmatch(tables[i].Name); m != nil {
+			id, _ := strconv.ParseUint(m[1], 10, 32)
+			tables[i].TableID = uint32(id)
+		}
+	}
+	for _, table := range dumped {
+		if _, ok := ids[tableKey{name: table.Name, ipv6: table.IPv6}]; ok {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// parseFibMemory parses the memory used by the IPv4 and IPv6 unicast FIB
+// tables from the 'show fib memory' output.
+func parseFibMemory(out string) (ip4, ip6 uint64) {
+	for _, line := range strings.Split(out, "\n") {
+		m := fibMemoryRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		bytes, _ := strconv.ParseUint(m[2], 10, 64)
+		if m[1] == "4" {
+			ip4 = bytes
+		} else {
+			ip6 = bytes
+		}
+	}
+	return ip4, ip6
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/i18n"
+)
+
+// Latencies of the connection health above the thresholds are highlighted,
+// slow responses are an early sign of the VPP main thread congestion.
+const (
+	pingLatencyWarn  = 50 * time.Millisecond
+	statsLatencyWarn = 50 * time.Millisecond
+	healthInterval   = time.Second
+)
+
+// statsHeartbeat is the stats segment path read to measure the read duration.
+const statsHeartbeat = "/sys/heartbeat"
+
+// connHealth holds the latencies measured by the last health probe.
+type connHealth struct {
+	sync.Mutex
+	measured bool
+	// ping is the binapi control ping round-trip time
+	ping    time.Duration
+	pingErr error
+	// stats is the duration of a stats segment read
+	stats    time.Duration
+	statsErr error
+}
+
+// probeHealth measures the binapi round-trip time and the stats
+// segment read duration periodically until the context is cancelled.
+func (p *vppProvider) probeHealth(ctx context.Context) {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.measureHealth(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// measureHealth measures latencies of both connections. The stats segment
+// is read directly if possible, otherwise via the handler (e.g. remote proxy).
+func (p *vppProvider) measureHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, healthInterval)
+	defer cancel()
+
+	start := time.Now()
+	pingErr := p.handler.Ping(ctx)
+	ping := time.Since(start)
+
+	start = time.Now()
+	var statsErr error
+	if p.statsClient != nil {
+		_, statsErr = p.statsClient.DumpStats(statsHeartbeat)
+	} else {
+		_, statsErr = p.handler.DumpInterfaceStats(ctx)
+	}
+	stats := time.Since(start)
+
+	p.health.Lock()
+	defer p.health.Unlock()
+	if ping > pingLatencyWarn && p.health.ping <= pingLatencyWarn {
+		logrus.Warnf("binapi round-trip time %v exceeds %v", ping, pingLatencyWarn)
+	}
+	if stats > statsLatencyWarn && p.health.stats <= statsLatencyWarn {
+		logrus.Warnf("stats segment read duration %v exceeds %v", stats, statsLatencyWarn)
+	}
+	p.health.measured = true
+	p.health.ping, p.health.pingErr = ping, pingErr
+	p.health.stats, p.health.statsErr = stats, statsErr
+}
+
+// healthState returns the measured latencies formatted for the state,
+// latencies above thresholds are highlighted and the bool is set.
+func (p *vppProvider) healthState() (string, bool) {
+	p.health.Lock()
+	defer p.health.Unlock()
+	if !p.health.measured {
+		return "", false
+	}
+	stats, statsSlow := formatLatency("stats", p.health.stats, p.health.statsErr, statsLatencyWarn)
+	if p.statsOnly {
+		return " (" + stats + ")", statsSlow
+	}
+	ping, pingSlow := formatLatency("api", p.health.ping, p.health.pingErr, pingLatencyWarn)
+	return " (" + ping + ", " + stats + ")", pingSlow || statsSlow
+}
+
+// formatLatency formats the latency of the connection, failures are red and
+// latencies above the threshold yellow.
+func formatLatency(name string, latency time.Duration, err error, warn time.Duration) (string, bool) {
+	switch {
+	case err != nil:
+		return "[" + i18n.T("%s failed", name) + "](fg:red)", true
+	case latency > warn:
+		return fmt.Sprintf("[%s %s](fg:yellow)", name, formatDuration(latency)), true
+	}
+	return fmt.Sprintf("%s %s", name, formatDuration(latency)), false
+}
+
+// formatDuration formats the duration in milliseconds with a precision
+// suitable for latencies.
+func formatDuration(d time.Duration) string {
+	ms := float64(d) / float64(time.Millisecond)
+	if ms < 10 {
+		return fmt.Sprintf("%.1fms", ms)
+	}
+	return fmt.Sprintf("%.0fms", ms)
+}
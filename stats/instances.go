@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"git.fd.io/govpp.git/adapter"
+	"git.fd.io/govpp.git/adapter/statsclient"
+	govppapi "git.fd.io/govpp.git/api"
+	"git.fd.io/govpp.git/core"
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// StatsInstance is the stats socket of a VPP instance managed together
+// with the connected VPP, e.g. one of the per-NUMA instances.
+type StatsInstance struct {
+	// Name distinguishes the interfaces of the instance.
+	Name   string
+	Socket string
+}
+
+// statsInstance is a connected stats socket of a VPP instance.
+type statsInstance struct {
+	StatsInstance
+	conn *core.StatsConnection
+}
+
+// WithStatsInstances merges the interface counters read from the stats
+// sockets of the instances into the interfaces of the connected VPP, the
+// interfaces of the connected VPP belong to the primary instance. The
+// instances are supported only if the VPP is connected locally.
+func WithStatsInstances(primary string, instances ...StatsInstance) ProviderOption {
+	return func(p *vppProvider) {
+		p.primaryInstance = primary
+		p.instanceSockets = instances
+	}
+}
+
+// ParseStatsInstance parses the instance in the form 'name=socket' or
+// 'socket', in which case the instance is named by the socket file.
+func ParseStatsInstance(s string) (StatsInstance, error) {
+	name, socket := "", s
+	if i := strings.Index(s, "="); i >= 0 {
+		name, socket = s[:i], s[i+1:]
+	}
+	if socket == "" {
+		return StatsInstance{}, fmt.Errorf("missing stats socket of the instance %q", s)
+	}
+	if name == "" {
+		name = InstanceName(socket)
+	}
+	return StatsInstance{Name: name, Socket: socket}, nil
+}
+
+// InstanceName returns the name of the instance of the stats socket, which
+// is the socket file name without the extension, or the name of the directory
+// of the default socket file, e.g. 'numa1' for /run/vpp/numa1/stats.sock.
+func InstanceName(socket string) string {
+	dir, file := filepath.Split(socket)
+	if file == filepath.Base(adapter.DefaultStatsSocket) && dir != "" {
+		return filepath.Base(dir)
+	}
+	return strings.TrimSuffix(file, filepath.Ext(file))
+}
+
+// connectInstances connects the stats sockets of the instances.
+func (p *vppProvider) connectInstances() error {
+	for _, instance := range p.instanceSockets {
+		conn, err := core.ConnectStats(statsclient.NewStatsClient(instance.Socket))
+		if err != nil {
+			p.disconnectInstances()
+			return fmt.Errorf("connection to the stats socket %s of the instance %s failed: %v",
+				instance.Socket, instance.Name, err)
+		}
+		p.instances = append(p.instances, statsInstance{StatsInstance: instance, conn: conn})
+	}
+	return nil
+}
+
+// disconnectInstances disconnects the stats sockets of the instances.
+func (p *vppProvider) disconnectInstances() {
+	for _, instance := range p.instances {
+		instance.conn.Disconnect()
+	}
+	p.instances = nil
+}
+
+// instanceInterfaces returns the interfaces of the connected instances.
+func (p *vppProvider) instanceInterfaces() []api.Interface {
+	var result []api.Interface
+	for _, instance := range p.instances {
+		ifStats := new(govppapi.InterfaceStats)
+		if err := instance.conn.GetInterfaceStats(ifStats); err != nil {
+			logrus.Warnf("failed to dump interface stats of the instance %s: %v", instance.Name, err)
+			continue
+		}
+		result = append(result, segmentInterfaces(ifStats, instance.Name)...)
+	}
+	return result
+}
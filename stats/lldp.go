@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// lldpInterval is the interval of dumping the LLDP neighbors, the peers
+// change rarely and the 'show lldp' CLI is too slow to be run on each poll.
+const lldpInterval = 30 * time.Second
+
+// lldpCache keeps the LLDP neighbors by the name of the local interface.
+type lldpCache struct {
+	sync.Mutex
+	neighbors map[string]api.LLDPNeighbor
+	dumpedAt  time.Time
+}
+
+// lldpNeighbors returns the LLDP neighbors by the interface name, they are
+// dumped again once the interval passed. No neighbors are returned if the
+// lldp plugin is not loaded.
+func (p *vppProvider) lldpNeighbors(ctx context.Context) map[string]api.LLDPNeighbor {
+	p.lldp.Lock()
+	defer p.lldp.Unlock()
+
+	if time.Since(p.lldp.dumpedAt) < lldpInterval {
+		return p.lldp.neighbors
+	}
+	p.lldp.dumpedAt = time.Now()
+	p.lldp.neighbors = nil
+
+	neighbors, err := p.handler.DumpLLDPNeighbors(ctx)
+	if err != nil {
+		if err != api.ErrNotSupported {
+			logrus.Warnf("failed to dump LLDP neighbors: %v", err)
+		}
+		return nil
+	}
+	p.lldp.neighbors = make(map[string]api.LLDPNeighbor, len(neighbors))
+	for _, neighbor := range neighbors {
+		p.lldp.neighbors[neighbor.Interface] = neighbor
+	}
+	return p.lldp.neighbors
+}
@@ -24,6 +24,7 @@ import (
 	"go.pantheon.tech/vpptop/stats/local/binapi/dhcp"
 	interfaces "go.pantheon.tech/vpptop/stats/local/binapi/interface"
 	"go.pantheon.tech/vpptop/stats/local/binapi/ip"
+	"go.pantheon.tech/vpptop/stats/local/binapi/ip_neighbor"
 	"go.pantheon.tech/vpptop/stats/local/binapi/vpe"
 	"go.pantheon.tech/vpptop/stats/local/vppcalls"
 )
@@ -46,6 +47,13 @@ func init() {
 // compatibility with the version of the connected VPP
 type HandlerDef struct{}
 
+// HandlerName is the name of the local handler
+const HandlerName = "local"
+
+func (d *HandlerDef) Name() string {
+	return HandlerName
+}
+
 func (d *HandlerDef) IsHandlerCompatible(c *api.VppClient, isRemote bool) (api.HandlerAPI, string, error) {
 	ch, err := c.NewAPIChannel()
 	if err != nil {
@@ -62,7 +70,13 @@ type Handler struct {
 	vppCoreCalls      vppcalls.VppCoreAPI
 	interfaceVppCalls vppcalls.InterfaceVppAPI
 	telemetryVppCalls vppcalls.TelemetryVppAPI
+	fibVppCalls       vppcalls.FibVppAPI
+	neighborVppCalls  vppcalls.NeighborVppAPI
+	tunnelVppCalls    vppcalls.TunnelVppAPI
 	apiChan           govppapi.Channel
+	ifCounters        api.InterfaceCounterSource
+	// set if the stats segment is accessed directly
+	statsSegment bool
 }
 
 // NewLocalHandler returns new instance of the local handler
@@ -71,12 +85,24 @@ func NewLocalHandler(c *api.VppClient, ch govppapi.Channel, isRemote bool) *Hand
 		for _, msg := range localMsgs {
 			gob.Register(msg)
 		}
+		// neighbor and tunnel messages are optional, not checked by the compatibility
+		for _, msg := range ip_neighbor.AllMessages() {
+			gob.Register(msg)
+		}
+		for _, msg := range vppcalls.TunnelMessages() {
+			gob.Register(msg)
+		}
 	}
 	return &Handler{
 		vppCoreCalls:      vppcalls.NewVppCoreHandler(c.Connection()),
 		interfaceVppCalls: vppcalls.NewInterfaceHandler(ch),
-		telemetryVppCalls: vppcalls.NewTelemetryHandler(c.Connection(), c.Stats()),
+		telemetryVppCalls: vppcalls.NewTelemetryHandler(c.Connection(), c.Stats(), c.StatsAPI()),
+		fibVppCalls:       vppcalls.NewFibHandler(ch),
+		neighborVppCalls:  vppcalls.NewNeighborHandler(ch, isRemote),
+		tunnelVppCalls:    vppcalls.NewTunnelHandler(ch),
 		apiChan:           ch,
+		ifCounters:        c.InterfaceCounterSource(),
+		statsSegment:      c.StatsAPI() != nil,
 	}
 }
 
@@ -85,6 +111,13 @@ func (h *Handler) DumpInterfaces(ctx context.Context) (map[uint32]*api.Interface
 }
 
 func (h *Handler) DumpInterfaceStats(ctx context.Context) (*govppapi.InterfaceStats, error) {
+	if h.ifCounters == api.CountersFromCLI {
+		out, err := h.RunCli(ctx, "show interface")
+		if err != nil {
+			return nil, err
+		}
+		return api.ParseInterfaceCounters(out), nil
+	}
 	return h.telemetryVppCalls.GetInterfaceStats(ctx)
 }
 
@@ -100,6 +133,10 @@ func (h *Handler) RunCli(ctx context.Context, cmd string) (string, error) {
 	return h.vppCoreCalls.RunCli(ctx, cmd)
 }
 
+func (h *Handler) Ping(ctx context.Context) error {
+	return h.vppCoreCalls.Ping(ctx)
+}
+
 func (h *Handler) DumpPlugins(ctx context.Context) ([]api.PluginInfo, error) {
 	return h.vppCoreCalls.GetPlugins(ctx)
 }
@@ -115,6 +152,103 @@ func (h *Handler) DumpThreads(ctx context.Context) ([]api.ThreadData, error) {
 	return h.telemetryVppCalls.GetThreads(ctx)
 }
 
+func (h *Handler) DumpPuntStats(ctx context.Context) ([]api.PuntStat, error) {
+	return h.telemetryVppCalls.GetPuntStats(ctx)
+}
+
+func (h *Handler) DumpTunnels(ctx context.Context) ([]api.Tunnel, error) {
+	return h.tunnelVppCalls.DumpTunnels(ctx)
+}
+
+func (h *Handler) DumpPolicers(ctx context.Context) ([]api.Policer, error) {
+	return h.telemetryVppCalls.GetPolicers(ctx)
+}
+
+func (h *Handler) DumpFibTables(ctx context.Context) ([]api.FibTable, error) {
+	return h.fibVppCalls.DumpFibTables(ctx)
+}
+
+func (h *Handler) DumpRxPlacement(ctx context.Context) ([]api.RxPlacement, error) {
+	return h.interfaceVppCalls.DumpRxPlacement(ctx)
+}
+
+func (h *Handler) DumpNeighbors(ctx context.Context) ([]api.Neighbor, error) {
+	return h.neighborVppCalls.DumpNeighbors(ctx)
+}
+
+func (h *Handler) WatchNeighbors(ctx context.Context, onChange func()) error {
+	return h.neighborVppCalls.WatchNeighbors(ctx, onChange)
+}
+
+// DumpLLDPNeighbors parses the 'show lldp' output,
+// the lldp plugin has no binary API dump of the neighbors.
+func (h *Handler) DumpLLDPNeighbors(ctx context.Context) ([]api.LLDPNeighbor, error) {
+	out, err := h.RunCli(ctx, "show lldp")
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseLLDPNeighbors(out)
+}
+
+// DumpMemifRings parses the 'show memif' output, the memif binary API
+// dumps the ring sizes only and it is not generated for the local handler.
+func (h *Handler) DumpMemifRings(ctx context.Context) ([]api.MemifRing, error) {
+	out, err := h.RunCli(ctx, "show memif")
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseMemifRings(out)
+}
+
+// DumpAPITraceStatus parses the 'api trace status' output.
+func (h *Handler) DumpAPITraceStatus(ctx context.Context) (*api.APITrace, error) {
+	return api.DumpAPITraceStatus(ctx, h.RunCli)
+}
+
+// DumpAPITrace parses the 'api trace dump' output.
+func (h *Handler) DumpAPITrace(ctx context.Context) ([]api.APITraceMessage, error) {
+	return api.DumpAPITrace(ctx, h.RunCli)
+}
+
+func (h *Handler) SetAPITrace(ctx context.Context, enable bool) error {
+	return api.SetAPITrace(ctx, h.RunCli, enable)
+}
+
+func (h *Handler) SaveAPITrace(ctx context.Context, file string) error {
+	return api.SaveAPITrace(ctx, h.RunCli, file)
+}
+
+func (h *Handler) FreeAPITrace(ctx context.Context) error {
+	return api.FreeAPITrace(ctx, h.RunCli)
+}
+
+// DumpSRv6 parses the SRv6 policies and local SIDs from the CLI,
+// the sr binary API is not generated for the local handler.
+func (h *Handler) DumpSRv6(ctx context.Context) ([]api.SRv6SID, error) {
+	return api.DumpSRv6(ctx, h.RunCli)
+}
+
+// DumpMpls parses the MPLS tunnels and FIB entries from the CLI,
+// the mpls binary API is not generated for the local handler.
+func (h *Handler) DumpMpls(ctx context.Context) ([]api.MplsEntry, error) {
+	return api.DumpMpls(ctx, h.RunCli)
+}
+
+func (h *Handler) ClearInterfaceCounters(ctx context.Context) error {
+	return h.interfaceVppCalls.ClearInterfaceStats(ctx)
+}
+
+// Capabilities of the local handler, the severities of the error counters
+// are parsed from the CLI if not known.
+func (h *Handler) Capabilities() api.Capabilities {
+	return api.Capabilities{
+		CLI:           true,
+		ErrorSeverity: true,
+		Threads:       true,
+		WorkerStats:   h.statsSegment,
+	}
+}
+
 func (h *Handler) Close() {
 	if h.apiChan != nil {
 		h.apiChan.Close()
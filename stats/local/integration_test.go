@@ -0,0 +1,292 @@
+//go:build integration
+// +build integration
+
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"git.fd.io/govpp.git"
+	"git.fd.io/govpp.git/adapter/statsclient"
+	"git.fd.io/govpp.git/core"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// The integration tests run the VPP in a docker container and validate
+// the data dumped by the local handler against the CLI output of the VPP.
+// The tests are run by 'make test-integration', the docker has to be
+// available and the sockets of the container accessible (e.g. by root).
+//
+// The VPP image is set by the VPPTOP_IT_IMAGE environment variable.
+const defaultImage = "ligato/vpp-base:21.01"
+
+// startTimeout is the time to wait for the VPP sockets.
+const startTimeout = 30 * time.Second
+
+// vppStartup is the startup config of the VPP, the sockets are placed
+// to the directory mounted from the host. The node counters are kept in
+// the stats segment and updated frequently to match the CLI output.
+const vppStartup = `unix { nodaemon cli-listen /run/vpp/cli.sock }
+api-segment { prefix vpptop-it }
+socksvr { socket-name /run/vpp/api.sock }
+statseg { socket-name /run/vpp/stats.sock per-node-counters on update-interval 0.1 }
+plugins { plugin dpdk_plugin.so { disable } }`
+
+// handler is connected to the VPP in the container, nil if the VPP
+// could not be started (the reason is kept in skipReason).
+var (
+	handler    *Handler
+	skipReason string
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runWithVPP(m))
+}
+
+// runWithVPP starts the VPP container and runs the tests connected to it.
+func runWithVPP(m *testing.M) int {
+	if _, err := exec.LookPath("docker"); err != nil {
+		skipReason = "docker is not available"
+		return m.Run()
+	}
+	dir, err := ioutil.TempDir("", "vpptop-it")
+	if err != nil {
+		skipReason = err.Error()
+		return m.Run()
+	}
+	defer os.RemoveAll(dir)
+
+	image := os.Getenv("VPPTOP_IT_IMAGE")
+	if image == "" {
+		image = defaultImage
+	}
+	out, err := exec.Command("docker", "run", "-d", "--rm", "-v", dir+":/run/vpp", image,
+		"vpp", vppStartup).CombinedOutput()
+	if err != nil {
+		skipReason = fmt.Sprintf("starting the VPP container failed: %v: %s", err, out)
+		return m.Run()
+	}
+	container := strings.TrimSpace(string(out))
+	defer exec.Command("docker", "rm", "-f", container).Run()
+
+	disconnect, err := connect(filepath.Join(dir, "api.sock"), filepath.Join(dir, "stats.sock"))
+	if err != nil {
+		logs, _ := exec.Command("docker", "logs", container).CombinedOutput()
+		skipReason = fmt.Sprintf("connecting to the VPP failed: %v\n%s", err, logs)
+		return m.Run()
+	}
+	defer disconnect()
+
+	return m.Run()
+}
+
+// connect connects the handler to the VPP once its sockets are created.
+func connect(apiSocket, statsSocket string) (func(), error) {
+	deadline := time.Now().Add(startTimeout)
+	for _, socket := range []string{apiSocket, statsSocket} {
+		for {
+			if _, err := os.Stat(socket); err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				return nil, fmt.Errorf("socket %s was not created within %v", socket, startTimeout)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	vppConn, err := govpp.Connect(apiSocket)
+	if err != nil {
+		return nil, err
+	}
+	statsClient := statsclient.NewStatsClient(statsSocket)
+	statsConn, err := core.ConnectStats(statsClient)
+	if err != nil {
+		vppConn.Disconnect()
+		return nil, err
+	}
+
+	client := api.NewVppClient(vppConn, statsConn)
+	client.SetStatsAPI(statsClient)
+	h, version, err := new(HandlerDef).IsHandlerCompatible(client, false)
+	if err == nil && version == "" {
+		err = fmt.Errorf("the local handler is not compatible with the VPP")
+	}
+	if err != nil {
+		statsConn.Disconnect()
+		vppConn.Disconnect()
+		return nil, err
+	}
+	handler = h.(*Handler)
+	return func() {
+		handler.Close()
+		client.Close()
+		statsConn.Disconnect()
+		vppConn.Disconnect()
+	}, nil
+}
+
+// connected returns the handler, the test is skipped if the VPP is not running.
+func connected(t *testing.T) *Handler {
+	t.Helper()
+	if handler == nil {
+		t.Skip(skipReason)
+	}
+	return handler
+}
+
+// cli runs the CLI command, the test fails on error.
+func cli(t *testing.T, h *Handler, cmd string) string {
+	t.Helper()
+	out, err := h.RunCli(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("CLI %q failed: %v", cmd, err)
+	}
+	return out
+}
+
+func TestIntegration_DumpInterfaces(t *testing.T) {
+	h := connected(t)
+	cli(t, h, "create loopback interface")
+	cli(t, h, "set interface state loop0 up")
+	cli(t, h, "set interface ip address loop0 10.10.0.1/24")
+
+	ifaces, err := h.DumpInterfaces(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byName := make(map[string]*api.InterfaceDetails, len(ifaces))
+	for _, iface := range ifaces {
+		byName[iface.Name] = iface
+	}
+
+	// every interface listed by the CLI has to be dumped,
+	// e.g. "loop0    1    up    9000/0/0/0"
+	ifaceRe := regexp.MustCompile(`^(\S+)\s+(\d+)\s+(up|down)\s`)
+	var listed int
+	for _, line := range strings.Split(cli(t, h, "show interface"), "\n") {
+		m := ifaceRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		listed++
+		iface, ok := byName[m[1]]
+		if !ok {
+			t.Errorf("interface %s listed by the CLI was not dumped", m[1])
+			continue
+		}
+		if fmt.Sprint(iface.SwIfIndex) != m[2] {
+			t.Errorf("interface %s: index %d, CLI shows %s", m[1], iface.SwIfIndex, m[2])
+		}
+		if iface.IsEnabled != (m[3] == "up") {
+			t.Errorf("interface %s: enabled %v, CLI shows %s", m[1], iface.IsEnabled, m[3])
+		}
+	}
+	if listed != len(ifaces) {
+		t.Errorf("dumped %d interfaces, CLI lists %d", len(ifaces), listed)
+	}
+
+	loop, ok := byName["loop0"]
+	if !ok {
+		t.Fatalf("loop0 was not dumped")
+	}
+	if len(loop.IPAddresses) != 1 || !strings.HasPrefix(loop.IPAddresses[0], "10.10.0.1") {
+		t.Errorf("loop0 addresses: got %v, want [10.10.0.1/24]", loop.IPAddresses)
+	}
+}
+
+func TestIntegration_DumpRuntimeInfo(t *testing.T) {
+	h := connected(t)
+	info, err := h.DumpRuntimeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(info.Threads) == 0 {
+		t.Fatalf("no threads were dumped")
+	}
+
+	// the nodes of the main thread are compared to those listed by the CLI
+	// (the counters change between the dump and the CLI command)
+	dumped := make(map[string]bool)
+	for _, item := range info.Threads[0].Items {
+		dumped[item.Name] = true
+	}
+	out := cli(t, h, "show runtime")
+	if i := strings.Index(out, "---------------"); i > 0 {
+		// only the main thread
+		out = out[:i]
+	}
+	// e.g. "ip4-lookup    active    10    10    0    1.20e3    1.00"
+	itemRe := regexp.MustCompile(`^\s*(\S+)\s+(?:active|polling|event wait|any wait|interrupt wait|suspended|done|disabled)\s+\d+\s+\d+\s+\d+\s`)
+	var listed int
+	for _, line := range strings.Split(out, "\n") {
+		m := itemRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		listed++
+		if !dumped[m[1]] {
+			t.Errorf("node %s listed by the CLI was not dumped", m[1])
+		}
+	}
+	if listed == 0 {
+		t.Errorf("no nodes found in the CLI output:\n%s", out)
+	}
+}
+
+func TestIntegration_DumpNodeCounters(t *testing.T) {
+	h := connected(t)
+	// counters are incremented by pinging an unreachable address
+	cli(t, h, "ping 10.10.0.2 repeat 3 interval 0.01")
+
+	counters, err := h.DumpNodeCounters(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dumped := make(map[string]bool)
+	for _, counter := range counters.Counters {
+		dumped[counter.Node+" "+counter.Reason] = true
+	}
+
+	// e.g. "         3             ip4-arp           ARP requests sent    info"
+	counterRe := regexp.MustCompile(`^\s+\d+\s+(\S+)\s+(.+?)\s+(?:error|warn|info|unknown)?\s*$`)
+	var listed int
+	for _, line := range strings.Split(cli(t, h, "show node counters"), "\n") {
+		m := counterRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		listed++
+		if !dumped[m[1]+" "+m[2]] {
+			t.Errorf("counter %q of node %s listed by the CLI was not dumped", m[2], m[1])
+		}
+	}
+	if listed != len(counters.Counters) {
+		t.Errorf("dumped %d counters, CLI lists %d", len(counters.Counters), listed)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vppcalls
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/local/binapi/ip"
+)
+
+// FibVppAPI defines FIB-specific methods
+type FibVppAPI interface {
+	DumpFibTables(ctx context.Context) ([]api.FibTable, error)
+}
+
+// FibHandler implements FibVppAPI
+type FibHandler struct {
+	ch govppapi.Channel
+}
+
+// NewFibHandler returns a new instance of the FibVppAPI
+func NewFibHandler(ch govppapi.Channel) FibVppAPI {
+	return &FibHandler{
+		ch: ch,
+	}
+}
+
+// DumpFibTables returns IPv4 and IPv6 FIB tables, the route counts are not set.
+func (h *FibHandler) DumpFibTables(_ context.Context) ([]api.FibTable, error) {
+	var tables []api.FibTable
+	reqCtx := h.ch.SendMultiRequest(&ip.IPTableDump{})
+	for {
+		details := &ip.IPTableDetails{}
+		stop, err := reqCtx.ReceiveReply(details)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump FIB tables: %v", err)
+		}
+		tables = append(tables, api.FibTable{
+			TableID: details.Table.TableID,
+			Name:    strings.TrimRight(details.Table.Name, "\x00"),
+			IPv6:    details.Table.IsIP6,
+		})
+	}
+	return tables, nil
+}
@@ -26,6 +26,7 @@ import (
 	govppapi "git.fd.io/govpp.git/api"
 	"go.pantheon.tech/vpptop/stats/api"
 	dhcpapi "go.pantheon.tech/vpptop/stats/local/binapi/dhcp"
+	"go.pantheon.tech/vpptop/stats/local/binapi/ethernet_types"
 	interfaces "go.pantheon.tech/vpptop/stats/local/binapi/interface"
 	"go.pantheon.tech/vpptop/stats/local/binapi/interface_types"
 	"go.pantheon.tech/vpptop/stats/local/binapi/ip"
@@ -35,6 +36,8 @@ import (
 // InterfaceVppAPI defines interface-specific methods
 type InterfaceVppAPI interface {
 	DumpInterfaces(ctx context.Context) (map[uint32]*api.InterfaceDetails, error)
+	DumpRxPlacement(ctx context.Context) ([]api.RxPlacement, error)
+	ClearInterfaceStats(ctx context.Context) error
 }
 
 // InterfaceHandler implements InterfaceVppAPI
@@ -103,10 +106,50 @@ func (h *InterfaceHandler) DumpInterfaces(_ context.Context) (map[uint32]*api.In
 	if err != nil {
 		return nil, err
 	}
+	// Retrieve VRF tables
+	if err = h.dumpInterfaceVrfs(ifs); err != nil {
+		return nil, err
+	}
 
 	return ifs, nil
 }
 
+// DumpRxPlacement returns the threads serving the rx queues of all interfaces
+func (h *InterfaceHandler) DumpRxPlacement(_ context.Context) ([]api.RxPlacement, error) {
+	var placement []api.RxPlacement
+	reqCtx := h.ch.SendMultiRequest(&interfaces.SwInterfaceRxPlacementDump{
+		SwIfIndex: interface_types.InterfaceIndex(allInterfaces),
+	})
+	for {
+		details := &interfaces.SwInterfaceRxPlacementDetails{}
+		stop, err := reqCtx.ReceiveReply(details)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump interface rx placement: %v", err)
+		}
+		placement = append(placement, api.RxPlacement{
+			SwIfIndex: uint32(details.SwIfIndex),
+			QueueID:   details.QueueID,
+			ThreadID:  details.WorkerID,
+		})
+	}
+	return placement, nil
+}
+
+// ClearInterfaceStats clears the counters of all interfaces.
+func (h *InterfaceHandler) ClearInterfaceStats(_ context.Context) error {
+	reply := &interfaces.SwInterfaceClearStatsReply{}
+	err := h.ch.SendRequest(&interfaces.SwInterfaceClearStats{
+		SwIfIndex: interface_types.InterfaceIndex(allInterfaces),
+	}).ReceiveReply(reply)
+	if err != nil {
+		return fmt.Errorf("failed to clear interface stats: %v", err)
+	}
+	return nil
+}
+
 func (h *InterfaceHandler) dumpInterfaces(ifIdxs ...uint32) (map[uint32]*api.InterfaceDetails, error) {
 	ifs := make(map[uint32]*api.InterfaceDetails)
 
@@ -132,9 +175,19 @@ func (h *InterfaceHandler) dumpInterfaces(ifIdxs ...uint32) (map[uint32]*api.Int
 		details := &api.InterfaceDetails{
 			Name:         strings.TrimRight(ifDetails.Tag, "\x00"),
 			IsEnabled:    ifDetails.Flags&interface_types.IF_STATUS_API_FLAG_ADMIN_UP != 0,
+			IsLinkUp:     ifDetails.Flags&interface_types.IF_STATUS_API_FLAG_LINK_UP != 0,
 			InternalName: name,
 			SwIfIndex:    uint32(ifDetails.SwIfIndex),
+			SupSwIfIndex: ifDetails.SupSwIfIndex,
 			MTU:          ifDetails.Mtu,
+			Device: api.DeviceDetails{
+				Type:       strings.ToLower(strings.TrimRight(ifDetails.InterfaceDevType, "\x00")),
+				LinkSpeed:  uint64(ifDetails.LinkSpeed),
+				LinkDuplex: api.LinkDuplexName(uint32(ifDetails.LinkDuplex)),
+			},
+		}
+		if ifDetails.L2Address != (ethernet_types.MacAddress{}) {
+			details.Device.MAC = ifDetails.L2Address.String()
 		}
 		ifs[uint32(ifDetails.SwIfIndex)] = details
 	}
@@ -214,6 +267,28 @@ func (h *InterfaceHandler) dumpIPAddressDetails(ifs map[uint32]*api.InterfaceDet
 	return nil
 }
 
+// dumpInterfaceVrfs dumps the IPv4 and IPv6 VRF tables of interfaces from VPP and fills them into the provided interface map.
+func (h *InterfaceHandler) dumpInterfaceVrfs(ifs map[uint32]*api.InterfaceDetails) error {
+	for idx, ifDetails := range ifs {
+		for _, isIPv6 := range []bool{false, true} {
+			reply := &interfaces.SwInterfaceGetTableReply{}
+			err := h.ch.SendRequest(&interfaces.SwInterfaceGetTable{
+				SwIfIndex: interface_types.InterfaceIndex(idx),
+				IsIPv6:    isIPv6,
+			}).ReceiveReply(reply)
+			if err != nil {
+				return fmt.Errorf("failed to get interface %d VRF: %v", idx, err)
+			}
+			if isIPv6 {
+				ifDetails.VrfIPv6 = reply.VrfID
+			} else {
+				ifDetails.VrfIPv4 = reply.VrfID
+			}
+		}
+	}
+	return nil
+}
+
 // processIPDetails processes ip.IPAddressDetails binary API message and fills the details into the provided interface map.
 func (h *InterfaceHandler) processIPDetails(ifs map[uint32]*api.InterfaceDetails, ipDetails *ip.IPAddressDetails, dhcpClients map[uint32]*dhcp) {
 	ifDetails, ifIdxExists := ifs[uint32(ipDetails.SwIfIndex)]
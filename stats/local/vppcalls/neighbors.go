@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vppcalls
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/local/binapi/interface_types"
+	"go.pantheon.tech/vpptop/stats/local/binapi/ip_neighbor"
+	"go.pantheon.tech/vpptop/stats/local/binapi/ip_types"
+)
+
+// NeighborVppAPI defines neighbor-specific methods
+type NeighborVppAPI interface {
+	DumpNeighbors(ctx context.Context) ([]api.Neighbor, error)
+	WatchNeighbors(ctx context.Context, onChange func()) error
+}
+
+// NeighborHandler implements NeighborVppAPI
+type NeighborHandler struct {
+	ch govppapi.Channel
+	// events are not supported by the proxy
+	isRemote bool
+	// incompatible is set if the VPP does not support
+	// the ip_neighbor messages of the local binary API
+	incompatible error
+}
+
+// NewNeighborHandler returns a new instance of the NeighborVppAPI
+func NewNeighborHandler(ch govppapi.Channel, isRemote bool) NeighborVppAPI {
+	return &NeighborHandler{
+		ch:           ch,
+		isRemote:     isRemote,
+		incompatible: ch.CheckCompatiblity(ip_neighbor.AllMessages()...),
+	}
+}
+
+// DumpNeighbors returns IPv4 and IPv6 neighbors, the interface names are not set.
+func (h *NeighborHandler) DumpNeighbors(_ context.Context) ([]api.Neighbor, error) {
+	if h.incompatible != nil {
+		return nil, api.ErrNotSupported
+	}
+	var neighbors []api.Neighbor
+	for _, af := range []ip_types.AddressFamily{ip_types.ADDRESS_IP4, ip_types.ADDRESS_IP6} {
+		reqCtx := h.ch.SendMultiRequest(&ip_neighbor.IPNeighborDump{
+			SwIfIndex: interface_types.InterfaceIndex(allInterfaces),
+			Af:        af,
+		})
+		for {
+			details := &ip_neighbor.IPNeighborDetails{}
+			stop, err := reqCtx.ReceiveReply(details)
+			if stop {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to dump neighbors: %v", err)
+			}
+			neighbor := details.Neighbor
+			neighbors = append(neighbors, api.Neighbor{
+				SwIfIndex:  uint32(neighbor.SwIfIndex),
+				IP:         neighbor.IPAddress.String(),
+				MAC:        neighbor.MacAddress.String(),
+				Age:        details.Age,
+				Static:     neighbor.Flags&ip_neighbor.IP_API_NEIGHBOR_FLAG_STATIC != 0,
+				NoFibEntry: neighbor.Flags&ip_neighbor.IP_API_NEIGHBOR_FLAG_NO_FIB_ENTRY != 0,
+			})
+		}
+	}
+	return neighbors, nil
+}
+
+// WatchNeighbors subscribes to the events of all IPv4 and IPv6 neighbors.
+// The events are unsubscribed once the context is cancelled.
+func (h *NeighborHandler) WatchNeighbors(ctx context.Context, onChange func()) error {
+	if h.isRemote || h.incompatible != nil {
+		return api.ErrNotSupported
+	}
+	events := make(chan govppapi.Message, 100)
+	sub, err := h.ch.SubscribeNotification(events, &ip_neighbor.IPNeighborEvent{})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe neighbor events: %v", err)
+	}
+	if err := h.wantEvents(true); err != nil {
+		if err := sub.Unsubscribe(); err != nil {
+			logrus.Warnf("failed to unsubscribe neighbor events: %v", err)
+		}
+		return err
+	}
+
+	go func() {
+		for {
+			select {
+			case <-events:
+				onChange()
+			case <-ctx.Done():
+				if err := h.wantEvents(false); err != nil {
+					logrus.Warnf("%v", err)
+				}
+				if err := sub.Unsubscribe(); err != nil {
+					logrus.Warnf("failed to unsubscribe neighbor events: %v", err)
+				}
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// wantEvents enables or disables the events of all neighbors, the zero
+// address of each address family matches all neighbors of the family.
+func (h *NeighborHandler) wantEvents(enable bool) error {
+	for _, af := range []ip_types.AddressFamily{ip_types.ADDRESS_IP4, ip_types.ADDRESS_IP6} {
+		req := &ip_neighbor.WantIPNeighborEvents{
+			Enable:    enable,
+			PID:       uint32(os.Getpid()),
+			IP:        ip_types.Address{Af: af},
+			SwIfIndex: interface_types.InterfaceIndex(allInterfaces),
+		}
+		reply := &ip_neighbor.WantIPNeighborEventsReply{}
+		if err := h.ch.SendRequest(req).ReceiveReply(reply); err != nil {
+			return fmt.Errorf("failed to set neighbor events (enable: %t): %v", enable, err)
+		}
+	}
+	return nil
+}
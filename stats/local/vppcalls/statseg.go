@@ -0,0 +1,390 @@
+/*
+ * Copyright (c) 2020 Cisco and/or its affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vppcalls
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"git.fd.io/govpp.git/adapter"
+	govppapi "git.fd.io/govpp.git/api"
+	"github.com/pkg/errors"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Stats segment paths of the interface counters. The legacy layout keeps
+// a counter per path indexed by the interface index (e.g. '/if/rx'), newer
+// VPPs add symlinks per interface (e.g. '/interfaces/<name>/rx').
+const (
+	statsIfPrefix      = "/if/"
+	statsIfNames       = "/if/names"
+	statsSymlinkPrefix = "/interfaces/"
+)
+
+// interfaceStatsPatterns are the patterns of the interface counter paths.
+var interfaceStatsPatterns = []string{"^" + statsIfPrefix, "^" + statsSymlinkPrefix}
+
+// Stats segment paths of the node runtime counters indexed by the thread and
+// the node index (only with 'per-node-counters on' in the statseg config),
+// the time of the last segment update and the error counters of nodes
+// ('/err/<node>/<reason>').
+const (
+	statsNodePrefix  = "/sys/node/"
+	statsNodeNames   = "/sys/node/names"
+	statsLastUpdate  = "/sys/last_update"
+	statsErrorPrefix = "/err/"
+)
+
+// unknownNodeState is the state of nodes read from the stats segment,
+// which does not keep the node state.
+const unknownNodeState = "-"
+
+// unknownSeverity is the severity of error counters read from the stats
+// segment without the CLI, which does not keep the severity.
+const unknownSeverity = "unknown"
+
+// interfaceCounterSetters map the counter names to the fields of the interface
+// counters. The stat is the counter of the interface at the given index.
+var interfaceCounterSetters = map[string]func(c *govppapi.InterfaceCounters, stat adapter.Stat, i int){
+	"rx":           func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.Rx = combinedValue(s, i) },
+	"tx":           func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.Tx = combinedValue(s, i) },
+	"rx-unicast":   func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.RxUnicast = combinedValue(s, i) },
+	"rx-multicast": func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.RxMulticast = combinedValue(s, i) },
+	"rx-broadcast": func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.RxBroadcast = combinedValue(s, i) },
+	"tx-unicast":   func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.TxUnicast = combinedValue(s, i) },
+	"tx-multicast": func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.TxMulticast = combinedValue(s, i) },
+	"tx-broadcast": func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.TxBroadcast = combinedValue(s, i) },
+	"rx-error":     func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.RxErrors = simpleValue(s, i) },
+	"tx-error":     func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.TxErrors = simpleValue(s, i) },
+	"drops":        func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.Drops = simpleValue(s, i) },
+	"punt":         func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.Punts = simpleValue(s, i) },
+	"ip4":          func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.IP4 = simpleValue(s, i) },
+	"ip6":          func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.IP6 = simpleValue(s, i) },
+	"rx-no-buf":    func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.RxNoBuf = simpleValue(s, i) },
+	"rx-miss":      func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.RxMiss = simpleValue(s, i) },
+	"mpls":         func(c *govppapi.InterfaceCounters, s adapter.Stat, i int) { c.Mpls = simpleValue(s, i) },
+}
+
+// StatsSegmentVppAPI reads the counters directly from the stats segment,
+// without the binary API or the CLI.
+type StatsSegmentVppAPI interface {
+	GetInterfaceStats(context.Context) (*govppapi.InterfaceStats, error)
+	GetNodeCounters(context.Context) (*api.NodeCounterInfo, error)
+	GetRuntimeInfo(context.Context) (*api.RuntimeInfo, error)
+}
+
+// statsSegment reads the interface counters directly from the stats segment.
+// Counter paths are discovered on every dump, so the counters available in
+// either layout are shown even if some of them are missing.
+type statsSegment struct {
+	stats adapter.StatsAPI
+}
+
+// NewStatsSegmentHandler returns a new instance of the StatsSegmentVppAPI
+// reading the stats segment of the stats API.
+func NewStatsSegmentHandler(statsAPI adapter.StatsAPI) StatsSegmentVppAPI {
+	return &statsSegment{stats: statsAPI}
+}
+
+func (s *statsSegment) GetInterfaceStats(context.Context) (*govppapi.InterfaceStats, error) {
+	return s.interfaceStats()
+}
+
+// GetNodeCounters returns the error counters of nodes with an unknown severity,
+// the result is empty if the segment does not contain the error counters.
+func (s *statsSegment) GetNodeCounters(context.Context) (*api.NodeCounterInfo, error) {
+	info, err := s.nodeCounters()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading error counters from the stats segment failed")
+	}
+	if info == nil {
+		return new(api.NodeCounterInfo), nil
+	}
+	for i := range info.Counters {
+		info.Counters[i].Severity = unknownSeverity
+	}
+	return info, nil
+}
+
+// GetRuntimeInfo returns the runtime counters of nodes per thread. It fails
+// if the segment does not contain the node counters.
+func (s *statsSegment) GetRuntimeInfo(context.Context) (*api.RuntimeInfo, error) {
+	info, err := s.runtimeInfo()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading runtime counters from the stats segment failed")
+	}
+	if info == nil {
+		return nil, errors.New("no node counters in the stats segment (is 'per-node-counters on' set in the statseg config?)")
+	}
+	return info, nil
+}
+
+// interfaceStats dumps the interface counter paths and maps them into the interface
+// counters. Counters of the legacy layout take precedence over the symlinks.
+func (s *statsSegment) interfaceStats() (*govppapi.InterfaceStats, error) {
+	entries, err := s.stats.DumpStats(interfaceStatsPatterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[uint32]*govppapi.InterfaceCounters)
+	counterAt := func(ifIdx uint32) *govppapi.InterfaceCounters {
+		c, ok := counters[ifIdx]
+		if !ok {
+			c = &govppapi.InterfaceCounters{InterfaceIndex: ifIdx}
+			counters[ifIdx] = c
+		}
+		return c
+	}
+
+	// interface names index the symlinks
+	indexes := make(map[string]uint32)
+	for _, entry := range entries {
+		if names, ok := entry.Data.(adapter.NameStat); ok && string(entry.Name) == statsIfNames {
+			for ifIdx, name := range names {
+				if len(name) == 0 {
+					continue
+				}
+				counterAt(uint32(ifIdx)).InterfaceName = string(name)
+				indexes[symlinkName(string(name))] = uint32(ifIdx)
+			}
+		}
+	}
+
+	legacy := make(map[string]bool)
+	for _, entry := range entries {
+		path := string(entry.Name)
+		if !strings.HasPrefix(path, statsIfPrefix) {
+			continue
+		}
+		set, ok := interfaceCounterSetters[strings.TrimPrefix(path, statsIfPrefix)]
+		if !ok {
+			continue
+		}
+		legacy[strings.TrimPrefix(path, statsIfPrefix)] = true
+		for ifIdx := 0; ifIdx < statLen(entry.Data); ifIdx++ {
+			set(counterAt(uint32(ifIdx)), entry.Data, ifIdx)
+		}
+	}
+
+	for _, entry := range entries {
+		path := string(entry.Name)
+		if !strings.HasPrefix(path, statsSymlinkPrefix) {
+			continue
+		}
+		// '/interfaces/<name>/<counter>'
+		pos := strings.LastIndex(path, "/")
+		name, counter := path[len(statsSymlinkPrefix):pos], path[pos+1:]
+		set, ok := interfaceCounterSetters[counter]
+		if !ok || legacy[counter] {
+			continue
+		}
+		ifIdx, ok := indexes[symlinkName(name)]
+		if !ok {
+			continue
+		}
+		// the symlink data holds the counter of the interface only
+		set(counterAt(ifIdx), entry.Data, 0)
+	}
+
+	ifStats := &govppapi.InterfaceStats{
+		Interfaces: make([]govppapi.InterfaceCounters, 0, len(counters)),
+	}
+	for _, c := range counters {
+		ifStats.Interfaces = append(ifStats.Interfaces, *c)
+	}
+	sort.Slice(ifStats.Interfaces, func(i, j int) bool {
+		return ifStats.Interfaces[i].InterfaceIndex < ifStats.Interfaces[j].InterfaceIndex
+	})
+	return ifStats, nil
+}
+
+// symlinkName returns the interface name as used in the symlink paths,
+// where the separators of the interface name are replaced.
+func symlinkName(name string) string {
+	return strings.NewReplacer("/", "_", " ", "_").Replace(name)
+}
+
+// statLen returns the number of interfaces the counter holds.
+func statLen(stat adapter.Stat) (n int) {
+	switch data := stat.(type) {
+	case adapter.SimpleCounterStat:
+		for _, counters := range data {
+			if len(counters) > n {
+				n = len(counters)
+			}
+		}
+	case adapter.CombinedCounterStat:
+		for _, counters := range data {
+			if len(counters) > n {
+				n = len(counters)
+			}
+		}
+	}
+	return n
+}
+
+// simpleValue returns the simple counter at the index summed over workers.
+func simpleValue(stat adapter.Stat, i int) (value uint64) {
+	if data, ok := stat.(adapter.SimpleCounterStat); ok {
+		for _, counters := range data {
+			if i < len(counters) {
+				value += uint64(counters[i])
+			}
+		}
+	}
+	return value
+}
+
+// combinedValue returns the combined counter at the index summed over workers.
+func combinedValue(stat adapter.Stat, i int) (value govppapi.InterfaceCounterCombined) {
+	if data, ok := stat.(adapter.CombinedCounterStat); ok {
+		for _, counters := range data {
+			if i < len(counters) {
+				value.Packets += counters[i].Packets()
+				value.Bytes += counters[i].Bytes()
+			}
+		}
+	}
+	return value
+}
+
+// runtimeInfo reads the node runtime counters from the stats segment, nodes
+// which were not called are skipped as by the 'show runtime'. The clocks are
+// per vector, or per call (suspend) for nodes not processing vectors. It
+// returns nil if the segment does not contain the node counters.
+func (s *statsSegment) runtimeInfo() (*api.RuntimeInfo, error) {
+	entries, err := s.stats.DumpStats("^"+statsNodePrefix, "^"+statsLastUpdate+"$")
+	if err != nil {
+		return nil, err
+	}
+
+	var names adapter.NameStat
+	var lastUpdate float64
+	counters := make(map[string]adapter.SimpleCounterStat)
+	for _, entry := range entries {
+		switch data := entry.Data.(type) {
+		case adapter.NameStat:
+			if string(entry.Name) == statsNodeNames {
+				names = data
+			}
+		case adapter.SimpleCounterStat:
+			counters[strings.TrimPrefix(string(entry.Name), statsNodePrefix)] = data
+		case adapter.ScalarStat:
+			lastUpdate = float64(data)
+		}
+	}
+	if names == nil || counters["clocks"] == nil {
+		return nil, nil
+	}
+
+	info := &api.RuntimeInfo{
+		Threads: make([]api.RuntimeThread, len(counters["clocks"])),
+	}
+	for t := range info.Threads {
+		thread := api.RuntimeThread{
+			ID:   uint(t),
+			Time: lastUpdate,
+		}
+		for i, name := range names {
+			if len(name) == 0 {
+				continue
+			}
+			calls := nodeValue(counters["calls"], t, i)
+			vectors := nodeValue(counters["vectors"], t, i)
+			suspends := nodeValue(counters["suspends"], t, i)
+			if calls == 0 && vectors == 0 && suspends == 0 {
+				continue
+			}
+			item := api.RuntimeItem{
+				Index:    uint(i),
+				Name:     string(name),
+				State:    unknownNodeState,
+				Calls:    calls,
+				Vectors:  vectors,
+				Suspends: suspends,
+			}
+			clocks := float64(nodeValue(counters["clocks"], t, i))
+			switch {
+			case vectors > 0:
+				item.Clocks = clocks / float64(vectors)
+			case calls > 0:
+				item.Clocks = clocks / float64(calls)
+			default:
+				item.Clocks = clocks / float64(suspends)
+			}
+			if calls > 0 {
+				item.VectorsPerCall = float64(vectors) / float64(calls)
+			}
+			thread.Items = append(thread.Items, item)
+		}
+		info.Threads[t] = thread
+	}
+	return info, nil
+}
+
+// nodeCounters reads the error counters of nodes from the stats segment summed
+// over the threads together with the counts per thread, counters which are
+// zero are skipped as by the 'show node counters'. The severity is not kept
+// in the segment. It returns nil if the segment does not contain the error counters.
+func (s *statsSegment) nodeCounters() (*api.NodeCounterInfo, error) {
+	entries, err := s.stats.DumpStats("^" + statsErrorPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var info *api.NodeCounterInfo
+	for _, entry := range entries {
+		data, ok := entry.Data.(adapter.ErrorStat)
+		if !ok {
+			continue
+		}
+		if info == nil {
+			info = new(api.NodeCounterInfo)
+		}
+		// '/err/<node>/<reason>'
+		parts := strings.SplitN(strings.TrimPrefix(string(entry.Name), statsErrorPrefix), "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		var count uint64
+		perThread := make([]uint64, len(data))
+		for i, value := range data {
+			count += uint64(value)
+			perThread[i] = uint64(value)
+		}
+		if count == 0 {
+			continue
+		}
+		info.Counters = append(info.Counters, api.NodeCounter{
+			Count:     count,
+			Node:      parts[0],
+			Reason:    parts[1],
+			PerThread: perThread,
+		})
+	}
+	return info, nil
+}
+
+// nodeValue returns the counter of the node at the index in the thread.
+func nodeValue(stat adapter.SimpleCounterStat, thread, i int) uint64 {
+	if thread < len(stat) && i < len(stat[thread]) {
+		return uint64(stat[thread][i])
+	}
+	return 0
+}
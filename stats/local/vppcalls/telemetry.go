@@ -22,11 +22,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
+	"git.fd.io/govpp.git/adapter"
 	govppapi "git.fd.io/govpp.git/api"
 	"go.pantheon.tech/vpptop/stats/api"
 	"go.pantheon.tech/vpptop/stats/local/binapi/vpe"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // TelemetryVppAPI defines telemetry-specific methods
@@ -35,20 +38,37 @@ type TelemetryVppAPI interface {
 	GetNodeCounters(context.Context) (*api.NodeCounterInfo, error)
 	GetRuntimeInfo(context.Context) (*api.RuntimeInfo, error)
 	GetThreads(context.Context) ([]api.ThreadData, error)
+	GetPuntStats(context.Context) ([]api.PuntStat, error)
+	GetPolicers(context.Context) ([]api.Policer, error)
 }
 
 // TelemetryHandler implements TelemetryVppAPI
 type TelemetryHandler struct {
 	sp     govppapi.StatsProvider
 	vpeRpc vpe.RPCService
+	// segment (optional) reads counters directly from the stats segment
+	segment *statsSegment
+
+	// severities of the error counters read from the stats segment
+	// by '<node>/<reason>', loaded from the CLI when a counter is new
+	mu         sync.Mutex
+	severities map[string]string
 }
 
-// NewTelemetryHandler returns a new instance of the TelemetryVppAPI
-func NewTelemetryHandler(conn govppapi.Connection, sp govppapi.StatsProvider) TelemetryVppAPI {
-	return &TelemetryHandler{
+// NewTelemetryHandler returns a new instance of the TelemetryVppAPI. The stats
+// API is optional, if set the interface counters are read directly from the
+// stats segment with the counter paths discovered at runtime. The node runtime
+// and error counters are read from the segment as well if the segment contains
+// them, otherwise the CLI output is parsed.
+func NewTelemetryHandler(conn govppapi.Connection, sp govppapi.StatsProvider, statsAPI adapter.StatsAPI) TelemetryVppAPI {
+	h := &TelemetryHandler{
 		vpeRpc: vpe.NewServiceClient(conn),
 		sp:     sp,
 	}
+	if statsAPI != nil {
+		h.segment = &statsSegment{stats: statsAPI}
+	}
+	return h
 }
 
 // Regular expressions used to parse telemetry output
@@ -63,9 +83,15 @@ var (
 	// 'show node counters'
 	nodeCountersRe    = regexp.MustCompile(`^\s+(\d+)\s+([\w-/]+)\s+(\w+(?:[ -]\w+)*)\s+(\w+)\s+$`)
 	nodeCountersReOld = regexp.MustCompile(`^\s+(\d+)\s+([\w-/]+)\s+(.+)$`)
+	// 'show policer'
+	policerNameRe     = regexp.MustCompile(`^\s*Name\s+"([^"]*)"\s*(.*)$`)
+	policerCountersRe = regexp.MustCompile(`^\s*(conform|exceed|violate)\s+(\d+)\s+packets,\s+(\d+)\s+bytes`)
 )
 
 func (h *TelemetryHandler) GetInterfaceStats(context.Context) (*govppapi.InterfaceStats, error) {
+	if h.segment != nil {
+		return h.segment.interfaceStats()
+	}
 	ifStats := &govppapi.InterfaceStats{}
 	err := h.sp.GetInterfaceStats(ifStats)
 	if err != nil {
@@ -74,7 +100,64 @@ func (h *TelemetryHandler) GetInterfaceStats(context.Context) (*govppapi.Interfa
 	return ifStats, nil
 }
 
+// GetNodeCounters returns the error counters of nodes, read from the stats
+// segment if available, or parsed from the 'show node counters' output.
 func (h *TelemetryHandler) GetNodeCounters(ctx context.Context) (*api.NodeCounterInfo, error) {
+	if h.segment != nil {
+		info, err := h.segment.nodeCounters()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading error counters from the stats segment failed")
+		}
+		if info != nil {
+			h.setSeverities(ctx, info.Counters)
+			return info, nil
+		}
+	}
+	return h.cliNodeCounters(ctx)
+}
+
+// setSeverities sets the severity of the error counters read from the stats segment.
+// Severities do not change, the 'show node counters' output is parsed only if
+// there is a counter with the severity not known yet.
+func (h *TelemetryHandler) setSeverities(ctx context.Context, counters []api.NodeCounter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.severities == nil {
+		h.severities = make(map[string]string)
+	}
+	for _, counter := range counters {
+		if _, ok := h.severities[counter.Node+"/"+counter.Reason]; !ok {
+			h.loadSeverities(ctx, counters)
+			break
+		}
+	}
+	for i := range counters {
+		counters[i].Severity = h.severities[counters[i].Node+"/"+counters[i].Reason]
+	}
+}
+
+// loadSeverities loads the severities of the error counters from the CLI.
+// Counters missing in the output get the unknown severity, so they are not
+// loaded again.
+func (h *TelemetryHandler) loadSeverities(ctx context.Context, counters []api.NodeCounter) {
+	info, err := h.cliNodeCounters(ctx)
+	if err != nil {
+		logrus.Debugf("failed to load error counter severities: %v", err)
+	} else {
+		for _, counter := range info.Counters {
+			h.severities[counter.Node+"/"+counter.Reason] = counter.Severity
+		}
+	}
+	for _, counter := range counters {
+		if _, ok := h.severities[counter.Node+"/"+counter.Reason]; !ok {
+			h.severities[counter.Node+"/"+counter.Reason] = "unknown"
+		}
+	}
+}
+
+// cliNodeCounters parses the error counters of nodes from the 'show node counters' output.
+func (h *TelemetryHandler) cliNodeCounters(ctx context.Context) (*api.NodeCounterInfo, error) {
 	var counters []api.NodeCounter
 	data, err := h.vpeRpc.CliInband(ctx, &vpe.CliInband{
 		Cmd: "show node counters",
@@ -120,7 +203,25 @@ func (h *TelemetryHandler) GetNodeCounters(ctx context.Context) (*api.NodeCounte
 	}, nil
 }
 
+// GetRuntimeInfo returns the runtime counters of nodes per thread, read from the
+// stats segment if available, or parsed from the 'show runtime' output. The node
+// state and thread rates are not kept in the segment, the thread time is the
+// time of the last segment update.
 func (h *TelemetryHandler) GetRuntimeInfo(ctx context.Context) (*api.RuntimeInfo, error) {
+	if h.segment != nil {
+		info, err := h.segment.runtimeInfo()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading runtime counters from the stats segment failed")
+		}
+		if info != nil {
+			return info, nil
+		}
+	}
+	return h.cliRuntimeInfo(ctx)
+}
+
+// cliRuntimeInfo parses the runtime counters of nodes from the 'show runtime' output.
+func (h *TelemetryHandler) cliRuntimeInfo(ctx context.Context) (*api.RuntimeInfo, error) {
 	cliResp, err := h.vpeRpc.CliInband(ctx, &vpe.CliInband{
 		Cmd: "show runtime",
 	})
@@ -194,6 +295,101 @@ func (h *TelemetryHandler) GetThreads(ctx context.Context) ([]api.ThreadData, er
 	return result, nil
 }
 
+func (h *TelemetryHandler) GetPuntStats(ctx context.Context) ([]api.PuntStat, error) {
+	data, err := h.vpeRpc.CliInband(ctx, &vpe.CliInband{
+		Cmd: "show punt stats",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "VPP CLI command \"show punt stats\" failed")
+	}
+	return api.ParsePuntStats(data.Reply)
+}
+
+// GetPolicers returns policers with their counters. Policer counters are kept
+// in the stats segment as well ('/net/policer/*'), but indexed by the policer
+// index only, so the counters are read together with the names from the CLI.
+func (h *TelemetryHandler) GetPolicers(ctx context.Context) ([]api.Policer, error) {
+	data, err := h.vpeRpc.CliInband(ctx, &vpe.CliInband{
+		Cmd: "show policer",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "VPP CLI command \"show policer\" failed")
+	}
+	return parsePolicers(data.Reply), nil
+}
+
+// parsePolicers parses the 'show policer' output, each policer is listed
+// with its configuration followed by its counters:
+//
+//	Name "pol1" type 1r2c cir 10000 eir 0 cb 20000 eb 0
+//	rate type kbps, round type closest
+//	conform action transmit, exceed action drop, violate action drop
+//	conform 12 packets, 1536 bytes
+//	exceed 0 packets, 0 bytes
+//	violate 3 packets, 384 bytes
+func parsePolicers(out string) []api.Policer {
+	var policers []api.Policer
+	var policer *api.Policer
+	for _, line := range strings.Split(out, "\n") {
+		if matches := policerNameRe.FindStringSubmatch(line); matches != nil {
+			policers = append(policers, api.Policer{Name: matches[1]})
+			policer = &policers[len(policers)-1]
+			line = matches[2]
+		}
+		if policer == nil {
+			continue
+		}
+		if matches := policerCountersRe.FindStringSubmatch(line); matches != nil {
+			counter := govppapi.InterfaceCounterCombined{
+				Packets: uint64(strToFloat64(matches[2])),
+				Bytes:   uint64(strToFloat64(matches[3])),
+			}
+			switch matches[1] {
+			case "conform":
+				policer.Conform = counter
+			case "exceed":
+				policer.Exceed = counter
+			case "violate":
+				policer.Violate = counter
+			}
+			continue
+		}
+		for _, part := range strings.Split(line, ",") {
+			fields := strings.Fields(part)
+			for i := 0; i < len(fields)-1; i++ {
+				value := fields[i+1]
+				switch fields[i] {
+				case "type":
+					if i > 0 && fields[i-1] == "rate" {
+						policer.RateType = value
+					} else if i == 0 {
+						policer.Type = value
+					}
+				case "cir":
+					policer.CIR = uint64(strToFloat64(value))
+				case "eir":
+					policer.EIR = uint64(strToFloat64(value))
+				case "cb":
+					policer.CB = uint64(strToFloat64(value))
+				case "eb":
+					policer.EB = uint64(strToFloat64(value))
+				case "action":
+					action := strings.Join(fields[i+1:], " ")
+					switch fields[0] {
+					case "conform":
+						policer.ConformAction = action
+					case "exceed":
+						policer.ExceedAction = action
+					case "violate":
+						policer.ViolateAction = action
+					}
+				}
+			}
+		}
+	}
+	return policers
+}
+
 func strToFloat64(s string) float64 {
 	// Replace 'k' (thousands) with 'e3' to make it parsable with strconv
 	s = strings.Replace(s, "k", "e3", 1)
@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vppcalls
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.ligato.io/vpp-agent/v3/plugins/vpp/binapi/vpp2101/gtpu"
+	"go.ligato.io/vpp-agent/v3/plugins/vpp/binapi/vpp2101/interface_types"
+	"go.ligato.io/vpp-agent/v3/plugins/vpp/binapi/vpp2101/vxlan"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/local/binapi/vpe"
+)
+
+// Tunnel types dumped by the TunnelHandler
+const (
+	TunnelVxlan  = "vxlan"
+	TunnelGtpu   = "gtpu"
+	TunnelGeneve = "geneve"
+)
+
+// TunnelVppAPI defines tunnel-specific methods
+type TunnelVppAPI interface {
+	DumpTunnels(ctx context.Context) ([]api.Tunnel, error)
+}
+
+// tunnelDump dumps the tunnels of a single type
+type tunnelDump struct {
+	tunnelType string
+	messages   []govppapi.Message
+	dump       func(ch govppapi.Channel) ([]api.Tunnel, error)
+}
+
+// tunnelDumps lists the dumps of the tunnel types. The vxlan and gtpu
+// tunnels are dumped by the VPP 21.01 bindings generated for the agent,
+// there are no generated bindings of the geneve plugin, its tunnels
+// are parsed from the CLI.
+var tunnelDumps = []tunnelDump{
+	{TunnelVxlan, vxlan.AllMessages(), dumpVxlanTunnels},
+	{TunnelGtpu, gtpu.AllMessages(), dumpGtpuTunnels},
+	{TunnelGeneve, []govppapi.Message{&vpe.CliInband{}, &vpe.CliInbandReply{}}, dumpGeneveTunnels},
+}
+
+// TunnelMessages returns the messages of all the tunnel dumps.
+func TunnelMessages() []govppapi.Message {
+	var msgs []govppapi.Message
+	for _, td := range tunnelDumps {
+		msgs = append(msgs, td.messages...)
+	}
+	return msgs
+}
+
+// TunnelHandler implements TunnelVppAPI
+type TunnelHandler struct {
+	ch govppapi.Channel
+	// dumps of the tunnel types whose messages are supported by the VPP,
+	// the messages of plugins which are not loaded are unknown
+	dumps []tunnelDump
+}
+
+// NewTunnelHandler returns a new instance of the TunnelVppAPI dumping
+// the tunnels of the given types (all types if none is given).
+func NewTunnelHandler(ch govppapi.Channel, tunnelTypes ...string) TunnelVppAPI {
+	h := &TunnelHandler{ch: ch}
+	for _, td := range tunnelDumps {
+		if len(tunnelTypes) != 0 && !containsType(tunnelTypes, td.tunnelType) {
+			continue
+		}
+		if err := ch.CheckCompatiblity(td.messages...); err != nil {
+			continue
+		}
+		h.dumps = append(h.dumps, td)
+	}
+	return h
+}
+
+// containsType returns true if the tunnel type is listed in the types.
+func containsType(tunnelTypes []string, tunnelType string) bool {
+	for _, t := range tunnelTypes {
+		if t == tunnelType {
+			return true
+		}
+	}
+	return false
+}
+
+// DumpTunnels returns the tunnels of all the supported types. Tunnel types
+// of plugins which are not loaded are skipped.
+func (h *TunnelHandler) DumpTunnels(_ context.Context) ([]api.Tunnel, error) {
+	var tunnels []api.Tunnel
+	for _, td := range h.dumps {
+		dumped, err := td.dump(h.ch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dump %s tunnels: %v", td.tunnelType, err)
+		}
+		tunnels = append(tunnels, dumped...)
+	}
+	return tunnels, nil
+}
+
+// dumpVxlanTunnels dumps the vxlan tunnels, the ID is the VNI.
+func dumpVxlanTunnels(ch govppapi.Channel) ([]api.Tunnel, error) {
+	var tunnels []api.Tunnel
+	reqCtx := ch.SendMultiRequest(&vxlan.VxlanTunnelDump{
+		SwIfIndex: interface_types.InterfaceIndex(allInterfaces),
+	})
+	for {
+		details := &vxlan.VxlanTunnelDetails{}
+		stop, err := reqCtx.ReceiveReply(details)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tunnels = append(tunnels, api.Tunnel{
+			Type:      TunnelVxlan,
+			SwIfIndex: uint32(details.SwIfIndex),
+			Src:       details.SrcAddress.String(),
+			Dst:       details.DstAddress.String(),
+			ID:        details.Vni,
+		})
+	}
+	return tunnels, nil
+}
+
+// dumpGtpuTunnels dumps the gtpu tunnels, the ID is the local TEID.
+func dumpGtpuTunnels(ch govppapi.Channel) ([]api.Tunnel, error) {
+	var tunnels []api.Tunnel
+	reqCtx := ch.SendMultiRequest(&gtpu.GtpuTunnelDump{
+		SwIfIndex: interface_types.InterfaceIndex(allInterfaces),
+	})
+	for {
+		details := &gtpu.GtpuTunnelDetails{}
+		stop, err := reqCtx.ReceiveReply(details)
+		if stop {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tunnels = append(tunnels, api.Tunnel{
+			Type:      TunnelGtpu,
+			SwIfIndex: uint32(details.SwIfIndex),
+			Src:       details.SrcAddress.String(),
+			Dst:       details.DstAddress.String(),
+			ID:        details.Teid,
+		})
+	}
+	return tunnels, nil
+}
+
+// dumpGeneveTunnels parses the geneve tunnels from the 'show geneve tunnel'
+// output, the ID is the VNI. No tunnels are returned if the geneve plugin
+// is not loaded (the command is unknown).
+func dumpGeneveTunnels(ch govppapi.Channel) ([]api.Tunnel, error) {
+	reply := &vpe.CliInbandReply{}
+	if err := ch.SendRequest(&vpe.CliInband{Cmd: "show geneve tunnel"}).ReceiveReply(reply); err != nil {
+		return nil, err
+	}
+	var tunnels []api.Tunnel
+	for _, line := range strings.Split(reply.Reply, "\n") {
+		if tunnel, ok := parseGeneveTunnel(line); ok {
+			tunnels = append(tunnels, tunnel)
+		}
+	}
+	return tunnels, nil
+}
+
+// parseGeneveTunnel parses a single tunnel line of the 'show geneve tunnel' output, e.g.
+// "[0] lcl 10.0.0.1 rmt 10.0.0.2 vni 13 fib-idx 0 sw-if-idx 5 decap-next-l2 "
+func parseGeneveTunnel(line string) (api.Tunnel, bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "[") {
+		return api.Tunnel{}, false
+	}
+
+	tunnel := api.Tunnel{Type: TunnelGeneve}
+	found := false
+	for i := 1; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "lcl":
+			tunnel.Src = fields[i+1]
+		case "rmt":
+			tunnel.Dst = fields[i+1]
+		case "vni":
+			vni, _ := strconv.ParseUint(fields[i+1], 10, 32)
+			tunnel.ID = uint32(vni)
+		case "sw-if-idx":
+			idx, err := strconv.ParseUint(fields[i+1], 10, 32)
+			tunnel.SwIfIndex, found = uint32(idx), err == nil
+		}
+	}
+	return tunnel, found
+}
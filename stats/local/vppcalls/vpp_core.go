@@ -22,13 +22,14 @@ import (
 	govppapi "git.fd.io/govpp.git/api"
 	"go.pantheon.tech/vpptop/stats/api"
 	"go.pantheon.tech/vpptop/stats/local/binapi/vpe"
-	"github.com/prometheus/common/log"
+	"github.com/sirupsen/logrus"
 	"strings"
 )
 
 // VppCoreAPI defines vpe-specific methods
 type VppCoreAPI interface {
 	RunCli(ctx context.Context, cmd string) (string, error)
+	Ping(ctx context.Context) error
 	GetPlugins(context.Context) ([]api.PluginInfo, error)
 	GetVersion(context.Context) (*api.VersionInfo, error)
 	GetSession(context.Context) (*api.SessionInfo, error)
@@ -57,6 +58,11 @@ func (h VppCoreHandler) RunCli(ctx context.Context, cmd string) (string, error)
 	return resp.Reply, nil
 }
 
+func (h VppCoreHandler) Ping(ctx context.Context) error {
+	_, err := h.vpeRpc.ControlPing(ctx, &vpe.ControlPing{})
+	return err
+}
+
 func (h VppCoreHandler) GetPlugins(ctx context.Context) ([]api.PluginInfo, error) {
 	const pluginPathPrefix = "Plugin path is:"
 
@@ -130,7 +136,7 @@ func (h VppCoreHandler) GetSession(ctx context.Context) (*api.SessionInfo, error
 
 	sysTime, err := h.vpeRpc.ShowVpeSystemTime(ctx, new(vpe.ShowVpeSystemTime))
 	if err != nil {
-		log.Warn("system time error: %v", err)
+		logrus.Warnf("system time error: %v", err)
 	} else {
 		info.Uptime = float64(sysTime.VpeSystemTime)
 	}
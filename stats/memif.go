@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// memifRingKey identifies a ring of a memif interface.
+type memifRingKey struct {
+	iface     string
+	direction string
+	id        uint32
+}
+
+// memifCounters keeps the number of polls each memif ring was found full at,
+// the memif plugin does not count the ring full events.
+type memifCounters struct {
+	sync.Mutex
+	fullPolls map[memifRingKey]uint64
+}
+
+// isMemif returns true if the interface is a memif interface.
+func isMemif(iface api.Interface) bool {
+	return iface.Device.Type == "memif" || strings.HasPrefix(iface.InterfaceName, "memif")
+}
+
+// memifRings returns the rings of the memif interfaces by the interface name,
+// the rings are dumped only if any of the interfaces is a memif. The rings of
+// removed interfaces are forgotten.
+func (p *vppProvider) memifRings(ctx context.Context, ifaces []api.Interface) map[string][]api.MemifRing {
+	hasMemif := false
+	for _, iface := range ifaces {
+		if isMemif(iface) {
+			hasMemif = true
+			break
+		}
+	}
+	if !hasMemif {
+		return nil
+	}
+
+	rings, err := p.handler.DumpMemifRings(ctx)
+	if err != nil {
+		if err != api.ErrNotSupported {
+			logrus.Warnf("failed to dump memif rings: %v", err)
+		}
+		return nil
+	}
+
+	p.memif.Lock()
+	defer p.memif.Unlock()
+	fullPolls := make(map[memifRingKey]uint64, len(rings))
+	result := make(map[string][]api.MemifRing)
+	for _, ring := range rings {
+		key := memifRingKey{iface: ring.Interface, direction: ring.Direction, id: ring.ID}
+		fullPolls[key] = p.memif.fullPolls[key]
+		if ring.Full() {
+			fullPolls[key]++
+		}
+		ring.FullPolls = fullPolls[key]
+		result[ring.Interface] = append(result[ring.Interface], ring)
+	}
+	p.memif.fullPolls = fullPolls
+	return result
+}
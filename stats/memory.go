@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// memoryHeaps are the 'show memory' commands of the heaps shown after the
+// main heaps of threads. Heaps not supported by the VPP are skipped.
+var memoryHeaps = []struct {
+	cmd string
+	// title of the section if not printed by the VPP
+	title string
+}{
+	{cmd: "show memory api-segment"},
+	{cmd: "show memory stats-segment"},
+	{cmd: "show memory numa-heaps"},
+	{cmd: "show memory map", title: "Memory map"},
+}
+
+// GetMemory returns memory usage of the main heap per thread, followed
+// by the API segment, stats segment and NUMA heaps and the memory map
+// regions. Every section starts by a row without the indentation
+// (e.g. "Thread 0 vpp_main" or "API segment"), its details are indented.
+func (p *vppProvider) GetMemory(ctx context.Context) ([]string, error) {
+	mem, err := p.handler.RunCli(ctx, "show memory main-heap verbose")
+	if err != nil {
+		return nil, err
+	}
+	rows := memoryRows(mem, "")
+
+	for _, heap := range memoryHeaps {
+		out, err := p.handler.RunCli(ctx, heap.cmd)
+		if err != nil {
+			logrus.Debugf("failed to dump memory (%s): %v", heap.cmd, err)
+			continue
+		}
+		if strings.Contains(out, "unknown input") || strings.Contains(out, "Need one of") {
+			// not supported by the VPP version
+			continue
+		}
+		rows = append(rows, memoryRows(out, heap.title)...)
+	}
+	return rows, nil
+}
+
+// memoryRows splits the 'show memory' output to rows without empty lines
+// and trailing spaces. If the title is set, it is added as the section
+// header and all lines of the output are indented as its details.
+func memoryRows(out, title string) []string {
+	var rows []string
+	if title != "" {
+		rows = append(rows, title)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, " \r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if title != "" {
+			line = "  " + line
+		}
+		rows = append(rows, line)
+	}
+	if title != "" && len(rows) == 1 {
+		return nil
+	}
+	return rows
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// 'show ip neighbors' output line, e.g.
+// "8.5390   10.0.0.2   D   52:54:00:00:01:02 GigabitEthernet0/8/0"
+var neighborRe = regexp.MustCompile(`^\s*[\d.]+\s+(\S+)\s+([SDN]+)\s+([0-9a-fA-F:]{17})\s+(\S+)\s*$`)
+
+// GetNeighbors returns the IPv4 (ARP) and IPv6 (ND) neighbors. Handlers which do
+// not support the neighbor dump fall back to the 'show ip neighbors' output,
+// the age of the neighbors is not known then.
+func (p *vppProvider) GetNeighbors(ctx context.Context) ([]api.Neighbor, error) {
+	neighbors, err := p.handler.DumpNeighbors(ctx)
+	if err == api.ErrNotSupported {
+		out, cliErr := p.handler.RunCli(ctx, "show ip neighbors")
+		if cliErr != nil {
+			return nil, fmt.Errorf("request failed: %v", cliErr)
+		}
+		neighbors, err = parseNeighbors(out), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+
+	ifDetails, err := p.handler.DumpInterfaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	indexes := make(map[string]uint32, len(ifDetails))
+	for idx, details := range ifDetails {
+		indexes[details.InternalName] = idx
+	}
+	for i := range neighbors {
+		if neighbors[i].Interface == "" {
+			if details, ok := ifDetails[neighbors[i].SwIfIndex]; ok {
+				neighbors[i].Interface = details.InternalName
+			}
+		} else if idx, ok := indexes[neighbors[i].Interface]; ok {
+			neighbors[i].SwIfIndex = idx
+		}
+	}
+	return neighbors, nil
+}
+
+// WatchNeighbors calls the onChange on each neighbor event reported by the VPP.
+func (p *vppProvider) WatchNeighbors(ctx context.Context, onChange func()) error {
+	return p.handler.WatchNeighbors(ctx, onChange)
+}
+
+// parseNeighbors parses the 'show ip neighbors' output, the flags
+// are S (static), D (dynamic) and N (no FIB entry):
+//
+//	Time                       IP                    Flags      Ethernet              Interface
+//	  8.5390               10.0.0.2                    D    52:54:00:00:01:02 GigabitEthernet0/8/0
+//	  0.0000              10.0.0.254                   SN   52:54:00:00:01:fe GigabitEthernet0/8/0
+func parseNeighbors(out string) []api.Neighbor {
+	var neighbors []api.Neighbor
+	for _, line := range strings.Split(out, "\n") {
+		m := neighborRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		neighbors = append(neighbors, api.Neighbor{
+			Interface:  m[4],
+			IP:         m[1],
+			MAC:        strings.ToLower(m[3]),
+			Age:        api.UnknownNeighborAge,
+			Static:     strings.Contains(m[2], "S"),
+			NoFibEntry: strings.Contains(m[2], "N"),
+		})
+	}
+	return neighbors
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// procRoot is the mount point of the proc filesystem.
+var procRoot = "/proc"
+
+// schedPolicies are the names of the scheduler policies by their value.
+var schedPolicies = map[int]string{
+	0: "other",
+	1: "fifo",
+	2: "rr",
+	3: "batch",
+	5: "idle",
+	6: "deadline",
+}
+
+// readThreadHostData reads the CPU affinity, the scheduler policy and priority,
+// and the context switches of the thread from /proc. The thread PID reported
+// by VPP is the thread id, which is accessible in /proc directly.
+func readThreadHostData(thread *api.ThreadData) error {
+	dir := filepath.Join(procRoot, strconv.FormatUint(uint64(thread.PID), 10))
+
+	status, err := ioutil.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(fields[1])
+		switch fields[0] {
+		case "Cpus_allowed_list":
+			thread.Affinity = value
+		case "voluntary_ctxt_switches":
+			thread.VoluntaryCtxSwitches, _ = strconv.ParseUint(value, 10, 64)
+		case "nonvoluntary_ctxt_switches":
+			thread.InvoluntaryCtxSwitches, _ = strconv.ParseUint(value, 10, 64)
+		}
+	}
+
+	stat, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return err
+	}
+	policy, priority, err := parseSchedStat(string(stat))
+	if err != nil {
+		return err
+	}
+	thread.SchedPolicy, thread.SchedPriority = policy, priority
+	return nil
+}
+
+// parseSchedStat returns the scheduler policy and priority from the /proc/<pid>/stat
+// content. The priority is the real-time priority of real-time policies, or the
+// nice value otherwise.
+func parseSchedStat(stat string) (string, int, error) {
+	// the command name may contain spaces, fields start after its closing parenthesis
+	end := strings.LastIndex(stat, ")")
+	if end < 0 {
+		return "", 0, fmt.Errorf("invalid stat %q", stat)
+	}
+	// fields[0] is the field 3 (state) described in proc(5)
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 39 {
+		return "", 0, fmt.Errorf("stat has only %d fields", len(fields)+2)
+	}
+	nice, _ := strconv.Atoi(fields[16])
+	rtPriority, _ := strconv.Atoi(fields[37])
+	value, _ := strconv.Atoi(fields[38])
+
+	policy, ok := schedPolicies[value]
+	if !ok {
+		policy = strconv.Itoa(value)
+	}
+	if value == 1 || value == 2 {
+		return policy, rtPriority, nil
+	}
+	return policy, nice, nil
+}
@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"git.fd.io/govpp.git/core"
+	"git.fd.io/govpp.git/proxy"
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/i18n"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+const (
+	// remoteFailures is the number of failed pings in a row after which
+	// the proxy is considered lost and the connection is re-established.
+	remoteFailures = 3
+	// remoteBackoffMax is the maximal interval between the attempts
+	// to reconnect to the proxy, the interval doubles after each attempt.
+	remoteBackoffMax = 30 * time.Second
+)
+
+// remoteConn is the state of the connection to the remote proxy.
+type remoteConn struct {
+	sync.Mutex
+	addr string
+	// handler wrapping the handler of the current proxy connection
+	handler *timedHandler
+	// set if the proxy accepted the last connection attempt
+	reachable bool
+	// pings failed in a row
+	failures int
+	// reconnection attempts failed in a row and the time of the next one
+	attempts int
+	retryAt  time.Time
+}
+
+// remoteSession is the VPP client connected via the proxy
+// together with the handler compatible with the VPP.
+type remoteSession struct {
+	vppClient *api.VppClient
+	handler   api.HandlerAPI
+	info      *api.VPPInfo
+}
+
+// initRemote creates the VPP client and the handler of the proxy client.
+// The handlers register binapi messages for the gob encoding when created,
+// so the messages are registered again for every proxy connection.
+func (p *vppProvider) initRemote(ctx context.Context, client *proxy.Client) (*remoteSession, error) {
+	statsConn, err := client.NewStatsClient()
+	if err != nil {
+		return nil, err
+	}
+	vppClient := api.NewProxyClient(client, statsConn)
+	vppClient.SetInterfaceCounterSource(p.ifCounters)
+
+	var (
+		handler       api.HandlerAPI
+		binapiVersion string
+	)
+	for _, handlerDef := range p.handlerDefs {
+		handler, binapiVersion, err = handlerDef.IsHandlerCompatible(vppClient, true)
+		if err != nil {
+			vppClient.Close()
+			return nil, err
+		}
+		if binapiVersion == "" {
+			logrus.Debugf("handler %s is not compatible with the connected VPP", handlerDef.Name())
+			continue
+		}
+		logrus.Infof("using handler %s with binapi version %s", handlerDef.Name(), binapiVersion)
+		p.diag.setHandler(handlerDef.Name(), binapiVersion)
+		break
+	}
+	if binapiVersion == "" {
+		vppClient.Close()
+		return nil, fmt.Errorf("no compatible handler was found")
+	}
+
+	info, err := dumpInfo(ctx, newTimedHandler(handler, p.requestTimeout, p.requests, p.diag))
+	if err != nil {
+		handler.Close()
+		vppClient.Close()
+		return nil, err
+	}
+	info.Version = binapiVersion
+	vppClient.SetInfo(*info)
+	p.diag.setInfo(info)
+
+	return &remoteSession{
+		vppClient: vppClient,
+		handler:   handler,
+		info:      info,
+	}, nil
+}
+
+// setSession sets the VPP client and version of the proxy session,
+// the previous client is returned.
+func (p *vppProvider) setSession(session *remoteSession) *api.VppClient {
+	p.clientMu.Lock()
+	defer p.clientMu.Unlock()
+	old := p.vppClient
+	p.vppClient = session.vppClient
+	p.vppVersion = &session.info.VersionInfo
+	return old
+}
+
+// client returns the current VPP client.
+func (p *vppProvider) client() *api.VppClient {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	return p.vppClient
+}
+
+// version returns the version of the connected VPP.
+func (p *vppProvider) version() *api.VersionInfo {
+	p.clientMu.RLock()
+	defer p.clientMu.RUnlock()
+	return p.vppVersion
+}
+
+// watchRemote pings the VPP via the proxy and re-establishes the connection
+// with a backoff if the pings fail, until the context is cancelled.
+func (p *vppProvider) watchRemote(ctx context.Context) {
+	ticker := time.NewTicker(healthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if atomic.LoadInt32(&p.vppConnectionState) != int32(core.Disconnected) {
+			p.pingRemote(ctx)
+		} else if p.remote.retryDue() {
+			p.reconnectRemote(ctx)
+		}
+	}
+}
+
+// pingRemote pings the VPP via the proxy. The VPP is not responding after
+// a failed ping and disconnected after remoteFailures pings failed in a row.
+func (p *vppProvider) pingRemote(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, healthInterval)
+	defer cancel()
+	err := p.handler.Ping(pingCtx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	r := p.remote
+	r.Lock()
+	defer r.Unlock()
+	state := core.Connected
+	if err == nil {
+		r.failures = 0
+	} else if r.failures++; r.failures < remoteFailures {
+		state = core.NotResponding
+	} else {
+		logrus.Warnf("connection to the proxy %s lost: %v", r.addr, err)
+		state = core.Disconnected
+		r.failures = 0
+		r.attempts = 0
+		r.retryAt = time.Now()
+	}
+	if lastState := atomic.SwapInt32(&p.vppConnectionState, int32(state)); lastState != int32(state) {
+		logrus.Infof("VPP API connection state was changed to %s", state)
+	}
+}
+
+// reconnectRemote connects to the proxy again and replaces the handler and
+// the VPP client by the ones of the new connection. The next attempt is
+// scheduled with a backoff if the proxy or the VPP behind it is not available.
+func (p *vppProvider) reconnectRemote(ctx context.Context) {
+	r := p.remote
+	client, err := proxy.Connect(r.addr)
+	if err != nil {
+		r.failed(false, p.retry.interval(), fmt.Errorf("connection to raddr %v failed: %v", r.addr, err))
+		return
+	}
+	session, err := p.initRemote(ctx, client)
+	if err != nil {
+		r.failed(true, p.retry.interval(), fmt.Errorf("connection to the VPP via raddr %v failed: %v", r.addr, err))
+		return
+	}
+
+	r.handler.swap(session.handler).Close()
+	p.setSession(session).Close()
+
+	r.Lock()
+	r.reachable = true
+	r.attempts = 0
+	r.Unlock()
+	atomic.StoreInt32(&p.vppConnectionState, int32(core.Connected))
+	logrus.Infof("reconnected to the proxy %s", r.addr)
+}
+
+// retryDue returns true if the next reconnection attempt is due.
+func (r *remoteConn) retryDue() bool {
+	r.Lock()
+	defer r.Unlock()
+	return !time.Now().Before(r.retryAt)
+}
+
+// failed schedules the next reconnection attempt, the interval doubles with
+// every failed attempt up to remoteBackoffMax.
+func (r *remoteConn) failed(reachable bool, interval time.Duration, err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.reachable = reachable
+	r.attempts++
+	backoff := interval
+	for i := 1; i < r.attempts && backoff < remoteBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > remoteBackoffMax {
+		backoff = remoteBackoffMax
+	}
+	r.retryAt = time.Now().Add(backoff)
+	logrus.Warnf("%v (attempt %d, next in %v)", err, r.attempts, backoff)
+}
+
+// remoteState returns the state of the proxy connection formatted for the
+// state, empty if the VPP is not connected via the proxy.
+func (p *vppProvider) remoteState() string {
+	if p.remote == nil {
+		return ""
+	}
+	r := p.remote
+	r.Lock()
+	defer r.Unlock()
+	if r.reachable {
+		return "\n[\u25CF](fg:green) " + i18n.T("Proxy: %s", r.addr)
+	}
+	retry := time.Until(r.retryAt).Round(time.Second)
+	if retry < 0 {
+		retry = 0
+	}
+	return "\n[\u25CF](fg:red) " + i18n.T("Proxy unreachable, retry in %v", retry)
+}
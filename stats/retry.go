@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"time"
+
+	"git.fd.io/govpp.git/core"
+)
+
+// remoteAttempts is the default number of attempts to connect to the proxy.
+const remoteAttempts = 3
+
+// RetryConfig configures the attempts to connect to the VPP.
+type RetryConfig struct {
+	// Attempts is the number of connection attempts. If zero, the local
+	// VPP is connected without a limit and the proxy is tried 3 times.
+	Attempts int
+	// Interval between the connection attempts (1s if zero). It is also
+	// the initial backoff of the reconnection to the proxy.
+	Interval time.Duration
+	// Timeout of the whole connection, including all attempts
+	// (no timeout if zero).
+	Timeout time.Duration
+}
+
+// ProviderOption configures the VPP provider.
+type ProviderOption func(*vppProvider)
+
+// WithRetry sets the attempts to connect to the VPP.
+func WithRetry(cfg RetryConfig) ProviderOption {
+	return func(p *vppProvider) {
+		p.retry = cfg
+	}
+}
+
+// attempts returns the number of connection attempts, or the default if not set.
+func (c RetryConfig) attempts(def int) int {
+	if c.Attempts <= 0 {
+		return def
+	}
+	return c.Attempts
+}
+
+// interval returns the interval between the connection attempts.
+func (c RetryConfig) interval() time.Duration {
+	if c.Interval <= 0 {
+		return core.DefaultReconnectInterval
+	}
+	return c.Interval
+}
+
+// deadline returns a timer firing once the connection times out. The timer
+// never fires if there is no timeout, it has to be stopped by the caller.
+func (c RetryConfig) deadline() *time.Timer {
+	if c.Timeout <= 0 {
+		timer := time.NewTimer(time.Hour)
+		timer.Stop()
+		return timer
+	}
+	return time.NewTimer(c.Timeout)
+}
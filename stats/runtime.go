@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// thread header of the 'show runtime' output, e.g. "Thread 1 vpp_wk_0 (lcore 2)"
+var runtimeThreadRe = regexp.MustCompile(`^Thread\s+(\d+)\s`)
+
+// runtimeMaxKey identifies a node of a thread.
+type runtimeMaxKey struct {
+	thread uint
+	node   string
+}
+
+// runtimeMax are the max clocks of a node.
+type runtimeMax struct {
+	clocks  float64
+	vectors uint64
+}
+
+// dumpRuntimeMax returns max clocks per node and thread from the 'show runtime max' output.
+func (p *vppProvider) dumpRuntimeMax(ctx context.Context) (map[runtimeMaxKey]runtimeMax, error) {
+	out, err := p.handler.RunCli(ctx, "show runtime max")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	return parseRuntimeMax(out), nil
+}
+
+// parseRuntimeMax parses the 'show runtime max' output. Node lines end with
+// the max node clocks and the vectors at max, the node state may contain spaces:
+//
+//	Name                State         Calls   Vectors   Suspends   Max Node Clocks   Vectors at Max
+//	ip4-lookup          active           10       120          0            1.36e2                  32
+func parseRuntimeMax(out string) map[runtimeMaxKey]runtimeMax {
+	result := make(map[runtimeMaxKey]runtimeMax)
+	var thread uint
+	for _, line := range strings.Split(out, "\n") {
+		if matches := runtimeThreadRe.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			id, _ := strconv.ParseUint(matches[1], 10, 32)
+			thread = uint(id)
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		clocks, err := strconv.ParseFloat(fields[len(fields)-2], 64)
+		if err != nil {
+			continue
+		}
+		vectors, err := strconv.ParseUint(fields[len(fields)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		result[runtimeMaxKey{thread: thread, node: fields[0]}] = runtimeMax{clocks: clocks, vectors: vectors}
+	}
+	return result
+}
+
+// busyClocks returns the clocks the node spent processing vectors, used for
+// the utilization of the threads. Unlike nodeClocks, the calls of nodes not
+// processing vectors (e.g. input nodes polling idle interfaces) and suspends
+// of process nodes are not busy, they would show an idle worker fully busy.
+func busyClocks(node api.Node) float64 {
+	// clocks are per vector only if the node processed vectors
+	if node.Vectors == 0 {
+		return 0
+	}
+	return node.Clocks * float64(node.Vectors)
+}
+
+// nodeClocks returns the total clocks spent in the node. VPP shows clocks
+// per vector, or per call (suspend) for nodes not processing vectors.
+func nodeClocks(node api.Node) float64 {
+	switch {
+	case node.Vectors > 0:
+		return node.Clocks * float64(node.Vectors)
+	case node.Calls > 0:
+		return node.Clocks * float64(node.Calls)
+	default:
+		return node.Clocks * float64(node.Suspends)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// Regular expressions used to parse the 'show interface rx-placement' output
+var (
+	// thread header, e.g. "Thread 1 (vpp_wk_0):"
+	rxThreadRe = regexp.MustCompile(`^Thread (\d+) \(`)
+	// rx queue, e.g. "    GigabitEthernet0/8/0 queue 0 (polling)"
+	rxQueueRe = regexp.MustCompile(`^\s+(\S+) queue (\d+) \(`)
+)
+
+// dumpThreadRxInterfaces returns the interfaces with rx queues placed on each
+// thread by the thread IDs, together with the packets received by the thread
+// on the interface. The packets are read from the per-thread counters of the
+// stats segment, or from the interface counters if all rx queues of the
+// interface are placed on a single thread.
+func (p *vppProvider) dumpThreadRxInterfaces(ctx context.Context) (map[uint32][]api.RxInterface, error) {
+	placement, err := p.handler.DumpRxPlacement(ctx)
+	if err == api.ErrNotSupported {
+		out, cliErr := p.handler.RunCli(ctx, "show interface rx-placement")
+		if cliErr != nil {
+			return nil, fmt.Errorf("request failed: %v", cliErr)
+		}
+		placement, err = parseRxPlacement(out), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	if len(placement) == 0 {
+		return nil, nil
+	}
+
+	ifDetails, err := p.handler.DumpInterfaces(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	ifStats, err := p.handler.DumpInterfaceStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	workerStats, err := p.dumpWorkerStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump interface worker stats: %v", err)
+	}
+	return threadRxInterfaces(placement, ifDetails, ifStats.Interfaces, workerStats), nil
+}
+
+// threadRxInterfaces joins the rx placement with the interface details and counters.
+func threadRxInterfaces(placement []api.RxPlacement, ifDetails map[uint32]*api.InterfaceDetails,
+	ifCounters []govppapi.InterfaceCounters, workerStats map[uint32][]api.WorkerCounters) map[uint32][]api.RxInterface {
+	indexes := make(map[string]uint32, len(ifDetails))
+	for idx, details := range ifDetails {
+		indexes[details.InternalName] = idx
+	}
+	rxPackets := make(map[uint32]uint64, len(ifCounters))
+	for _, iface := range ifCounters {
+		rxPackets[iface.InterfaceIndex] = iface.Rx.Packets
+	}
+
+	type ifaceKey struct {
+		thread    uint32
+		swIfIndex uint32
+	}
+	ifaces := make(map[ifaceKey]*api.RxInterface)
+	threads := make(map[uint32]map[uint32]bool)
+	for _, queue := range placement {
+		if queue.Interface != "" {
+			idx, ok := indexes[queue.Interface]
+			if !ok {
+				continue
+			}
+			queue.SwIfIndex = idx
+		}
+		key := ifaceKey{thread: queue.ThreadID, swIfIndex: queue.SwIfIndex}
+		iface, ok := ifaces[key]
+		if !ok {
+			iface = &api.RxInterface{SwIfIndex: queue.SwIfIndex}
+			if details, ok := ifDetails[queue.SwIfIndex]; ok {
+				iface.Name = details.InternalName
+			}
+			ifaces[key] = iface
+		}
+		iface.Queues = append(iface.Queues, queue.QueueID)
+		if threads[queue.SwIfIndex] == nil {
+			threads[queue.SwIfIndex] = make(map[uint32]bool)
+		}
+		threads[queue.SwIfIndex][queue.ThreadID] = true
+	}
+
+	result := make(map[uint32][]api.RxInterface)
+	for key, iface := range ifaces {
+		if workers := workerStats[key.swIfIndex]; int(key.thread) < len(workers) {
+			iface.RxPackets = workers[key.thread].Rx.Packets
+		} else if len(threads[key.swIfIndex]) == 1 {
+			iface.RxPackets = rxPackets[key.swIfIndex]
+		}
+		sort.Slice(iface.Queues, func(i, j int) bool { return iface.Queues[i] < iface.Queues[j] })
+		result[key.thread] = append(result[key.thread], *iface)
+	}
+	for _, ifaces := range result {
+		sort.Slice(ifaces, func(i, j int) bool { return ifaces[i].SwIfIndex < ifaces[j].SwIfIndex })
+	}
+	return result
+}
+
+// parseRxPlacement parses the 'show interface rx-placement' output, the rx
+// queues of interfaces are listed per thread and input node:
+//
+//	Thread 1 (vpp_wk_0):
+//	  node dpdk-input:
+//	    GigabitEthernet0/8/0 queue 0 (polling)
+//	    GigabitEthernet0/9/0 queue 0 (polling)
+func parseRxPlacement(out string) []api.RxPlacement {
+	var placement []api.RxPlacement
+	thread := -1
+	for _, line := range strings.Split(out, "\n") {
+		if m := rxThreadRe.FindStringSubmatch(line); m != nil {
+			thread, _ = strconv.Atoi(m[1])
+			continue
+		}
+		m := rxQueueRe.FindStringSubmatch(line)
+		if m == nil || thread < 0 {
+			continue
+		}
+		queue, _ := strconv.ParseUint(m[2], 10, 32)
+		placement = append(placement, api.RxPlacement{
+			Interface: m[1],
+			QueueID:   uint32(queue),
+			ThreadID:  uint32(thread),
+		})
+	}
+	return placement
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+const (
+	// allNamespaces is used for sessions, since the session CLI
+	// does not report the app namespace of a session
+	allNamespaces = "*"
+	// stateApps is used for application counts per namespace
+	stateApps = "apps"
+)
+
+var (
+	// 'show session verbose' session line, e.g.
+	// "[0:1][T] 10.0.0.1:80->10.0.0.2:4321     ESTABLISHED    0         0"
+	sessionRe = regexp.MustCompile(`^\s*\[(\d+):(\d+)\]\[(\w+)\]\s+(\S+)\s+(\S+)`)
+	// 'show app' application line, e.g. "0         proxy               default"
+	appRe = regexp.MustCompile(`^\s*(\d+)\s+(\S+)\s+(\S+)`)
+)
+
+// transport protocols by the short name used in the session CLI
+var sessionProtocols = map[string]string{
+	"T": "tcp",
+	"U": "udp",
+	"Q": "quic",
+	"J": "tls",
+	"D": "dtls",
+	"S": "sctp",
+}
+
+// GetSessions returns host-stack session counts per protocol and state,
+// and application counts per app namespace.
+func (p *vppProvider) GetSessions(ctx context.Context) ([]api.SessionStat, error) {
+	sessions, err := p.handler.RunCli(ctx, "show session verbose")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	apps, err := p.handler.RunCli(ctx, "show app")
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+
+	return append(parseSessions(sessions), parseApps(apps)...), nil
+}
+
+// parseSessions counts sessions of the 'show session verbose' output
+// per protocol and state.
+func parseSessions(out string) []api.SessionStat {
+	var result []api.SessionStat
+	counts := make(map[api.SessionStat]uint64)
+	for _, line := range strings.Split(out, "\n") {
+		matches := sessionRe.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		protocol, ok := sessionProtocols[matches[3]]
+		if !ok {
+			protocol = strings.ToLower(matches[3])
+		}
+		key := api.SessionStat{Namespace: allNamespaces, Protocol: protocol, State: matches[5]}
+		if counts[key] == 0 {
+			result = append(result, key)
+		}
+		counts[key]++
+	}
+	for i := range result {
+		result[i].Count = counts[result[i]]
+	}
+	return result
+}
+
+// parseApps counts applications of the 'show app' output per namespace.
+func parseApps(out string) []api.SessionStat {
+	var result []api.SessionStat
+	counts := make(map[string]uint64)
+	for _, line := range strings.Split(out, "\n") {
+		matches := appRe.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		namespace := matches[3]
+		if counts[namespace] == 0 {
+			result = append(result, api.SessionStat{Namespace: namespace, Protocol: "-", State: stateApps})
+		}
+		counts[namespace]++
+	}
+	for i := range result {
+		result[i].Count = counts[result[i].Namespace]
+	}
+	return result
+}
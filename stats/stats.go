@@ -21,7 +21,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -33,6 +36,7 @@ import (
 	govppapi "git.fd.io/govpp.git/api"
 	"git.fd.io/govpp.git/core"
 	"git.fd.io/govpp.git/proxy"
+	"go.pantheon.tech/vpptop/i18n"
 	"go.pantheon.tech/vpptop/stats/api"
 	"github.com/sirupsen/logrus"
 )
@@ -42,6 +46,37 @@ const (
 	stateDown = "down"
 )
 
+const (
+	typeDrop = "drop"
+	typePunt = "punt"
+)
+
+// severity of the error counters read without their severity
+// (from the stats segment, or from the CLI of an older VPP)
+const severityUnknown = "unknown"
+
+// stats segment interface counters (indexed per worker thread)
+const (
+	statsIfRx      = "/if/rx"
+	statsIfTx      = "/if/tx"
+	statsIfRxNoBuf = "/if/rx-no-buf"
+	statsIfRxMiss  = "/if/rx-miss"
+	statsIfRxError = "/if/rx-error"
+	statsIfTxError = "/if/tx-error"
+)
+
+// workerStatsPatterns are the patterns of the per worker thread interface
+// counters, anchored since the stats are dumped by regular expressions
+// ("/if/rx" would match "/if/rx-no-buf" and the others as well).
+var workerStatsPatterns = []string{
+	"^" + statsIfRx + "$",
+	"^" + statsIfTx + "$",
+	"^" + statsIfRxNoBuf + "$",
+	"^" + statsIfRxMiss + "$",
+	"^" + statsIfRxError + "$",
+	"^" + statsIfTxError + "$",
+}
+
 // vppProvider provides statistics about VPP such as runtime counters,
 // interface counters, error counters and so on
 type vppProvider struct {
@@ -55,6 +90,9 @@ type vppProvider struct {
 	// list of available VPP handler definitions
 	handlerDefs []api.HandlerDef
 
+	// guards the VPP client and version replaced on the reconnection to the proxy
+	clientMu sync.RWMutex
+
 	// current VPP API and stats connection states
 	vppConnectionState   int32
 	statsConnectionState int32
@@ -62,8 +100,55 @@ type vppProvider struct {
 	// interface to the chosen VPP handler
 	handler api.HandlerAPI
 
-	vppVersion        *api.VersionInfo
-	lastErrorCounters map[string]uint64
+	vppVersion *api.VersionInfo
+	// baseline of the cleared error counters by the node and reason
+	lastErrors errorBaseline
+	// baseline of the cleared punt counters by the punt reason
+	lastPunts errorBaseline
+
+	// guards the CPU frequency and the thread clocks, the threads are
+	// polled by the collector and by the batch requests of the proxy
+	threadMu sync.Mutex
+	// CPU frequency in Hz used to estimate the thread utilization
+	// (negative if it is not available)
+	cpuFreq float64
+	// busy clocks and runtime of threads from the last poll
+	lastThreadClocks map[uint]threadClocks
+
+	// latencies of the VPP API and stats connections
+	health connHealth
+
+	// stats sockets of VPP instances managed together with the connected
+	// one, their interfaces are merged into the interfaces of the primary
+	primaryInstance string
+	instanceSockets []StatsInstance
+	instances       []statsInstance
+
+	// attempts to connect to the VPP
+	retry RetryConfig
+	// timeout of a single handler request
+	requestTimeout time.Duration
+	// durations of the handler requests
+	requests *requestTimings
+	// diagnostic bundle logged if the handler requests fail repeatedly
+	diag *diagnostics
+	// source of the interface counters, the stats socket is not
+	// connected if the counters are parsed from the CLI
+	ifCounters api.InterfaceCounterSource
+
+	// connection to the remote proxy (nil if the VPP is connected locally)
+	remote *remoteConn
+	// batch RPC of the remote proxy (nil if the VPP is connected locally)
+	batch *batchClient
+	// set if the payload of the batch RPC is compressed
+	compressBatch bool
+	// set if only the stats socket is connected, see ConnectStats
+	statsOnly bool
+
+	// LLDP neighbors of the interfaces dumped in a longer interval
+	lldp lldpCache
+	// polls the memif rings were found full at
+	memif memifCounters
 
 	// cancel connection changes watcher
 	cancel context.CancelFunc
@@ -71,84 +156,199 @@ type vppProvider struct {
 
 // NewVppProvider constructs new VppProviderAPI object with available
 // VPP version definitions
-func NewVppProvider(defs []api.HandlerDef, logFile io.Writer) api.VppProviderAPI {
-	return &vppProvider{
-		handlerDefs: defs,
-		out:         logFile,
+func NewVppProvider(defs []api.HandlerDef, logFile io.Writer, opts ...ProviderOption) api.VppProviderAPI {
+	p := &vppProvider{
+		handlerDefs:    defs,
+		out:            logFile,
+		requestTimeout: DefaultRequestTimeout,
+		requests:       newRequestTimings(),
+		diag:           newDiagnostics(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithInterfaceCounters sets where the interface counters are read from.
+func WithInterfaceCounters(source api.InterfaceCounterSource) ProviderOption {
+	return func(p *vppProvider) {
+		p.ifCounters = source
 	}
 }
 
 // Connect establishes a VPP connection using GoVPP API
 func (p *vppProvider) Connect(soc string) error {
-	p.lastErrorCounters = make(map[string]uint64)
+	p.lastErrors.reset()
+	p.lastPunts.reset()
+	p.redirectLogs()
 
-	// redirect GoVPP loggers to the log file
-	core.SetLogger(&logrus.Logger{Out: p.out})
-	statsclient.Log.Out = p.out
-
-	// very high number of attempts
-	retryAttempts := int(^uint(0) >> 1)
+	// very high number of attempts by default
+	retryAttempts := p.retry.attempts(int(^uint(0) >> 1))
+	deadline := p.retry.deadline()
+	defer deadline.Stop()
 
 	// connect to the VPP and wait for reply
-	vppConn, vppConnEv, err := govpp.AsyncConnect("", retryAttempts, core.DefaultReconnectInterval)
+	vppConn, vppConnEv, err := govpp.AsyncConnect("", retryAttempts, p.retry.interval())
 	if err != nil {
 		return fmt.Errorf("connection to govpp failed: %v", err)
 	}
 	select {
 	case e := <-vppConnEv:
-		if e.State == core.Connected {
-			// OK
-		} else {
-			log.Fatalf("Error: unexpected VPP state: %s\n", e.State.String())
+		if e.State != core.Connected {
+			vppConn.Disconnect()
+			return fmt.Errorf("connection to the VPP API failed after %d attempts: %v", retryAttempts, e.Error)
 		}
+	case <-deadline.C:
+		vppConn.Disconnect()
+		return fmt.Errorf("connection to the VPP API timed out after %v (is the VPP running?)", p.retry.Timeout)
 	}
 
-	// connect to the VPP stats and wait for reply
-	statsClient := statsclient.NewStatsClient(soc)
-	statsConn, statsConnEv, err := core.AsyncConnectStats(statsClient, retryAttempts, core.DefaultReconnectInterval)
-	if err != nil {
-		return fmt.Errorf("connection to stats api failed: %v", err)
+	// connect to the VPP stats and wait for reply, the stats socket
+	// is not needed if the interface counters are parsed from the CLI
+	var statsConn *core.StatsConnection
+	var statsConnEv chan core.ConnectionEvent
+	if p.ifCounters == api.CountersFromCLI {
+		logrus.Infof("interface counters are parsed from the show interface CLI, stats socket %s is not connected", soc)
+	} else {
+		statsConn, statsConnEv, err = p.connectStats(soc, retryAttempts, deadline)
+		if err != nil {
+			vppConn.Disconnect()
+			return err
+		}
 	}
-	select {
-	case e := <-statsConnEv:
-		if e.State == core.Connected {
-			// OK
-		} else {
-			log.Fatalf("Error: unexpected VPP state: %s\n", e.State.String())
+	disconnect := func() {
+		vppConn.Disconnect()
+		if statsConn != nil {
+			statsConn.Disconnect()
 		}
 	}
 
 	if err := p.initConnection(vppConn, statsConn); err != nil {
-		log.Fatalln("Error connecting to the vpp")
+		disconnect()
+		return fmt.Errorf("error connecting to the vpp: %v", err)
+	}
+	if err := p.connectInstances(); err != nil {
+		disconnect()
+		return err
 	}
 
 	// watch connection changes
 	var ctx context.Context
 	ctx, p.cancel = context.WithCancel(context.Background())
-	go func() {
-		for {
-			select {
-			case e := <-vppConnEv:
-				lastState := atomic.LoadInt32(&p.vppConnectionState)
-				if atomic.CompareAndSwapInt32(&p.vppConnectionState, lastState, int32(e.State)) {
-					log.Printf("VPP API connection state was changed to %s", e.State)
-				}
-			case e := <-statsConnEv:
-				lastState := atomic.LoadInt32(&p.statsConnectionState)
-				if atomic.CompareAndSwapInt32(&p.statsConnectionState, lastState, int32(e.State)) {
-					log.Printf("VPP stats connection state was changed to %s", e.State)
-				}
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
+	go p.probeHealth(ctx)
+	go p.watchConnections(ctx, vppConnEv, statsConnEv)
+
+	return nil
+}
+
+// ConnectStats connects only the stats socket of the VPP. The interface, node
+// and error counters are read directly from the stats segment, neither the binary
+// API nor the CLI is used, so the other data are not available. The node counters
+// are available only with 'per-node-counters on' in the statseg config.
+func (p *vppProvider) ConnectStats(soc string) error {
+	p.lastErrors.reset()
+	p.lastPunts.reset()
+	p.statsOnly = true
+	p.redirectLogs()
+
+	retryAttempts := p.retry.attempts(int(^uint(0) >> 1))
+	deadline := p.retry.deadline()
+	defer deadline.Stop()
+
+	statsConn, statsConnEv, err := p.connectStats(soc, retryAttempts, deadline)
+	if err != nil {
+		return err
+	}
+	p.vppClient = api.NewVppClient(nil, statsConn)
+	p.vppClient.SetStatsAPI(p.statsClient)
+	p.handler = newTimedHandler(newStatsOnlyHandler(p.statsClient), p.requestTimeout, p.requests, p.diag)
+
+	info, err := dumpInfo(context.Background(), p.handler)
+	if err != nil {
+		statsConn.Disconnect()
+		return err
+	}
+	p.vppVersion = &info.VersionInfo
+	p.vppClient.SetInfo(*info)
+	p.diag.setInfo(info)
+	// there is no API connection to watch
+	atomic.StoreInt32(&p.vppConnectionState, int32(core.Connected))
+
+	if err := p.connectInstances(); err != nil {
+		statsConn.Disconnect()
+		return err
+	}
+
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go p.probeHealth(ctx)
+	go p.watchConnections(ctx, nil, statsConnEv)
 
 	return nil
 }
 
+// redirectLogs redirects the GoVPP loggers to the log file.
+func (p *vppProvider) redirectLogs() {
+	govppLogger := logrus.New()
+	govppLogger.SetOutput(p.out)
+	govppLogger.SetLevel(logrus.GetLevel())
+	core.SetLogger(govppLogger)
+	statsclient.Log.Out = p.out
+}
+
+// connectStats connects the stats socket and waits until it is connected
+// or the deadline fires.
+func (p *vppProvider) connectStats(soc string, attempts int, deadline *time.Timer) (*core.StatsConnection, chan core.ConnectionEvent, error) {
+	statsClient := statsclient.NewStatsClient(soc)
+	statsConn, statsConnEv, err := core.AsyncConnectStats(statsClient, attempts, p.retry.interval())
+	if err != nil {
+		return nil, nil, fmt.Errorf("connection to stats api failed: %v", err)
+	}
+	select {
+	case e := <-statsConnEv:
+		if e.State != core.Connected {
+			statsConn.Disconnect()
+			return nil, nil, fmt.Errorf("connection to the VPP stats socket %s failed after %d attempts: %v", soc, attempts, e.Error)
+		}
+	case <-deadline.C:
+		statsConn.Disconnect()
+		return nil, nil, fmt.Errorf("connection to the VPP stats socket %s timed out after %v", soc, p.retry.Timeout)
+	}
+	p.statsClient = statsClient
+	return statsConn, statsConnEv, nil
+}
+
+// watchConnections updates the connection states on the connection events until
+// the context is cancelled. A nil channel is not watched.
+func (p *vppProvider) watchConnections(ctx context.Context, vppConnEv, statsConnEv chan core.ConnectionEvent) {
+	for {
+		select {
+		case e := <-vppConnEv:
+			lastState := atomic.LoadInt32(&p.vppConnectionState)
+			if atomic.CompareAndSwapInt32(&p.vppConnectionState, lastState, int32(e.State)) {
+				logrus.Infof("VPP API connection state was changed to %s", e.State)
+			}
+		case e := <-statsConnEv:
+			lastState := atomic.LoadInt32(&p.statsConnectionState)
+			if atomic.CompareAndSwapInt32(&p.statsConnectionState, lastState, int32(e.State)) {
+				logrus.Infof("VPP stats connection state was changed to %s", e.State)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (p *vppProvider) initConnection(vppConn *core.Connection, statsConn *core.StatsConnection) (err error) {
-	p.vppClient = api.NewVppClient(vppConn, statsConn)
+	// a nil connection must not be wrapped into a non-nil stats provider
+	if statsConn != nil {
+		p.vppClient = api.NewVppClient(vppConn, statsConn)
+	} else {
+		p.vppClient = api.NewVppClient(vppConn, nil)
+	}
+	p.vppClient.SetStatsAPI(p.statsClient)
+	p.vppClient.SetInterfaceCounterSource(p.ifCounters)
 
 	var (
 		handler       api.HandlerAPI
@@ -160,111 +360,134 @@ func (p *vppProvider) initConnection(vppConn *core.Connection, statsConn *core.S
 		if err != nil {
 			return err
 		}
-		if binapiVersion != "" {
-			p.handler = handler
-			handlerFound = true
-			break
+		if binapiVersion == "" {
+			logrus.Debugf("handler %s is not compatible with the connected VPP", handlerDef.Name())
+			continue
 		}
+		logrus.Infof("using handler %s with binapi version %s", handlerDef.Name(), binapiVersion)
+		p.diag.setHandler(handlerDef.Name(), binapiVersion)
+		p.handler = newTimedHandler(handler, p.requestTimeout, p.requests, p.diag)
+		handlerFound = true
+		break
 	}
 	if !handlerFound {
 		return fmt.Errorf("no compatible handler was found")
 	}
 
-	ctx := context.Background()
-	plugins, err := p.handler.DumpPlugins(ctx)
+	info, err := dumpInfo(context.Background(), p.handler)
 	if err != nil {
 		return err
 	}
+	p.vppVersion = &info.VersionInfo
+	p.vppClient.SetInfo(*info)
+	p.diag.setInfo(info)
+
+	return nil
+}
 
-	session, err := p.handler.DumpSession(ctx)
+// dumpInfo retrieves basic information about the VPP connected by the handler.
+func dumpInfo(ctx context.Context, handler api.HandlerAPI) (*api.VPPInfo, error) {
+	plugins, err := handler.DumpPlugins(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	p.vppVersion, err = p.handler.DumpVersion(ctx)
+	session, err := handler.DumpSession(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get vpp version: %v", err)
+		return nil, err
 	}
 
-	p.vppClient.SetInfo(api.VPPInfo{
+	version, err := handler.DumpVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vpp version: %v", err)
+	}
+
+	return &api.VPPInfo{
 		Connected:   true,
-		VersionInfo: *p.vppVersion,
+		VersionInfo: *version,
 		SessionInfo: *session,
 		Plugins:     plugins,
-	})
-
-	return nil
+	}, nil
 }
 
-// ConnectRemote connects VPPTop to a remote proxy providing vpp statistics
+// ConnectRemote connects VPPTop to a remote proxy providing vpp statistics.
+// The proxy is reconnected if it stops responding (e.g. after its restart).
 func (p *vppProvider) ConnectRemote(rAddr string) error {
-	p.lastErrorCounters = make(map[string]uint64)
+	p.lastErrors.reset()
+	p.lastPunts.reset()
+	if len(p.instanceSockets) != 0 {
+		logrus.Warnf("stats sockets of other VPP instances are not supported via the remote proxy")
+	}
+
+	deadline := p.retry.deadline()
+	defer deadline.Stop()
 
 	var err error
 	var client *proxy.Client
-	for i := 0; i < 3; i++ {
+	attempts := p.retry.attempts(remoteAttempts)
+	for i := 0; i < attempts; i++ {
 		client, err = proxy.Connect(rAddr)
 		if err == nil {
 			break
 		}
-		time.Sleep(1 * time.Second)
+		if i == attempts-1 {
+			break
+		}
+		select {
+		case <-time.After(p.retry.interval()):
+		case <-deadline.C:
+			return fmt.Errorf("connection to raddr %v timed out after %v, reason: %v", rAddr, p.retry.Timeout, err)
+		}
 	}
 	if err != nil {
-		return fmt.Errorf("failed to connect to raddr %v, reason: %v", rAddr, err)
+		return fmt.Errorf("failed to connect to raddr %v after %d attempts, reason: %v", rAddr, attempts, err)
 	}
 
-	statsConn, err := client.NewStatsClient()
+	session, err := p.initRemote(context.Background(), client)
 	if err != nil {
 		return err
 	}
-
-	p.vppClient = api.NewProxyClient(client, statsConn)
-
-	var (
-		handler       api.HandlerAPI
-		handlerFound  bool
-		binapiVersion string
-	)
-	for _, handlerDef := range p.handlerDefs {
-		handler, binapiVersion, err = handlerDef.IsHandlerCompatible(p.vppClient, true)
-		if err != nil {
-			return err
-		}
-		if binapiVersion != "" {
-			p.handler = handler
-			handlerFound = true
-			break
-		}
-	}
-	if !handlerFound {
-		return fmt.Errorf("no compatible handler was found")
+	handler := newTimedHandler(session.handler, p.requestTimeout, p.requests, p.diag)
+	p.handler = handler
+	p.setSession(session)
+	p.remote = &remoteConn{
+		addr:      rAddr,
+		handler:   handler,
+		reachable: true,
 	}
+	p.batch = newBatchClient(rAddr, p.compressBatch, p.requestTimeout, p.requests)
 
-	ctx := context.Background()
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go p.probeHealth(ctx)
+	go p.watchRemote(ctx)
 
-	plugins, err := p.handler.DumpPlugins(ctx)
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	session, err := p.handler.DumpSession(ctx)
+// ConnectHandler uses the given handler instead of the one compatible with
+// the connected VPP. No connection is established, the handler provides all data.
+func (p *vppProvider) ConnectHandler(handler api.HandlerAPI) error {
+	p.lastErrors.reset()
+	p.lastPunts.reset()
+	p.vppClient = api.NewVppClient(nil, nil)
+	p.handler = newTimedHandler(handler, p.requestTimeout, p.requests, p.diag)
+
+	info, err := dumpInfo(context.Background(), p.handler)
 	if err != nil {
 		return err
 	}
+	info.Version = info.VersionInfo.Version
+	p.vppVersion = &info.VersionInfo
+	p.vppClient.SetInfo(*info)
+	p.diag.setInfo(info)
 
-	p.vppVersion, err = p.handler.DumpVersion(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get vpp version: %v", err)
-	}
-
-	p.vppClient.SetInfo(api.VPPInfo{
-		Connected:   true,
-		VersionInfo: *p.vppVersion,
-		SessionInfo: *session,
-		Plugins:     plugins,
-		Version:     binapiVersion,
-	})
+	atomic.StoreInt32(&p.vppConnectionState, int32(core.Connected))
+	atomic.StoreInt32(&p.statsConnectionState, int32(core.Connected))
 
+	var ctx context.Context
+	ctx, p.cancel = context.WithCancel(context.Background())
+	go p.probeHealth(ctx)
 	return nil
 }
 
@@ -272,38 +495,72 @@ func (p *vppProvider) ConnectRemote(rAddr string) error {
 func (p *vppProvider) Disconnect() {
 	p.cancel()
 	p.handler.Close()
-	if p.vppClient != nil {
-		p.vppClient.Disconnect()
-		p.vppClient.Close()
+	p.batch.close()
+	if vppClient := p.client(); vppClient != nil {
+		vppClient.Disconnect()
+		vppClient.Close()
 	}
 
 	if p.statsClient != nil {
 		if err := p.statsClient.Disconnect(); err != nil {
-			log.Printf("error disconnecting VPP provider: %v", err)
+			logrus.Errorf("error disconnecting VPP provider: %v", err)
 		}
 	}
+	p.disconnectInstances()
 }
 
 func (p *vppProvider) GetState() (core.ConnectionState, string) {
 	vppConn := atomic.LoadInt32(&p.vppConnectionState)
 	statsConn := atomic.LoadInt32(&p.statsConnectionState)
 
+	proxyState := p.remoteState()
+
 	if vppConn == int32(core.Failed) || statsConn == int32(core.Failed) {
-		return core.Failed, "[\u25CF](fg:red) Connection failed\nVPP version: -"
+		return core.Failed, "[\u25CF](fg:red) " + i18n.T("Connection failed") + "\n" + i18n.T("VPP version: %s", "-") + proxyState
 	}
 	if vppConn == int32(core.Disconnected) || statsConn == int32(core.Disconnected) {
-		return core.Disconnected, "[\u25CF](fg:red) Disconnected\nVPP version: -"
+		return core.Disconnected, "[\u25CF](fg:red) " + i18n.T("Disconnected") + "\n" + i18n.T("VPP version: %s", "-") + proxyState
 	}
+	health, slow := p.healthState()
+	vppVersion := p.version()
+	version := "\n" + i18n.T("VPP version: %s", vppVersion.Version) + "\n" + vppVersion.BuildDate + proxyState
 	if vppConn == int32(core.NotResponding) || statsConn == int32(core.NotResponding) {
-		return core.NotResponding, "[\u25CF](fg:yellow) Not responding\nVPP version: " + p.vppVersion.Version + "\n" +
-			p.vppVersion.BuildDate
+		return core.NotResponding, "[\u25CF](fg:yellow) " + i18n.T("Not responding") + health + version
 	}
-	return core.Connected, "[\u25CF](fg:green) Connected\nVPP version: " + p.vppVersion.Version + "\n" +
-		p.vppVersion.BuildDate
+	if slow {
+		return core.Connected, "[\u25CF](fg:yellow) " + i18n.T("Connected") + health + version
+	}
+	return core.Connected, "[\u25CF](fg:green) " + i18n.T("Connected") + health + version
+}
+
+// GetCapabilities returns the capabilities of the connected handler,
+// none if the provider is not connected yet.
+func (p *vppProvider) GetCapabilities() api.Capabilities {
+	if p.handler == nil {
+		return api.Capabilities{}
+	}
+	return p.handler.Capabilities()
+}
+
+// GetInfo re-dumps information about the connected VPP
+// including its version, session and loaded plugins.
+func (p *vppProvider) GetInfo(ctx context.Context) (*api.VPPInfo, error) {
+	info, err := dumpInfo(ctx, p.handler)
+	if err != nil {
+		return nil, err
+	}
+	info.Version = string(p.client().BinapiVersion())
+	return info, nil
 }
 
 // GetNodes returns per node statistics.
 func (p *vppProvider) GetNodes(ctx context.Context) ([]api.Node, error) {
+	if data, err := p.batch.fetch(ctx, BatchNodes); err != errNoBatch {
+		if err != nil {
+			return nil, err
+		}
+		return data.Nodes, nil
+	}
 	runtimeInfo, err := p.handler.DumpRuntimeInfo(ctx)
 	if err != nil {
 		return nil, errors.New(err.Error())
@@ -314,9 +571,26 @@ func (p *vppProvider) GetNodes(ctx context.Context) ([]api.Node, error) {
 		return nil, errors.New("no runtime counters")
 	}
 
+	maxClocks, err := p.dumpRuntimeMax(ctx)
+	if err != nil {
+		logrus.Debugf("failed to dump runtime max: %v", err)
+	}
+
 	result := make([]api.Node, 0, len(threads[0].Items))
 	for _, thread := range threads {
+		var total float64
 		for _, item := range thread.Items {
+			total += nodeClocks(item)
+		}
+		for _, item := range thread.Items {
+			item.Thread = thread.ID
+			item.TotalClocks = nodeClocks(item)
+			if total > 0 {
+				item.ClocksPercent = item.TotalClocks / total * 100
+			}
+			if peak, ok := maxClocks[runtimeMaxKey{thread: thread.ID, node: item.Name}]; ok {
+				item.MaxClocks, item.VectorsAtMax = peak.clocks, peak.vectors
+			}
 			result = append(result, item)
 		}
 	}
@@ -326,6 +600,15 @@ func (p *vppProvider) GetNodes(ctx context.Context) ([]api.Node, error) {
 
 // GetInterfaces returns per interface statistics.
 func (p *vppProvider) GetInterfaces(ctx context.Context) ([]api.Interface, error) {
+	if p.statsOnly {
+		return p.statsOnlyInterfaces(ctx)
+	}
+	if data, err := p.batch.fetch(ctx, BatchInterfaces); err != errNoBatch {
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		return data.Interfaces, nil
+	}
 	var ifStats *govppapi.InterfaceStats
 	var ifDetails map[uint32]*api.InterfaceDetails
 
@@ -359,71 +642,569 @@ func (p *vppProvider) GetInterfaces(ctx context.Context) ([]api.Interface, error
 		}
 	}
 
+	workerStats, err := p.dumpWorkerStats()
+	if err != nil {
+		logrus.Warnf("failed to dump interface worker stats: %v", err)
+	}
+
+	peers := p.lldpNeighbors(ctx)
+
+	result := mergeInterfaces(ifStats.Interfaces, ifDetails)
+	rings := p.memifRings(ctx, result)
+	for i := range result {
+		result[i].Workers = workerStats[result[i].InterfaceIndex]
+		if peer, ok := peers[result[i].InterfaceName]; ok {
+			result[i].LLDP = &peer
+		}
+		result[i].MemifRings = rings[result[i].InterfaceName]
+	}
+	if len(p.instances) != 0 {
+		for i := range result {
+			result[i].Instance = p.primaryInstance
+		}
+		result = append(result, p.instanceInterfaces()...)
+	}
+	return result, nil
+}
+
+// mergeInterfaces returns the union of the interface counters and the dumped
+// interface details ordered by the interface index, so the interfaces do not
+// vanish or move while the stats segment and the binary API disagree (e.g.
+// right after an interface is created). Interfaces without counters have all
+// counters zero, interfaces without details have unknown state. Counters
+// of deleted interfaces (without a name) are skipped.
+func mergeInterfaces(counters []govppapi.InterfaceCounters, ifDetails map[uint32]*api.InterfaceDetails) []api.Interface {
 	result := make([]api.Interface, 0, len(ifDetails))
-	for _, iface := range ifStats.Interfaces {
+	counted := make(map[uint32]bool, len(counters))
+	for _, iface := range counters {
 		details, ok := ifDetails[iface.InterfaceIndex]
 		if !ok {
+			if iface.InterfaceName == "" {
+				continue
+			}
+			counted[iface.InterfaceIndex] = true
+			result = append(result, api.Interface{
+				InterfaceCounters: iface,
+				SupSwIfIndex:      iface.InterfaceIndex,
+				MTU:               make([]uint32, 4),
+				NoDetails:         true,
+			})
 			continue
 		}
-		state := stateDown
-		if details.IsEnabled {
-			state = stateUp
+		counted[iface.InterfaceIndex] = true
+		result = append(result, withDetails(iface, details))
+	}
+	for index, details := range ifDetails {
+		if counted[index] {
+			continue
 		}
-		result = append(result, api.Interface{
-			InterfaceCounters: iface,
-			IPAddresses:       details.IPAddresses,
-			State:             state,
-			MTU:               details.MTU,
-		})
+		iface := withDetails(govppapi.InterfaceCounters{
+			InterfaceIndex: index,
+			InterfaceName:  details.Name,
+		}, details)
+		iface.NoStats = true
+		result = append(result, iface)
 	}
-	return result, nil
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].InterfaceIndex < result[j].InterfaceIndex
+	})
+	return result
+}
+
+// withDetails returns the interface with the counters and the dumped details.
+func withDetails(counters govppapi.InterfaceCounters, details *api.InterfaceDetails) api.Interface {
+	state, linkState := stateDown, stateDown
+	if details.IsEnabled {
+		state = stateUp
+	}
+	if details.IsLinkUp {
+		linkState = stateUp
+	}
+	return api.Interface{
+		InterfaceCounters: counters,
+		SupSwIfIndex:      details.SupSwIfIndex,
+		IPAddresses:       details.IPAddresses,
+		State:             state,
+		LinkState:         linkState,
+		MTU:               details.MTU,
+		VrfIPv4:           details.VrfIPv4,
+		VrfIPv6:           details.VrfIPv6,
+		Device:            details.Device,
+	}
+}
+
+// dumpWorkerStats reads interface counters per worker thread directly from
+// the stats segment. The /if counters are indexed by the thread, the queues
+// of an interface served by the same thread are summed up. The result is nil
+// if the stats segment is not accessible directly (i.e. connected via remote
+// proxy).
+func (p *vppProvider) dumpWorkerStats() (map[uint32][]api.WorkerCounters, error) {
+	if p.statsClient == nil {
+		return nil, nil
+	}
+	entries, err := p.statsClient.DumpStats(workerStatsPatterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// workerAt returns counters of the interface at the worker thread,
+	// missing threads are created
+	workers := make(map[uint32][]api.WorkerCounters)
+	workerAt := func(ifIdx uint32, worker int) *api.WorkerCounters {
+		for len(workers[ifIdx]) <= worker {
+			workers[ifIdx] = append(workers[ifIdx], api.WorkerCounters{Worker: len(workers[ifIdx])})
+		}
+		return &workers[ifIdx][worker]
+	}
+
+	for _, entry := range entries {
+		name := string(entry.Name)
+		switch data := entry.Data.(type) {
+		case adapter.SimpleCounterStat:
+			for worker, counters := range data {
+				for ifIdx, counter := range counters {
+					counters := workerAt(uint32(ifIdx), worker)
+					switch name {
+					case statsIfRxNoBuf:
+						counters.RxNoBuf = uint64(counter)
+					case statsIfRxMiss:
+						counters.RxMiss = uint64(counter)
+					case statsIfRxError:
+						counters.RxErrors = uint64(counter)
+					case statsIfTxError:
+						counters.TxErrors = uint64(counter)
+					}
+				}
+			}
+		case adapter.CombinedCounterStat:
+			for worker, counters := range data {
+				for ifIdx, counter := range counters {
+					counters := workerAt(uint32(ifIdx), worker)
+					combined := govppapi.InterfaceCounterCombined{
+						Packets: counter.Packets(),
+						Bytes:   counter.Bytes(),
+					}
+					switch name {
+					case statsIfRx:
+						counters.Rx = combined
+					case statsIfTx:
+						counters.Tx = combined
+					}
+				}
+			}
+		}
+	}
+	return workers, nil
 }
 
 // GetErrors returns per error statistics.
 func (p *vppProvider) GetErrors(ctx context.Context) ([]api.Error, error) {
-	nodeCounters, err := p.handler.DumpNodeCounters(ctx)
-	if err != nil {
+	var nodeCounters *api.NodeCounterInfo
+	data, err := p.batch.fetch(ctx, BatchErrors)
+	switch err {
+	case nil:
+		nodeCounters = data.NodeCounters
+	case errNoBatch:
+		if nodeCounters, err = p.dumpNodeCounters(ctx); err != nil {
+			return nil, err
+		}
+	default:
 		return nil, err
 	}
 	result := make([]api.Error, 0)
 	for _, counter := range nodeCounters.Counters {
-		counter.Count -= p.lastErrorCounters[counter.Node+counter.Reason]
+		last := p.lastErrors.get(counter)
+		counter.Count -= last.Count
 		if counter.Count == 0 {
 			continue
 		}
+		counter.PerThread = perThreadSince(counter.PerThread, last.PerThread)
 		result = append(result, counter)
 	}
 
 	return result, nil
 }
 
-// GetMemory returns memory usage per thread.
-func (p *vppProvider) GetMemory(ctx context.Context) ([]string, error) {
-	mem, err := p.handler.RunCli(ctx, "show memory main-heap verbose")
-	if err != nil {
+// perThreadSince returns the counts per thread added since the baseline.
+func perThreadSince(counts, baseline []uint64) []uint64 {
+	if len(baseline) == 0 {
+		return counts
+	}
+	result := make([]uint64, len(counts))
+	for i, count := range counts {
+		if i < len(baseline) && baseline[i] <= count {
+			count -= baseline[i]
+		}
+		result[i] = count
+	}
+	return result
+}
+
+// dumpNodeCounters retrieves node counters using the handler. If the handler
+// fails (i.e. the CLI output format is not supported), error counters are read
+// directly from the stats segment.
+func (p *vppProvider) dumpNodeCounters(ctx context.Context) (*api.NodeCounterInfo, error) {
+	nodeCounters, err := p.handler.DumpNodeCounters(ctx)
+	if err == nil {
+		return nodeCounters, nil
+	}
+
+	statsConn := p.client().Stats()
+	if statsConn == nil {
 		return nil, err
 	}
+	errorStats := new(govppapi.ErrorStats)
+	if statsErr := statsConn.GetErrorStats(errorStats); statsErr != nil {
+		return nil, fmt.Errorf("%v (stats fallback failed: %v)", err, statsErr)
+	}
 
-	rows := make([]string, 0, 1) // there's going to be at least one thread
-	for _, r := range strings.Split(mem, "\n") {
-		if r == "" {
+	counters := make([]api.NodeCounter, 0, len(errorStats.Errors))
+	for _, errorCounter := range errorStats.Errors {
+		// counter name format is /err/<node>/<reason>
+		name := strings.TrimPrefix(errorCounter.CounterName, "/err/")
+		nameParts := strings.SplitN(name, "/", 2)
+		if len(nameParts) != 2 {
 			continue
 		}
-
-		rows = append(rows, strings.Trim(r, " \n"))
+		var count uint64
+		for _, value := range errorCounter.Values {
+			count += value
+		}
+		counters = append(counters, api.NodeCounter{
+			Count:     count,
+			Node:      nameParts[0],
+			Reason:    nameParts[1],
+			Severity:  severityUnknown,
+			PerThread: append([]uint64(nil), errorCounter.Values...),
+		})
 	}
 
-	return rows, nil
+	return &api.NodeCounterInfo{
+		Counters: counters,
+	}, nil
+}
+
+// RunCli executes the CLI command and returns its raw output.
+func (p *vppProvider) RunCli(ctx context.Context, cmd string) (string, error) {
+	return p.handler.RunCli(ctx, cmd)
 }
 
 // GetThreads returns thread data per thread.
 func (p *vppProvider) GetThreads(ctx context.Context) ([]api.ThreadData, error) {
-	return p.handler.DumpThreads(ctx)
+	if data, err := p.batch.fetch(ctx, BatchThreads); err != errNoBatch {
+		if err != nil {
+			return nil, err
+		}
+		return data.Threads, nil
+	}
+	threads, err := p.handler.DumpThreads(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range threads {
+		threads[i].Utilization = -1
+	}
+	// the host of a remote VPP is not accessible
+	if p.statsClient != nil {
+		for i := range threads {
+			if err := readThreadHostData(&threads[i]); err != nil {
+				logrus.Debugf("failed to read host data of thread %d: %v", threads[i].PID, err)
+			}
+		}
+	}
+
+	rxInterfaces, err := p.dumpThreadRxInterfaces(ctx)
+	if err != nil {
+		logrus.Warnf("failed to dump interface rx placement: %v", err)
+	}
+	for i := range threads {
+		threads[i].RxInterfaces = rxInterfaces[threads[i].ID]
+	}
+
+	cpuFreq := p.cpuFrequency(ctx)
+	if cpuFreq < 0 {
+		return threads, nil
+	}
+	runtimeInfo, err := p.handler.DumpRuntimeInfo(ctx)
+	if err != nil {
+		logrus.Warnf("failed to dump runtime info: %v", err)
+		return threads, nil
+	}
+
+	utilization := p.threadUtilization(runtimeInfo, cpuFreq)
+	for i, thread := range threads {
+		if value, ok := utilization[uint(thread.ID)]; ok {
+			threads[i].Utilization = value
+		}
+	}
+	return threads, nil
+}
+
+// cpuFrequency returns the CPU frequency in Hz, dumped on the first call.
+// The frequency is negative if it is not available.
+func (p *vppProvider) cpuFrequency(ctx context.Context) float64 {
+	p.threadMu.Lock()
+	defer p.threadMu.Unlock()
+	if p.cpuFreq == 0 {
+		var err error
+		if p.cpuFreq, err = p.dumpCPUFrequency(ctx); err != nil {
+			// do not retry, the utilization is not available
+			logrus.Warnf("failed to dump CPU frequency: %v", err)
+			p.cpuFreq = -1
+		}
+	}
+	return p.cpuFreq
 }
 
-// ClearInterfaceCounters resets the counters for the interface.
+// threadUtilization returns the utilization of the threads in percent by
+// the thread ID, estimated from the busy clocks since the last poll.
+func (p *vppProvider) threadUtilization(runtimeInfo *api.RuntimeInfo, cpuFreq float64) map[uint]float64 {
+	p.threadMu.Lock()
+	defer p.threadMu.Unlock()
+
+	utilization := make(map[uint]float64, len(runtimeInfo.Threads))
+	lastThreadClocks := make(map[uint]threadClocks, len(runtimeInfo.Threads))
+	for _, thread := range runtimeInfo.Threads {
+		curr := threadClocks{time: thread.Time}
+		for _, item := range thread.Items {
+			curr.busy += busyClocks(item)
+		}
+		lastThreadClocks[thread.ID] = curr
+
+		// use the difference to the last poll, or the values since the last clear
+		// if there is no last poll or the runtime counters were cleared
+		busy, elapsed := curr.busy, curr.time
+		if last, ok := p.lastThreadClocks[thread.ID]; ok && curr.busy >= last.busy && curr.time > last.time {
+			busy, elapsed = curr.busy-last.busy, curr.time-last.time
+		}
+		if elapsed <= 0 {
+			continue
+		}
+		utilization[thread.ID] = math.Min(100, busy/(elapsed*cpuFreq)*100)
+	}
+	p.lastThreadClocks = lastThreadClocks
+	return utilization
+}
+
+// threadClocks are the busy clocks of a thread
+// and the runtime in seconds they were measured in.
+type threadClocks struct {
+	busy float64
+	time float64
+}
+
+// 'show cpu' base frequency, e.g. "Base frequency:      2.59 GHz"
+var cpuFreqRe = regexp.MustCompile(`(?i)base frequency:\s*([\d.]+)\s*GHz`)
+
+// dumpCPUFrequency returns the CPU base frequency in Hz reported by VPP.
+func (p *vppProvider) dumpCPUFrequency(ctx context.Context) (float64, error) {
+	out, err := p.handler.RunCli(ctx, "show cpu")
+	if err != nil {
+		return 0, err
+	}
+	matches := cpuFreqRe.FindStringSubmatch(out)
+	if matches == nil {
+		return 0, fmt.Errorf("base frequency not found in %q", out)
+	}
+	freq, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil || freq <= 0 {
+		return 0, fmt.Errorf("invalid base frequency %q", matches[1])
+	}
+	return freq * 1e9, nil
+}
+
+// GetDropsPunts returns drop counters broken down by the node and reason
+// together with punt counters per punt reason.
+func (p *vppProvider) GetDropsPunts(ctx context.Context) ([]api.DropPunt, error) {
+	var nodeCounters *api.NodeCounterInfo
+	var puntStats []api.PuntStat
+	data, err := p.batch.fetch(ctx, BatchDropsPunts)
+	switch err {
+	case nil:
+		nodeCounters, puntStats = data.NodeCounters, data.Punts
+	case errNoBatch:
+		if nodeCounters, err = p.dumpNodeCounters(ctx); err != nil {
+			return nil, err
+		}
+		// the drops are shown even if the punts are not available
+		if puntStats, err = p.handler.DumpPuntStats(ctx); err != nil {
+			logrus.Warnf("failed to dump punt stats: %v", err)
+		}
+	default:
+		return nil, err
+	}
+
+	result := make([]api.DropPunt, 0)
+	for _, counter := range nodeCounters.Counters {
+		if !isDropCounter(counter) {
+			continue
+		}
+		count := counter.Count - p.lastErrors.get(counter).Count
+		if count == 0 {
+			continue
+		}
+		result = append(result, api.DropPunt{
+			Type:   typeDrop,
+			Node:   counter.Node,
+			Reason: counter.Reason,
+			Count:  count,
+		})
+	}
+	for _, punt := range puntStats {
+		counter := puntCounter(punt)
+		count := counter.Count - p.lastPunts.get(counter).Count
+		if count == 0 {
+			continue
+		}
+		result = append(result, api.DropPunt{
+			Type:   typePunt,
+			Node:   typePunt,
+			Reason: punt.Reason,
+			Count:  count,
+		})
+	}
+
+	return result, nil
+}
+
+// GetTunnels returns vxlan, gtpu and geneve tunnels joined with
+// counters of their interfaces.
+func (p *vppProvider) GetTunnels(ctx context.Context) ([]api.TunnelCounters, error) {
+	if data, err := p.batch.fetch(ctx, BatchTunnels); err != errNoBatch {
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		return data.Tunnels, nil
+	}
+	tunnels, err := p.handler.DumpTunnels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	ifStats, err := p.handler.DumpInterfaceStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+
+	counters := make(map[uint32]govppapi.InterfaceCounters, len(ifStats.Interfaces))
+	for _, iface := range ifStats.Interfaces {
+		counters[iface.InterfaceIndex] = iface
+	}
+
+	result := make([]api.TunnelCounters, 0, len(tunnels))
+	for _, tunnel := range tunnels {
+		iface := counters[tunnel.SwIfIndex]
+		result = append(result, api.TunnelCounters{
+			Tunnel:        tunnel,
+			InterfaceName: iface.InterfaceName,
+			Rx:            iface.Rx,
+			Tx:            iface.Tx,
+			Drops:         iface.Drops,
+		})
+	}
+	return result, nil
+}
+
+// GetPolicers returns policers with their counters sorted by name.
+func (p *vppProvider) GetPolicers(ctx context.Context) ([]api.Policer, error) {
+	policers, err := p.handler.DumpPolicers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	sort.Slice(policers, func(i, j int) bool { return policers[i].Name < policers[j].Name })
+	return policers, nil
+}
+
+// GetSRv6 returns the SRv6 policies followed by the local SIDs, both sorted
+// by the SID. No SIDs are returned if the VPP has no SRv6 support.
+func (p *vppProvider) GetSRv6(ctx context.Context) ([]api.SRv6SID, error) {
+	sids, err := p.handler.DumpSRv6(ctx)
+	if err == api.ErrNotSupported {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	sort.Slice(sids, func(i, j int) bool {
+		if sids[i].Policy != sids[j].Policy {
+			return sids[i].Policy
+		}
+		return sids[i].SID < sids[j].SID
+	})
+	return sids, nil
+}
+
+// GetMpls returns the MPLS tunnels followed by the MPLS FIB entries sorted by
+// the table and the label. The counters of a tunnel are the transmit counters
+// of its interface. No entries are returned if the VPP has no MPLS support.
+func (p *vppProvider) GetMpls(ctx context.Context) ([]api.MplsEntry, error) {
+	entries, err := p.handler.DumpMpls(ctx)
+	if err == api.ErrNotSupported {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	var tunnels bool
+	for _, entry := range entries {
+		tunnels = tunnels || entry.Tunnel != ""
+	}
+	if tunnels {
+		ifStats, err := p.handler.DumpInterfaceStats(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %v", err)
+		}
+		tx := make(map[uint32]govppapi.InterfaceCounterCombined, len(ifStats.Interfaces))
+		for _, iface := range ifStats.Interfaces {
+			tx[iface.InterfaceIndex] = iface.Tx
+		}
+		for i := range entries {
+			if entries[i].Tunnel != "" {
+				entries[i].Counter = tx[entries[i].SwIfIndex]
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.Tunnel != "") != (b.Tunnel != "") {
+			return a.Tunnel != ""
+		}
+		if a.Tunnel != b.Tunnel {
+			return a.Tunnel < b.Tunnel
+		}
+		if a.TableID != b.TableID {
+			return a.TableID < b.TableID
+		}
+		if a.Label != b.Label {
+			return a.Label < b.Label
+		}
+		return a.EOS && !b.EOS
+	})
+	return entries, nil
+}
+
+// isDropCounter returns true if the node counter represents dropped packets,
+// i.e. the counter of a drop node or a counter of the error severity. Without
+// the severity the error counters can not be told apart from the info ones,
+// only the counters of drop nodes and the reasons naming drops are drops then
+// (e.g. "Tx packet drops"), other errors dropping packets are not listed.
+func isDropCounter(counter api.NodeCounter) bool {
+	switch {
+	case strings.Contains(counter.Node, typeDrop):
+		return true
+	case counter.Severity == severityUnknown:
+		return strings.Contains(strings.ToLower(counter.Reason), typeDrop)
+	}
+	return counter.Severity == "error"
+}
+
+// ClearInterfaceCounters resets the counters for the interface. The binary
+// API is used if supported by the handler, the CLI otherwise.
 func (p *vppProvider) ClearInterfaceCounters(ctx context.Context) error {
-	if _, err := p.handler.RunCli(ctx, "clear interfaces"); err != nil {
+	err := p.handler.ClearInterfaceCounters(ctx)
+	if err == api.ErrNotSupported {
+		_, err = p.handler.RunCli(ctx, "clear interfaces")
+	}
+	if err != nil {
 		return fmt.Errorf("request failed: %v", err)
 	}
 
@@ -439,9 +1220,17 @@ func (p *vppProvider) ClearRuntimeCounters(ctx context.Context) error {
 	return nil
 }
 
-// ClearErrorCounters clears the counters for errors.
-func (p *vppProvider) ClearErrorCounters(ctx context.Context) error {
-	p.updateLastErrors(ctx)
+// ClearErrorCounters clears the counters for errors. If match is set, only
+// matching counters are cleared, keeping the counts of the others.
+func (p *vppProvider) ClearErrorCounters(ctx context.Context, match func(api.Error) bool) error {
+	p.updateLastErrors(ctx, match)
+	if match != nil {
+		// the CLI clears all errors, the matching
+		// counters are cleared by the baseline only
+		return nil
+	}
+	// the punts shown with the drops have no clear CLI
+	p.updateLastPunts(ctx)
 	if _, err := p.handler.RunCli(ctx, "clear errors"); err != nil {
 		return fmt.Errorf("request failed: %v", err)
 	}
@@ -449,9 +1238,10 @@ func (p *vppProvider) ClearErrorCounters(ctx context.Context) error {
 	return nil
 }
 
-// updateLastErrors clears the error counters.
-func (p *vppProvider) updateLastErrors(ctx context.Context) {
-	nodeCounters, err := p.handler.DumpNodeCounters(ctx)
+// updateLastErrors clears the error counters by setting their baseline
+// per node and reason, either of all counters or only the matching ones.
+func (p *vppProvider) updateLastErrors(ctx context.Context, match func(api.Error) bool) {
+	nodeCounters, err := p.dumpNodeCounters(ctx)
 	if err != nil {
 		return
 	}
@@ -460,6 +1250,64 @@ func (p *vppProvider) updateLastErrors(ctx context.Context) {
 		if counter.Count == 0 {
 			continue
 		}
-		p.lastErrorCounters[counter.Node+counter.Reason] = counter.Count
+		if match != nil && !match(counter) {
+			continue
+		}
+		p.lastErrors.set(counter)
+	}
+}
+
+// updateLastPunts clears the punt counters by setting their baseline
+// per punt reason.
+func (p *vppProvider) updateLastPunts(ctx context.Context) {
+	puntStats, err := p.handler.DumpPuntStats(ctx)
+	if err != nil {
+		logrus.Warnf("failed to dump punt stats: %v", err)
+		return
+	}
+
+	for _, punt := range puntStats {
+		if punt.Packets == 0 {
+			continue
+		}
+		p.lastPunts.set(puntCounter(punt))
+	}
+}
+
+// puntCounter returns the punt counter keyed by the punt node
+// and its reason, as the drops are.
+func puntCounter(punt api.PuntStat) api.Error {
+	return api.Error{Node: typePunt, Reason: punt.Reason, Count: punt.Packets}
+}
+
+// errorBaseline is the baseline of the cleared error counters by the node
+// and reason, or of the punt counters by their reason. It is set by the
+// clears while the polls of the errors and the drops/punts tabs read it.
+type errorBaseline struct {
+	sync.RWMutex
+	counters map[string]api.Error
+}
+
+// reset drops the baseline of all counters.
+func (b *errorBaseline) reset() {
+	b.Lock()
+	defer b.Unlock()
+	b.counters = make(map[string]api.Error)
+}
+
+// get returns the baseline of the counter, zero if it was not cleared.
+func (b *errorBaseline) get(counter api.Error) api.Error {
+	b.RLock()
+	defer b.RUnlock()
+	return b.counters[counter.Node+counter.Reason]
+}
+
+// set sets the baseline of the counter to its current count.
+func (b *errorBaseline) set(counter api.Error) {
+	b.Lock()
+	defer b.Unlock()
+	if b.counters == nil {
+		b.counters = make(map[string]api.Error)
 	}
+	b.counters[counter.Node+counter.Reason] = counter
 }
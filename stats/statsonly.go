@@ -0,0 +1,210 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	"git.fd.io/govpp.git/adapter"
+	govppapi "git.fd.io/govpp.git/api"
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/local/vppcalls"
+)
+
+// statsOnlyVersion is the version reported in the stats-only mode, where
+// the version of the VPP is not known.
+const statsOnlyVersion = "-"
+
+// statsOnlyHandler reads the interface, node and error counters directly from
+// the stats segment in the stats-only mode. The binary API and the CLI are not
+// used, all other requests fail with the api.ErrNotSupported.
+type statsOnlyHandler struct {
+	segment vppcalls.StatsSegmentVppAPI
+}
+
+// newStatsOnlyHandler returns the handler reading the stats segment of the stats API.
+func newStatsOnlyHandler(statsAPI adapter.StatsAPI) *statsOnlyHandler {
+	return &statsOnlyHandler{segment: vppcalls.NewStatsSegmentHandler(statsAPI)}
+}
+
+func (h *statsOnlyHandler) RunCli(context.Context, string) (string, error) {
+	return "", api.ErrNotSupported
+}
+
+// Ping succeeds, there is no binary API connection to check.
+func (h *statsOnlyHandler) Ping(context.Context) error {
+	return nil
+}
+
+func (h *statsOnlyHandler) DumpInterfaces(context.Context) (map[uint32]*api.InterfaceDetails, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpInterfaceStats(ctx context.Context) (*govppapi.InterfaceStats, error) {
+	return h.segment.GetInterfaceStats(ctx)
+}
+
+func (h *statsOnlyHandler) DumpNodeCounters(ctx context.Context) (*api.NodeCounterInfo, error) {
+	return h.segment.GetNodeCounters(ctx)
+}
+
+func (h *statsOnlyHandler) DumpRuntimeInfo(ctx context.Context) (*api.RuntimeInfo, error) {
+	return h.segment.GetRuntimeInfo(ctx)
+}
+
+func (h *statsOnlyHandler) DumpPlugins(context.Context) ([]api.PluginInfo, error) {
+	return nil, nil
+}
+
+func (h *statsOnlyHandler) DumpVersion(context.Context) (*api.VersionInfo, error) {
+	return &api.VersionInfo{
+		Version:   statsOnlyVersion,
+		BuildDate: "stats segment only",
+	}, nil
+}
+
+func (h *statsOnlyHandler) DumpSession(context.Context) (*api.SessionInfo, error) {
+	return new(api.SessionInfo), nil
+}
+
+func (h *statsOnlyHandler) DumpThreads(context.Context) ([]api.ThreadData, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpPuntStats(context.Context) ([]api.PuntStat, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpTunnels(context.Context) ([]api.Tunnel, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpPolicers(context.Context) ([]api.Policer, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpFibTables(context.Context) ([]api.FibTable, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpRxPlacement(context.Context) ([]api.RxPlacement, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpNeighbors(context.Context) ([]api.Neighbor, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) WatchNeighbors(context.Context, func()) error {
+	return api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpLLDPNeighbors(context.Context) ([]api.LLDPNeighbor, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpMemifRings(context.Context) ([]api.MemifRing, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpAPITraceStatus(context.Context) (*api.APITrace, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpAPITrace(context.Context) ([]api.APITraceMessage, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) SetAPITrace(context.Context, bool) error {
+	return api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) SaveAPITrace(context.Context, string) error {
+	return api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) FreeAPITrace(context.Context) error {
+	return api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpSRv6(context.Context) ([]api.SRv6SID, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) DumpMpls(context.Context) ([]api.MplsEntry, error) {
+	return nil, api.ErrNotSupported
+}
+
+func (h *statsOnlyHandler) ClearInterfaceCounters(context.Context) error {
+	return api.ErrNotSupported
+}
+
+// Capabilities of the stats-only handler, only the counters
+// of the stats segment are available.
+func (h *statsOnlyHandler) Capabilities() api.Capabilities {
+	return api.Capabilities{WorkerStats: true}
+}
+
+func (h *statsOnlyHandler) Close() {}
+
+// segmentInterfaces returns the interfaces of the counters read from the stats
+// segment. The segment provides only the counters, the state and details of
+// the interfaces are unknown.
+func segmentInterfaces(ifStats *govppapi.InterfaceStats, instance string) []api.Interface {
+	result := make([]api.Interface, 0, len(ifStats.Interfaces))
+	for _, iface := range ifStats.Interfaces {
+		result = append(result, api.Interface{
+			InterfaceCounters: iface,
+			SupSwIfIndex:      iface.InterfaceIndex,
+			MTU:               make([]uint32, 4),
+			Instance:          instance,
+		})
+	}
+	return result
+}
+
+// statsOnlyInterfaces returns the interfaces read from the stats segment in
+// the stats-only mode, indexes without a name (deleted interfaces) are skipped.
+func (p *vppProvider) statsOnlyInterfaces(ctx context.Context) ([]api.Interface, error) {
+	ifStats, err := p.handler.DumpInterfaceStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	named := ifStats.Interfaces[:0]
+	for _, iface := range ifStats.Interfaces {
+		if iface.InterfaceName != "" {
+			named = append(named, iface)
+		}
+	}
+	ifStats.Interfaces = named
+
+	var instance string
+	if len(p.instances) != 0 {
+		instance = p.primaryInstance
+	}
+	result := segmentInterfaces(ifStats, instance)
+	workerStats, err := p.dumpWorkerStats()
+	if err != nil {
+		logrus.Warnf("failed to dump interface worker stats: %v", err)
+	}
+	for i := range result {
+		result[i].Workers = workerStats[result[i].InterfaceIndex]
+	}
+	return append(result, p.instanceInterfaces()...), nil
+}
@@ -0,0 +1,366 @@
+/*
+ * Copyright (c) 2019 PANTHEON.tech.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stats
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	govppapi "git.fd.io/govpp.git/api"
+	"github.com/sirupsen/logrus"
+	"go.pantheon.tech/vpptop/stats/api"
+)
+
+// DefaultRequestTimeout is the default timeout of a single handler request.
+const DefaultRequestTimeout = 5 * time.Second
+
+// slowRequest is the duration of handler requests logged at the info level.
+const slowRequest = 250 * time.Millisecond
+
+// WithRequestTimeout sets the timeout of a single handler request
+// (no timeout if zero).
+func WithRequestTimeout(timeout time.Duration) ProviderOption {
+	return func(p *vppProvider) {
+		p.requestTimeout = timeout
+	}
+}
+
+// requestTiming are the durations of the requests of the same kind.
+type requestTiming struct {
+	api.RequestStats
+	// total duration of the requests the average is computed from
+	total time.Duration
+}
+
+// requestTimings are the durations of the requests made by all handlers of
+// the provider, they are kept across the reconnections to the proxy.
+type requestTimings struct {
+	sync.Mutex
+	requests map[string]*requestTiming
+}
+
+// newRequestTimings returns an empty instance of <*requestTimings>
+func newRequestTimings() *requestTimings {
+	return &requestTimings{
+		requests: make(map[string]*requestTiming),
+	}
+}
+
+// record adds the duration and the outcome of the request.
+func (t *requestTimings) record(request string, duration time.Duration, err error) {
+	t.Lock()
+	defer t.Unlock()
+	timing, ok := t.requests[request]
+	if !ok {
+		timing = &requestTiming{RequestStats: api.RequestStats{Request: request}}
+		t.requests[request] = timing
+	}
+	if err != nil {
+		timing.Errors++
+	}
+	timing.Calls++
+	timing.Last = duration
+	timing.total += duration
+	timing.Avg = timing.total / time.Duration(timing.Calls)
+	if duration > timing.Max {
+		timing.Max = duration
+	}
+}
+
+// snapshot returns the durations of all requests ordered by the request name.
+func (t *requestTimings) snapshot() []api.RequestStats {
+	t.Lock()
+	defer t.Unlock()
+	stats := make([]api.RequestStats, 0, len(t.requests))
+	for _, timing := range t.requests {
+		stats = append(stats, timing.RequestStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Request < stats[j].Request
+	})
+	return stats
+}
+
+// GetRequestStats returns the durations of the handler requests
+// made since the provider was created.
+func (p *vppProvider) GetRequestStats() []api.RequestStats {
+	return p.requests.snapshot()
+}
+
+// timedHandler wraps the VPP handler, records the duration of every request,
+// logs it at the debug level (at the info level if the request is slow)
+// and cancels the requests taking longer than the timeout. The failures
+// and CLI outputs are passed to the diagnostics.
+type timedHandler struct {
+	mu      sync.RWMutex
+	handler api.HandlerAPI
+	timeout time.Duration
+	timings *requestTimings
+	diag    *diagnostics
+}
+
+// newTimedHandler returns the handler wrapped with request logging and timeouts,
+// the durations of the requests are recorded to the timings.
+func newTimedHandler(handler api.HandlerAPI, timeout time.Duration, timings *requestTimings, diag *diagnostics) *timedHandler {
+	return &timedHandler{handler: handler, timeout: timeout, timings: timings, diag: diag}
+}
+
+// current returns the wrapped handler.
+func (h *timedHandler) current() api.HandlerAPI {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.handler
+}
+
+// swap replaces the wrapped handler (e.g. after the reconnection
+// to the proxy) and returns the previous one.
+func (h *timedHandler) swap(handler api.HandlerAPI) api.HandlerAPI {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	old := h.handler
+	h.handler = handler
+	return old
+}
+
+// withTimeout returns the context of a single request.
+func (h *timedHandler) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, h.timeout)
+}
+
+// logRequest records and logs the request name, its duration and the error if any.
+func (h *timedHandler) logRequest(request string, start time.Time, err error) {
+	duration := time.Since(start)
+	h.timings.record(request, duration, err)
+	h.diag.record(request, err)
+	entry := logrus.WithFields(logrus.Fields{
+		"request":  request,
+		"duration": duration,
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	if duration >= slowRequest {
+		entry.Infof("%s took %v", request, duration.Round(time.Millisecond))
+		return
+	}
+	entry.Debug("handler request")
+}
+
+func (h *timedHandler) RunCli(ctx context.Context, cmd string) (reply string, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) {
+		h.logRequest("RunCli("+cmd+")", start, err)
+		if err == nil {
+			h.diag.recordCli(cmd, reply)
+		}
+	}(time.Now())
+	return h.current().RunCli(ctx, cmd)
+}
+
+func (h *timedHandler) Ping(ctx context.Context) error {
+	// pings are timed by the health probe
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	return h.current().Ping(ctx)
+}
+
+func (h *timedHandler) DumpInterfaces(ctx context.Context) (ifaces map[uint32]*api.InterfaceDetails, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpInterfaces", start, err) }(time.Now())
+	return h.current().DumpInterfaces(ctx)
+}
+
+func (h *timedHandler) DumpInterfaceStats(ctx context.Context) (stats *govppapi.InterfaceStats, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpInterfaceStats", start, err) }(time.Now())
+	return h.current().DumpInterfaceStats(ctx)
+}
+
+func (h *timedHandler) DumpNodeCounters(ctx context.Context) (counters *api.NodeCounterInfo, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpNodeCounters", start, err) }(time.Now())
+	return h.current().DumpNodeCounters(ctx)
+}
+
+func (h *timedHandler) DumpRuntimeInfo(ctx context.Context) (info *api.RuntimeInfo, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpRuntimeInfo", start, err) }(time.Now())
+	return h.current().DumpRuntimeInfo(ctx)
+}
+
+func (h *timedHandler) DumpPlugins(ctx context.Context) (plugins []api.PluginInfo, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpPlugins", start, err) }(time.Now())
+	return h.current().DumpPlugins(ctx)
+}
+
+func (h *timedHandler) DumpVersion(ctx context.Context) (version *api.VersionInfo, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpVersion", start, err) }(time.Now())
+	return h.current().DumpVersion(ctx)
+}
+
+func (h *timedHandler) DumpSession(ctx context.Context) (session *api.SessionInfo, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpSession", start, err) }(time.Now())
+	return h.current().DumpSession(ctx)
+}
+
+func (h *timedHandler) DumpThreads(ctx context.Context) (threads []api.ThreadData, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpThreads", start, err) }(time.Now())
+	return h.current().DumpThreads(ctx)
+}
+
+func (h *timedHandler) DumpPuntStats(ctx context.Context) (stats []api.PuntStat, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpPuntStats", start, err) }(time.Now())
+	return h.current().DumpPuntStats(ctx)
+}
+
+func (h *timedHandler) DumpTunnels(ctx context.Context) (tunnels []api.Tunnel, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpTunnels", start, err) }(time.Now())
+	return h.current().DumpTunnels(ctx)
+}
+
+func (h *timedHandler) DumpLLDPNeighbors(ctx context.Context) (neighbors []api.LLDPNeighbor, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpLLDPNeighbors", start, err) }(time.Now())
+	return h.current().DumpLLDPNeighbors(ctx)
+}
+
+func (h *timedHandler) DumpAPITraceStatus(ctx context.Context) (trace *api.APITrace, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpAPITraceStatus", start, err) }(time.Now())
+	return h.current().DumpAPITraceStatus(ctx)
+}
+
+func (h *timedHandler) DumpAPITrace(ctx context.Context) (messages []api.APITraceMessage, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpAPITrace", start, err) }(time.Now())
+	return h.current().DumpAPITrace(ctx)
+}
+
+func (h *timedHandler) SetAPITrace(ctx context.Context, enable bool) (err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("SetAPITrace", start, err) }(time.Now())
+	return h.current().SetAPITrace(ctx, enable)
+}
+
+func (h *timedHandler) SaveAPITrace(ctx context.Context, file string) (err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("SaveAPITrace", start, err) }(time.Now())
+	return h.current().SaveAPITrace(ctx, file)
+}
+
+func (h *timedHandler) FreeAPITrace(ctx context.Context) (err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("FreeAPITrace", start, err) }(time.Now())
+	return h.current().FreeAPITrace(ctx)
+}
+
+func (h *timedHandler) DumpMemifRings(ctx context.Context) (rings []api.MemifRing, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpMemifRings", start, err) }(time.Now())
+	return h.current().DumpMemifRings(ctx)
+}
+
+func (h *timedHandler) DumpSRv6(ctx context.Context) (sids []api.SRv6SID, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpSRv6", start, err) }(time.Now())
+	return h.current().DumpSRv6(ctx)
+}
+
+func (h *timedHandler) DumpMpls(ctx context.Context) (entries []api.MplsEntry, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpMpls", start, err) }(time.Now())
+	return h.current().DumpMpls(ctx)
+}
+
+func (h *timedHandler) ClearInterfaceCounters(ctx context.Context) (err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("ClearInterfaceCounters", start, err) }(time.Now())
+	return h.current().ClearInterfaceCounters(ctx)
+}
+
+func (h *timedHandler) DumpPolicers(ctx context.Context) (policers []api.Policer, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpPolicers", start, err) }(time.Now())
+	return h.current().DumpPolicers(ctx)
+}
+
+func (h *timedHandler) DumpFibTables(ctx context.Context) (tables []api.FibTable, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpFibTables", start, err) }(time.Now())
+	return h.current().DumpFibTables(ctx)
+}
+
+func (h *timedHandler) DumpRxPlacement(ctx context.Context) (placement []api.RxPlacement, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpRxPlacement", start, err) }(time.Now())
+	return h.current().DumpRxPlacement(ctx)
+}
+
+func (h *timedHandler) DumpNeighbors(ctx context.Context) (neighbors []api.Neighbor, err error) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	defer func(start time.Time) { h.logRequest("DumpNeighbors", start, err) }(time.Now())
+	return h.current().DumpNeighbors(ctx)
+}
+
+func (h *timedHandler) WatchNeighbors(ctx context.Context, onChange func()) (err error) {
+	defer func(start time.Time) { h.logRequest("WatchNeighbors", start, err) }(time.Now())
+	return h.current().WatchNeighbors(ctx, onChange)
+}
+
+func (h *timedHandler) Capabilities() api.Capabilities {
+	return h.current().Capabilities()
+}
+
+func (h *timedHandler) Close() {
+	h.current().Close()
+}
@@ -19,9 +19,13 @@ package vpp
 import (
 	"context"
 	"encoding/gob"
+	"regexp"
+	"strconv"
+	"strings"
 
 	govppapi "git.fd.io/govpp.git/api"
 	"go.pantheon.tech/vpptop/stats/api"
+	"go.pantheon.tech/vpptop/stats/local/vppcalls"
 	"go.ligato.io/cn-infra/v2/logging/logrus"
 	"go.ligato.io/vpp-agent/v3/plugins/vpp"
 	"go.ligato.io/vpp-agent/v3/plugins/vpp/binapi"
@@ -41,10 +45,23 @@ import (
 	_ "go.ligato.io/vpp-agent/v3/plugins/telemetry/vppcalls/vpp2202"
 )
 
+// 'show policer' output lines
+var (
+	policerNameRe     = regexp.MustCompile(`^\s*Name\s+"([^"]*)"\s*(.*)$`)
+	policerCountersRe = regexp.MustCompile(`^\s*(conform|exceed|violate)\s+(\d+)\s+packets,\s+(\d+)\s+bytes`)
+)
+
 // HandlerDef is a VPP handler definition. It is used to validate
 // compatibility with the version of the connected VPP
 type HandlerDef struct{}
 
+// HandlerName is the name of the VPP-Agent based handler
+const HandlerName = "agent"
+
+func (d *HandlerDef) Name() string {
+	return HandlerName
+}
+
 func (d *HandlerDef) IsHandlerCompatible(c *api.VppClient, isRemote bool) (api.HandlerAPI, string, error) {
 	ch, err := c.NewAPIChannel()
 	if err != nil {
@@ -52,7 +69,10 @@ func (d *HandlerDef) IsHandlerCompatible(c *api.VppClient, isRemote bool) (api.H
 	}
 	binapiVersion, err := binapi.CompatibleVersion(ch)
 	if err == nil {
-		return NewVPPHandler(c, ch, string(binapiVersion), isRemote), string(binapiVersion), nil
+		h := NewVPPHandler(c, ch, string(binapiVersion), isRemote)
+		h.ifCounters = c.InterfaceCounterSource()
+		h.statsSegment = c.StatsAPI() != nil
+		return h, string(binapiVersion), nil
 	}
 	return nil, "", nil
 }
@@ -63,9 +83,16 @@ type Handler struct {
 	vppCoreCalls      govppcalls.VppCoreAPI
 	interfaceVppCalls ifplugincalls.InterfaceVppAPI
 	telemetryVppCalls telemetrycalls.TelemetryVppAPI
+	// geneve tunnels are not modelled by the agent, they are parsed
+	// from the CLI by the local handler
+	geneveVppCalls vppcalls.TunnelVppAPI
 
 	apiChan       govppapi.Channel
 	binapiVersion string
+	// interface counters are parsed from the CLI if not read from the stats segment
+	ifCounters api.InterfaceCounterSource
+	// set if the stats segment is accessed directly
+	statsSegment bool
 }
 
 // NewVPPHandler creates a new instance of the VPP Handler
@@ -75,11 +102,15 @@ func NewVPPHandler(c vpp.Client, ch govppapi.Channel, binapiVersion string, isRe
 		for _, msg := range msgList.AllMessages() {
 			gob.Register(msg)
 		}
+		for _, msg := range vppcalls.TunnelMessages() {
+			gob.Register(msg)
+		}
 	}
 	return &Handler{
 		vppCoreCalls:      govppcalls.CompatibleHandler(c),
 		interfaceVppCalls: ifplugincalls.CompatibleInterfaceVppHandler(c, logrus.NewLogger("")),
 		telemetryVppCalls: telemetrycalls.CompatibleTelemetryHandler(c),
+		geneveVppCalls:    vppcalls.NewTunnelHandler(ch, vppcalls.TunnelGeneve),
 		binapiVersion:     binapiVersion,
 		apiChan:           ch,
 	}
@@ -89,6 +120,10 @@ func (h *Handler) RunCli(ctx context.Context, cmd string) (string, error) {
 	return h.vppCoreCalls.RunCli(ctx, cmd)
 }
 
+func (h *Handler) Ping(ctx context.Context) error {
+	return h.vppCoreCalls.Ping(ctx)
+}
+
 func (h *Handler) DumpInterfaces(ctx context.Context) (map[uint32]*api.InterfaceDetails, error) {
 	interfaceDetails := make(map[uint32]*api.InterfaceDetails)
 	interfaceMap, err := h.interfaceVppCalls.DumpInterfaces(ctx)
@@ -100,15 +135,32 @@ func (h *Handler) DumpInterfaces(ctx context.Context) (map[uint32]*api.Interface
 			Name:         ifData.Interface.Name,
 			InternalName: ifData.Meta.InternalName,
 			SwIfIndex:    swIfIdx,
+			SupSwIfIndex: ifData.Meta.SupSwIfIndex,
 			IsEnabled:    ifData.Interface.Enabled,
+			IsLinkUp:     ifData.Meta.IsLinkStateUp,
 			IPAddresses:  ifData.Interface.IpAddresses,
 			MTU:          ifData.Meta.MTU,
+			VrfIPv4:      ifData.Meta.VrfIPv4,
+			VrfIPv6:      ifData.Meta.VrfIPv6,
+			Device: api.DeviceDetails{
+				MAC:        ifData.Interface.PhysAddress,
+				Type:       strings.ToLower(ifData.Interface.Type.String()),
+				LinkSpeed:  uint64(ifData.Meta.LinkSpeed),
+				LinkDuplex: api.LinkDuplexName(uint32(ifData.Meta.LinkDuplex)),
+			},
 		}
 	}
 	return interfaceDetails, nil
 }
 
 func (h *Handler) DumpInterfaceStats(ctx context.Context) (*govppapi.InterfaceStats, error) {
+	if h.ifCounters == api.CountersFromCLI {
+		out, err := h.RunCli(ctx, "show interface")
+		if err != nil {
+			return nil, err
+		}
+		return api.ParseInterfaceCounters(out), nil
+	}
 	return h.telemetryVppCalls.GetInterfaceStats(ctx)
 }
 
@@ -232,6 +284,232 @@ func (h *Handler) DumpThreads(ctx context.Context) ([]api.ThreadData, error) {
 	return result, nil
 }
 
+func (h *Handler) DumpPuntStats(ctx context.Context) ([]api.PuntStat, error) {
+	out, err := h.vppCoreCalls.RunCli(ctx, "show punt stats")
+	if err != nil {
+		return nil, err
+	}
+	return api.ParsePuntStats(out)
+}
+
+// DumpTunnels returns vxlan and gtpu tunnels dumped by the interface plugin
+// together with geneve tunnels, which are not supported by the agent and are
+// parsed from the CLI (none if the geneve plugin is not loaded).
+func (h *Handler) DumpTunnels(ctx context.Context) ([]api.Tunnel, error) {
+	interfaceMap, err := h.interfaceVppCalls.DumpInterfaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var tunnels []api.Tunnel
+	for swIfIdx, ifData := range interfaceMap {
+		if vxlan := ifData.Interface.GetVxlan(); vxlan != nil {
+			tunnels = append(tunnels, api.Tunnel{
+				Type:      "vxlan",
+				SwIfIndex: swIfIdx,
+				Src:       vxlan.SrcAddress,
+				Dst:       vxlan.DstAddress,
+				ID:        vxlan.Vni,
+			})
+		}
+		if gtpu := ifData.Interface.GetGtpu(); gtpu != nil {
+			tunnels = append(tunnels, api.Tunnel{
+				Type:      "gtpu",
+				SwIfIndex: swIfIdx,
+				Src:       gtpu.SrcAddr,
+				Dst:       gtpu.DstAddr,
+				ID:        gtpu.Teid,
+			})
+		}
+	}
+	geneveTunnels, err := h.geneveVppCalls.DumpTunnels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(tunnels, geneveTunnels...), nil
+}
+
+// DumpPolicers returns policers parsed from the 'show policer' output,
+// the agent does not dump the policer counters.
+func (h *Handler) DumpPolicers(ctx context.Context) ([]api.Policer, error) {
+	out, err := h.vppCoreCalls.RunCli(ctx, "show policer")
+	if err != nil {
+		return nil, err
+	}
+	return parsePolicers(out), nil
+}
+
+// parsePolicers parses the 'show policer' output, each policer is listed
+// with its configuration followed by its counters:
+//
+//	Name "pol1" type 1r2c cir 10000 eir 0 cb 20000 eb 0
+//	rate type kbps, round type closest
+//	conform action transmit, exceed action drop, violate action drop
+//	conform 12 packets, 1536 bytes
+//	exceed 0 packets, 0 bytes
+//	violate 3 packets, 384 bytes
+func parsePolicers(out string) []api.Policer {
+	var policers []api.Policer
+	var policer *api.Policer
+	for _, line := range strings.Split(out, "\n") {
+		if matches := policerNameRe.FindStringSubmatch(line); matches != nil {
+			policers = append(policers, api.Policer{Name: matches[1]})
+			policer = &policers[len(policers)-1]
+			line = matches[2]
+		}
+		if policer == nil {
+			continue
+		}
+		if matches := policerCountersRe.FindStringSubmatch(line); matches != nil {
+			counter := govppapi.InterfaceCounterCombined{
+				Packets: parseUint(matches[2]),
+				Bytes:   parseUint(matches[3]),
+			}
+			switch matches[1] {
+			case "conform":
+				policer.Conform = counter
+			case "exceed":
+				policer.Exceed = counter
+			case "violate":
+				policer.Violate = counter
+			}
+			continue
+		}
+		for _, part := range strings.Split(line, ",") {
+			fields := strings.Fields(part)
+			for i := 0; i < len(fields)-1; i++ {
+				value := fields[i+1]
+				switch fields[i] {
+				case "type":
+					if i > 0 && fields[i-1] == "rate" {
+						policer.RateType = value
+					} else if i == 0 {
+						policer.Type = value
+					}
+				case "cir":
+					policer.CIR = parseUint(value)
+				case "eir":
+					policer.EIR = parseUint(value)
+				case "cb":
+					policer.CB = parseUint(value)
+				case "eb":
+					policer.EB = parseUint(value)
+				case "action":
+					action := strings.Join(fields[i+1:], " ")
+					switch fields[0] {
+					case "conform":
+						policer.ConformAction = action
+					case "exceed":
+						policer.ExceedAction = action
+					case "violate":
+						policer.ViolateAction = action
+					}
+				}
+			}
+		}
+	}
+	return policers
+}
+
+// parseUint returns the number or zero if it is not a number.
+func parseUint(s string) uint64 {
+	n, _ := strconv.ParseUint(s, 10, 64)
+	return n
+}
+
+// DumpFibTables is not supported by the VPP-Agent based handler, the FIB
+// tables are identified by their names shown by the CLI only.
+func (h *Handler) DumpFibTables(_ context.Context) ([]api.FibTable, error) {
+	return nil, nil
+}
+
+// DumpRxPlacement is not supported by the VPP-Agent based handler,
+// the rx placement is parsed from the CLI.
+func (h *Handler) DumpRxPlacement(_ context.Context) ([]api.RxPlacement, error) {
+	return nil, api.ErrNotSupported
+}
+
+// DumpNeighbors is not supported by the VPP-Agent based handler,
+// the neighbors are parsed from the CLI.
+func (h *Handler) DumpNeighbors(_ context.Context) ([]api.Neighbor, error) {
+	return nil, api.ErrNotSupported
+}
+
+// DumpLLDPNeighbors returns the LLDP neighbors parsed from the 'show lldp'
+// output, the agent does not dump them.
+func (h *Handler) DumpLLDPNeighbors(ctx context.Context) ([]api.LLDPNeighbor, error) {
+	out, err := h.RunCli(ctx, "show lldp")
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseLLDPNeighbors(out)
+}
+
+// DumpMemifRings returns the memif rings parsed from the 'show memif'
+// output, the agent does not dump the state of the rings.
+func (h *Handler) DumpMemifRings(ctx context.Context) ([]api.MemifRing, error) {
+	out, err := h.RunCli(ctx, "show memif")
+	if err != nil {
+		return nil, err
+	}
+	return api.ParseMemifRings(out)
+}
+
+// DumpAPITraceStatus parses the 'api trace status' output.
+func (h *Handler) DumpAPITraceStatus(ctx context.Context) (*api.APITrace, error) {
+	return api.DumpAPITraceStatus(ctx, h.RunCli)
+}
+
+// DumpAPITrace parses the 'api trace dump' output.
+func (h *Handler) DumpAPITrace(ctx context.Context) ([]api.APITraceMessage, error) {
+	return api.DumpAPITrace(ctx, h.RunCli)
+}
+
+func (h *Handler) SetAPITrace(ctx context.Context, enable bool) error {
+	return api.SetAPITrace(ctx, h.RunCli, enable)
+}
+
+func (h *Handler) SaveAPITrace(ctx context.Context, file string) error {
+	return api.SaveAPITrace(ctx, h.RunCli, file)
+}
+
+func (h *Handler) FreeAPITrace(ctx context.Context) error {
+	return api.FreeAPITrace(ctx, h.RunCli)
+}
+
+// DumpSRv6 returns the SRv6 policies and local SIDs parsed from the CLI,
+// the agent does not dump the counters of the local SIDs.
+func (h *Handler) DumpSRv6(ctx context.Context) ([]api.SRv6SID, error) {
+	return api.DumpSRv6(ctx, h.RunCli)
+}
+
+// DumpMpls returns the MPLS tunnels and FIB entries parsed from the CLI.
+func (h *Handler) DumpMpls(ctx context.Context) ([]api.MplsEntry, error) {
+	return api.DumpMpls(ctx, h.RunCli)
+}
+
+// WatchNeighbors is not supported by the VPP-Agent based handler,
+// the neighbors are polled only.
+func (h *Handler) WatchNeighbors(_ context.Context, _ func()) error {
+	return api.ErrNotSupported
+}
+
+// ClearInterfaceCounters is not supported by the VPP-Agent based handler,
+// the interface counters are cleared by the CLI.
+func (h *Handler) ClearInterfaceCounters(_ context.Context) error {
+	return api.ErrNotSupported
+}
+
+// Capabilities of the VPP-Agent based handler, the agent does not dump
+// the severities of the error counters.
+func (h *Handler) Capabilities() api.Capabilities {
+	return api.Capabilities{
+		CLI:         true,
+		Threads:     true,
+		WorkerStats: h.statsSegment,
+	}
+}
+
 func (h *Handler) Close() {
 	if h.apiChan != nil {
 		h.apiChan.Close()